@@ -0,0 +1,38 @@
+// Package auth provides commands for logging in to YNAB with an OAuth app.
+package auth
+
+import (
+	"github.com/pgbytes/moneypenny/cmd/cli/auth/login"
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/spf13/cobra"
+)
+
+// Cmd is the parent command for YNAB authorization.
+var Cmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Log in to YNAB with an OAuth app",
+	Long: `Log in to YNAB with an OAuth app instead of a personal access token.
+
+Register an OAuth app in the YNAB developer settings with the redirect URI
+http://127.0.0.1:8976/oauth/callback and configure it:
+
+  "ynab": {
+    "budget_id": "...",
+    "oauth": {"client_id": "...", "client_secret": "vault:ynab-oauth-app"}
+  }
+
+One build and app can be shared by the whole family: every member runs
+mp auth login once, and the access and refresh tokens are kept in their own
+encrypted vault. Expired tokens are refreshed transparently.
+
+Example:
+  mp auth login
+  mp auth login -p household --no-browser`,
+}
+
+func init() {
+	cliutil.AddConfigFlags(Cmd)
+
+	// Register subcommands
+	Cmd.AddCommand(login.Cmd)
+}
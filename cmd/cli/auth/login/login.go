@@ -0,0 +1,116 @@
+// Package login provides the OAuth login command.
+package login
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/oauth"
+	"github.com/spf13/cobra"
+)
+
+var noBrowser bool
+
+// Cmd runs the OAuth authorization code flow.
+var Cmd = &cobra.Command{
+	Use:   "login",
+	Short: "Authorize MoneyPenny with the configured OAuth app",
+	Long: `Authorize MoneyPenny with the OAuth app configured in ynab.oauth.
+
+The authorization page is opened in the browser (or printed with
+--no-browser). After access is granted, YNAB redirects to a listener on
+127.0.0.1, the code is exchanged for access and refresh tokens, and the
+tokens are stored in the encrypted vault (entry ynab-oauth, or
+ynab-oauth-<profile>). Running login again replaces the stored tokens.
+--record, --replay and --read-only apply to the requests verifying the
+login, as for the ynab commands (see mp ynab --help).
+
+Example:
+  mp auth login
+  mp auth login -p household
+  mp auth login --no-browser`,
+	RunE: run,
+}
+
+func init() {
+	Cmd.Flags().BoolVar(&noBrowser, "no-browser", false, "only print the authorization URL instead of opening a browser")
+	cliutil.AddClientFlags(Cmd)
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+
+	cfg, err := cliutil.LoadConfig(cmd)
+	if err != nil {
+		return err
+	}
+	if !cfg.YNAB.OAuth.Enabled() {
+		return fmt.Errorf("no OAuth app configured, set ynab.oauth.client_id and ynab.oauth.client_secret")
+	}
+	if err := cfg.YNAB.OAuth.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	app, err := cliutil.OAuthConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Unlock the vault first, so nothing is asked after the browser returns
+	vault, passphrase, err := cliutil.OpenVault(cliutil.Prompter())
+	if err != nil {
+		return err
+	}
+
+	token, err := oauth.Login(context.Background(), app, func(authURL string) error {
+		fmt.Fprintf(os.Stderr, "Open this URL to authorize MoneyPenny:\n\n  %s\n\n", authURL)
+		if !noBrowser {
+			if err := openBrowser(authURL); err != nil {
+				logger.Warnf("Could not open a browser: %v", err)
+			}
+		}
+		logger.Info("Waiting for the authorization redirect...")
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("logging in: %w", err)
+	}
+
+	entry := cliutil.OAuthTokenEntry(cfg)
+	if err := cliutil.SaveOAuthToken(vault, passphrase, entry, token); err != nil {
+		return fmt.Errorf("storing tokens: %w", err)
+	}
+	logger.Infof("Stored the tokens in %s as %s", vault.Path(), entry)
+
+	client, err := cliutil.NewClient(cmd, ynab.Config{APIKey: token.AccessToken, BudgetID: ynab.LastUsedBudgetID, BaseURL: cfg.YNAB.BaseURL})
+	if err != nil {
+		return err
+	}
+	budgets, err := client.GetBudgets(false)
+	if err != nil {
+		return fmt.Errorf("verifying the login: %w", err)
+	}
+
+	logger.Infof("Logged in, %d budgets accessible", len(budgets))
+	return nil
+}
+
+// openBrowser opens url with the platform's default handler.
+func openBrowser(url string) error {
+	var c *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		c = exec.Command("open", url)
+	case "windows":
+		c = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		c = exec.Command("xdg-open", url)
+	}
+	return c.Start()
+}
@@ -0,0 +1,152 @@
+// Package cliutil provides helpers shared by CLI command packages.
+package cliutil
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/config"
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/rules"
+	"github.com/spf13/cobra"
+)
+
+// AddConfigFlags registers the --config and --profile flags read by
+// LoadConfig as persistent flags of cmd.
+func AddConfigFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP("config", "f", "", "path to config file (JSON, YAML or TOML; default: ~/.config/moneypenny/config.* and ./.moneypenny.*)")
+	cmd.PersistentFlags().StringP("profile", "p", "", "config profile to use (default: default_profile of the config file)")
+}
+
+// LoadConfig merges the config layers (defaults, XDG config file, the file
+// given with the inherited --config flag or the project-local file,
+// MONEYPENNY_* variables and flags) and resolves the profile chosen with the
+// inherited --profile flag.
+func LoadConfig(cmd *cobra.Command) (*config.Config, error) {
+	configPath, err := cmd.Flags().GetString("config")
+	if err != nil {
+		return nil, fmt.Errorf("getting config flag: %w", err)
+	}
+
+	var flags []config.FlagValue
+	if f := cmd.Flags().Lookup("rules"); f != nil && f.Changed {
+		flags = append(flags, config.FlagValue{Key: "rules", Flag: "rules", Value: f.Value.String()})
+	}
+
+	cfg, err := config.Load(config.LoadOptions{File: configPath, Environ: os.Environ(), Flags: flags})
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+
+	// Commands outside the ynab tree have no --profile flag and use the default
+	profileName, _ := cmd.Flags().GetString("profile")
+	resolved, err := cfg.Profile(profileName)
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	if resolved.ProfileName != "" {
+		log.GetLogger().Debugf("Using profile %s (budget %s)", resolved.ProfileName, resolved.YNAB.BudgetID)
+	}
+
+	return resolved, nil
+}
+
+// clientCacheTTL bounds how long a command reuses a read response, so long
+// interactive sessions still see changes made elsewhere.
+const clientCacheTTL = 5 * time.Minute
+
+// NewYNABClient loads and validates the configuration and creates a YNAB client
+// from it. Secret references in the token are resolved first; with an OAuth
+// app the tokens stored by mp auth login are used and refreshed as needed.
+// The client is created with NewClient, so reads are cached and the client
+// flags apply.
+func NewYNABClient(cmd *cobra.Command) (ynab.API, *config.Config, error) {
+	cfg, err := LoadConfig(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := cfg.YNAB.Validate(); err != nil && !Replaying(cmd) {
+		if len(cfg.Files) == 0 {
+			return nil, nil, fmt.Errorf("invalid config: %w (no config file found, run mp config init or pass -f)", err)
+		}
+		return nil, nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	clientCfg, err := ClientConfig(cmd, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, err := NewClient(cmd, clientCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, cfg, nil
+}
+
+// NewClient creates a YNAB client for clientCfg with the decorators chosen by
+// the client flags (see AddClientFlags): --record and --replay attach a
+// cassette and --read-only rejects all changes. Reads are cached for up to
+// clientCacheTTL. Every command talking to YNAB creates its client
+// here or through NewYNABClient.
+func NewClient(cmd *cobra.Command, clientCfg ynab.Config) (ynab.API, error) {
+	clientCfg, err := withCassette(cmd, clientCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := ynab.NewClient(clientCfg, log.GetLogger())
+	if err != nil {
+		return nil, fmt.Errorf("creating YNAB client: %w", err)
+	}
+
+	cachingClient := ynab.NewCachingClient(client, clientCacheTTL)
+	if ReadOnly(cmd) {
+		log.GetLogger().Info("Read-only mode, changes to the budget are rejected")
+		return ynab.NewReadOnlyClient(cachingClient), nil
+	}
+	return cachingClient, nil
+}
+
+// ReadOnly reports whether the inherited --read-only flag is set.
+func ReadOnly(cmd *cobra.Command) bool {
+	f := cmd.Flags().Lookup("read-only")
+	return f != nil && f.Value.String() == "true"
+}
+
+// DataDir returns the local data directory holding the journal, the ledger,
+// the payee mappings and the category models.
+func DataDir() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving data directory: %w", err)
+	}
+	return dir, nil
+}
+
+// ApplyRules runs the rules file of cfg (set by --rules, the profile or the
+// config file) or the rules file next to the config file over the
+// transactions. Without a rules file the transactions are returned unchanged.
+func ApplyRules(cfg *config.Config, source string, transactions []domain.Transaction) ([]domain.Transaction, error) {
+	engine, err := rules.LoadFor(cfg.Rules, cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	if engine == nil {
+		return transactions, nil
+	}
+
+	kept, outcomes, err := engine.ApplyAll(source, transactions)
+	if err != nil {
+		return nil, fmt.Errorf("applying rules: %w", err)
+	}
+
+	changed, skipped := rules.Count(outcomes)
+	log.GetLogger().Infof("Rules changed %d transactions and skipped %d", changed, skipped)
+
+	return kept, nil
+}
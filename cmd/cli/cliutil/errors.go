@@ -0,0 +1,68 @@
+package cliutil
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/oauth"
+)
+
+// ErrorHint returns what the user can do about err, or "" when there is
+// nothing specific to suggest.
+func ErrorHint(err error) string {
+	var apiErr *ynab.Error
+	switch {
+	case errors.Is(err, oauth.ErrInvalidGrant):
+		return "the OAuth login was revoked or has expired, run mp auth login"
+	case errors.Is(err, ynab.ErrReadOnly):
+		return "changes are disabled by --read-only, run the command without it to change the budget"
+	case errors.Is(err, ynab.ErrNotRecorded):
+		return "the cassette has no response for this request, record it again with --record"
+	case !errors.As(err, &apiErr):
+		return ""
+	}
+
+	switch {
+	case errors.Is(apiErr, ynab.ErrUnauthorized):
+		return "token revoked or expired, run mp auth login (OAuth) or store a new personal access token with mp secrets set ynab"
+	case errors.Is(apiErr, ynab.ErrForbidden):
+		return forbiddenHint(apiErr.Name())
+	case errors.Is(apiErr, ynab.ErrNotFound):
+		if apiErr.API != nil && apiErr.API.ID == ynab.ErrorIDEndpointNotFound {
+			return "the API endpoint does not exist, check ynab.base_url"
+		}
+		return "check budget_id and the account mappings with mp config validate"
+	case errors.Is(apiErr, ynab.ErrRateLimited):
+		return rateLimitHint(apiErr)
+	case errors.Is(apiErr, ynab.ErrServer):
+		return "YNAB is having problems, try again later"
+	}
+	return ""
+}
+
+// forbiddenHint explains a 403 by its API error name.
+func forbiddenHint(name string) string {
+	switch name {
+	case ynab.ErrorNameSubscriptionLapsed, ynab.ErrorNameTrialExpired:
+		return "the YNAB subscription has lapsed, renew it at app.ynab.com"
+	case ynab.ErrorNameUnauthorizedScope:
+		return "the token is read-only, run mp auth login with an OAuth app that has write access"
+	case ynab.ErrorNameDataLimitReached:
+		return "the budget exceeds the API data limit, archive old data in YNAB"
+	default:
+		return "YNAB denied access, check the token's permissions"
+	}
+}
+
+// rateLimitHint tells when the rate limit allows requests again.
+func rateLimitHint(err *ynab.Error) string {
+	limit := "the YNAB rate limit"
+	if err.RateLimit != nil {
+		limit = fmt.Sprintf("the YNAB rate limit of %d requests per hour", err.RateLimit.Limit)
+	}
+	if err.RetryAfter > 0 {
+		return fmt.Sprintf("%s is used up, try again in %s", limit, err.RetryAfter)
+	}
+	return limit + " is used up, try again later"
+}
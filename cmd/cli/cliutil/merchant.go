@@ -0,0 +1,21 @@
+package cliutil
+
+import (
+	"github.com/pgbytes/moneypenny/internal/domain"
+	transform "github.com/pgbytes/moneypenny/internal/transform/ynab"
+	"github.com/spf13/cobra"
+)
+
+// AddRawPayeeFlag registers the --raw-payee flag honoured by MerchantPayees.
+func AddRawPayeeFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("raw-payee", false, "keep the statement payee instead of the merchant name and location memo")
+}
+
+// MerchantPayees splits card payees into the merchant, used as payee, and the
+// location, put in the memo, unless the --raw-payee flag is set.
+func MerchantPayees(cmd *cobra.Command, transactions []domain.Transaction) []domain.Transaction {
+	if f := cmd.Flags().Lookup("raw-payee"); f != nil && f.Value.String() == "true" {
+		return transactions
+	}
+	return transform.WithMerchantPayees(transactions)
+}
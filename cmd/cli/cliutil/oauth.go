@@ -0,0 +1,121 @@
+package cliutil
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/config"
+	"github.com/pgbytes/moneypenny/internal/oauth"
+	"github.com/pgbytes/moneypenny/internal/secrets"
+)
+
+// DefaultOAuthTokenEntry is the vault entry holding the OAuth tokens of the
+// default profile; named profiles use ynab-oauth-<profile>.
+const DefaultOAuthTokenEntry = "ynab-oauth"
+
+// OAuthTokenEntry returns the vault entry holding the OAuth tokens of cfg.
+func OAuthTokenEntry(cfg *config.Config) string {
+	if cfg.YNAB.OAuth.TokenEntry != "" {
+		return cfg.YNAB.OAuth.TokenEntry
+	}
+	if cfg.ProfileName != "" {
+		return DefaultOAuthTokenEntry + "-" + cfg.ProfileName
+	}
+	return DefaultOAuthTokenEntry
+}
+
+// OAuthConfig returns the OAuth app of cfg with the client secret resolved.
+func OAuthConfig(cfg *config.Config) (oauth.Config, error) {
+	secret, err := resolveSecret("ynab.oauth.client_secret", cfg.YNAB.OAuth.ClientSecret)
+	if err != nil {
+		return oauth.Config{}, err
+	}
+
+	port := cfg.YNAB.OAuth.RedirectPort
+	if port == 0 {
+		port = config.DefaultOAuthRedirectPort
+	}
+	return oauth.Config{ClientID: cfg.YNAB.OAuth.ClientID, ClientSecret: secret, RedirectPort: port}, nil
+}
+
+// SaveOAuthToken stores token as JSON under entry and saves the vault.
+func SaveOAuthToken(vault *secrets.Vault, passphrase []byte, entry string, token *oauth.Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("encoding token: %w", err)
+	}
+	vault.Set(entry, string(data))
+	return vault.Save(passphrase)
+}
+
+// vaultTokenStore keeps OAuth tokens in a vault entry. The vault is reopened
+// on every access, so a refresh does not overwrite entries stored meanwhile.
+type vaultTokenStore struct {
+	path  string
+	entry string
+}
+
+// Load implements oauth.Store.
+func (s *vaultTokenStore) Load() (*oauth.Token, error) {
+	vault, _, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := vault.Get(s.entry)
+	if !ok {
+		return nil, fmt.Errorf("no OAuth login in vault entry %s, run mp auth login: %w", s.entry, secrets.ErrNotFound)
+	}
+	var token oauth.Token
+	if err := json.Unmarshal([]byte(data), &token); err != nil {
+		return nil, fmt.Errorf("parsing vault entry %s: %w", s.entry, err)
+	}
+	return &token, nil
+}
+
+// Save implements oauth.Store.
+func (s *vaultTokenStore) Save(token *oauth.Token) error {
+	vault, passphrase, err := s.open()
+	if err != nil {
+		return err
+	}
+	return SaveOAuthToken(vault, passphrase, s.entry, token)
+}
+
+func (s *vaultTokenStore) open() (*secrets.Vault, []byte, error) {
+	passphrase, err := unlockPassphrase()
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading vault passphrase: %w", err)
+	}
+	vault, err := secrets.OpenVault(s.path, passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+	return vault, passphrase, nil
+}
+
+// YNABClientConfig returns the client configuration for cfg: a token source
+// backed by the vault when an OAuth app is configured, or else the api_key
+// with any secret reference resolved.
+func YNABClientConfig(cfg *config.Config) (ynab.Config, error) {
+	if !cfg.YNAB.OAuth.Enabled() {
+		token, err := ResolveToken(cfg)
+		if err != nil {
+			return ynab.Config{}, err
+		}
+		return ynab.Config{APIKey: token, BudgetID: cfg.YNAB.BudgetID, BaseURL: cfg.YNAB.BaseURL}, nil
+	}
+
+	app, err := OAuthConfig(cfg)
+	if err != nil {
+		return ynab.Config{}, err
+	}
+	path, err := VaultPath()
+	if err != nil {
+		return ynab.Config{}, err
+	}
+
+	store := &vaultTokenStore{path: path, entry: OAuthTokenEntry(cfg)}
+	return ynab.Config{TokenSource: oauth.NewSource(app, store), BudgetID: cfg.YNAB.BudgetID, BaseURL: cfg.YNAB.BaseURL}, nil
+}
@@ -0,0 +1,55 @@
+package cliutil
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/pgbytes/moneypenny/internal/payee"
+	"github.com/pgbytes/moneypenny/internal/prompt"
+)
+
+// ResolvePayees sets PayeeID on every transaction whose payee matches an
+// existing YNAB payee, previews the resolutions and returns them, one per
+// distinct payee name. When confirm is set, the user is asked whether new
+// fuzzy matches should be remembered for future imports.
+func ResolvePayees(p *prompt.Prompter, client payee.Client, transactions []domain.Transaction, confirm bool) ([]payee.Resolution, error) {
+	dir, err := DataDir()
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, mappings, err := payee.LoadResolver(client, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	resolutions := resolver.ResolveAll(transactions)
+	if len(resolutions) > 0 {
+		if err := output.Preview(resolutions, PayeeResolutionTable()); err != nil {
+			return nil, err
+		}
+	}
+
+	var fuzzy []payee.Resolution
+	for _, res := range resolutions {
+		if res.Source == payee.SourceFuzzy {
+			fuzzy = append(fuzzy, res)
+		}
+	}
+	if !confirm || len(fuzzy) == 0 {
+		return resolutions, nil
+	}
+
+	ok, err := p.Confirm(
+		fmt.Sprintf("Remember the %d fuzzy payee matches for future imports?", len(fuzzy)))
+	if err != nil || !ok {
+		return resolutions, err
+	}
+
+	for _, res := range fuzzy {
+		resolver.Remember(res, time.Now())
+	}
+	return resolutions, mappings.Save()
+}
@@ -0,0 +1,15 @@
+package cliutil
+
+import (
+	"os"
+	"sync"
+
+	"github.com/pgbytes/moneypenny/internal/prompt"
+)
+
+// Prompter returns the prompter shared by every question of the process. It
+// buffers stdin once, so answers piped in for several questions are not lost
+// between them; never create another prompter on stdin.
+var Prompter = sync.OnceValue(func() *prompt.Prompter {
+	return prompt.New(os.Stdin, os.Stderr)
+})
@@ -0,0 +1,128 @@
+package cliutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pgbytes/moneypenny/internal/config"
+	"github.com/pgbytes/moneypenny/internal/prompt"
+	"github.com/pgbytes/moneypenny/internal/secrets"
+)
+
+// VaultPassphraseEnv is the environment variable that supplies the vault
+// passphrase for non-interactive use.
+const VaultPassphraseEnv = "MONEYPENNY_VAULT_PASSPHRASE"
+
+// VaultPath returns the location of the encrypted secret vault in the config directory.
+func VaultPath() (string, error) {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving config directory: %w", err)
+	}
+	return filepath.Join(dir, secrets.DefaultVaultFileName), nil
+}
+
+// VaultPassphrase returns the passphrase from MONEYPENNY_VAULT_PASSPHRASE or
+// asks for it without echo.
+func VaultPassphrase(p *prompt.Prompter, question string) ([]byte, error) {
+	if passphrase := os.Getenv(VaultPassphraseEnv); passphrase != "" {
+		return []byte(passphrase), nil
+	}
+
+	answer, err := p.AskSecret(question)
+	if err != nil {
+		return nil, err
+	}
+	if answer == "" {
+		return nil, fmt.Errorf("passphrase must not be empty")
+	}
+	return []byte(answer), nil
+}
+
+// NewVaultPassphrase asks for a new passphrase twice, or takes it from
+// MONEYPENNY_VAULT_PASSPHRASE.
+func NewVaultPassphrase(p *prompt.Prompter) ([]byte, error) {
+	passphrase, err := VaultPassphrase(p, "New vault passphrase:")
+	if err != nil || os.Getenv(VaultPassphraseEnv) != "" {
+		return passphrase, err
+	}
+
+	repeated, err := p.AskSecret("Repeat passphrase:")
+	if err != nil {
+		return nil, err
+	}
+	if repeated != string(passphrase) {
+		return nil, fmt.Errorf("passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+// OpenVault opens the secret vault, asking for its passphrase. When the vault
+// does not exist yet, a new passphrase is asked for twice. The passphrase is
+// returned for saving the vault.
+func OpenVault(p *prompt.Prompter) (*secrets.Vault, []byte, error) {
+	path, err := VaultPath()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var passphrase []byte
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		passphrase, err = NewVaultPassphrase(p)
+	} else {
+		passphrase, err = VaultPassphrase(p, "Vault passphrase:")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vault, err := secrets.OpenVault(path, passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+	return vault, passphrase, nil
+}
+
+// unlockPassphrase asks for the passphrase of the existing vault once per run,
+// so resolving the token and loading OAuth tokens share one prompt.
+var unlockPassphrase = sync.OnceValues(func() ([]byte, error) {
+	return VaultPassphrase(Prompter(), "Vault passphrase:")
+})
+
+// SecretStore returns a store resolving env:, file:, cmd: and vault: references.
+// The vault passphrase is only asked for when a vault: reference is resolved.
+func SecretStore() (*secrets.Store, error) {
+	path, err := VaultPath()
+	if err != nil {
+		return nil, err
+	}
+
+	store := secrets.NewStore()
+	store.Register(secrets.SchemeVault, &secrets.VaultResolver{
+		Path:       path,
+		Passphrase: unlockPassphrase,
+	})
+	return store, nil
+}
+
+// ResolveToken returns the YNAB token of cfg with any secret reference resolved.
+func ResolveToken(cfg *config.Config) (string, error) {
+	return resolveSecret("ynab.api_key", cfg.YNAB.APIKey)
+}
+
+// resolveSecret resolves a secret reference in the config value of key.
+func resolveSecret(key, ref string) (string, error) {
+	store, err := SecretStore()
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := store.Resolve(context.Background(), ref)
+	if err != nil {
+		return "", fmt.Errorf("resolving %s: %w", key, err)
+	}
+	return secret, nil
+}
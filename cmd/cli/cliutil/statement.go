@@ -0,0 +1,90 @@
+package cliutil
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/pgbytes/moneypenny/internal/ledger"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/parsers/milesmore"
+	"github.com/pgbytes/moneypenny/internal/prompt"
+)
+
+// ParseMilesMoreStrict parses a Miles & More statement with
+// milesmore.ParseFile, which fails on any row error, and logs the row count.
+func ParseMilesMoreStrict(ctx context.Context, path string) (*milesmore.ParseResult, error) {
+	result, err := milesmore.ParseFile(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	log.GetLogger().Infof("Successfully parsed %d transactions from %d rows", result.SuccessfulRows, result.TotalRows)
+
+	return result, nil
+}
+
+// ParseStatements parses Miles & More statements in strict mode and returns
+// their transactions dated within since and until (YYYY-MM-DD, both optional).
+func ParseStatements(ctx context.Context, paths []string, since, until string) ([]domain.Transaction, error) {
+	var from, to time.Time
+	var err error
+	if since != "" {
+		if from, err = time.Parse("2006-01-02", since); err != nil {
+			return nil, fmt.Errorf("invalid --since date %q (expected YYYY-MM-DD): %w", since, err)
+		}
+	}
+	if until != "" {
+		if to, err = time.Parse("2006-01-02", until); err != nil {
+			return nil, fmt.Errorf("invalid --until date %q (expected YYYY-MM-DD): %w", until, err)
+		}
+	}
+
+	var transactions []domain.Transaction
+	for _, path := range paths {
+		parseResult, err := ParseMilesMoreStrict(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range parseResult.Transactions {
+			if (!from.IsZero() && tx.Date.Before(from)) || (!to.IsZero() && tx.Date.After(to)) {
+				continue
+			}
+			transactions = append(transactions, tx)
+		}
+	}
+
+	return transactions, nil
+}
+
+// GuardStatement checks a statement against the ledger before it is processed.
+// Exact re-processing is refused unless force is set; overlapping date ranges
+// require confirmation from p unless force is set. It returns false when the user
+// declined to continue.
+func GuardStatement(p *prompt.Prompter, l *ledger.Ledger, stmt ledger.Statement, force bool) (bool, error) {
+	logger := log.GetLogger()
+
+	conflicts := l.Check(stmt)
+	if len(conflicts) == 0 {
+		return true, nil
+	}
+
+	for _, c := range conflicts {
+		logger.Warnf("  %s: %s processed %s (%s to %s, %d transactions)",
+			c.Kind, c.Existing.FileName, c.Existing.ProcessedAt.Local().Format("2006-01-02 15:04"),
+			c.Existing.FromDate, c.Existing.ToDate, c.Existing.TransactionCount)
+	}
+
+	if force {
+		logger.Warnf("Continuing despite %d conflicts with previously processed statements (--force)", len(conflicts))
+		return true, nil
+	}
+
+	if ledger.HasExact(conflicts) {
+		return false, fmt.Errorf("statement %s was already processed, use --force to process it again", stmt.FileName)
+	}
+
+	return p.Confirm(
+		fmt.Sprintf("Statement overlaps %d previously processed statements. Continue?", len(conflicts)))
+}
@@ -0,0 +1,45 @@
+package cliutil
+
+import (
+	"fmt"
+
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/pgbytes/moneypenny/internal/payee"
+	"github.com/pgbytes/moneypenny/internal/suggest"
+)
+
+// PayeeResolutionTable shows how each distinct payee name was resolved.
+func PayeeResolutionTable() output.Table[payee.Resolution] {
+	return output.Table[payee.Resolution]{
+		Headers: []string{"IMPORT PAYEE", "YNAB PAYEE", "MATCH", "SCORE"},
+		Row: func(res payee.Resolution) []string {
+			return []string{
+				output.Truncate(res.Name, 40),
+				output.Truncate(res.PayeeName, 30),
+				string(res.Source),
+				fmt.Sprintf("%.2f", res.Score),
+			}
+		},
+	}
+}
+
+// SuggestionTable shows the suggested category and confidence of every transaction.
+func SuggestionTable() output.Table[suggest.Result] {
+	return output.Table[suggest.Result]{
+		Headers: []string{"DATE", "PAYEE", "AMOUNT", "SUGGESTED CATEGORY", "CONFIDENCE", "APPLIED"},
+		Row: func(r suggest.Result) []string {
+			applied := "no"
+			if r.Applied {
+				applied = "yes"
+			}
+			return []string{
+				r.Transaction.Date.Format("2006-01-02"),
+				output.Truncate(r.Transaction.Payee, 35),
+				fmt.Sprintf("%.2f", r.Transaction.Amount),
+				output.Truncate(r.Suggestion.CategoryName, 25),
+				fmt.Sprintf("%.0f%%", r.Suggestion.Confidence*100),
+				applied,
+			}
+		},
+	}
+}
@@ -0,0 +1,28 @@
+// Package config provides commands for creating and checking the config file.
+package config
+
+import (
+	"github.com/pgbytes/moneypenny/cmd/cli/config/initconfig"
+	"github.com/pgbytes/moneypenny/cmd/cli/config/show"
+	"github.com/pgbytes/moneypenny/cmd/cli/config/validate"
+	"github.com/spf13/cobra"
+)
+
+// Cmd is the parent command for config file operations.
+var Cmd = &cobra.Command{
+	Use:   "config",
+	Short: "Create, validate and show the configuration",
+	Long: `Commands for the JSON config file used by the ynab commands.
+
+mp config init asks for the YNAB token, the budget and the YNAB account of
+every statement source and writes the file; mp config validate checks the
+config against the live API; mp config show lists the values merged from all
+config layers and where each came from.`,
+}
+
+func init() {
+	// Register subcommands
+	Cmd.AddCommand(initconfig.Cmd)
+	Cmd.AddCommand(validate.Cmd)
+	Cmd.AddCommand(show.Cmd)
+}
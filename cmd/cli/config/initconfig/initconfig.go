@@ -0,0 +1,245 @@
+// Package initconfig provides the interactive wizard that writes a new config file.
+package initconfig
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/config"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/prompt"
+	"github.com/pgbytes/moneypenny/internal/secrets"
+	"github.com/spf13/cobra"
+)
+
+// vaultEntry is the vault entry that holds the token when it is stored in the vault.
+const vaultEntry = "ynab"
+
+// Flags for the init command - isolated to this package.
+var (
+	configPath string
+	force      bool
+)
+
+// Cmd walks through creating a config file.
+var Cmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a config file interactively",
+	Long: `Create a config file step by step:
+
+  1. Enter a YNAB personal access token (Account Settings > Developer
+     Settings in YNAB). It is verified by listing your budgets.
+  2. Pick the budget to work with.
+  3. Map each Miles & More card number (as printed in the statement header,
+     masked digits allowed) and each Sparkasse IBAN to a YNAB account, so
+     imports find the account without --account-id.
+  4. Optionally store the token in the encrypted vault (see mp secrets), so
+     the config only holds the reference vault:ynab.
+
+The config is validated before it is written. The file is created readable
+only by you, since it contains the token. By default it is written to the XDG
+config directory, where every command finds it without -f. An existing file is
+not replaced unless --force is given. --record, --replay and --read-only work
+as for the ynab commands (see mp ynab --help).
+
+Example:
+  mp config init
+  mp config init -f ~/moneypenny.json --force`,
+	RunE: run,
+}
+
+func init() {
+	Cmd.Flags().StringVarP(&configPath, "config", "f", "", "path of the config file to write (default: ~/.config/moneypenny/config.json)")
+	Cmd.Flags().BoolVar(&force, "force", false, "replace an existing config file")
+	cliutil.AddClientFlags(Cmd)
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+
+	if configPath == "" {
+		path, err := config.DefaultConfigPath()
+		if err != nil {
+			return fmt.Errorf("resolving config path: %w", err)
+		}
+		configPath = path
+	}
+
+	if _, err := os.Stat(configPath); err == nil && !force {
+		return fmt.Errorf("%s already exists, use --force to replace it", configPath)
+	}
+
+	p := cliutil.Prompter()
+	out := os.Stderr
+
+	token, err := askRequired(p.AskSecret, "YNAB personal access token:")
+	if err != nil {
+		return err
+	}
+
+	// MONEYPENNY_YNAB_BASE_URL points the wizard at mp dev fake-ynab
+	baseURL := os.Getenv(config.EnvName("ynab.base_url"))
+
+	client, err := cliutil.NewClient(cmd, ynab.Config{APIKey: token, BudgetID: ynab.LastUsedBudgetID, BaseURL: baseURL})
+	if err != nil {
+		return err
+	}
+
+	budgets, err := client.GetBudgets(false)
+	if errors.Is(err, ynab.ErrUnauthorized) {
+		return fmt.Errorf("the token was rejected by YNAB, create a new one in the developer settings")
+	}
+	if err != nil {
+		return fmt.Errorf("verifying token: %w", err)
+	}
+	if len(budgets) == 0 {
+		return fmt.Errorf("the token is valid but has no budgets")
+	}
+	logger.Infof("Token verified, %d budgets found", len(budgets))
+
+	fmt.Fprintln(out, "\nBudgets:")
+	for i, b := range budgets {
+		fmt.Fprintf(out, "  %d) %s\n", i+1, b.Name)
+	}
+	i, err := choose(p, "Budget", len(budgets))
+	if err != nil {
+		return err
+	}
+	budget := budgets[i]
+
+	cfg := &config.Config{YNAB: config.YNABConfig{APIKey: token, BudgetID: budget.ID, BaseURL: baseURL}}
+
+	client, err = cliutil.NewClient(cmd, ynab.Config{APIKey: token, BudgetID: budget.ID, BaseURL: baseURL})
+	if err != nil {
+		return err
+	}
+	all, err := client.GetAccounts()
+	if err != nil {
+		return fmt.Errorf("fetching accounts: %w", err)
+	}
+
+	var accounts []ynab.Account
+	for _, acc := range all {
+		if !acc.Closed && !acc.Deleted {
+			accounts = append(accounts, acc)
+		}
+	}
+
+	if len(accounts) == 0 {
+		logger.Warnf("Budget %q has no open accounts, skipping account mapping", budget.Name)
+	} else {
+		sources := []struct{ source, label string }{
+			{config.SourceMilesMore, "Miles & More card number"},
+			{config.SourceSparkasse, "Sparkasse IBAN"},
+		}
+		for _, s := range sources {
+			mappings, err := mapAccounts(p, out, s.source, s.label, accounts)
+			if err != nil {
+				return err
+			}
+			cfg.Accounts = append(cfg.Accounts, mappings...)
+		}
+	}
+
+	store, err := p.Confirm("\nStore the token in the encrypted vault instead of the config file?")
+	if err != nil {
+		return err
+	}
+	if store {
+		vault, passphrase, err := cliutil.OpenVault(p)
+		if err != nil {
+			return err
+		}
+		vault.Set(vaultEntry, token)
+		if err := vault.Save(passphrase); err != nil {
+			return err
+		}
+		cfg.YNAB.APIKey = secrets.SchemeVault + ":" + vaultEntry
+		logger.Infof("Stored the token in %s", vault.Path())
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	if err := cfg.SaveToFile(configPath); err != nil {
+		return err
+	}
+
+	logger.Infof("Wrote %s for budget %q with %d account mappings", configPath, budget.Name, len(cfg.Accounts))
+	logger.Infof("Check it at any time with: mp config validate")
+	return nil
+}
+
+// mapAccounts asks for statement identifiers of one source until an empty
+// answer and lets the user pick the YNAB account for each.
+func mapAccounts(p *prompt.Prompter, out io.Writer, source, label string, accounts []ynab.Account) ([]config.AccountMapping, error) {
+	var mappings []config.AccountMapping
+	for {
+		identifier, err := p.Ask(fmt.Sprintf("\n%s (empty to continue):", label))
+		if err != nil {
+			return nil, err
+		}
+		if identifier == "" {
+			return mappings, nil
+		}
+
+		m := config.AccountMapping{Source: source}
+		valid := false
+		if source == config.SourceSparkasse {
+			m.IBAN = config.NormalizeIBAN(identifier)
+			valid = config.ValidIBAN(m.IBAN)
+		} else {
+			m.CardNumber = config.NormalizeCardNumber(identifier)
+			valid = config.ValidCardNumber(m.CardNumber)
+		}
+		if !valid {
+			fmt.Fprintf(out, "  %s is not a valid %s, try again\n", identifier, label)
+			continue
+		}
+
+		fmt.Fprintln(out, "YNAB accounts:")
+		for i, acc := range accounts {
+			fmt.Fprintf(out, "  %d) %s (%s)\n", i+1, acc.Name, acc.Type)
+		}
+		i, err := choose(p, "Account for "+m.Identifier(), len(accounts))
+		if err != nil {
+			return nil, err
+		}
+
+		m.AccountID = accounts[i].ID
+		m.AccountName = accounts[i].Name
+		mappings = append(mappings, m)
+	}
+}
+
+// choose asks for a number between 1 and n, defaulting to 1, and returns its index.
+func choose(p *prompt.Prompter, question string, n int) (int, error) {
+	for {
+		answer, err := p.AskDefault(fmt.Sprintf("%s (1-%d):", question, n), "1")
+		if err != nil {
+			return 0, err
+		}
+		i, err := strconv.Atoi(answer)
+		if err == nil && i >= 1 && i <= n {
+			return i - 1, nil
+		}
+	}
+}
+
+// askRequired asks until a non-empty answer is given.
+func askRequired(ask func(string) (string, error), question string) (string, error) {
+	for {
+		answer, err := ask(question)
+		if err != nil {
+			return "", err
+		}
+		if answer != "" {
+			return answer, nil
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Package show provides the command that prints the merged configuration.
+package show
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pgbytes/moneypenny/internal/config"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Flags for the show command - isolated to this package.
+var (
+	configPath string
+	profile    string
+	resolved   bool
+)
+
+// Cmd prints the configuration merged from all layers.
+var Cmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the merged configuration and where each value came from",
+	Long: `Show the configuration merged from all layers, lowest precedence first:
+
+  1. built-in defaults
+  2. the XDG config file ($XDG_CONFIG_HOME/moneypenny/config.json, .yaml or
+     .toml, default ~/.config/moneypenny)
+  3. the file given with -f, or else a project-local .moneypenny.json, .yaml or
+     .toml in the current directory
+  4. MONEYPENNY_* environment variables, e.g. MONEYPENNY_YNAB_API_KEY or
+     MONEYPENNY_FEES_FOREIGN_RATE_PERCENT
+  5. command line flags
+
+Every value is listed with the layer it came from. Without --resolved the
+profiles are listed as configured; with --resolved the settings of the chosen
+profile are applied, showing the values the ynab commands use. Secrets are
+masked.
+
+Example:
+  mp config show
+  mp config show --resolved --profile household
+  mp config show --resolved -o json`,
+	RunE: run,
+}
+
+func init() {
+	Cmd.Flags().StringVarP(&configPath, "config", "f", "", "path to the config file (default: project-local .moneypenny.*)")
+	Cmd.Flags().StringVarP(&profile, "profile", "p", "", "profile to resolve (default: default_profile)")
+	Cmd.Flags().BoolVar(&resolved, "resolved", false, "apply the profile and show the effective values")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+
+	cfg, err := config.Load(config.LoadOptions{File: configPath, Environ: os.Environ()})
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.Files) == 0 {
+		logger.Warn("No config file found, showing defaults and environment only")
+	}
+	for _, path := range cfg.Files {
+		logger.Infof("Loaded %s", path)
+	}
+
+	if resolved {
+		cfg, err = cfg.Profile(profile)
+		if err != nil {
+			return err
+		}
+		if cfg.ProfileName != "" {
+			logger.Infof("Resolved profile %s", cfg.ProfileName)
+		}
+	} else if profile != "" {
+		return fmt.Errorf("--profile requires --resolved")
+	}
+
+	return output.Print(cfg.Settings(), output.Table[config.Setting]{
+		Headers: []string{"KEY", "VALUE", "SOURCE"},
+		Row: func(s config.Setting) []string {
+			return []string{s.Key, s.Value, s.Source}
+		},
+	})
+}
@@ -0,0 +1,193 @@
+// Package validate provides the command that checks a config file against the YNAB API.
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/config"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/pgbytes/moneypenny/internal/secrets"
+	"github.com/spf13/cobra"
+)
+
+// Check statuses.
+const (
+	statusOK   = "ok"
+	statusWarn = "warn"
+	statusFail = "fail"
+)
+
+// Flags for the validate command - isolated to this package.
+var configPath string
+
+// Cmd validates a config file.
+var Cmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check a config file against the YNAB API",
+	Long: `Check the config offline and against the live YNAB API. A token stored in
+plain text is reported as a warning; secret references are resolved.
+
+The config is merged from all layers like for the ynab commands (see
+mp config show) and validated first (required fields, fee and currency settings,
+card numbers and IBANs of the account mappings). Then the token is verified,
+the budget is looked up and every mapped account is checked to exist in the
+budget. Closed accounts are reported as warnings. With profiles, every profile
+is checked with its inherited settings applied.
+
+--record, --replay and --read-only work as for the ynab commands (see
+mp ynab --help), e.g. to attach the API traffic to a bug report.
+
+Example:
+  mp config validate
+  mp config validate -f config.json
+  mp config validate -f config.json -o json`,
+	RunE: run,
+}
+
+// check is the outcome of a single validation step.
+type check struct {
+	Check  string `json:"check"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// rejectedHint tells how to replace a token YNAB rejected.
+func rejectedHint(cfg *config.Config) string {
+	if cfg.YNAB.OAuth.Enabled() {
+		return "rejected by YNAB and could not be refreshed, run mp auth login"
+	}
+	return "rejected by YNAB, create a new token or run mp config init"
+}
+
+func init() {
+	Cmd.Flags().StringVarP(&configPath, "config", "f", "", "path to the config file (default: layered config)")
+	cliutil.AddClientFlags(Cmd)
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+
+	cfg, err := config.Load(config.LoadOptions{File: configPath, Environ: os.Environ()})
+	if err != nil {
+		return err
+	}
+
+	checks := []check{{Check: "config", Status: statusOK, Detail: strings.Join(cfg.Files, ", ")}}
+	if len(cfg.Files) == 0 {
+		checks[0].Detail = "no config file, environment only"
+	}
+	if err := cfg.Validate(); err != nil {
+		checks[0] = check{Check: "config", Status: statusFail, Detail: err.Error()}
+	} else if len(cfg.Profiles) == 0 {
+		checks = append(checks, liveChecks(cmd, cfg)...)
+	} else {
+		for _, name := range cfg.ProfileNames() {
+			resolved, err := cfg.Profile(name)
+			if err != nil {
+				return err
+			}
+			for _, c := range liveChecks(cmd, resolved) {
+				c.Check = name + ": " + c.Check
+				checks = append(checks, c)
+			}
+		}
+	}
+
+	if err := output.Print(checks, output.Table[check]{
+		Headers: []string{"CHECK", "STATUS", "DETAIL"},
+		Row: func(c check) []string {
+			return []string{c.Check, c.Status, c.Detail}
+		},
+	}); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, c := range checks {
+		if c.Status == statusFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+
+	logger.Info("Config is valid")
+	return nil
+}
+
+// liveChecks verifies the token, the budget and the mapped accounts against YNAB.
+func liveChecks(cmd *cobra.Command, cfg *config.Config) []check {
+	storage := check{Check: "token storage", Status: statusOK, Detail: cfg.YNAB.APIKey}
+	switch {
+	case cfg.YNAB.OAuth.Enabled():
+		storage.Detail = "oauth, vault:" + cliutil.OAuthTokenEntry(cfg)
+	case !secrets.IsReference(cfg.YNAB.APIKey):
+		storage = check{Check: "token storage", Status: statusWarn, Detail: "plain text in the config, move it with mp secrets set ynab"}
+	}
+
+	clientCfg, err := cliutil.ClientConfig(cmd, cfg)
+	if err != nil {
+		return []check{storage, {Check: "token", Status: statusFail, Detail: err.Error()}}
+	}
+
+	client, err := cliutil.NewClient(cmd, clientCfg)
+	if err != nil {
+		return []check{storage, {Check: "token", Status: statusFail, Detail: err.Error()}}
+	}
+
+	budgets, err := client.GetBudgets(false)
+	if errors.Is(err, ynab.ErrUnauthorized) {
+		return []check{storage, {Check: "token", Status: statusFail, Detail: rejectedHint(cfg)}}
+	}
+	if err != nil {
+		detail := err.Error()
+		if hint := cliutil.ErrorHint(err); hint != "" {
+			detail += " (" + hint + ")"
+		}
+		return []check{storage, {Check: "token", Status: statusFail, Detail: detail}}
+	}
+	checks := []check{storage, {Check: "token", Status: statusOK, Detail: fmt.Sprintf("%d budgets accessible", len(budgets))}}
+
+	budget := check{Check: "budget", Status: statusFail, Detail: cfg.YNAB.BudgetID + " not found"}
+	for _, b := range budgets {
+		if b.ID == cfg.YNAB.BudgetID {
+			budget = check{Check: "budget", Status: statusOK, Detail: b.Name}
+		}
+	}
+	checks = append(checks, budget)
+	if budget.Status == statusFail || len(cfg.Accounts) == 0 {
+		return checks
+	}
+
+	accounts, err := client.GetAccounts()
+	if err != nil {
+		return append(checks, check{Check: "accounts", Status: statusFail, Detail: err.Error()})
+	}
+	byID := make(map[string]ynab.Account, len(accounts))
+	for _, acc := range accounts {
+		byID[acc.ID] = acc
+	}
+
+	for _, m := range cfg.Accounts {
+		c := check{Check: m.Source + " " + m.Identifier()}
+		acc, ok := byID[m.AccountID]
+		switch {
+		case !ok || acc.Deleted:
+			c.Status, c.Detail = statusFail, fmt.Sprintf("account %s not found in budget", config.AccountLabel(m))
+		case acc.Closed:
+			c.Status, c.Detail = statusWarn, fmt.Sprintf("account %s is closed", acc.Name)
+		default:
+			c.Status, c.Detail = statusOK, acc.Name
+		}
+		checks = append(checks, c)
+	}
+
+	return checks
+}
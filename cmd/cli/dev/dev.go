@@ -0,0 +1,22 @@
+// Package dev provides commands for developing and demoing MoneyPenny offline.
+package dev
+
+import (
+	"github.com/pgbytes/moneypenny/cmd/cli/dev/fakeynab"
+	"github.com/spf13/cobra"
+)
+
+// Cmd is the parent command for development tools.
+var Cmd = &cobra.Command{
+	Use:   "dev",
+	Short: "Development tools",
+	Long: `Tools for developing and demoing MoneyPenny without a YNAB account.
+
+mp dev fake-ynab starts an in-memory fake of the YNAB API; point the other
+commands at it with MONEYPENNY_YNAB_BASE_URL.`,
+}
+
+func init() {
+	// Register subcommands
+	Cmd.AddCommand(fakeynab.Cmd)
+}
@@ -0,0 +1,120 @@
+// Package fakeynab provides the command that serves the fake YNAB API.
+package fakeynab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/client/fakeynab"
+	"github.com/pgbytes/moneypenny/internal/config"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	port      int
+	token     string
+	rateLimit int
+	empty     bool
+)
+
+// Cmd serves the in-memory fake YNAB API.
+var Cmd = &cobra.Command{
+	Use:   "fake-ynab",
+	Short: "Serve an in-memory fake of the YNAB API",
+	Long: `Serve an in-memory fake of the YNAB API on 127.0.0.1 until interrupted.
+
+The fake starts with a demo budget (checking account, Miles & More card,
+cash, categories and last month's transactions, some of them waiting for
+review) unless --empty is given. It implements budgets, accounts,
+transactions with import_id de-duplication and server_knowledge deltas,
+categories and payees. All data is lost when it stops.
+
+Point the other commands at it with MONEYPENNY_YNAB_BASE_URL; any token is
+accepted unless --token is set.
+
+Example:
+  mp dev fake-ynab --port 8080
+  MONEYPENNY_YNAB_BASE_URL=http://127.0.0.1:8080/v1 MONEYPENNY_YNAB_API_KEY=demo \
+    MONEYPENNY_YNAB_BUDGET_ID=demo-budget mp ynab accounts list
+  mp dev fake-ynab --rate-limit 200`,
+	RunE: run,
+}
+
+func init() {
+	Cmd.Flags().IntVar(&port, "port", 8080, "port to listen on")
+	Cmd.Flags().StringVar(&token, "token", "", "only accept this bearer token (default: any)")
+	Cmd.Flags().IntVar(&rateLimit, "rate-limit", 0, "requests allowed per hour before answering 429 (default: unlimited)")
+	Cmd.Flags().BoolVar(&empty, "empty", false, "start without the demo budget")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+
+	fake := fakeynab.New()
+	if !empty {
+		demo, err := fakeynab.NewDemo()
+		if err != nil {
+			return fmt.Errorf("creating demo budget: %w", err)
+		}
+		fake = demo
+	}
+	fake.SetToken(token)
+	fake.SetRateLimit(rateLimit, fakeynab.DefaultRateLimitWindow)
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return fmt.Errorf("listening on port %d: %w", port, err)
+	}
+
+	server := &http.Server{Handler: logRequests(fake, logger), ReadHeaderTimeout: 10 * time.Second}
+	baseURL := fmt.Sprintf("http://%s/v1", listener.Addr())
+	logger.Infof("Fake YNAB API listening on %s", baseURL)
+	logger.Infof("Use it with %s=%s", config.EnvName("ynab.base_url"), baseURL)
+	if !empty {
+		logger.Infof("Demo budget ID: %s", fakeynab.DemoBudgetID)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdown)
+	}()
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serving fake YNAB API: %w", err)
+	}
+	logger.Info("Fake YNAB API stopped")
+	return nil
+}
+
+// statusRecorder captures the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// logRequests logs every request with its status and duration.
+func logRequests(next http.Handler, logger log.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		logger.Infof("%s %s %d %s", r.Method, r.URL.RequestURI(), rec.status, time.Since(start).Round(time.Millisecond))
+	})
+}
@@ -0,0 +1,23 @@
+// Package history provides commands for inspecting processed statements.
+package history
+
+import (
+	"github.com/pgbytes/moneypenny/cmd/cli/history/list"
+	"github.com/spf13/cobra"
+)
+
+// Cmd is the parent command for statement history operations.
+var Cmd = &cobra.Command{
+	Use:   "history",
+	Short: "Processed statement history",
+	Long: `Commands for inspecting the local ledger of processed statement files.
+
+Every transformed or imported statement is recorded with its content hash,
+billing date, account, date range and transaction count. The ledger is used
+to refuse exact re-imports and to warn about overlapping date ranges.`,
+}
+
+func init() {
+	// Register subcommands
+	Cmd.AddCommand(list.Cmd)
+}
@@ -0,0 +1,60 @@
+// Package list provides the command for listing processed statements.
+package list
+
+import (
+	"fmt"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/ledger"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Cmd lists the statements recorded in the ledger.
+var Cmd = &cobra.Command{
+	Use:   "list",
+	Short: "List processed statement files",
+	Long: `List all statement files recorded in the local ledger, most recent first.
+
+Example:
+  mp history list
+  mp history list -o json`,
+	RunE: run,
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	dir, err := cliutil.DataDir()
+	if err != nil {
+		return err
+	}
+
+	l, err := ledger.OpenDir(dir)
+	if err != nil {
+		return fmt.Errorf("opening statement ledger: %w", err)
+	}
+
+	statements := l.List()
+	if len(statements) == 0 {
+		log.GetLogger().Info("No statements processed yet")
+		return nil
+	}
+
+	return output.Print(statements, output.Table[ledger.Statement]{
+		Headers: []string{"PROCESSED", "ACTION", "SOURCE", "BILLING", "FROM", "TO", "COUNT", "ACCOUNT", "HASH", "FILE"},
+		Row: func(stmt ledger.Statement) []string {
+			return []string{
+				stmt.ProcessedAt.Local().Format("2006-01-02 15:04"),
+				string(stmt.Action),
+				stmt.Source,
+				stmt.BillingDate,
+				stmt.FromDate,
+				stmt.ToDate,
+				fmt.Sprintf("%d", stmt.TransactionCount),
+				stmt.AccountID,
+				output.Truncate(stmt.Hash, 12),
+				stmt.FileName,
+			}
+		},
+	})
+}
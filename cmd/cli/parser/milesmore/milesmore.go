@@ -7,9 +7,10 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"text/tabwriter"
 
+	"github.com/pgbytes/moneypenny/internal/domain"
 	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/output"
 	"github.com/pgbytes/moneypenny/internal/parsers/milesmore"
 	"github.com/spf13/cobra"
 )
@@ -25,18 +26,19 @@ var Cmd = &cobra.Command{
 	Short: "Parse Miles & More credit card CSV statement",
 	Long: `Parse a Miles & More credit card statement from a CSV file.
 
-This command validates the CSV format, parses all transactions, and displays them
-in a formatted table. Any parsing errors are reported at the end.
+This command validates the CSV format, parses all transactions, and writes them
+in the format selected with --output. Any parsing errors are logged at the end.
 
 Example:
   mp parser milesmore --file statement.csv
-  mp parser milesmore -f statement.csv --verbose`,
+  mp parser milesmore -f statement.csv --verbose
+  mp parser milesmore -f statement.csv -o json | jq '.[].amount'`,
 	RunE: run,
 }
 
 func init() {
 	Cmd.Flags().StringVarP(&filePath, "file", "f", "", "path to CSV file")
-	Cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "show detailed output")
+	Cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "show all transaction fields in table and csv output")
 	_ = Cmd.MarkFlagRequired("file")
 }
 
@@ -60,7 +62,9 @@ func run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("parsing CSV: %w", err)
 	}
 
-	displayTable(result, logger)
+	if err := displayTable(result, logger); err != nil {
+		return err
+	}
 
 	if len(result.Errors) > 0 {
 		displayErrors(result, logger)
@@ -98,87 +102,71 @@ func validateFilePath(path string) error {
 	return nil
 }
 
-func displayTable(result *milesmore.ParseResult, logger log.Logger) {
+func displayTable(result *milesmore.ParseResult, logger log.Logger) error {
 	if len(result.Transactions) == 0 {
 		logger.Warn("No transactions found in CSV")
-		return
+		return nil
+	}
+
+	table := output.Table[domain.Transaction]{
+		Headers: []string{"DATE", "PAYEE", "AMOUNT (EUR)"},
+		Row: func(tx domain.Transaction) []string {
+			return []string{tx.Date.Format("2006-01-02"), truncateString(tx.Payee, 45), fmt.Sprintf("%.2f", tx.Amount)}
+		},
+	}
+	if verbose {
+		table = output.Table[domain.Transaction]{
+			Headers: []string{"DATE", "POSTING DATE", "PAYEE", "AMOUNT", "CURRENCY", "FOREIGN AMOUNT", "FOREIGN CURRENCY", "EXCHANGE RATE", "MEMO", "IMPORT ID"},
+			Row: func(tx domain.Transaction) []string {
+				foreignAmount, exchangeRate := "", ""
+				if tx.ForeignCurrency != "" {
+					foreignAmount = fmt.Sprintf("%.2f", tx.ForeignAmount)
+					exchangeRate = fmt.Sprintf("%.5f", tx.ExchangeRate)
+				}
+				return []string{
+					tx.Date.Format("2006-01-02"),
+					tx.PostingDate.Format("2006-01-02"),
+					tx.Payee,
+					fmt.Sprintf("%.2f", tx.Amount),
+					tx.Currency,
+					foreignAmount,
+					tx.ForeignCurrency,
+					exchangeRate,
+					tx.Memo,
+					tx.ImportID,
+				}
+			},
+		}
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-
-	fmt.Fprintln(w, "\nDATE\tPAYEE\tAMOUNT (EUR)")
-	fmt.Fprintln(w, strings.Repeat("═", 12)+"\t"+strings.Repeat("═", 45)+"\t"+strings.Repeat("═", 12))
+	if err := output.Print(result.Transactions, table); err != nil {
+		return err
+	}
 
 	totalAmount := 0.0
 	for _, tx := range result.Transactions {
-		dateStr := tx.Date.Format("2006-01-02")
-		payee := truncateString(tx.Payee, 45)
-		amountStr := fmt.Sprintf("%.2f", tx.Amount)
-		fmt.Fprintf(w, "%s\t%s\t%s\n", dateStr, payee, amountStr)
 		totalAmount += tx.Amount
 	}
 
-	fmt.Fprintln(w, strings.Repeat("─", 12)+"\t"+strings.Repeat("─", 45)+"\t"+strings.Repeat("─", 12))
+	logger.Infof("Total transactions: %d", len(result.Transactions))
+	logger.Infof("Total amount: %.2f EUR", totalAmount)
+	logger.Infof("Date range: %s to %s",
+		result.Transactions[len(result.Transactions)-1].Date.Format("2006-01-02"),
+		result.Transactions[0].Date.Format("2006-01-02"))
+	logger.Infof("Parsing errors: %d", len(result.Errors))
 
-	fmt.Fprintf(w, "\nSummary:\n")
-	fmt.Fprintf(w, "  Total Transactions:\t%d\n", len(result.Transactions))
-	fmt.Fprintf(w, "  Total Amount:\t%.2f EUR\n", totalAmount)
-
-	if len(result.Transactions) > 0 {
-		firstDate := result.Transactions[len(result.Transactions)-1].Date.Format("2006-01-02")
-		lastDate := result.Transactions[0].Date.Format("2006-01-02")
-		fmt.Fprintf(w, "  Date Range:\t%s to %s\n", firstDate, lastDate)
-	}
-
-	fmt.Fprintf(w, "  Parsing Errors:\t%d\n", len(result.Errors))
-	w.Flush()
-
-	if verbose && len(result.Transactions) > 0 {
-		displayVerboseDetails(result)
-	}
-}
-
-func displayVerboseDetails(result *milesmore.ParseResult) {
-	fmt.Println("\n" + strings.Repeat("═", 80))
-	fmt.Println("VERBOSE TRANSACTION DETAILS")
-	fmt.Println(strings.Repeat("═", 80))
-
-	for i, tx := range result.Transactions {
-		fmt.Printf("\nTransaction #%d:\n", i+1)
-		fmt.Printf("  Date:           %s\n", tx.Date.Format("2006-01-02"))
-		fmt.Printf("  Posting Date:   %s\n", tx.PostingDate.Format("2006-01-02"))
-		fmt.Printf("  Payee:          %s\n", tx.Payee)
-		fmt.Printf("  Amount:         %.2f %s\n", tx.Amount, tx.Currency)
-
-		if tx.ForeignCurrency != "" {
-			fmt.Printf("  Foreign Amount: %.2f %s\n", tx.ForeignAmount, tx.ForeignCurrency)
-			fmt.Printf("  Exchange Rate:  %.5f\n", tx.ExchangeRate)
-		}
-
-		if tx.Memo != "" {
-			fmt.Printf("  Memo:           %s\n", tx.Memo)
-		}
-
-		fmt.Printf("  Import ID:      %s\n", tx.ImportID)
-	}
-
-	fmt.Println()
+	return nil
 }
 
 func displayErrors(result *milesmore.ParseResult, logger log.Logger) {
-	fmt.Println("\n" + strings.Repeat("═", 80))
-	fmt.Printf("PARSING ERRORS (%d)\n", len(result.Errors))
-	fmt.Println(strings.Repeat("═", 80))
-
-	for i, parseErr := range result.Errors {
-		fmt.Printf("\nError #%d (Line %d):\n", i+1, parseErr.Line)
-		fmt.Printf("  Error:   %s\n", parseErr.Error.Error())
+	for _, parseErr := range result.Errors {
 		if len(parseErr.Row) > 0 {
-			fmt.Printf("  Raw Row: %s\n", strings.Join(parseErr.Row, " | "))
+			logger.Warnf("Line %d: %s (row: %s)", parseErr.Line, parseErr.Error.Error(), strings.Join(parseErr.Row, " | "))
+			continue
 		}
+		logger.Warnf("Line %d: %s", parseErr.Line, parseErr.Error.Error())
 	}
 
-	fmt.Println()
 	logger.Warnf("Found %d parsing errors. Please review the CSV file.", len(result.Errors))
 }
 
@@ -0,0 +1,92 @@
+// Package countries provides the command for reporting spending by country.
+package countries
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/pgbytes/moneypenny/internal/report"
+	"github.com/spf13/cobra"
+)
+
+// Flags for the countries command - isolated to this package.
+var (
+	inputPaths []string
+	since      string
+	until      string
+)
+
+// Cmd reports statement spending grouped by merchant country.
+var Cmd = &cobra.Command{
+	Use:   "countries",
+	Short: "Report spending by merchant country",
+	Long: `Parse one or more Miles & More statements and sum spending by the
+merchant country encoded in the card payee. Foreign transaction fees count
+towards the country of the transaction they belong to. Rows without a
+location (e.g. payments to the card) are listed last.
+
+Example:
+  mp report countries -i january.csv -i february.csv --since 2026-01-15`,
+	RunE: run,
+}
+
+func init() {
+	Cmd.Flags().StringSliceVarP(&inputPaths, "input", "i", nil, "path to Miles & More CSV statement file (repeatable)")
+	Cmd.Flags().StringVar(&since, "since", "", "only include transactions on or after this date (YYYY-MM-DD)")
+	Cmd.Flags().StringVar(&until, "until", "", "only include transactions on or before this date (YYYY-MM-DD)")
+
+	_ = Cmd.MarkFlagRequired("input")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	transactions, err := cliutil.ParseStatements(ctx, inputPaths, since, until)
+	if err != nil {
+		return err
+	}
+
+	if len(transactions) == 0 {
+		logger.Info("No transactions in the selected range")
+		return nil
+	}
+
+	totals := report.ByCountry(transactions)
+
+	if err := output.Print(totals, output.Table[report.CountryTotal]{
+		Headers: []string{"COUNTRY", "COUNT", "SPENT", "RECEIVED", "NET", "CURRENCIES"},
+		Row: func(t report.CountryTotal) []string {
+			country := t.Country
+			if country == "" {
+				country = "-"
+			}
+			return []string{
+				country,
+				fmt.Sprintf("%d", t.Transactions),
+				fmt.Sprintf("%.2f", t.Spent),
+				fmt.Sprintf("%.2f", t.Received),
+				fmt.Sprintf("%.2f", t.Net()),
+				strings.Join(t.Currencies, ", "),
+			}
+		},
+	}); err != nil {
+		return err
+	}
+
+	var spent, received float64
+	for _, t := range totals {
+		spent += t.Spent
+		received += t.Received
+	}
+
+	logger.Infof("Total spent: %.2f, received: %.2f over %d transactions", spent, received, len(transactions))
+	return nil
+}
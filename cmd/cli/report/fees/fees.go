@@ -0,0 +1,162 @@
+// Package fees provides the command for auditing foreign transaction fees.
+package fees
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/fx"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Flags for the fees command - isolated to this package.
+var (
+	inputPaths []string
+	configPath string
+	rate       float64
+	tolerance  float64
+	since      string
+	until      string
+	all        bool
+)
+
+// Cmd audits foreign transaction fees against the contract rate.
+var Cmd = &cobra.Command{
+	Use:   "fees",
+	Short: "Audit foreign transaction fees against the contract rate",
+	Long: `Parse one or more Miles & More statements, pair every foreign transaction
+fee (AUSLANDSEINSATZENTGELT) with its foreign-currency purchase and check it
+against the contract rate. Differences up to the tolerance are accepted as
+rounding.
+
+Reported are purchases without a fee, overcharged and undercharged fees, and
+fees without a matching purchase. Overcharges and orphan fees are the ones
+worth disputing with the bank.
+
+The contract rate is taken from --rate or from "fees.foreign_rate_percent" in
+the config file.
+
+Example:
+  mp report fees -i january.csv -f config.json
+  mp report fees -i january.csv -i february.csv --rate 1.75 --all`,
+	RunE: run,
+}
+
+func init() {
+	Cmd.Flags().StringSliceVarP(&inputPaths, "input", "i", nil, "path to Miles & More CSV statement file (repeatable)")
+	Cmd.Flags().StringVarP(&configPath, "config", "f", "", "path to config file with the contract fees (default: layered config, see mp config show)")
+	Cmd.Flags().Float64Var(&rate, "rate", 0, "contract fee rate in percent of the EUR amount (overrides the config)")
+	Cmd.Flags().Float64Var(&tolerance, "tolerance", 0, "accepted difference in EUR (default: config or 0.01)")
+	Cmd.Flags().StringVar(&since, "since", "", "only include transactions on or after this date (YYYY-MM-DD)")
+	Cmd.Flags().StringVar(&until, "until", "", "only include transactions on or before this date (YYYY-MM-DD)")
+	Cmd.Flags().BoolVar(&all, "all", false, "also list fees that match the contract rate")
+
+	_ = Cmd.MarkFlagRequired("input")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	opts, err := auditOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	transactions, err := cliutil.ParseStatements(ctx, inputPaths, since, until)
+	if err != nil {
+		return err
+	}
+
+	findings := fx.AuditFees(transactions, opts)
+	if len(findings) == 0 {
+		logger.Info("No foreign transactions or fees in the selected range")
+		return nil
+	}
+
+	if err := printFindings(findings); err != nil {
+		return err
+	}
+
+	counts := make(map[fx.FeeStatus]int)
+	disputed := 0.0
+	for _, f := range findings {
+		counts[f.Status]++
+		if f.Disputable() {
+			disputed += f.Difference
+		}
+	}
+
+	logger.Infof("Audited %d foreign transactions at %.2f%%: %d ok, %d missing, %d overcharged, %d undercharged, %d orphan fees",
+		len(findings)-counts[fx.FeeOrphan], opts.RatePercent,
+		counts[fx.FeeOK], counts[fx.FeeMissing], counts[fx.FeeOvercharged], counts[fx.FeeUndercharged], counts[fx.FeeOrphan])
+	if disputed > 0 {
+		logger.Warnf("%.2f EUR in overcharged and orphan fees can be disputed with the bank", disputed)
+	}
+
+	return nil
+}
+
+// auditOptions combines the flags with the fee settings from the config.
+func auditOptions(cmd *cobra.Command) (fx.AuditOptions, error) {
+	opts := fx.AuditOptions{RatePercent: rate, Tolerance: tolerance}
+
+	cfg, err := cliutil.LoadConfig(cmd)
+	if err != nil {
+		return opts, err
+	}
+	if err := cfg.Fees.Validate(); err != nil {
+		return opts, fmt.Errorf("invalid fees config: %w", err)
+	}
+	if !cmd.Flags().Changed("rate") {
+		opts.RatePercent = cfg.Fees.ForeignRatePercent
+	}
+	if !cmd.Flags().Changed("tolerance") {
+		opts.Tolerance = cfg.Fees.Tolerance
+	}
+
+	if opts.RatePercent <= 0 {
+		return opts, fmt.Errorf("no contract fee rate, use --rate or set fees.foreign_rate_percent in the config")
+	}
+
+	return opts, nil
+}
+
+// printFindings lists the audit findings, skipping correct fees unless --all is set.
+func printFindings(findings []fx.FeeFinding) error {
+	var shown []fx.FeeFinding
+	for _, f := range findings {
+		if f.Status == fx.FeeOK && !all {
+			continue
+		}
+		shown = append(shown, f)
+	}
+
+	return output.Print(shown, output.Table[fx.FeeFinding]{
+		Headers: []string{"STATUS", "DATE", "PURCHASE", "AMOUNT", "FOREIGN", "FEE DATE", "EXPECTED", "CHARGED", "DIFF"},
+		Row: func(f fx.FeeFinding) []string {
+			date, purchase, amount, foreign, feeDate := "-", "-", "-", "-", "-"
+			if tx := f.Transaction; tx != nil {
+				date = tx.Date.Format("2006-01-02")
+				purchase = output.Truncate(tx.Payee, 30)
+				amount = fmt.Sprintf("%.2f", tx.Amount)
+				foreign = fmt.Sprintf("%.2f %s", tx.ForeignAmount, tx.ForeignCurrency)
+			}
+			if f.Fee != nil {
+				feeDate = f.Fee.Date.Format("2006-01-02")
+			}
+			return []string{
+				string(f.Status), date, purchase, amount, foreign, feeDate,
+				fmt.Sprintf("%.2f", f.Expected),
+				fmt.Sprintf("%.2f", f.Charged),
+				fmt.Sprintf("%+.2f", f.Difference),
+			}
+		},
+	})
+}
@@ -0,0 +1,199 @@
+// Package fxreport provides the command for reporting currency conversion costs.
+package fxreport
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/pgbytes/moneypenny/internal/fx"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/pgbytes/moneypenny/internal/report"
+	"github.com/spf13/cobra"
+)
+
+// Flags for the fx command - isolated to this package.
+var (
+	inputPaths []string
+	ratesPath  string
+	since      string
+	until      string
+	details    bool
+)
+
+// Cmd reports markup and foreign fees of foreign-currency transactions.
+var Cmd = &cobra.Command{
+	Use:   "fx",
+	Short: "Report currency conversion markup and foreign fees",
+	Long: `Parse one or more Miles & More statements and compare every
+foreign-currency transaction with the ECB euro reference rate of its date.
+The difference between the charged EUR amount and the fair amount is the
+hidden markup of the card issuer; together with the foreign transaction fees
+it is summarized per month and currency.
+
+The rates are read from a locally stored eurofxref-hist CSV or XML file
+(download eurofxref-hist.zip from the ECB website), given with --rates or
+placed in the data directory. Weekend and holiday dates use the previous
+published rate.
+
+With --details every foreign-currency transaction is listed before the
+summary. In json, ndjson, csv and yaml output --details replaces the summary
+with the transaction list, so the output stays a single record set.
+
+Example:
+  mp report fx -i january.csv -i february.csv
+  mp report fx -i january.csv --rates ~/Downloads/eurofxref-hist.csv --details
+  mp report fx -i january.csv --details -o csv > conversions.csv`,
+	RunE: run,
+}
+
+func init() {
+	Cmd.Flags().StringSliceVarP(&inputPaths, "input", "i", nil, "path to Miles & More CSV statement file (repeatable)")
+	Cmd.Flags().StringVar(&ratesPath, "rates", "", "path to ECB eurofxref-hist CSV or XML file (default: data directory)")
+	Cmd.Flags().StringVar(&since, "since", "", "only include transactions on or after this date (YYYY-MM-DD)")
+	Cmd.Flags().StringVar(&until, "until", "", "only include transactions on or before this date (YYYY-MM-DD)")
+	Cmd.Flags().BoolVar(&details, "details", false, "also list every foreign-currency transaction")
+
+	_ = Cmd.MarkFlagRequired("input")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	dir, err := cliutil.DataDir()
+	if err != nil {
+		return err
+	}
+
+	rates, err := fx.LoadRates(ratesPath, dir)
+	if err != nil {
+		return err
+	}
+
+	first, last := rates.Range()
+	logger.Debugf("Loaded ECB rates for %d currencies from %s to %s",
+		len(rates.Currencies()), first.Format("2006-01-02"), last.Format("2006-01-02"))
+
+	transactions, err := cliutil.ParseStatements(ctx, inputPaths, since, until)
+	if err != nil {
+		return err
+	}
+
+	if details && !output.IsTable() {
+		return output.Print(conversions(transactions, rates), conversionTable())
+	}
+	if details {
+		if err := output.Print(conversions(transactions, rates), conversionTable()); err != nil {
+			return err
+		}
+		fmt.Fprintln(output.Stdout)
+	}
+
+	totals, err := report.FX(transactions, rates)
+	if err != nil {
+		return err
+	}
+	if len(totals) == 0 {
+		logger.Info("No foreign-currency transactions in the selected range")
+		return nil
+	}
+
+	if err := output.Print(totals, output.Table[report.FXTotal]{
+		Headers: []string{"MONTH", "CURRENCY", "COUNT", "FOREIGN", "CHARGED", "FAIR", "MARKUP", "FEES", "COST", "COST %"},
+		Row: func(t report.FXTotal) []string {
+			currency := t.Currency
+			if currency == "" {
+				currency = "-"
+			}
+			return []string{
+				t.Month,
+				currency,
+				fmt.Sprintf("%d", t.Transactions),
+				fmt.Sprintf("%.2f", t.ForeignAmount),
+				fmt.Sprintf("%.2f", t.Charged),
+				fmt.Sprintf("%.2f", t.Fair),
+				fmt.Sprintf("%.2f", t.Markup),
+				fmt.Sprintf("%.2f", t.Fees),
+				fmt.Sprintf("%.2f", t.Cost()),
+				fmt.Sprintf("%.2f", t.CostPercent()),
+			}
+		},
+	}); err != nil {
+		return err
+	}
+
+	var sum report.FXTotal
+	unrated := 0
+	for _, t := range totals {
+		sum.Charged += t.Charged
+		sum.Fair += t.Fair
+		sum.Markup += t.Markup
+		sum.Fees += t.Fees
+		unrated += t.Unrated
+	}
+
+	logger.Infof("Total markup: %.2f EUR, fees: %.2f EUR, cost: %.2f EUR (%.2f%% of %.2f EUR fair value)",
+		sum.Markup, sum.Fees, sum.Cost(), sum.CostPercent(), sum.Fair)
+	if unrated > 0 {
+		logger.Warnf("%d transactions have no ECB rate for their date and are excluded from markup", unrated)
+	}
+
+	return nil
+}
+
+// conversion is a foreign-currency transaction with its reference rate
+// comparison; Conversion is nil when no rate was published for its date.
+type conversion struct {
+	Transaction domain.Transaction `json:"transaction"`
+	Conversion  *fx.Conversion     `json:"conversion,omitempty"`
+}
+
+// conversions compares every foreign-currency transaction with its reference rate.
+func conversions(transactions []domain.Transaction, rates *fx.Rates) []conversion {
+	var result []conversion
+	for _, tx := range transactions {
+		if !fx.IsForeign(tx) {
+			continue
+		}
+
+		r := conversion{Transaction: tx}
+		if c, err := fx.Convert(tx, rates); err == nil {
+			r.Conversion = &c
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// conversionTable lists every foreign-currency transaction with its reference rate.
+func conversionTable() output.Table[conversion] {
+	return output.Table[conversion]{
+		Headers: []string{"DATE", "PAYEE", "FOREIGN", "CHARGED", "BANK RATE", "ECB RATE", "FAIR", "MARKUP", "MARKUP %"},
+		Row: func(r conversion) []string {
+			tx := r.Transaction
+			row := []string{
+				tx.Date.Format("2006-01-02"),
+				output.Truncate(tx.Payee, 30),
+				fmt.Sprintf("%.2f %s", tx.ForeignAmount, tx.ForeignCurrency),
+				fmt.Sprintf("%.2f", tx.Amount),
+			}
+			c := r.Conversion
+			if c == nil {
+				return append(row, "-", "-", "-", "-", "-")
+			}
+			return append(row,
+				fmt.Sprintf("%.5f", c.BankRate),
+				fmt.Sprintf("%.5f", c.ReferenceRate),
+				fmt.Sprintf("%.2f", c.FairAmount),
+				fmt.Sprintf("%.2f", c.Markup),
+				fmt.Sprintf("%.2f", c.MarkupPercent),
+			)
+		},
+	}
+}
@@ -0,0 +1,26 @@
+// Package report provides commands for spending reports over statements.
+package report
+
+import (
+	"github.com/pgbytes/moneypenny/cmd/cli/report/countries"
+	"github.com/pgbytes/moneypenny/cmd/cli/report/fees"
+	"github.com/pgbytes/moneypenny/cmd/cli/report/fxreport"
+	"github.com/spf13/cobra"
+)
+
+// Cmd is the parent command for report operations.
+var Cmd = &cobra.Command{
+	Use:   "report",
+	Short: "Spending reports over statement files",
+	Long: `Commands for aggregating statement transactions into spending reports.
+
+Reports work on local statement files and do not need YNAB access.
+Currency reports additionally need the ECB reference rate history.`,
+}
+
+func init() {
+	// Register subcommands
+	Cmd.AddCommand(countries.Cmd)
+	Cmd.AddCommand(fxreport.Cmd)
+	Cmd.AddCommand(fees.Cmd)
+}
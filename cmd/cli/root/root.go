@@ -3,27 +3,59 @@ package root
 import (
 	"os"
 
+	"github.com/pgbytes/moneypenny/cmd/cli/auth"
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/cmd/cli/config"
+	"github.com/pgbytes/moneypenny/cmd/cli/dev"
+	"github.com/pgbytes/moneypenny/cmd/cli/history"
 	"github.com/pgbytes/moneypenny/cmd/cli/parser"
+	"github.com/pgbytes/moneypenny/cmd/cli/report"
+	"github.com/pgbytes/moneypenny/cmd/cli/rules"
+	"github.com/pgbytes/moneypenny/cmd/cli/secrets"
 	"github.com/pgbytes/moneypenny/cmd/cli/ynab"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/output"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 )
 
 var (
-	csvFilePath string
+	csvFilePath  string
+	outputFormat string
 )
 
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&csvFilePath, "csv", "c", "sample/sample.csv", "relative path to csv file to process")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", string(output.FormatTable), "output format: table, json, ndjson, csv or yaml")
 
 	// Register top-level commands
 	rootCmd.AddCommand(parser.Cmd)
 	rootCmd.AddCommand(ynab.Cmd)
+	rootCmd.AddCommand(history.Cmd)
+	rootCmd.AddCommand(rules.Cmd)
+	rootCmd.AddCommand(report.Cmd)
+	rootCmd.AddCommand(config.Cmd)
+	rootCmd.AddCommand(secrets.Cmd)
+	rootCmd.AddCommand(auth.Cmd)
+	rootCmd.AddCommand(dev.Cmd)
 }
 
 var rootCmd = &cobra.Command{
 	Use:   "mp",
 	Short: "MoneyPenny is my finance assistant",
+	Long: `MoneyPenny is my finance assistant.
+
+Command results are written to stdout in the format selected with --output;
+logs and prompts are written to stderr, so results can be piped into jq or
+spreadsheets.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		format, err := output.ParseFormat(outputFormat)
+		if err != nil {
+			return err
+		}
+		output.SetFormat(format)
+		return nil
+	},
 }
 
 func Execute() {
@@ -40,6 +72,9 @@ func Execute() {
 func handleError(err error) {
 	if err != nil {
 		zap.S().Errorf("error while executing command: %v", err)
+		if hint := cliutil.ErrorHint(err); hint != "" {
+			log.GetLogger().Infof("Hint: %s", hint)
+		}
 		os.Exit(1)
 	}
 	os.Exit(0)
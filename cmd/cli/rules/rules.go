@@ -0,0 +1,28 @@
+// Package rules provides commands for working with payee and category rules.
+package rules
+
+import (
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/cmd/cli/rules/test"
+	"github.com/spf13/cobra"
+)
+
+// Cmd is the parent command for rules operations.
+var Cmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Payee cleanup and categorization rules",
+	Long: `Commands for working with the rules file.
+
+Rules rename payees, set categories, memos and flags, split or skip
+transactions. They are read from rules.yaml, rules.yml or rules.json next to
+the config file, or from the file given with --rules or the profile's "rules"
+setting, and run in both the transform and the import flows. The config is
+loaded like for the ynab commands (see mp ynab --help).`,
+}
+
+func init() {
+	cliutil.AddConfigFlags(Cmd)
+
+	// Register subcommands
+	Cmd.AddCommand(test.Cmd)
+}
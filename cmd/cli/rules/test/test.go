@@ -0,0 +1,148 @@
+// Package test provides the command for trying rules against a statement.
+package test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/pgbytes/moneypenny/internal/rules"
+	"github.com/spf13/cobra"
+)
+
+// Flags for the test command - isolated to this package.
+var (
+	statementPath string
+	rulesPath     string
+)
+
+// Cmd shows which rules fire for each row of a statement, without changing anything.
+var Cmd = &cobra.Command{
+	Use:   "test",
+	Short: "Show which rules fire for each statement row",
+	Long: `Parse a Miles & More statement and run the rules over every row, showing
+which rules fired and the resulting payee, category, flag and split. Nothing
+is written or sent to YNAB. As on import, card payees are split into merchant
+and location before the rules run, unless --raw-payee is given.
+
+The rules file is chosen like on import: --rules, the "rules" setting of the
+profile or config file, or rules.yaml/rules.json next to the config file. The
+config is loaded from all layers (see mp ynab --help), so the dry run tests
+the same rules that import and transform apply.
+
+Example:
+  mp rules test -i statement.csv
+  mp rules test -i statement.csv --profile household
+  mp rules test -i statement.csv --rules ~/moneypenny/rules.yaml`,
+	RunE: run,
+}
+
+func init() {
+	Cmd.Flags().StringVarP(&statementPath, "input", "i", "", "path to Miles & More CSV statement file")
+	Cmd.Flags().StringVarP(&rulesPath, "rules", "r", "", "path to rules file (default: rules.yaml/rules.json next to the config file)")
+	cliutil.AddRawPayeeFlag(Cmd)
+
+	_ = Cmd.MarkFlagRequired("input")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	cfg, err := cliutil.LoadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	engine, err := rules.LoadFor(cfg.Rules, cfg.Path)
+	if err != nil {
+		return err
+	}
+	if engine == nil {
+		return fmt.Errorf("no rules file found, use --rules or put rules.yaml next to the config file")
+	}
+
+	parseResult, err := cliutil.ParseMilesMoreStrict(ctx, statementPath)
+	if err != nil {
+		return err
+	}
+
+	_, outcomes, err := engine.ApplyAll("milesmore", cliutil.MerchantPayees(cmd, parseResult.Transactions))
+	if err != nil {
+		return err
+	}
+
+	if err := printOutcomes(outcomes); err != nil {
+		return err
+	}
+
+	fired, skipped := 0, 0
+	for _, o := range outcomes {
+		if len(o.Fired) > 0 {
+			fired++
+		}
+		if o.Skipped {
+			skipped++
+		}
+	}
+	logger.Infof("%d of %d rows matched a rule, %d would be skipped", fired, len(outcomes), skipped)
+
+	return nil
+}
+
+// printOutcomes lists every statement row with the rules that fired and the result.
+func printOutcomes(outcomes []rules.Outcome) error {
+	return output.Print(outcomes, output.Table[rules.Outcome]{
+		Headers: []string{"LINE", "DATE", "AMOUNT", "PAYEE", "RULES", "RESULT"},
+		Row: func(o rules.Outcome) []string {
+			fired := strings.Join(o.Fired, ", ")
+			if fired == "" {
+				fired = "-"
+			}
+			return []string{
+				fmt.Sprintf("%d", o.Original.SourceLine),
+				o.Original.Date.Format("2006-01-02"),
+				fmt.Sprintf("%.2f", o.Original.Amount),
+				output.Truncate(o.Original.Payee, 40),
+				fired,
+				describe(o),
+			}
+		},
+	})
+}
+
+// describe summarizes the changes rules made to a transaction.
+func describe(o rules.Outcome) string {
+	if o.Skipped {
+		return "skipped"
+	}
+
+	tx := o.Transaction
+	var changes []string
+	if tx.Payee != o.Original.Payee {
+		changes = append(changes, fmt.Sprintf("payee=%q", tx.Payee))
+	}
+	if tx.Category != o.Original.Category {
+		changes = append(changes, fmt.Sprintf("category=%q", tx.Category))
+	}
+	if tx.Memo != o.Original.Memo {
+		changes = append(changes, fmt.Sprintf("memo=%q", tx.Memo))
+	}
+	if tx.FlagColor != o.Original.FlagColor {
+		changes = append(changes, "flag="+tx.FlagColor)
+	}
+	for _, s := range tx.Splits {
+		changes = append(changes, fmt.Sprintf("split %.2f %s", s.Amount, s.Category))
+	}
+
+	if len(changes) == 0 {
+		return "-"
+	}
+	return strings.Join(changes, " ")
+}
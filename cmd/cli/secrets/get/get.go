@@ -0,0 +1,54 @@
+// Package get provides the command for reading a secret from the vault.
+package get
+
+import (
+	"fmt"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Cmd prints a secret from the vault.
+var Cmd = &cobra.Command{
+	Use:   "get <name>",
+	Short: "Print a secret from the vault",
+	Long: `Print a secret from the encrypted vault to stdout, e.g. to pass it to
+another tool. Without a name the names of all entries are listed.
+
+Example:
+  mp secrets get ynab
+  mp secrets get`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: run,
+}
+
+// entry is a vault entry name in the list output.
+type entry struct {
+	Name string `json:"name"`
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	vault, _, err := cliutil.OpenVault(cliutil.Prompter())
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		var entries []entry
+		for _, name := range vault.Names() {
+			entries = append(entries, entry{Name: name})
+		}
+		return output.Print(entries, output.Table[entry]{
+			Headers: []string{"NAME"},
+			Row:     func(e entry) []string { return []string{e.Name} },
+		})
+	}
+
+	secret, ok := vault.Get(args[0])
+	if !ok {
+		return fmt.Errorf("no entry %q in %s", args[0], vault.Path())
+	}
+	_, err = fmt.Fprintln(output.Stdout, secret)
+	return err
+}
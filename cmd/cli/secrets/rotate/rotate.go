@@ -0,0 +1,69 @@
+// Package rotate provides the command for changing the vault passphrase.
+package rotate
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/spf13/cobra"
+)
+
+// Cmd re-encrypts the vault with a new passphrase.
+var Cmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Re-encrypt the vault with a new passphrase",
+	Long: `Re-encrypt the vault with a new passphrase. The current passphrase is
+asked for first (or taken from MONEYPENNY_VAULT_PASSPHRASE), then the new one
+twice. A fresh salt and nonce are used, so the old passphrase no longer opens
+the vault.
+
+Example:
+  mp secrets rotate`,
+	RunE: run,
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+
+	path, err := cliutil.VaultPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return fmt.Errorf("no vault at %s, create one with mp secrets set", path)
+	}
+
+	p := cliutil.Prompter()
+	vault, _, err := cliutil.OpenVault(p)
+	if err != nil {
+		return err
+	}
+
+	// The new passphrase is always asked for, even when the current one came from the environment
+	passphrase, err := p.AskSecret("New vault passphrase:")
+	if err != nil {
+		return err
+	}
+	repeated, err := p.AskSecret("Repeat passphrase:")
+	if err != nil {
+		return err
+	}
+	if passphrase == "" {
+		return fmt.Errorf("passphrase must not be empty")
+	}
+	if passphrase != repeated {
+		return fmt.Errorf("passphrases do not match")
+	}
+
+	if err := vault.Save([]byte(passphrase)); err != nil {
+		return err
+	}
+
+	logger.Infof("Re-encrypted %d secrets in %s", len(vault.Names()), path)
+	if os.Getenv(cliutil.VaultPassphraseEnv) != "" {
+		logger.Warnf("Update %s to the new passphrase", cliutil.VaultPassphraseEnv)
+	}
+	return nil
+}
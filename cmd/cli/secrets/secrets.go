@@ -0,0 +1,40 @@
+// Package secrets provides commands for the encrypted secret vault.
+package secrets
+
+import (
+	"github.com/pgbytes/moneypenny/cmd/cli/secrets/get"
+	"github.com/pgbytes/moneypenny/cmd/cli/secrets/rotate"
+	"github.com/pgbytes/moneypenny/cmd/cli/secrets/set"
+	"github.com/spf13/cobra"
+)
+
+// Cmd is the parent command for the secret vault.
+var Cmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Manage the encrypted secret vault",
+	Long: `Manage secrets in the encrypted vault file (secrets.vault in the config
+directory, ~/.config/moneypenny by default).
+
+The vault is encrypted with a key derived from a passphrase (scrypt) and
+XChaCha20-Poly1305. The passphrase is asked for without echo, or taken from
+MONEYPENNY_VAULT_PASSPHRASE for scripts.
+
+Instead of a plain token the config can reference a secret:
+
+  "api_key": "vault:ynab"            entry of the vault
+  "api_key": "env:YNAB_TOKEN"        environment variable
+  "api_key": "file:~/.ynab-token"    first line of a file
+  "api_key": "cmd:pass show ynab"    first line of a command's output
+
+Example:
+  mp secrets set ynab
+  mp secrets get ynab
+  mp secrets rotate`,
+}
+
+func init() {
+	// Register subcommands
+	Cmd.AddCommand(set.Cmd)
+	Cmd.AddCommand(get.Cmd)
+	Cmd.AddCommand(rotate.Cmd)
+}
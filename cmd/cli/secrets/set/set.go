@@ -0,0 +1,60 @@
+// Package set provides the command for storing a secret in the vault.
+package set
+
+import (
+	"fmt"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/spf13/cobra"
+)
+
+// Cmd stores a secret in the vault.
+var Cmd = &cobra.Command{
+	Use:   "set <name>",
+	Short: "Store a secret in the vault",
+	Long: `Store a secret in the encrypted vault, replacing an existing entry.
+
+The secret is asked for without echo, or read from the first line of stdin
+when it is not a terminal. The vault is created on first use. Reference the
+entry in the config as vault:<name>.
+
+Example:
+  mp secrets set ynab
+  pass show ynab | MONEYPENNY_VAULT_PASSPHRASE=... mp secrets set ynab`,
+	Args: cobra.ExactArgs(1),
+	RunE: run,
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+	name := args[0]
+
+	p := cliutil.Prompter()
+	vault, passphrase, err := cliutil.OpenVault(p)
+	if err != nil {
+		return err
+	}
+
+	secret, err := p.AskSecret(fmt.Sprintf("Secret for %s:", name))
+	if err != nil {
+		return err
+	}
+	if secret == "" {
+		return fmt.Errorf("secret must not be empty")
+	}
+
+	_, replaced := vault.Get(name)
+	vault.Set(name, secret)
+	if err := vault.Save(passphrase); err != nil {
+		return err
+	}
+
+	if replaced {
+		logger.Infof("Replaced %s in %s", name, vault.Path())
+	} else {
+		logger.Infof("Stored %s in %s", name, vault.Path())
+	}
+	logger.Infof("Reference it in the config as \"vault:%s\"", name)
+	return nil
+}
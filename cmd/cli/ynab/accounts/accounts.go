@@ -0,0 +1,28 @@
+// Package accounts provides commands for listing and creating YNAB accounts.
+package accounts
+
+import (
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/accounts/create"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/accounts/list"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/accounts/networth"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/accounts/show"
+	"github.com/spf13/cobra"
+)
+
+// Cmd is the parent command for account operations.
+var Cmd = &cobra.Command{
+	Use:   "accounts",
+	Short: "List, inspect and create YNAB accounts",
+	Long: `Commands for the accounts of the configured budget.
+
+Balances are shown in the budget's currency format in tables; json, ndjson
+and yaml output keep the YNAB milliunits.`,
+}
+
+func init() {
+	// Register subcommands
+	Cmd.AddCommand(list.Cmd)
+	Cmd.AddCommand(show.Cmd)
+	Cmd.AddCommand(create.Cmd)
+	Cmd.AddCommand(networth.Cmd)
+}
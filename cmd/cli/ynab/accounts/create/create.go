@@ -0,0 +1,79 @@
+// Package create provides the command for creating a YNAB account.
+package create
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/accounts/display"
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Flags for the create command - isolated to this package.
+var (
+	name        string
+	accountType string
+	balance     float64
+)
+
+// Cmd creates an account in the configured budget.
+var Cmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an account",
+	Long: `Create an account in the configured budget with a starting balance.
+Liabilities (credit cards, loans) take a negative balance.
+
+Account types: ` + strings.Join(ynab.AccountTypes, ", ") + `
+
+Example:
+  mp ynab accounts create -f config.json --name "Miles & More" --type creditCard --balance -120.50
+  mp ynab accounts create -f config.json --name Cash --type cash`,
+	RunE: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&name, "name", "", "account name")
+	Cmd.Flags().StringVar(&accountType, "type", "", "account type (e.g. checking, savings, creditCard)")
+	Cmd.Flags().Float64Var(&balance, "balance", 0, "starting balance")
+
+	_ = Cmd.MarkFlagRequired("name")
+	_ = Cmd.MarkFlagRequired("type")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("account name must not be empty")
+	}
+	if !ynab.IsValidAccountType(accountType) {
+		return fmt.Errorf("unknown account type %q (expected one of %s)", accountType, strings.Join(ynab.AccountTypes, ", "))
+	}
+
+	client, _, err := cliutil.NewYNABClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	format, err := ynab.BudgetCurrencyFormat(client)
+	if err != nil {
+		return err
+	}
+
+	acc, err := client.CreateAccount(ynab.SaveAccount{
+		Name:    name,
+		Type:    accountType,
+		Balance: ynab.FloatToMilliunits(balance),
+	})
+	if err != nil {
+		return fmt.Errorf("creating account: %w", err)
+	}
+
+	logger.Infof("Created account %q (%s)", acc.Name, acc.ID)
+
+	return output.Print([]ynab.Account{*acc}, display.Table(format))
+}
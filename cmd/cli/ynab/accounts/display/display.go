@@ -0,0 +1,55 @@
+// Package display provides the account table shared by the account commands.
+package display
+
+import (
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/output"
+)
+
+// Table shows accounts with their balances in the budget's currency format.
+func Table(format ynab.CurrencyFormat) output.Table[ynab.Account] {
+	return output.Table[ynab.Account]{
+		Headers: []string{"NAME", "TYPE", "BUDGET", "BALANCE", "CLEARED", "UNCLEARED", "DIRECT IMPORT", "STATUS", "ID"},
+		Row: func(acc ynab.Account) []string {
+			return []string{
+				output.Truncate(acc.Name, 30),
+				acc.Type,
+				Budget(acc),
+				format.Format(acc.Balance),
+				format.Format(acc.ClearedBalance),
+				format.Format(acc.UnclearedBalance),
+				DirectImport(acc),
+				Status(acc),
+				acc.ID,
+			}
+		},
+	}
+}
+
+// Budget returns "on" for budget accounts and "tracking" for off-budget accounts.
+func Budget(acc ynab.Account) string {
+	if acc.OnBudget {
+		return "on"
+	}
+	return "tracking"
+}
+
+// DirectImport describes the bank connection of an account.
+func DirectImport(acc ynab.Account) string {
+	switch {
+	case acc.DirectImportError:
+		return "error"
+	case acc.DirectImportLinked:
+		return "linked"
+	default:
+		return "-"
+	}
+}
+
+// Status returns "closed" or "open".
+func Status(acc ynab.Account) string {
+	if acc.Closed {
+		return "closed"
+	}
+	return "open"
+}
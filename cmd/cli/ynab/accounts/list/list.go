@@ -0,0 +1,77 @@
+// Package list provides the command for listing YNAB accounts.
+package list
+
+import (
+	"fmt"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/accounts/display"
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Flags for the list command - isolated to this package.
+var (
+	includeClosed bool
+)
+
+// Cmd lists the accounts of the configured budget.
+var Cmd = &cobra.Command{
+	Use:   "list",
+	Short: "List accounts with their balances",
+	Long: `List the accounts of the configured budget with balance, cleared and
+uncleared amounts, on-budget or tracking, type and direct import status.
+Closed accounts are hidden unless --all is set.
+
+Example:
+  mp ynab accounts list -f config.json
+  mp ynab accounts list -f config.json --all -o json`,
+	RunE: run,
+}
+
+func init() {
+	Cmd.Flags().BoolVar(&includeClosed, "all", false, "include closed accounts")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+
+	client, _, err := cliutil.NewYNABClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	accounts, err := client.GetAccounts()
+	if err != nil {
+		return fmt.Errorf("fetching accounts: %w", err)
+	}
+
+	format, err := ynab.BudgetCurrencyFormat(client)
+	if err != nil {
+		return err
+	}
+
+	var shown []ynab.Account
+	importErrors := 0
+	for _, acc := range accounts {
+		if acc.Deleted || (acc.Closed && !includeClosed) {
+			continue
+		}
+		if acc.DirectImportError {
+			importErrors++
+		}
+		shown = append(shown, acc)
+	}
+
+	if err := output.Print(shown, display.Table(format)); err != nil {
+		return err
+	}
+
+	logger.Infof("Total accounts: %d", len(shown))
+	if importErrors > 0 {
+		logger.Warnf("%d accounts have a direct import error, reconnect them in YNAB", importErrors)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Package networth provides the command for summarizing assets and liabilities.
+package networth
+
+import (
+	"fmt"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/pgbytes/moneypenny/internal/report"
+	"github.com/spf13/cobra"
+)
+
+// Cmd summarizes the net worth of the configured budget.
+var Cmd = &cobra.Command{
+	Use:   "networth",
+	Short: "Summarize assets, liabilities and net worth",
+	Long: `Sum the balances of all open accounts, tracking accounts included, grouped
+by account type into assets and liabilities. Table and csv output list the
+groups followed by the totals; json, ndjson and yaml output the summary with
+amounts in milliunits.
+
+Example:
+  mp ynab accounts networth -f config.json
+  mp ynab accounts networth -f config.json -o json | jq .net_worth`,
+	RunE: run,
+}
+
+// line is a row of the net worth table: an account type group or a total.
+type line struct {
+	Label    string
+	Kind     string
+	Accounts string
+	Balance  int64
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	client, _, err := cliutil.NewYNABClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	accounts, err := client.GetAccounts()
+	if err != nil {
+		return fmt.Errorf("fetching accounts: %w", err)
+	}
+
+	summary := report.NetWorth(accounts)
+
+	switch output.CurrentFormat() {
+	case output.FormatTable, output.FormatCSV:
+	default:
+		return output.Print([]report.NetWorthSummary{summary}, output.Table[report.NetWorthSummary]{})
+	}
+
+	format, err := ynab.BudgetCurrencyFormat(client)
+	if err != nil {
+		return err
+	}
+
+	lines := make([]line, 0, len(summary.Groups)+3)
+	for _, g := range summary.Groups {
+		kind := "asset"
+		if g.Liability {
+			kind = "liability"
+		}
+		lines = append(lines, line{Label: g.Type, Kind: kind, Accounts: fmt.Sprintf("%d", g.Accounts), Balance: g.Balance})
+	}
+	lines = append(lines,
+		line{Label: "Assets", Kind: "total", Balance: summary.Assets},
+		line{Label: "Liabilities", Kind: "total", Balance: summary.Liabilities},
+		line{Label: "Net worth", Kind: "total", Balance: summary.NetWorth},
+	)
+
+	return output.Print(lines, output.Table[line]{
+		Headers: []string{"TYPE", "KIND", "ACCOUNTS", "BALANCE"},
+		Row: func(l line) []string {
+			return []string{l.Label, l.Kind, l.Accounts, format.Format(l.Balance)}
+		},
+	})
+}
@@ -0,0 +1,79 @@
+// Package show provides the command for showing a single YNAB account.
+package show
+
+import (
+	"fmt"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/accounts/display"
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Cmd shows the details of one account.
+var Cmd = &cobra.Command{
+	Use:   "show <account-id-or-name>",
+	Short: "Show the details of an account",
+	Long: `Show the details of an account, given by ID or by its name
+(case-insensitive).
+
+Example:
+  mp ynab accounts show -f config.json "Miles & More"
+  mp ynab accounts show -f config.json acc-123 -o yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: run,
+}
+
+// field is one line of the account details table.
+type field struct {
+	Name  string
+	Value string
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	client, _, err := cliutil.NewYNABClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	accounts, err := client.GetAccounts()
+	if err != nil {
+		return fmt.Errorf("fetching accounts: %w", err)
+	}
+
+	acc, err := ynab.FindAccount(accounts, args[0])
+	if err != nil {
+		return err
+	}
+
+	format, err := ynab.BudgetCurrencyFormat(client)
+	if err != nil {
+		return err
+	}
+
+	if output.CurrentFormat() != output.FormatTable {
+		return output.Print([]ynab.Account{*acc}, display.Table(format))
+	}
+
+	fields := []field{
+		{"Name", acc.Name},
+		{"ID", acc.ID},
+		{"Type", acc.Type},
+		{"Budget", display.Budget(*acc)},
+		{"Status", display.Status(*acc)},
+		{"Balance", format.Format(acc.Balance)},
+		{"Cleared", format.Format(acc.ClearedBalance)},
+		{"Uncleared", format.Format(acc.UnclearedBalance)},
+		{"Direct import", display.DirectImport(*acc)},
+		{"Transfer payee", acc.TransferPayeeID},
+		{"Note", acc.Note},
+	}
+
+	return output.Print(fields, output.Table[field]{
+		Headers: []string{"FIELD", "VALUE"},
+		Row: func(f field) []string {
+			return []string{f.Name, f.Value}
+		},
+	})
+}
@@ -4,9 +4,10 @@ package fetch
 import (
 	"fmt"
 
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
 	"github.com/pgbytes/moneypenny/internal/client/ynab"
-	"github.com/pgbytes/moneypenny/internal/config"
 	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -20,12 +21,15 @@ var (
 var Cmd = &cobra.Command{
 	Use:   "fetch",
 	Short: "Fetch budgets from YNAB",
-	Long: `Fetch all budgets for the authenticated YNAB user.
+	Long: `Fetch all budgets for the authenticated YNAB user. In table and CSV output,
+--include-accounts lists one row per account; structured formats nest the
+accounts in their budget.
 
 Example:
   mp ynab budgets fetch -f config.json
   mp ynab budgets fetch -f config.json --include-accounts
-  mp ynab budgets fetch -f config.json --include-accounts --verbose`,
+  mp ynab budgets fetch -f config.json --include-accounts --verbose
+  mp ynab budgets fetch -f config.json --include-accounts -o json`,
 	RunE: run,
 }
 
@@ -38,30 +42,25 @@ func init() {
 func run(cmd *cobra.Command, args []string) error {
 	logger := log.GetLogger()
 
-	// Get config path from parent's persistent flag
-	configPath, err := cmd.Flags().GetString("config")
+	cfg, err := cliutil.LoadConfig(cmd)
 	if err != nil {
-		return fmt.Errorf("getting config flag: %w", err)
+		return err
 	}
 
-	// Load configuration
-	cfg, err := config.LoadFromFile(configPath)
-	if err != nil {
-		return fmt.Errorf("loading config: %w", err)
+	// Validate a token or OAuth app is configured
+	if cfg.YNAB.APIKey == "" && !cfg.YNAB.OAuth.Enabled() && !cliutil.Replaying(cmd) {
+		return fmt.Errorf("invalid config: api_key or oauth.client_id is required")
 	}
 
-	// Validate API key is present
-	if cfg.YNAB.APIKey == "" {
-		return fmt.Errorf("invalid config: api_key is required")
+	// Create YNAB client - budget_id not required for listing budgets
+	clientCfg, err := cliutil.ClientConfig(cmd, cfg)
+	if err != nil {
+		return err
 	}
 
-	// Create YNAB client - budget_id not required for listing budgets
-	client, err := ynab.NewClient(ynab.Config{
-		APIKey:   cfg.YNAB.APIKey,
-		BudgetID: cfg.YNAB.BudgetID,
-	}, logger)
+	client, err := cliutil.NewClient(cmd, clientCfg)
 	if err != nil {
-		return fmt.Errorf("creating YNAB client: %w", err)
+		return err
 	}
 
 	// Fetch budgets
@@ -70,67 +69,86 @@ func run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("fetching budgets: %w", err)
 	}
 
-	// Display budgets
 	logger.Infof("Fetched %d budgets", len(budgets))
 
-	for _, b := range budgets {
-		if verbose {
-			printBudgetVerbose(logger, b, includeAccounts)
-		} else {
-			printBudgetShort(logger, b, includeAccounts)
-		}
+	if includeAccounts && output.IsTable() {
+		return output.Print(budgetAccounts(budgets), accountTable())
 	}
-
-	return nil
+	return output.Print(budgets, budgetTable())
 }
 
-// printBudgetShort prints budget in short format (id, name, last_modified_on).
-func printBudgetShort(logger log.Logger, b ynab.BudgetSummary, showAccounts bool) {
-	logger.Infof("  Budget: %s | %s | Last Modified: %s", b.ID, b.Name, b.LastModifiedOn)
+// budgetAccount is an account together with the budget it belongs to.
+type budgetAccount struct {
+	Budget  ynab.BudgetSummary
+	Account ynab.Account
+}
 
-	if showAccounts && len(b.Accounts) > 0 {
+// budgetAccounts flattens the accounts of all budgets.
+func budgetAccounts(budgets []ynab.BudgetSummary) []budgetAccount {
+	var rows []budgetAccount
+	for _, b := range budgets {
 		for _, acc := range b.Accounts {
-			logger.Infof("    Account: %s | %s", acc.ID, acc.Name)
+			rows = append(rows, budgetAccount{Budget: b, Account: acc})
 		}
 	}
+	return rows
 }
 
-// printBudgetVerbose prints budget with all details.
-func printBudgetVerbose(logger log.Logger, b ynab.BudgetSummary, showAccounts bool) {
-	logger.Infof("  Budget:")
-	logger.Infof("    ID:            %s", b.ID)
-	logger.Infof("    Name:          %s", b.Name)
-	logger.Infof("    Last Modified: %s", b.LastModifiedOn)
-	logger.Infof("    First Month:   %s", b.FirstMonth)
-	logger.Infof("    Last Month:    %s", b.LastMonth)
-
-	if b.DateFormat != nil {
-		logger.Infof("    Date Format:   %s", b.DateFormat.Format)
+// budgetTable shows one row per budget (id, name, last_modified_on), or all details with --verbose.
+func budgetTable() output.Table[ynab.BudgetSummary] {
+	if !verbose {
+		return output.Table[ynab.BudgetSummary]{
+			Headers: []string{"ID", "NAME", "LAST MODIFIED"},
+			Row: func(b ynab.BudgetSummary) []string {
+				return []string{b.ID, b.Name, b.LastModifiedOn}
+			},
+		}
 	}
 
-	if b.CurrencyFormat != nil {
-		logger.Infof("    Currency:      %s (%s)", b.CurrencyFormat.CurrencySymbol, b.CurrencyFormat.ISOCode)
+	return output.Table[ynab.BudgetSummary]{
+		Headers: []string{"ID", "NAME", "LAST MODIFIED", "FIRST MONTH", "LAST MONTH", "DATE FORMAT", "CURRENCY", "ACCOUNTS"},
+		Row: func(b ynab.BudgetSummary) []string {
+			dateFormat, currency := "", ""
+			if b.DateFormat != nil {
+				dateFormat = b.DateFormat.Format
+			}
+			if b.CurrencyFormat != nil {
+				currency = fmt.Sprintf("%s (%s)", b.CurrencyFormat.CurrencySymbol, b.CurrencyFormat.ISOCode)
+			}
+			return []string{b.ID, b.Name, b.LastModifiedOn, b.FirstMonth, b.LastMonth, dateFormat, currency, fmt.Sprintf("%d", len(b.Accounts))}
+		},
 	}
+}
 
-	if showAccounts && len(b.Accounts) > 0 {
-		logger.Infof("    Accounts (%d):", len(b.Accounts))
-		for _, acc := range b.Accounts {
-			printAccountVerbose(logger, acc)
+// accountTable shows one row per account of every budget, with all details with --verbose.
+func accountTable() output.Table[budgetAccount] {
+	if !verbose {
+		return output.Table[budgetAccount]{
+			Headers: []string{"BUDGET", "ACCOUNT ID", "ACCOUNT"},
+			Row: func(r budgetAccount) []string {
+				return []string{r.Budget.Name, r.Account.ID, r.Account.Name}
+			},
 		}
 	}
-}
 
-// printAccountVerbose prints account with all details.
-func printAccountVerbose(logger log.Logger, acc ynab.Account) {
-	logger.Infof("      Account:")
-	logger.Infof("        ID:       %s", acc.ID)
-	logger.Infof("        Name:     %s", acc.Name)
-	logger.Infof("        Type:     %s", acc.Type)
-	logger.Infof("        On Budget: %t", acc.OnBudget)
-	logger.Infof("        Closed:   %t", acc.Closed)
-	logger.Infof("        Balance:  %.2f", ynab.MilliunitsToFloat(acc.Balance))
-
-	if acc.Note != "" {
-		logger.Infof("        Note:     %s", acc.Note)
+	return output.Table[budgetAccount]{
+		Headers: []string{"BUDGET", "ACCOUNT ID", "ACCOUNT", "TYPE", "ON BUDGET", "CLOSED", "BALANCE", "NOTE"},
+		Row: func(r budgetAccount) []string {
+			acc := r.Account
+			balance := fmt.Sprintf("%.2f", ynab.MilliunitsToFloat(acc.Balance))
+			if r.Budget.CurrencyFormat != nil {
+				balance = r.Budget.CurrencyFormat.Format(acc.Balance)
+			}
+			return []string{
+				r.Budget.Name,
+				acc.ID,
+				acc.Name,
+				acc.Type,
+				fmt.Sprintf("%t", acc.OnBudget),
+				fmt.Sprintf("%t", acc.Closed),
+				balance,
+				acc.Note,
+			}
+		},
 	}
 }
@@ -0,0 +1,27 @@
+// Package importer provides the parent command for importing statements into YNAB.
+package importer
+
+import (
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/importer/list"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/importer/milesmore"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/importer/undo"
+	"github.com/spf13/cobra"
+)
+
+// Cmd is the parent command for import operations.
+var Cmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import bank statements into YNAB",
+	Long: `Commands for importing bank statements directly into YNAB via the API.
+
+Every import run is recorded in a local journal (in $XDG_DATA_HOME/moneypenny)
+with the transactions it created, so a bad import can be reverted with
+"mp ynab import undo <run-id>".`,
+}
+
+func init() {
+	// Register subcommands
+	Cmd.AddCommand(milesmore.Cmd)
+	Cmd.AddCommand(list.Cmd)
+	Cmd.AddCommand(undo.Cmd)
+}
@@ -0,0 +1,56 @@
+// Package list provides the command for listing recorded import runs.
+package list
+
+import (
+	"fmt"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/journal"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Cmd lists the import runs recorded in the journal.
+var Cmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recorded import runs",
+	Long: `List all import runs recorded in the local import journal, most recent first.
+
+Example:
+  mp ynab import list -f config.json`,
+	RunE: run,
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	dir, err := cliutil.DataDir()
+	if err != nil {
+		return err
+	}
+
+	j, err := journal.OpenDir(dir)
+	if err != nil {
+		return fmt.Errorf("opening import journal: %w", err)
+	}
+
+	runs := j.List()
+	if len(runs) == 0 {
+		log.GetLogger().Info("No import runs recorded")
+		return nil
+	}
+
+	return output.Print(runs, output.Table[journal.Run]{
+		Headers: []string{"RUN ID", "CREATED", "SOURCE", "ACCOUNT", "TRANSACTIONS", "STATUS", "FILE"},
+		Row: func(r journal.Run) []string {
+			return []string{
+				r.ID,
+				r.CreatedAt.Local().Format("2006-01-02 15:04"),
+				r.Source,
+				r.AccountID,
+				fmt.Sprintf("%d", len(r.Transactions)),
+				string(r.Status),
+				r.SourceFile,
+			}
+		},
+	})
+}
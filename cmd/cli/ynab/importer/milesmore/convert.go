@@ -0,0 +1,84 @@
+package milesmore
+
+import (
+	"fmt"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/config"
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/pgbytes/moneypenny/internal/fx"
+	"github.com/pgbytes/moneypenny/internal/journal"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/output"
+)
+
+// convertToBudgetCurrency converts transactions that are not in the budget
+// currency using the configured rate source and lists the conversions. The
+// returned map holds the conversion metadata for the import journal by import
+// ID. dir is searched for the ECB rates file when none is configured.
+func convertToBudgetCurrency(client ynab.BudgetReader, cfg *config.Config, dir string, transactions []domain.Transaction) ([]domain.Transaction, map[string]journal.Conversion, error) {
+	settings, err := client.GetBudgetSettings()
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetching budget settings: %w", err)
+	}
+	if settings.CurrencyFormat == nil || settings.CurrencyFormat.ISOCode == "" {
+		return transactions, nil, nil
+	}
+	budgetCurrency := settings.CurrencyFormat.ISOCode
+
+	if !fx.NeedsConversion(transactions, budgetCurrency) {
+		return transactions, nil, nil
+	}
+
+	src, err := rateSource(cfg.Currency, budgetCurrency, dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	converted, exchanges, err := fx.ConvertAll(transactions, budgetCurrency, settings.CurrencyFormat.DecimalDigits, src)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conversions := make(map[string]journal.Conversion, len(exchanges))
+	for _, e := range exchanges {
+		conversions[e.ImportID] = journal.NewConversion(e)
+	}
+
+	if err := output.Preview(exchanges, exchangeTable()); err != nil {
+		return nil, nil, err
+	}
+	log.GetLogger().Infof("Converted %d of %d transactions into the budget currency", len(exchanges), len(transactions))
+
+	return converted, conversions, nil
+}
+
+// rateSource creates the rate source configured for converting statements
+// into the budget currency.
+func rateSource(cfg config.CurrencyConfig, budgetCurrency, dir string) (fx.Source, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid currency config: %w", err)
+	}
+
+	if cfg.RateSource == fx.SourceFixed {
+		return fx.FixedRates{BaseCurrency: budgetCurrency, Rates: cfg.FixedRates}, nil
+	}
+
+	return fx.LoadRates(cfg.RatesFile, dir)
+}
+
+// exchangeTable shows the converted amounts with their rates.
+func exchangeTable() output.Table[fx.Exchange] {
+	return output.Table[fx.Exchange]{
+		Headers: []string{"ORIGINAL", "RATE", "RATE DATE", "SOURCE", "CONVERTED"},
+		Row: func(e fx.Exchange) []string {
+			return []string{
+				fmt.Sprintf("%.2f %s", e.FromAmount, e.FromCurrency),
+				fmt.Sprintf("%.4f", e.Rate),
+				e.RateDate.Format("2006-01-02"),
+				e.Source,
+				fmt.Sprintf("%.2f %s", e.ToAmount, e.ToCurrency),
+			}
+		},
+	}
+}
@@ -0,0 +1,312 @@
+// Package milesmore provides the command for importing Miles & More statements into YNAB.
+package milesmore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/config"
+	"github.com/pgbytes/moneypenny/internal/importer"
+	"github.com/pgbytes/moneypenny/internal/journal"
+	"github.com/pgbytes/moneypenny/internal/ledger"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/matcher"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/pgbytes/moneypenny/internal/rules"
+	"github.com/pgbytes/moneypenny/internal/storage"
+	"github.com/pgbytes/moneypenny/internal/suggest"
+	"github.com/spf13/cobra"
+)
+
+// Flags for the milesmore command - isolated to this package.
+var (
+	inputPath        string
+	accountID        string
+	force            bool
+	dateWindow       int
+	includeAmbiguous bool
+	suggestThreshold float64
+	noSuggest        bool
+	noPayeeResolve   bool
+)
+
+// Cmd imports a Miles & More statement into a YNAB account.
+var Cmd = &cobra.Command{
+	Use:   "milesmore",
+	Short: "Import Miles & More statement into YNAB",
+	Long: `Import a Miles & More credit card CSV statement into a YNAB account.
+
+The statement is parsed in strict mode and compared with the transactions
+already in the account (e.g. from YNAB direct import). Rows are matched by
+import ID, or by identical amount within a date window and a similar payee.
+Only new rows are created, in a single request, and the run is recorded in
+the import journal. Matched and ambiguous rows are listed for review.
+
+Before creation, card payees are split into the merchant, used as payee, and
+the location, put in the memo (unless --raw-payee). The rules file (see
+--rules) then cleans up payees and assigns categories, flags and splits.
+Remaining uncategorized rows get a category suggested by the trained model
+(mp ynab suggest train); suggestions at or above --suggest-threshold are
+applied, the rest are left for review. Payee names are mapped to existing
+YNAB payees (mp ynab payees) so no near-duplicate payees are created.
+
+Statements in another currency than the budget (CurrencyFormat.ISOCode) are
+converted first, using the rate source from the "currency" config section
+(ECB history or a fixed table); the original amount and rate are added to
+the memo and recorded in the import journal.
+
+Without --account-id, the statement is imported into the account mapped to
+its card number in the "accounts" config section (see mp config init).
+
+Importing the same file into the same account again is refused and
+overlapping date ranges ask for confirmation, unless --force is given.
+
+Example:
+  mp ynab import milesmore -f config.json -a account-id -i /path/to/statement.csv
+  mp ynab import milesmore -f config.json -i /path/to/statement.csv`,
+	RunE: run,
+}
+
+func init() {
+	Cmd.Flags().StringVarP(&inputPath, "input", "i", "", "path to Miles & More CSV statement file")
+	Cmd.Flags().StringVarP(&accountID, "account-id", "a", "", "YNAB account ID to import into (default: account mapped to the card number in the config)")
+	Cmd.Flags().BoolVar(&force, "force", false, "import the statement even if it was processed before")
+	Cmd.Flags().IntVar(&dateWindow, "date-window", matcher.DefaultDateWindow, "days of date tolerance when matching existing transactions")
+	Cmd.Flags().BoolVar(&includeAmbiguous, "include-ambiguous", false, "also import rows with ambiguous matches")
+	Cmd.Flags().Float64Var(&suggestThreshold, "suggest-threshold", suggest.DefaultThreshold, "confidence at or above which suggested categories are applied")
+	Cmd.Flags().BoolVar(&noSuggest, "no-suggest", false, "do not suggest categories from the trained model")
+	cliutil.AddRawPayeeFlag(Cmd)
+	Cmd.Flags().BoolVar(&noPayeeResolve, "no-payee-resolve", false, "do not map payee names to existing YNAB payees")
+
+	_ = Cmd.MarkFlagRequired("input")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	parseResult, err := cliutil.ParseMilesMoreStrict(ctx, inputPath)
+	if err != nil {
+		return fmt.Errorf("%w, aborting import", err)
+	}
+
+	if len(parseResult.Transactions) == 0 {
+		return fmt.Errorf("no transactions to import")
+	}
+
+	// Credentials may need the vault passphrase, so they are resolved before
+	// any confirmation is asked for
+	client, cfg, err := cliutil.NewYNABClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	if accountID == "" {
+		m, err := cfg.StatementAccount(config.SourceMilesMore, parseResult.CardNumber)
+		if err != nil {
+			return err
+		}
+		logger.Infof("Using account %s mapped to %s", config.AccountLabel(m), parseResult.CardNumber)
+		accountID = m.AccountID
+	}
+
+	hash, err := storage.HashFile(inputPath)
+	if err != nil {
+		return err
+	}
+
+	dir, err := cliutil.DataDir()
+	if err != nil {
+		return err
+	}
+
+	statementLedger, err := ledger.OpenDir(dir)
+	if err != nil {
+		return fmt.Errorf("opening statement ledger: %w", err)
+	}
+
+	stmt := ledger.NewStatement("milesmore", inputPath, hash, parseResult.BillingDate, parseResult.Transactions)
+	stmt.Action = ledger.ActionImport
+	stmt.AccountID = accountID
+
+	proceed, err := cliutil.GuardStatement(cliutil.Prompter(), statementLedger, stmt, force)
+	if err != nil {
+		return err
+	}
+	if !proceed {
+		logger.Info("Import cancelled")
+		return nil
+	}
+
+	// Amounts are converted before matching, since YNAB holds them in the budget currency
+	statement, conversions, err := convertToBudgetCurrency(client, cfg, dir, parseResult.Transactions)
+	if err != nil {
+		return err
+	}
+
+	j, err := journal.OpenDir(dir)
+	if err != nil {
+		return fmt.Errorf("opening import journal: %w", err)
+	}
+
+	// Compare against transactions already in the account
+	sinceDate := windowStart(stmt.FromDate, dateWindow)
+	existing, err := client.GetTransactionsByAccount(accountID, ynab.TransactionOptions{SinceDate: sinceDate})
+	if err != nil {
+		return fmt.Errorf("fetching existing transactions: %w", err)
+	}
+
+	results := matcher.Match(statement, existing, matcher.Options{DateWindow: dateWindow})
+	summary := matcher.Summarize(results)
+	if err := printMatches(results); err != nil {
+		return err
+	}
+
+	logger.Infof("Matched %d, ambiguous %d, new %d", summary.Matched, summary.Ambiguous, summary.New)
+
+	statuses := []matcher.Status{matcher.StatusNew}
+	if includeAmbiguous {
+		statuses = append(statuses, matcher.StatusAmbiguous)
+	}
+	toImport := matcher.FilterByStatus(results, statuses...)
+
+	// Merchant payees and rules are applied after matching so existing
+	// transactions are compared with the original payees
+	toImport = cliutil.MerchantPayees(cmd, toImport)
+	toImport, err = cliutil.ApplyRules(cfg, "milesmore", toImport)
+	if err != nil {
+		return err
+	}
+
+	if len(toImport) == 0 {
+		logger.Info("No new transactions to import")
+		return nil
+	}
+
+	if rules.NeedsCategories(toImport) {
+		groups, err := client.GetCategories()
+		if err != nil {
+			return fmt.Errorf("fetching categories: %w", err)
+		}
+		if err := rules.ResolveCategories(toImport, ynab.ActiveCategories(groups)); err != nil {
+			return fmt.Errorf("resolving rule categories: %w", err)
+		}
+	}
+
+	if !noSuggest {
+		model, err := suggest.LoadModel(dir, client.BudgetID())
+		if err != nil {
+			return err
+		}
+		if model == nil {
+			logger.Debug("No category model for this budget, train one with: mp ynab suggest train")
+		} else if results := model.Apply(toImport, suggestThreshold); len(results) > 0 {
+			if err := output.Preview(results, cliutil.SuggestionTable()); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !noPayeeResolve {
+		if _, err := cliutil.ResolvePayees(cliutil.Prompter(), client, toImport, true); err != nil {
+			return err
+		}
+	}
+
+	importRun, err := importer.New(client, j, logger).Import(importer.Request{
+		Source:       "milesmore",
+		SourceFile:   inputPath,
+		SourceHash:   hash,
+		AccountID:    accountID,
+		Transactions: toImport,
+		Conversions:  conversions,
+	})
+	if err != nil {
+		return fmt.Errorf("importing transactions: %w", err)
+	}
+
+	stmt.RunID = importRun.ID
+	statementLedger.Record(stmt, importRun.CreatedAt)
+	if err := statementLedger.Save(); err != nil {
+		return err
+	}
+
+	if err := printEntries(importRun.Transactions); err != nil {
+		return err
+	}
+
+	logger.Infof("Import complete!")
+	logger.Infof("  Run ID:               %s", importRun.ID)
+	logger.Infof("  Transactions created: %d", len(importRun.Transactions))
+	logger.Infof("  Duplicates skipped:   %d", len(importRun.DuplicateImportIDs))
+	logger.Infof("  Undo with: mp ynab import undo %s", importRun.ID)
+
+	return nil
+}
+
+// windowStart returns the ISO date window days before the statement's first date.
+func windowStart(fromDate string, window int) string {
+	start, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return fromDate
+	}
+	return start.AddDate(0, 0, -window).Format("2006-01-02")
+}
+
+// printEntries lists the transactions created by the import run.
+func printEntries(entries []journal.Entry) error {
+	return output.Print(entries, output.Table[journal.Entry]{
+		Headers: []string{"DATE", "PAYEE", "AMOUNT", "MEMO", "TRANSACTION ID"},
+		Row: func(e journal.Entry) []string {
+			return []string{
+				e.Date,
+				output.Truncate(e.PayeeName, 35),
+				fmt.Sprintf("%.2f", ynab.MilliunitsToFloat(e.Amount)),
+				output.Truncate(e.Memo, 40),
+				e.TransactionID,
+			}
+		},
+	})
+}
+
+// printMatches previews matched and ambiguous statement rows for review.
+func printMatches(results []matcher.Result) error {
+	var review []matcher.Result
+	for _, r := range results {
+		if r.Status != matcher.StatusNew {
+			review = append(review, r)
+		}
+	}
+	if len(review) == 0 {
+		return nil
+	}
+
+	return output.Preview(review, output.Table[matcher.Result]{
+		Headers: []string{"STATUS", "DATE", "STATEMENT PAYEE", "AMOUNT", "YNAB DATE", "YNAB PAYEE", "CANDIDATES"},
+		Row: func(r matcher.Result) []string {
+			ynabDate, ynabPayee := "", ""
+			if r.Match != nil {
+				ynabDate = r.Match.Transaction.Date
+				ynabPayee = r.Match.Transaction.PayeeName
+			} else if len(r.Candidates) > 0 {
+				ynabDate = r.Candidates[0].Transaction.Date
+				ynabPayee = r.Candidates[0].Transaction.PayeeName
+			}
+			return []string{
+				string(r.Status),
+				r.Transaction.Date.Format("2006-01-02"),
+				output.Truncate(r.Transaction.Payee, 35),
+				fmt.Sprintf("%.2f", r.Transaction.Amount),
+				ynabDate,
+				output.Truncate(ynabPayee, 30),
+				fmt.Sprintf("%d", len(r.Candidates)),
+			}
+		},
+	})
+}
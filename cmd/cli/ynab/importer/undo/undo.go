@@ -0,0 +1,116 @@
+// Package undo provides the command for reverting a recorded import run.
+package undo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/importer"
+	"github.com/pgbytes/moneypenny/internal/journal"
+	"github.com/pgbytes/moneypenny/internal/ledger"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/spf13/cobra"
+)
+
+// Flags for the undo command - isolated to this package.
+var assumeYes bool
+
+// Cmd reverts an import run by deleting the transactions it created.
+var Cmd = &cobra.Command{
+	Use:   "undo <run-id>",
+	Short: "Revert a previous import run",
+	Long: `Delete exactly the transactions created by a previous import run and mark
+the run as reverted in the import journal.
+
+Transactions that were edited or reconciled since the import are listed
+before anything is deleted.
+
+Example:
+  mp ynab import undo -f config.json 20260203-141502-a1b2c3`,
+	Args: cobra.ExactArgs(1),
+	RunE: run,
+}
+
+func init() {
+	Cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "skip confirmation prompt")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+	runID := args[0]
+
+	client, _, err := cliutil.NewYNABClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	dir, err := cliutil.DataDir()
+	if err != nil {
+		return err
+	}
+
+	j, err := journal.OpenDir(dir)
+	if err != nil {
+		return fmt.Errorf("opening import journal: %w", err)
+	}
+
+	imp := importer.New(client, j, logger)
+
+	plan, err := imp.PlanUndo(runID)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Run %s imported %d transactions from %s", plan.Run.ID, len(plan.Run.Transactions), plan.Run.SourceFile)
+	if len(plan.Missing) > 0 {
+		logger.Warnf("%d transactions were already deleted in YNAB", len(plan.Missing))
+	}
+
+	for _, w := range plan.Warnings {
+		logger.Warnf("  %s | %s | %10.2f | %s",
+			w.Entry.Date, w.Entry.PayeeName, ynab.MilliunitsToFloat(w.Entry.Amount), strings.Join(w.Reasons, ", "))
+	}
+
+	if len(plan.ToDelete) == 0 {
+		logger.Info("Nothing left to delete")
+	}
+
+	if !assumeYes && len(plan.ToDelete) > 0 {
+		question := fmt.Sprintf("Delete %d transactions?", len(plan.ToDelete))
+		if len(plan.Warnings) > 0 {
+			question = fmt.Sprintf("Delete %d transactions, including %d edited or reconciled since the import?",
+				len(plan.ToDelete), len(plan.Warnings))
+		}
+
+		ok, err := cliutil.Prompter().Confirm(question)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			logger.Info("Undo cancelled")
+			return nil
+		}
+	}
+
+	deleted, err := imp.ExecuteUndo(plan)
+	if err != nil {
+		return err
+	}
+
+	// Allow the statement to be imported again
+	statementLedger, err := ledger.OpenDir(dir)
+	if err != nil {
+		return fmt.Errorf("opening statement ledger: %w", err)
+	}
+	if statementLedger.RemoveRun(plan.Run.ID) {
+		if err := statementLedger.Save(); err != nil {
+			return err
+		}
+	}
+
+	logger.Infof("Reverted run %s: deleted %d transactions", plan.Run.ID, deleted)
+
+	return nil
+}
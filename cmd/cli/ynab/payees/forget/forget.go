@@ -0,0 +1,54 @@
+// Package forget provides the command for removing a payee mapping.
+package forget
+
+import (
+	"fmt"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/payee"
+	"github.com/spf13/cobra"
+)
+
+// Cmd removes a confirmed payee mapping.
+var Cmd = &cobra.Command{
+	Use:   "forget <import-name>",
+	Short: "Remove a payee mapping",
+	Long: `Remove the confirmed mapping of an import payee name, so it is matched
+fuzzily again on the next import.
+
+Example:
+  mp ynab payees forget -f config.json "REWE MARKT 1234 BERLIN"`,
+	Args: cobra.ExactArgs(1),
+	RunE: run,
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+
+	cfg, err := cliutil.LoadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	dir, err := cliutil.DataDir()
+	if err != nil {
+		return err
+	}
+
+	mappings, err := payee.OpenMappingsDir(dir)
+	if err != nil {
+		return err
+	}
+
+	normalized := payee.Normalize(args[0])
+	if !mappings.Delete(cfg.YNAB.BudgetID, normalized) {
+		return fmt.Errorf("no mapping for %q", normalized)
+	}
+	if err := mappings.Save(); err != nil {
+		return err
+	}
+
+	logger.Infof("Removed mapping for %q", normalized)
+	return nil
+}
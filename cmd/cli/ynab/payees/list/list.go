@@ -0,0 +1,75 @@
+// Package list provides the command for listing confirmed payee mappings.
+package list
+
+import (
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/pgbytes/moneypenny/internal/payee"
+	"github.com/spf13/cobra"
+)
+
+// Cmd lists the confirmed payee mappings of the configured budget.
+var Cmd = &cobra.Command{
+	Use:   "list",
+	Short: "List confirmed payee mappings",
+	Long: `List the confirmed payee mappings of the configured budget.
+
+Example:
+  mp ynab payees list -f config.json`,
+	RunE: run,
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+
+	cfg, err := cliutil.LoadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	dir, err := cliutil.DataDir()
+	if err != nil {
+		return err
+	}
+
+	mappings, err := payee.OpenMappingsDir(dir)
+	if err != nil {
+		return err
+	}
+
+	names := mappings.Names(cfg.YNAB.BudgetID)
+	if len(names) == 0 {
+		logger.Info("No payee mappings for this budget")
+		return nil
+	}
+
+	records := make([]mappingRecord, 0, len(names))
+	for _, name := range names {
+		m, _ := mappings.Get(cfg.YNAB.BudgetID, name)
+		records = append(records, mappingRecord{ImportName: name, Mapping: m})
+	}
+
+	if err := output.Print(records, output.Table[mappingRecord]{
+		Headers: []string{"IMPORT NAME", "YNAB PAYEE", "PAYEE ID", "CONFIRMED"},
+		Row: func(r mappingRecord) []string {
+			return []string{
+				output.Truncate(r.ImportName, 35),
+				output.Truncate(r.PayeeName, 30),
+				r.PayeeID,
+				r.ConfirmedAt.Format("2006-01-02"),
+			}
+		},
+	}); err != nil {
+		return err
+	}
+
+	logger.Infof("Total mappings: %d", len(names))
+	return nil
+}
+
+// mappingRecord is a payee mapping together with the import name it applies to.
+type mappingRecord struct {
+	ImportName string `json:"import_name"`
+	payee.Mapping
+}
@@ -0,0 +1,101 @@
+// Package mapping provides the command for mapping an import payee name to a YNAB payee.
+package mapping
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/payee"
+	"github.com/spf13/cobra"
+)
+
+// Cmd stores a confirmed mapping from an import payee name to a YNAB payee.
+var Cmd = &cobra.Command{
+	Use:   "map <import-name> <payee-id-or-name>",
+	Short: "Map an import payee name to a YNAB payee",
+	Long: `Store a mapping from an import payee name to an existing YNAB payee.
+The YNAB payee is given by ID or by its exact name (case-insensitive). The
+import name is normalized, so the mapping also applies to variants with other
+store numbers or locations.
+
+Example:
+  mp ynab payees map -f config.json "REWE MARKT 1234 BERLIN" "REWE"`,
+	Args: cobra.ExactArgs(2),
+	RunE: run,
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+	importName, target := args[0], args[1]
+
+	normalized := payee.Normalize(importName)
+	if normalized == "" {
+		return fmt.Errorf("import payee name %q is empty after normalization", importName)
+	}
+
+	client, _, err := cliutil.NewYNABClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	payees, err := client.GetPayees()
+	if err != nil {
+		return fmt.Errorf("fetching payees: %w", err)
+	}
+
+	p, err := findPayee(payees, target)
+	if err != nil {
+		return err
+	}
+
+	dir, err := cliutil.DataDir()
+	if err != nil {
+		return err
+	}
+
+	mappings, err := payee.OpenMappingsDir(dir)
+	if err != nil {
+		return err
+	}
+
+	mappings.Set(client.BudgetID(), normalized, payee.Mapping{
+		PayeeID:     p.ID,
+		PayeeName:   p.Name,
+		ConfirmedAt: time.Now().UTC(),
+	})
+	if err := mappings.Save(); err != nil {
+		return err
+	}
+
+	logger.Infof("Mapped %q to payee %q (%s)", normalized, p.Name, p.ID)
+	return nil
+}
+
+// findPayee returns the active payee with the given ID or exact name.
+func findPayee(payees []ynab.Payee, target string) (ynab.Payee, error) {
+	var byName []ynab.Payee
+	for _, p := range payees {
+		if p.Deleted || p.TransferAccountID != "" {
+			continue
+		}
+		if p.ID == target {
+			return p, nil
+		}
+		if strings.EqualFold(p.Name, target) {
+			byName = append(byName, p)
+		}
+	}
+
+	switch len(byName) {
+	case 0:
+		return ynab.Payee{}, fmt.Errorf("no payee with ID or name %q", target)
+	case 1:
+		return byName[0], nil
+	default:
+		return ynab.Payee{}, fmt.Errorf("%d payees are named %q, use the payee ID", len(byName), target)
+	}
+}
@@ -0,0 +1,30 @@
+// Package payees provides commands for mapping import payees to YNAB payees.
+package payees
+
+import (
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/payees/forget"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/payees/list"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/payees/mapping"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/payees/resolve"
+	"github.com/spf13/cobra"
+)
+
+// Cmd is the parent command for payee operations.
+var Cmd = &cobra.Command{
+	Use:   "payees",
+	Short: "Map import payee names to existing YNAB payees",
+	Long: `Commands for resolving statement payee names to existing YNAB payees.
+
+Names are normalized (case, accents, store numbers, trailing city and country)
+and matched against the budget's payees, so imports reuse "REWE" instead of
+creating "REWE MARKT 1234 BERLIN DE". Confirmed mappings are stored per budget
+in the local data directory and take precedence over fuzzy matches.`,
+}
+
+func init() {
+	// Register subcommands
+	Cmd.AddCommand(resolve.Cmd)
+	Cmd.AddCommand(mapping.Cmd)
+	Cmd.AddCommand(list.Cmd)
+	Cmd.AddCommand(forget.Cmd)
+}
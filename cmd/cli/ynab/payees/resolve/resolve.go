@@ -0,0 +1,84 @@
+// Package resolve provides the command for previewing payee resolution for a statement.
+package resolve
+
+import (
+	"context"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Flags for the resolve command - isolated to this package.
+var (
+	inputPath string
+	remember  bool
+)
+
+// Cmd shows the YNAB payee every statement payee resolves to.
+var Cmd = &cobra.Command{
+	Use:   "resolve",
+	Short: "Preview payee resolution for a statement",
+	Long: `Parse a Miles & More statement, run the rules and show the YNAB payee
+every payee name resolves to. Nothing is sent to YNAB. With --remember, fuzzy
+matches can be confirmed and stored as mappings for future imports. As on
+import, card payees are split into merchant and location before the rules
+run, unless --raw-payee is given.
+
+Example:
+  mp ynab payees resolve -f config.json -i statement.csv --remember`,
+	RunE: run,
+}
+
+func init() {
+	Cmd.Flags().StringVarP(&inputPath, "input", "i", "", "path to Miles & More CSV statement file")
+	Cmd.Flags().BoolVar(&remember, "remember", false, "ask to store fuzzy matches as mappings")
+	cliutil.AddRawPayeeFlag(Cmd)
+
+	_ = Cmd.MarkFlagRequired("input")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	parseResult, err := cliutil.ParseMilesMoreStrict(ctx, inputPath)
+	if err != nil {
+		return err
+	}
+
+	client, cfg, err := cliutil.NewYNABClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	transactions, err := cliutil.ApplyRules(cfg, "milesmore", cliutil.MerchantPayees(cmd, parseResult.Transactions))
+	if err != nil {
+		return err
+	}
+
+	resolutions, err := cliutil.ResolvePayees(cliutil.Prompter(), client, transactions, remember)
+	if err != nil {
+		return err
+	}
+	// The table preview already shows the resolutions
+	if !output.IsTable() {
+		if err := output.Print(resolutions, cliutil.PayeeResolutionTable()); err != nil {
+			return err
+		}
+	}
+
+	resolved := 0
+	for _, tx := range transactions {
+		if tx.PayeeID != "" {
+			resolved++
+		}
+	}
+	logger.Infof("%d of %d transactions resolve to an existing payee", resolved, len(transactions))
+
+	return nil
+}
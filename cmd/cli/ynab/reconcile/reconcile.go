@@ -0,0 +1,246 @@
+// Package reconcile provides the command for reconciling a YNAB account against a statement.
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/matcher"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/pgbytes/moneypenny/internal/reconcile"
+	"github.com/spf13/cobra"
+)
+
+// Flags for the reconcile command - isolated to this package.
+var (
+	account       string
+	statementPath string
+	adjust        bool
+	assumeYes     bool
+	dateWindow    int
+)
+
+// Cmd reconciles a YNAB account against a Miles & More statement.
+var Cmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Reconcile an account against a statement balance",
+	Long: `Compare a Miles & More statement's closing balance ("Balance:" line) with
+the account's cleared balance in YNAB as of the billing date.
+
+Any difference is explained by listing statement rows missing in YNAB,
+matched transactions that are not yet cleared, and cleared transactions that
+do not appear on the statement. When nothing is missing, all matched
+transactions are marked as reconciled. With --adjust, a remaining unexplained
+difference is closed with a "Reconciliation Balance Adjustment" transaction.
+
+Example:
+  mp ynab reconcile -f config.json -a "Miles & More" --statement statement.csv
+  mp ynab reconcile -f config.json -a account-id --statement statement.csv --adjust`,
+	RunE: run,
+}
+
+func init() {
+	Cmd.Flags().StringVarP(&account, "account", "a", "", "YNAB account ID or name")
+	Cmd.Flags().StringVarP(&statementPath, "statement", "s", "", "path to Miles & More CSV statement file")
+	Cmd.Flags().BoolVar(&adjust, "adjust", false, "create an adjustment transaction for any unexplained difference")
+	Cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "skip confirmation prompt")
+	Cmd.Flags().IntVar(&dateWindow, "date-window", matcher.DefaultDateWindow, "days of date tolerance when matching transactions")
+
+	_ = Cmd.MarkFlagRequired("account")
+	_ = Cmd.MarkFlagRequired("statement")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	parseResult, err := cliutil.ParseMilesMoreStrict(ctx, statementPath)
+	if err != nil {
+		return fmt.Errorf("%w, aborting reconciliation", err)
+	}
+	if !parseResult.HasClosingBalance {
+		return fmt.Errorf("statement has no \"Balance:\" line")
+	}
+	if len(parseResult.Transactions) == 0 {
+		return fmt.Errorf("statement contains no transactions")
+	}
+
+	stmt := reconcile.Statement{
+		Transactions:   parseResult.Transactions,
+		ClosingBalance: parseResult.ClosingBalance,
+		Date:           parseResult.BillingDate,
+	}
+	if stmt.Date.IsZero() {
+		stmt.Date = latestDate(parseResult.Transactions)
+	}
+
+	client, _, err := cliutil.NewYNABClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	accounts, err := client.GetAccounts()
+	if err != nil {
+		return fmt.Errorf("fetching accounts: %w", err)
+	}
+	acc, err := ynab.FindAccount(accounts, account)
+	if err != nil {
+		return err
+	}
+
+	since := earliestDate(parseResult.Transactions).AddDate(0, 0, -dateWindow).Format("2006-01-02")
+	existing, err := client.GetTransactionsByAccount(acc.ID, ynab.TransactionOptions{SinceDate: since})
+	if err != nil {
+		return fmt.Errorf("fetching transactions: %w", err)
+	}
+
+	report := reconcile.Build(stmt, *acc, existing, matcher.Options{DateWindow: dateWindow})
+	if err := printReport(acc.Name, report); err != nil {
+		return err
+	}
+
+	if len(report.MissingInYNAB) > 0 || len(report.NotOnStatement) > 0 {
+		logger.Warnf("Resolve the %d missing and %d extra transactions first (e.g. mp ynab import milesmore), then reconcile again",
+			len(report.MissingInYNAB), len(report.NotOnStatement))
+		return nil
+	}
+
+	adjustment, needsAdjustment := report.Adjustment()
+	if needsAdjustment && !adjust {
+		logger.Warnf("Unexplained difference of %.2f remains, rerun with --adjust to create an adjustment transaction",
+			ynab.MilliunitsToFloat(report.Unexplained))
+		return nil
+	}
+
+	updates := report.ReconcileUpdates()
+	if len(updates) == 0 && !needsAdjustment {
+		logger.Info("Account is already reconciled")
+		return nil
+	}
+
+	if !assumeYes {
+		question := fmt.Sprintf("Mark %d transactions as reconciled?", len(updates))
+		if needsAdjustment {
+			question = fmt.Sprintf("Create an adjustment of %.2f and mark %d transactions as reconciled?",
+				ynab.MilliunitsToFloat(adjustment.Amount), len(updates))
+		}
+		ok, err := cliutil.Prompter().Confirm(question)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			logger.Info("Reconciliation cancelled")
+			return nil
+		}
+	}
+
+	if needsAdjustment {
+		if _, err := client.CreateTransaction(adjustment); err != nil {
+			return fmt.Errorf("creating adjustment transaction: %w", err)
+		}
+		logger.Infof("Created adjustment transaction of %.2f", ynab.MilliunitsToFloat(adjustment.Amount))
+	}
+
+	if len(updates) > 0 {
+		if _, err := client.UpdateTransactions(updates); err != nil {
+			return fmt.Errorf("marking transactions as reconciled: %w", err)
+		}
+	}
+
+	logger.Infof("Reconciled %d transactions in %s as of %s", len(updates), acc.Name, report.StatementDate)
+
+	return nil
+}
+
+// printReport writes the balance comparison and the transactions explaining
+// any difference. Structured formats get the report as a single record, with
+// amounts in milliunits; csv has one row with the balances.
+func printReport(accountName string, r *reconcile.Report) error {
+	if !output.IsTable() {
+		return output.Print([]reconcile.Report{*r}, output.Table[reconcile.Report]{
+			Headers: []string{"ACCOUNT", "DATE", "STATEMENT", "CLEARED", "DIFFERENCE", "EXPLAINED", "UNEXPLAINED", "MATCHED", "MISSING", "NOT ON STATEMENT"},
+			Row: func(r reconcile.Report) []string {
+				return []string{
+					accountName,
+					r.StatementDate,
+					fmt.Sprintf("%.2f", ynab.MilliunitsToFloat(r.StatementBalance)),
+					fmt.Sprintf("%.2f", ynab.MilliunitsToFloat(r.ClearedBalance)),
+					fmt.Sprintf("%.2f", ynab.MilliunitsToFloat(r.Difference)),
+					fmt.Sprintf("%.2f", ynab.MilliunitsToFloat(r.Explained)),
+					fmt.Sprintf("%.2f", ynab.MilliunitsToFloat(r.Unexplained)),
+					fmt.Sprintf("%d", len(r.Matched)),
+					fmt.Sprintf("%d", len(r.MissingInYNAB)),
+					fmt.Sprintf("%d", len(r.NotOnStatement)),
+				}
+			},
+		})
+	}
+
+	w := tabwriter.NewWriter(output.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "\nReconciliation of %s as of %s:\n", accountName, r.StatementDate)
+	fmt.Fprintf(w, "  Statement Balance:\t%10.2f\n", ynab.MilliunitsToFloat(r.StatementBalance))
+	fmt.Fprintf(w, "  YNAB Cleared Balance:\t%10.2f\n", ynab.MilliunitsToFloat(r.ClearedBalance))
+	fmt.Fprintf(w, "  Difference:\t%10.2f\n", ynab.MilliunitsToFloat(r.Difference))
+	fmt.Fprintf(w, "  Explained:\t%10.2f\n", ynab.MilliunitsToFloat(r.Explained))
+	fmt.Fprintf(w, "  Unexplained:\t%10.2f\n", ynab.MilliunitsToFloat(r.Unexplained))
+	fmt.Fprintf(w, "  Matched Transactions:\t%d\n", len(r.Matched))
+
+	if len(r.MissingInYNAB) > 0 {
+		fmt.Fprintf(w, "\nOn statement, missing in YNAB (%d):\n", len(r.MissingInYNAB))
+		for _, m := range r.MissingInYNAB {
+			fmt.Fprintf(w, "  %s\t%s\t%10.2f\t%s\n",
+				m.Transaction.Date.Format("2006-01-02"), output.Truncate(m.Transaction.Payee, 40), m.Transaction.Amount, m.Status)
+		}
+	}
+
+	if len(r.UnclearedMatches) > 0 {
+		fmt.Fprintf(w, "\nIn YNAB but not cleared (%d, will be reconciled):\n", len(r.UnclearedMatches))
+		for _, t := range r.UnclearedMatches {
+			fmt.Fprintf(w, "  %s\t%s\t%10.2f\n", t.Date, output.Truncate(t.PayeeName, 40), ynab.MilliunitsToFloat(t.Amount))
+		}
+	}
+
+	if len(r.NotOnStatement) > 0 {
+		fmt.Fprintf(w, "\nCleared in YNAB, not on statement (%d):\n", len(r.NotOnStatement))
+		for _, t := range r.NotOnStatement {
+			fmt.Fprintf(w, "  %s\t%s\t%10.2f\n", t.Date, output.Truncate(t.PayeeName, 40), ynab.MilliunitsToFloat(t.Amount))
+		}
+	}
+
+	fmt.Fprintln(w, strings.Repeat("─", 60))
+	return w.Flush()
+}
+
+// earliestDate returns the earliest transaction date, or the zero time
+// without transactions.
+func earliestDate(transactions []domain.Transaction) time.Time {
+	var earliest time.Time
+	for i, tx := range transactions {
+		if i == 0 || tx.Date.Before(earliest) {
+			earliest = tx.Date
+		}
+	}
+	return earliest
+}
+
+// latestDate returns the latest transaction date.
+func latestDate(transactions []domain.Transaction) time.Time {
+	var latest time.Time
+	for _, tx := range transactions {
+		if tx.Date.After(latest) {
+			latest = tx.Date
+		}
+	}
+	return latest
+}
@@ -0,0 +1,304 @@
+// Package review provides the interactive command for reviewing unapproved
+// or uncategorized YNAB transactions.
+package review
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/pgbytes/moneypenny/internal/prompt"
+	"github.com/pgbytes/moneypenny/internal/review"
+	"github.com/spf13/cobra"
+)
+
+// maxCategoryChoices limits how many categories are listed for one search.
+const maxCategoryChoices = 20
+
+// Flags for the review command - isolated to this package.
+var (
+	txType    string
+	account   string
+	sinceDate string
+	assumeYes bool
+)
+
+// Cmd walks through unapproved or uncategorized transactions one at a time.
+var Cmd = &cobra.Command{
+	Use:   "review",
+	Short: "Review unapproved or uncategorized transactions interactively",
+	Long: `Walk through unapproved or uncategorized transactions one at a time.
+
+For each transaction enter one of:
+  a  approve and continue
+  c  set a category (search the categories list)
+  p  rename the payee
+  f  set a flag color
+  s  skip (or just press Enter)
+  q  stop reviewing
+
+Category, payee and flag changes stay on the current transaction so they can
+be combined. All changes are sent in batched bulk updates at the end.
+
+Example:
+  mp ynab review -f config.json
+  mp ynab review -f config.json --type uncategorized -a "Miles & More"`,
+	RunE: run,
+}
+
+func init() {
+	Cmd.Flags().StringVarP(&txType, "type", "t", ynab.TransactionTypeUnapproved, "transactions to review: unapproved or uncategorized")
+	Cmd.Flags().StringVarP(&account, "account", "a", "", "only review transactions of this account (ID or name)")
+	Cmd.Flags().StringVar(&sinceDate, "since", "", "only review transactions on or after this date (YYYY-MM-DD)")
+	Cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "save changes without a final confirmation")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+
+	if txType != ynab.TransactionTypeUnapproved && txType != ynab.TransactionTypeUncategorized {
+		return fmt.Errorf("invalid type %q, use %s or %s", txType, ynab.TransactionTypeUnapproved, ynab.TransactionTypeUncategorized)
+	}
+
+	client, _, err := cliutil.NewYNABClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	transactions, err := fetchTransactions(client)
+	if err != nil {
+		return err
+	}
+	if len(transactions) == 0 {
+		logger.Infof("No %s transactions to review", txType)
+		return nil
+	}
+
+	groups, err := client.GetCategories()
+	if err != nil {
+		return fmt.Errorf("fetching categories: %w", err)
+	}
+	categories := ynab.ActiveCategories(groups)
+
+	p := cliutil.Prompter()
+	session := review.NewSession()
+
+	if err := walk(p, os.Stderr, session, transactions, categories); err != nil {
+		return err
+	}
+
+	summary := session.Summary()
+	printSummary(os.Stderr, summary)
+
+	updates := session.Updates()
+	if len(updates) == 0 {
+		logger.Info("No changes to save")
+		return nil
+	}
+
+	if !assumeYes {
+		ok, err := p.Confirm(fmt.Sprintf("Save changes to %d transactions?", len(updates)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			logger.Info("Changes discarded")
+			return nil
+		}
+	}
+
+	saved := 0
+	for _, batch := range review.Batches(updates, review.DefaultBatchSize) {
+		if _, err := client.UpdateTransactions(batch); err != nil {
+			return fmt.Errorf("saving changes after %d of %d transactions: %w", saved, len(updates), err)
+		}
+		saved += len(batch)
+	}
+
+	logger.Infof("Saved changes to %d transactions", saved)
+
+	return nil
+}
+
+// fetchTransactions loads the transactions to review, optionally limited to one account.
+func fetchTransactions(client ynab.Reader) ([]ynab.Transaction, error) {
+	opts := ynab.TransactionOptions{SinceDate: sinceDate, Type: txType}
+
+	if account == "" {
+		transactions, err := client.GetTransactions(opts)
+		if err != nil {
+			return nil, fmt.Errorf("fetching transactions: %w", err)
+		}
+		return transactions, nil
+	}
+
+	accounts, err := client.GetAccounts()
+	if err != nil {
+		return nil, fmt.Errorf("fetching accounts: %w", err)
+	}
+	acc, err := ynab.FindAccount(accounts, account)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := client.GetTransactionsByAccount(acc.ID, opts)
+	if err != nil {
+		return nil, fmt.Errorf("fetching transactions: %w", err)
+	}
+	return transactions, nil
+}
+
+// walk asks for a decision on every transaction until all are reviewed or the user quits.
+func walk(p *prompt.Prompter, out io.Writer, session *review.Session, transactions []ynab.Transaction, categories []ynab.Category) error {
+	for i, t := range transactions {
+		if t.Deleted {
+			continue
+		}
+
+	ask:
+		for {
+			printTransaction(out, i+1, len(transactions), t, session)
+
+			key, err := p.Ask("[a]pprove [c]ategory [p]ayee [f]lag [s]kip [q]uit >")
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("reading answer: %w", err)
+			}
+
+			switch strings.ToLower(key) {
+			case "a":
+				session.Approve(t)
+				break ask
+			case "c":
+				category, err := chooseCategory(p, out, categories)
+				if err != nil {
+					return err
+				}
+				if category != nil {
+					session.SetCategory(t, *category)
+				}
+			case "p":
+				name, err := p.Ask("New payee name:")
+				if err != nil && err != io.EOF {
+					return fmt.Errorf("reading answer: %w", err)
+				}
+				if name != "" {
+					if err := session.RenamePayee(t, name); err != nil {
+						fmt.Fprintln(out, err)
+					}
+				}
+			case "f":
+				color, err := p.Ask(fmt.Sprintf("Flag color (%s):", strings.Join(ynab.FlagColors, ", ")))
+				if err != nil && err != io.EOF {
+					return fmt.Errorf("reading answer: %w", err)
+				}
+				if color != "" {
+					if err := session.Flag(t, color); err != nil {
+						fmt.Fprintln(out, err)
+					}
+				}
+			case "s", "":
+				session.Skip(t)
+				break ask
+			case "q":
+				return nil
+			default:
+				fmt.Fprintf(out, "Unknown choice %q\n", key)
+			}
+		}
+	}
+
+	return nil
+}
+
+// chooseCategory searches the categories and lets the user pick one by number.
+// It returns nil when the choice is cancelled.
+func chooseCategory(p *prompt.Prompter, out io.Writer, categories []ynab.Category) (*ynab.Category, error) {
+	for {
+		query, err := p.Ask("Search categories (empty to cancel):")
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("reading answer: %w", err)
+		}
+		if query == "" {
+			return nil, nil
+		}
+
+		found := ynab.SearchCategories(categories, query)
+		if len(found) == 0 {
+			fmt.Fprintf(out, "No category matches %q\n", query)
+			continue
+		}
+		if len(found) > maxCategoryChoices {
+			fmt.Fprintf(out, "%d categories match, showing the first %d\n", len(found), maxCategoryChoices)
+			found = found[:maxCategoryChoices]
+		}
+
+		for i, c := range found {
+			fmt.Fprintf(out, "  %2d) %s / %s\n", i+1, c.CategoryGroupName, c.Name)
+		}
+
+		answer, err := p.Ask("Number (empty to search again):")
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("reading answer: %w", err)
+		}
+		if answer == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(answer)
+		if err != nil || n < 1 || n > len(found) {
+			fmt.Fprintf(out, "Invalid choice %q\n", answer)
+			continue
+		}
+		return &found[n-1], nil
+	}
+}
+
+// printTransaction shows a transaction together with its pending changes.
+func printTransaction(out io.Writer, n, total int, t ynab.Transaction, session *review.Session) {
+	payee := t.PayeeName
+	category := t.CategoryName
+	flag := t.FlagColor
+	approved := t.Approved
+
+	if e, ok := session.Pending(t.ID); ok {
+		if e.PayeeName != "" {
+			payee = e.PayeeName + " *"
+		}
+		if e.Category != nil {
+			category = e.Category.Name + " *"
+		}
+		if e.FlagColor != "" {
+			flag = e.FlagColor + " *"
+		}
+		approved = approved || e.Approve
+	}
+
+	if category == "" {
+		category = "-"
+	}
+
+	fmt.Fprintf(out, "\n[%d/%d] %s  %s  %.2f\n", n, total, t.Date, output.Truncate(payee, 40), ynab.MilliunitsToFloat(t.Amount))
+	fmt.Fprintf(out, "  Account: %s  Category: %s  Approved: %t", t.AccountName, category, approved)
+	if flag != "" {
+		fmt.Fprintf(out, "  Flag: %s", flag)
+	}
+	fmt.Fprintln(out)
+	if t.Memo != "" {
+		fmt.Fprintf(out, "  Memo: %s\n", t.Memo)
+	}
+}
+
+// printSummary writes the counts of the review decisions.
+func printSummary(out io.Writer, s review.Summary) {
+	fmt.Fprintf(out, "\nReviewed %d transactions: %d approved, %d categorized, %d renamed, %d flagged, %d skipped\n",
+		s.Reviewed, s.Approved, s.Categorized, s.Renamed, s.Flagged, s.Skipped)
+}
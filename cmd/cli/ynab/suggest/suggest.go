@@ -0,0 +1,26 @@
+// Package suggest provides commands for learned category suggestions.
+package suggest
+
+import (
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/suggest/test"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/suggest/train"
+	"github.com/spf13/cobra"
+)
+
+// Cmd is the parent command for category suggestion operations.
+var Cmd = &cobra.Command{
+	Use:   "suggest",
+	Short: "Category suggestions learned from YNAB history",
+	Long: `Commands for the category suggestion model.
+
+The model is trained offline on the budget's categorized transactions (payee
+and memo words and an amount bucket) and stored in the local data directory.
+Imports use it to suggest categories for uncategorized rows; suggestions at or
+above the confidence threshold are applied, the rest are left for review.`,
+}
+
+func init() {
+	// Register subcommands
+	Cmd.AddCommand(train.Cmd)
+	Cmd.AddCommand(test.Cmd)
+}
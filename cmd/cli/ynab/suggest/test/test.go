@@ -0,0 +1,92 @@
+// Package test provides the command for previewing category suggestions for a statement.
+package test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/pgbytes/moneypenny/internal/suggest"
+	"github.com/spf13/cobra"
+)
+
+// Flags for the test command - isolated to this package.
+var (
+	inputPath string
+	threshold float64
+)
+
+// Cmd shows the suggested category for every row of a statement.
+var Cmd = &cobra.Command{
+	Use:   "test",
+	Short: "Preview category suggestions for a statement",
+	Long: `Parse a Miles & More statement, run the rules and show the suggested
+category and confidence for every uncategorized row. Nothing is sent to YNAB.
+As on import, card payees are split into merchant and location before the
+rules run, unless --raw-payee is given.
+
+Example:
+  mp ynab suggest test -f config.json -i statement.csv --threshold 0.8`,
+	RunE: run,
+}
+
+func init() {
+	Cmd.Flags().StringVarP(&inputPath, "input", "i", "", "path to Miles & More CSV statement file")
+	Cmd.Flags().Float64Var(&threshold, "threshold", suggest.DefaultThreshold, "confidence at or above which a suggestion would be applied")
+	cliutil.AddRawPayeeFlag(Cmd)
+
+	_ = Cmd.MarkFlagRequired("input")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	cfg, err := cliutil.LoadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	dir, err := cliutil.DataDir()
+	if err != nil {
+		return err
+	}
+
+	model, err := suggest.LoadModel(dir, cfg.YNAB.BudgetID)
+	if err != nil {
+		return err
+	}
+	if model == nil {
+		return fmt.Errorf("no category model for this budget, train one with: mp ynab suggest train")
+	}
+
+	parseResult, err := cliutil.ParseMilesMoreStrict(ctx, inputPath)
+	if err != nil {
+		return err
+	}
+
+	transactions, err := cliutil.ApplyRules(cfg, "milesmore", cliutil.MerchantPayees(cmd, parseResult.Transactions))
+	if err != nil {
+		return err
+	}
+
+	results := model.Apply(transactions, threshold)
+	if err := output.Print(results, cliutil.SuggestionTable()); err != nil {
+		return err
+	}
+
+	applied := 0
+	for _, r := range results {
+		if r.Applied {
+			applied++
+		}
+	}
+	logger.Infof("%d of %d suggestions reach the threshold of %.2f", applied, len(results), threshold)
+
+	return nil
+}
@@ -0,0 +1,67 @@
+// Package train provides the command for training the category suggestion model.
+package train
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/suggest"
+	"github.com/spf13/cobra"
+)
+
+// Flags for the train command - isolated to this package.
+var sinceDate string
+
+// Cmd trains the category model from the budget's transaction history.
+var Cmd = &cobra.Command{
+	Use:   "train",
+	Short: "Train the category model from YNAB history",
+	Long: `Fetch the budget's transactions and train the category suggestion model on
+every categorized transaction. Transfers and split transactions are ignored.
+
+Example:
+  mp ynab suggest train -f config.json
+  mp ynab suggest train -f config.json --since 2024-01-01`,
+	RunE: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&sinceDate, "since", "", "only train on transactions on or after this date (YYYY-MM-DD)")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+
+	client, _, err := cliutil.NewYNABClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	transactions, err := client.GetTransactions(ynab.TransactionOptions{SinceDate: sinceDate})
+	if err != nil {
+		return fmt.Errorf("fetching transactions: %w", err)
+	}
+
+	model, err := suggest.Train(client.BudgetID(), transactions, time.Now())
+	if err != nil {
+		return err
+	}
+
+	dir, err := cliutil.DataDir()
+	if err != nil {
+		return err
+	}
+
+	path := suggest.ModelPath(dir, client.BudgetID())
+	if err := model.Save(path); err != nil {
+		return err
+	}
+
+	logger.Infof("Trained on %d transactions in %d categories (%d features)", model.Documents, len(model.Categories), model.Vocabulary)
+	logger.Infof("Model saved to %s", path)
+
+	return nil
+}
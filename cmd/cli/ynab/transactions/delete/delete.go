@@ -0,0 +1,96 @@
+// Package delete provides the command for deleting YNAB transactions.
+package delete
+
+import (
+	"fmt"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/transactions/selection"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/spf13/cobra"
+)
+
+// Flags for the delete command - isolated to this package.
+var (
+	selectFlags selection.Flags
+	assumeYes   bool
+)
+
+// Cmd deletes a selection of YNAB transactions.
+var Cmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete transactions from YNAB",
+	Long: `Delete all transactions matching a selection.
+
+Transactions are selected by account, date range, import_id prefix and payee
+regular expression. A preview of the selection is shown and transactions are
+only deleted after confirmation.
+
+Example:
+  mp ynab transactions delete -f config.json -a account-id --import-id-prefix YNAB:
+  mp ynab transactions delete -f config.json --from 2026-01-01 --to 2026-01-31 --payee-regex 'AMAZON'`,
+	RunE: run,
+}
+
+func init() {
+	selectFlags.Register(Cmd)
+	Cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "skip confirmation prompt")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+
+	filter, err := selectFlags.Filter()
+	if err != nil {
+		return err
+	}
+
+	client, _, err := cliutil.NewYNABClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	transactions, err := selection.Select(client, filter)
+	if err != nil {
+		return err
+	}
+
+	if len(transactions) == 0 {
+		logger.Warn("No transactions match the selection")
+		return nil
+	}
+
+	if err := selection.PrintPreview(transactions); err != nil {
+		return err
+	}
+
+	if !assumeYes {
+		ok, err := cliutil.Prompter().Confirm(fmt.Sprintf("Delete %d transactions? This cannot be undone.", len(transactions)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			logger.Info("Delete cancelled")
+			return nil
+		}
+	}
+
+	// YNAB has no bulk delete endpoint, so transactions are deleted one by one.
+	failed := 0
+	for _, t := range transactions {
+		if _, err := client.DeleteTransaction(t.ID); err != nil {
+			logger.Errorf("Deleting transaction %s (%s, %s): %v", t.ID, t.Date, t.PayeeName, err)
+			failed++
+			continue
+		}
+		logger.Debugf("Deleted transaction %s", t.ID)
+	}
+
+	logger.Infof("Deleted %d of %d transactions", len(transactions)-failed, len(transactions))
+
+	if failed > 0 {
+		return fmt.Errorf("%d transactions could not be deleted", failed)
+	}
+
+	return nil
+}
@@ -4,9 +4,10 @@ package fetch
 import (
 	"fmt"
 
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
 	"github.com/pgbytes/moneypenny/internal/client/ynab"
-	"github.com/pgbytes/moneypenny/internal/config"
 	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -25,7 +26,8 @@ var Cmd = &cobra.Command{
 
 Example:
   mp ynab transactions fetch -f config.json -a account-id -n 20
-  mp ynab transactions fetch -f config.json -a account-id --since-date 2026-01-01`,
+  mp ynab transactions fetch -f config.json -a account-id --since-date 2026-01-01
+  mp ynab transactions fetch -f config.json -a account-id -o csv > transactions.csv`,
 	RunE: run,
 }
 
@@ -42,30 +44,9 @@ func init() {
 func run(cmd *cobra.Command, args []string) error {
 	logger := log.GetLogger()
 
-	// Get config path from parent's persistent flag
-	configPath, err := cmd.Flags().GetString("config")
+	client, _, err := cliutil.NewYNABClient(cmd)
 	if err != nil {
-		return fmt.Errorf("getting config flag: %w", err)
-	}
-
-	// Load configuration
-	cfg, err := config.LoadFromFile(configPath)
-	if err != nil {
-		return fmt.Errorf("loading config: %w", err)
-	}
-
-	// Validate YNAB config
-	if err := cfg.YNAB.Validate(); err != nil {
-		return fmt.Errorf("invalid config: %w", err)
-	}
-
-	// Create YNAB client
-	client, err := ynab.NewClient(ynab.Config{
-		APIKey:   cfg.YNAB.APIKey,
-		BudgetID: cfg.YNAB.BudgetID,
-	}, logger)
-	if err != nil {
-		return fmt.Errorf("creating YNAB client: %w", err)
+		return err
 	}
 
 	// Build transaction options
@@ -82,20 +63,19 @@ func run(cmd *cobra.Command, args []string) error {
 	// Limit transactions if requested
 	transactions = ynab.LimitTransactions(transactions, limit)
 
-	// Display transactions
 	logger.Infof("Fetched %d transactions from account %s", len(transactions), accountID)
 
-	for _, t := range transactions {
-		amount := ynab.MilliunitsToFloat(t.Amount)
-		logger.Infof("  %s | %10.2f | %-30s | %s",
-			t.Date,
-			amount,
-			truncateString(t.PayeeName, 30),
-			truncateString(t.Memo, 40),
-		)
-	}
-
-	return nil
+	return output.Print(transactions, output.Table[ynab.Transaction]{
+		Headers: []string{"DATE", "AMOUNT", "PAYEE", "MEMO"},
+		Row: func(t ynab.Transaction) []string {
+			return []string{
+				t.Date,
+				fmt.Sprintf("%.2f", ynab.MilliunitsToFloat(t.Amount)),
+				truncateString(t.PayeeName, 30),
+				truncateString(t.Memo, 40),
+			}
+		},
+	})
 }
 
 // truncateString truncates a string to the specified length.
@@ -0,0 +1,103 @@
+// Package selection provides the shared transaction selection flags and preview
+// used by commands that modify existing YNAB transactions.
+package selection
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Flags holds the transaction selection criteria given on the command line.
+type Flags struct {
+	AccountID      string
+	FromDate       string
+	ToDate         string
+	ImportIDPrefix string
+	PayeeRegex     string
+}
+
+// Register adds the selection flags to a command.
+func (f *Flags) Register(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.AccountID, "account-id", "a", "", "select transactions in this account")
+	cmd.Flags().StringVar(&f.FromDate, "from", "", "select transactions on or after this date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&f.ToDate, "to", "", "select transactions on or before this date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&f.ImportIDPrefix, "import-id-prefix", "", "select transactions whose import_id starts with this prefix")
+	cmd.Flags().StringVar(&f.PayeeRegex, "payee-regex", "", "select transactions whose payee matches this regular expression")
+}
+
+// Filter builds a transaction filter from the flags.
+// At least one criterion is required so a command never selects a whole budget by accident.
+func (f *Flags) Filter() (ynab.TransactionFilter, error) {
+	filter := ynab.TransactionFilter{
+		AccountID:      f.AccountID,
+		FromDate:       f.FromDate,
+		ToDate:         f.ToDate,
+		ImportIDPrefix: f.ImportIDPrefix,
+	}
+
+	if f.PayeeRegex != "" {
+		pattern, err := regexp.Compile(f.PayeeRegex)
+		if err != nil {
+			return filter, fmt.Errorf("invalid payee regex: %w", err)
+		}
+		filter.PayeePattern = pattern
+	}
+
+	if filter.IsEmpty() {
+		return filter, fmt.Errorf("at least one selection flag is required (--account-id, --from, --to, --import-id-prefix, --payee-regex)")
+	}
+
+	return filter, nil
+}
+
+// Select fetches transactions from YNAB and returns those matching the filter.
+// The date lower bound is pushed to the API to keep responses small.
+func Select(client ynab.TransactionReader, filter ynab.TransactionFilter) ([]ynab.Transaction, error) {
+	opts := ynab.TransactionOptions{SinceDate: filter.FromDate}
+
+	var transactions []ynab.Transaction
+	var err error
+	if filter.AccountID != "" {
+		transactions, err = client.GetTransactionsByAccount(filter.AccountID, opts)
+	} else {
+		transactions, err = client.GetTransactions(opts)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetching transactions: %w", err)
+	}
+
+	return ynab.FilterTransactions(transactions, filter), nil
+}
+
+// PrintPreview shows the selected transactions as a table before they are
+// changed, on stderr unless the output is a table, and logs their count and
+// total. Stdout stays reserved for the command's result.
+func PrintPreview(transactions []ynab.Transaction) error {
+	if err := output.Preview(transactions, output.Table[ynab.Transaction]{
+		Headers: []string{"DATE", "ACCOUNT", "PAYEE", "AMOUNT", "CLEARED", "IMPORT ID"},
+		Row: func(t ynab.Transaction) []string {
+			return []string{
+				t.Date,
+				output.Truncate(t.AccountName, 20),
+				output.Truncate(t.PayeeName, 35),
+				fmt.Sprintf("%.2f", ynab.MilliunitsToFloat(t.Amount)),
+				string(t.Cleared),
+				t.ImportID,
+			}
+		},
+	}); err != nil {
+		return err
+	}
+
+	var total int64
+	for _, t := range transactions {
+		total += t.Amount
+	}
+	log.GetLogger().Infof("Selected %d transactions, total amount %.2f", len(transactions), ynab.MilliunitsToFloat(total))
+	return nil
+}
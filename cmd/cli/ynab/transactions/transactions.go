@@ -2,7 +2,9 @@
 package transactions
 
 import (
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/transactions/delete"
 	"github.com/pgbytes/moneypenny/cmd/cli/ynab/transactions/fetch"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/transactions/update"
 	"github.com/spf13/cobra"
 )
 
@@ -10,10 +12,12 @@ import (
 var Cmd = &cobra.Command{
 	Use:   "transactions",
 	Short: "Transaction management commands",
-	Long:  `Commands for fetching and uploading transactions.`,
+	Long:  `Commands for fetching, uploading, updating and deleting transactions.`,
 }
 
 func init() {
 	// Register subcommands
 	Cmd.AddCommand(fetch.Cmd)
+	Cmd.AddCommand(update.Cmd)
+	Cmd.AddCommand(delete.Cmd)
 }
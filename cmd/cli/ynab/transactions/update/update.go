@@ -0,0 +1,169 @@
+// Package update provides the command for bulk updating YNAB transactions.
+package update
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/transactions/selection"
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/spf13/cobra"
+)
+
+// Flags for the update command - isolated to this package.
+var (
+	selectFlags selection.Flags
+	payeeName   string
+	memo        string
+	categoryID  string
+	cleared     string
+	flagColor   string
+	approve     bool
+	assumeYes   bool
+)
+
+// Cmd updates a selection of YNAB transactions in one bulk request.
+var Cmd = &cobra.Command{
+	Use:   "update",
+	Short: "Bulk update transactions in YNAB",
+	Long: `Update all transactions matching a selection in a single bulk request.
+
+Transactions are selected by account, date range, import_id prefix and payee
+regular expression. A preview of the selection is shown and the update is
+only sent after confirmation. Only the requested fields are sent: an empty
+--memo, --category-id or --flag clears the field, and --approve=false marks
+transactions as unapproved.
+
+Example:
+  mp ynab transactions update -f config.json -a account-id --from 2026-01-01 --cleared cleared
+  mp ynab transactions update -f config.json --payee-regex '^PAYPAL' --payee PayPal --approve
+  mp ynab transactions update -f config.json --import-id-prefix MM: --memo "" --flag ""`,
+	RunE: run,
+}
+
+func init() {
+	selectFlags.Register(Cmd)
+
+	Cmd.Flags().StringVar(&payeeName, "payee", "", "set payee name")
+	Cmd.Flags().StringVar(&memo, "memo", "", "set memo (empty to clear)")
+	Cmd.Flags().StringVar(&categoryID, "category-id", "", "set category ID (empty to clear)")
+	Cmd.Flags().StringVar(&cleared, "cleared", "", "set cleared status (cleared, uncleared, reconciled)")
+	Cmd.Flags().StringVar(&flagColor, "flag", "", "set flag color (red, orange, yellow, green, blue, purple; empty to clear)")
+	Cmd.Flags().BoolVar(&approve, "approve", false, "mark transactions as approved (--approve=false to unapprove)")
+	Cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "skip confirmation prompt")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+
+	filter, err := selectFlags.Filter()
+	if err != nil {
+		return err
+	}
+
+	if err := validateChanges(cmd); err != nil {
+		return err
+	}
+
+	client, _, err := cliutil.NewYNABClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	transactions, err := selection.Select(client, filter)
+	if err != nil {
+		return err
+	}
+
+	if len(transactions) == 0 {
+		logger.Warn("No transactions match the selection")
+		return nil
+	}
+
+	if err := selection.PrintPreview(transactions); err != nil {
+		return err
+	}
+
+	if !assumeYes {
+		ok, err := cliutil.Prompter().Confirm(fmt.Sprintf("Update %d transactions?", len(transactions)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			logger.Info("Update cancelled")
+			return nil
+		}
+	}
+
+	updates := make([]ynab.SaveTransactionWithID, 0, len(transactions))
+	for _, t := range transactions {
+		updates = append(updates, applyChanges(cmd, t))
+	}
+
+	result, err := client.UpdateTransactions(updates)
+	if err != nil {
+		return fmt.Errorf("updating transactions: %w", err)
+	}
+
+	logger.Infof("Updated %d transactions", len(result.Data.TransactionIDs))
+
+	return nil
+}
+
+// validateChanges ensures at least one change is requested and values are valid.
+func validateChanges(cmd *cobra.Command) error {
+	changed := false
+	for _, name := range []string{"payee", "memo", "category-id", "cleared", "flag", "approve"} {
+		if cmd.Flags().Changed(name) {
+			changed = true
+		}
+	}
+	if !changed {
+		return fmt.Errorf("no changes requested: use --payee, --memo, --category-id, --cleared, --flag or --approve")
+	}
+
+	if cmd.Flags().Changed("cleared") {
+		switch ynab.ClearedStatus(cleared) {
+		case ynab.ClearedStatusCleared, ynab.ClearedStatusUncleared, ynab.ClearedStatusReconciled:
+		default:
+			return fmt.Errorf("invalid cleared status: %s", cleared)
+		}
+	}
+
+	if flagColor != "" && !slices.Contains(ynab.FlagColors, flagColor) {
+		return fmt.Errorf("invalid flag color %q: use one of %s or an empty value to clear", flagColor, strings.Join(ynab.FlagColors, ", "))
+	}
+
+	return nil
+}
+
+// applyChanges builds an update carrying only the requested changes. Empty
+// --memo, --category-id and --flag values clear the field.
+func applyChanges(cmd *cobra.Command, t ynab.Transaction) ynab.SaveTransactionWithID {
+	update := ynab.SaveTransactionWithID{ID: t.ID}
+
+	if cmd.Flags().Changed("payee") {
+		update.PayeeID = ynab.Ptr("")
+		update.PayeeName = ynab.Ptr(payeeName)
+	}
+	if cmd.Flags().Changed("memo") {
+		update.Memo = ynab.Ptr(memo)
+	}
+	if cmd.Flags().Changed("category-id") {
+		update.CategoryID = ynab.Ptr(categoryID)
+	}
+	if cmd.Flags().Changed("cleared") {
+		update.Cleared = ynab.ClearedStatus(cleared)
+	}
+	if cmd.Flags().Changed("flag") {
+		update.FlagColor = ynab.Ptr(flagColor)
+	}
+	if cmd.Flags().Changed("approve") {
+		update.Approved = ynab.Ptr(approve)
+	}
+
+	return update
+}
@@ -0,0 +1,225 @@
+// Package transfers provides the command for detecting and creating transfers between accounts.
+package transfers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/log"
+	"github.com/pgbytes/moneypenny/internal/matcher"
+	"github.com/pgbytes/moneypenny/internal/output"
+	"github.com/pgbytes/moneypenny/internal/transfer"
+	"github.com/spf13/cobra"
+)
+
+// defaultLookbackDays is how far back transactions are scanned without a statement.
+const defaultLookbackDays = 60
+
+// Flags for the transfers command - isolated to this package.
+var (
+	inputPath  string
+	account    string
+	sinceDate  string
+	dateWindow int
+	assumeYes  bool
+)
+
+// Cmd detects transfer pairs and turns them into YNAB transfers.
+var Cmd = &cobra.Command{
+	Use:   "transfers",
+	Short: "Detect and create transfers between accounts",
+	Long: `Detect transactions in two accounts that are really one transfer, such as
+the monthly Miles & More settlement debited from the checking account and
+credited on the card, and turn them into YNAB transfers.
+
+A pair has opposite amounts in different accounts, dates within the window
+and at least one payee matching a settlement pattern (configurable via
+"transfers.payee_patterns" in the config file).
+
+Existing YNAB transactions of all accounts are scanned; reconciled ones are
+never touched. With --input, rows of
+a Miles & More statement for --account that are not yet in YNAB are included
+too. Pairs are converted using the other account's transfer payee:
+  - both legs in YNAB: one leg becomes a transfer, the other is replaced
+    (the uncleared one, preferring the inflow)
+  - one leg in YNAB: that transaction becomes a transfer
+  - statement row only: the outflow is created as a transfer
+
+Example:
+  mp ynab transfers -f config.json --since 2026-01-01
+  mp ynab transfers -f config.json -i statement.csv -a "Miles & More"`,
+	RunE: run,
+}
+
+func init() {
+	Cmd.Flags().StringVarP(&inputPath, "input", "i", "", "path to Miles & More CSV statement file (optional)")
+	Cmd.Flags().StringVarP(&account, "account", "a", "", "YNAB account ID or name of the statement (required with --input)")
+	Cmd.Flags().StringVar(&sinceDate, "since", "", "scan transactions on or after this date (YYYY-MM-DD)")
+	Cmd.Flags().IntVar(&dateWindow, "date-window", transfer.DefaultDateWindow, "maximum days between both legs of a transfer")
+	Cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "skip confirmation prompt")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	logger := log.GetLogger()
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if inputPath != "" && account == "" {
+		return fmt.Errorf("--account is required with --input")
+	}
+
+	client, cfg, err := cliutil.NewYNABClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	patterns, err := transfer.CompilePatterns(cfg.Transfers.PayeePatterns)
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	accounts, err := client.GetAccounts()
+	if err != nil {
+		return fmt.Errorf("fetching accounts: %w", err)
+	}
+
+	var statementLegs []transfer.Leg
+	var statementAccountID string
+	since := sinceDate
+
+	if inputPath != "" {
+		parseResult, err := cliutil.ParseMilesMoreStrict(ctx, inputPath)
+		if err != nil {
+			return fmt.Errorf("%w, aborting", err)
+		}
+		acc, err := ynab.FindAccount(accounts, account)
+		if err != nil {
+			return err
+		}
+		statementAccountID = acc.ID
+
+		for _, tx := range parseResult.Transactions {
+			start := tx.Date.AddDate(0, 0, -dateWindow).Format("2006-01-02")
+			if since == "" || start < since {
+				since = start
+			}
+		}
+
+		existing, err := client.GetTransactionsByAccount(acc.ID, ynab.TransactionOptions{SinceDate: since})
+		if err != nil {
+			return fmt.Errorf("fetching transactions: %w", err)
+		}
+
+		// Rows already in YNAB are covered by their existing transaction
+		results := matcher.Match(parseResult.Transactions, existing, matcher.Options{DateWindow: dateWindow})
+		for _, tx := range matcher.FilterByStatus(results, matcher.StatusNew) {
+			statementLegs = append(statementLegs, transfer.StatementLeg(acc.ID, tx))
+		}
+	}
+
+	if since == "" {
+		since = time.Now().AddDate(0, 0, -defaultLookbackDays).Format("2006-01-02")
+	}
+
+	existing, err := client.GetTransactions(ynab.TransactionOptions{SinceDate: since})
+	if err != nil {
+		return fmt.Errorf("fetching transactions: %w", err)
+	}
+
+	legs := statementLegs
+	for _, t := range existing {
+		if leg, ok := transfer.ExistingLeg(t); ok {
+			legs = append(legs, leg)
+		}
+	}
+
+	pairs := transfer.Detect(legs, transfer.Options{DateWindow: dateWindow, PayeePatterns: patterns})
+	if len(pairs) == 0 {
+		logger.Info("No transfers detected")
+		return nil
+	}
+
+	if err := printPairs(pairs, accounts, statementAccountID); err != nil {
+		return err
+	}
+
+	plan, err := transfer.BuildPlan(pairs, accounts)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Plan: create %d, convert %d, replace %d transactions", len(plan.Create), len(plan.Update), len(plan.Delete))
+	for _, w := range plan.Warnings {
+		logger.Warn(w)
+	}
+
+	if !assumeYes {
+		ok, err := cliutil.Prompter().Confirm(fmt.Sprintf("Create %d transfers?", len(pairs)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			logger.Info("Transfers cancelled")
+			return nil
+		}
+	}
+
+	if len(plan.Create) > 0 {
+		if _, err := client.CreateTransactions(plan.Create); err != nil {
+			return fmt.Errorf("creating transfers: %w", err)
+		}
+	}
+
+	if len(plan.Update) > 0 {
+		if _, err := client.UpdateTransactions(plan.Update); err != nil {
+			return fmt.Errorf("converting transactions to transfers: %w", err)
+		}
+	}
+
+	// Only delete replaced legs once their transfer counterpart exists
+	for _, id := range plan.Delete {
+		if _, err := client.DeleteTransaction(id); err != nil {
+			return fmt.Errorf("deleting replaced transaction %s: %w", id, err)
+		}
+	}
+
+	logger.Infof("Created %d transfers", len(pairs))
+
+	return nil
+}
+
+// printPairs lists the detected transfers.
+func printPairs(pairs []transfer.Pair, accounts []ynab.Account, statementAccountID string) error {
+	names := make(map[string]string, len(accounts))
+	for _, acc := range accounts {
+		names[acc.ID] = acc.Name
+	}
+
+	return output.Print(pairs, output.Table[transfer.Pair]{
+		Headers: []string{"DATE", "FROM", "TO", "AMOUNT", "OUTFLOW PAYEE", "INFLOW PAYEE", "DAYS"},
+		Row: func(p transfer.Pair) []string {
+			return []string{
+				p.Outflow.Date.Format("2006-01-02"),
+				output.Truncate(names[p.Outflow.AccountID], 20),
+				output.Truncate(names[p.Inflow.AccountID], 20),
+				fmt.Sprintf("%.2f", ynab.MilliunitsToFloat(p.Inflow.Amount)),
+				output.Truncate(legPayee(p.Outflow, statementAccountID), 30),
+				output.Truncate(legPayee(p.Inflow, statementAccountID), 30),
+				fmt.Sprintf("%d", p.DayDelta),
+			}
+		},
+	})
+}
+
+// legPayee returns the payee of a leg, marking rows that come from the statement.
+func legPayee(l transfer.Leg, statementAccountID string) string {
+	if l.Statement != nil && l.AccountID == statementAccountID {
+		return "(statement) " + l.Payee
+	}
+	return l.Payee
+}
@@ -4,16 +4,23 @@ package milesmore
 import (
 	"context"
 	"fmt"
-	"os"
+	"time"
 
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/pgbytes/moneypenny/internal/ledger"
 	"github.com/pgbytes/moneypenny/internal/log"
-	"github.com/pgbytes/moneypenny/internal/parsers/milesmore"
+	"github.com/pgbytes/moneypenny/internal/rules"
+	"github.com/pgbytes/moneypenny/internal/storage"
 	"github.com/pgbytes/moneypenny/internal/transform/ynab"
 	"github.com/spf13/cobra"
 )
 
 // Flags for the milesmore command - isolated to this package.
-var inputPath string
+var (
+	inputPath string
+	force     bool
+)
 
 // Cmd transforms Miles & More statements to YNAB format.
 var Cmd = &cobra.Command{
@@ -26,6 +33,15 @@ and creates a new CSV file in YNAB import format at the same location with "_yna
 
 The transformation is strict: if any parsing errors occur, the process aborts.
 
+Card payees such as "RECALL, 19709 MIDDLETOWN, DE, USA" are split into the
+merchant, used as payee, and the location, put in the memo, unless
+--raw-payee is given. Rules from the rules file (see --rules) then rename
+payees, set memos and skip rows before the file is written.
+
+Processed statements are recorded in a local ledger. Transforming the same
+file again is refused and overlapping date ranges ask for confirmation,
+unless --force is given.
+
 Example:
   mp ynab transform milesmore -i /path/to/statement.csv
 
@@ -35,6 +51,8 @@ Output will be created at: /path/to/statement_ynab.csv`,
 
 func init() {
 	Cmd.Flags().StringVarP(&inputPath, "input", "i", "", "path to Miles & More CSV statement file")
+	Cmd.Flags().BoolVar(&force, "force", false, "process the statement even if it was processed before")
+	cliutil.AddRawPayeeFlag(Cmd)
 
 	_ = Cmd.MarkFlagRequired("input")
 }
@@ -49,41 +67,61 @@ func run(cmd *cobra.Command, args []string) error {
 	logger.Infof("Starting Miles & More to YNAB transformation")
 	logger.Debugf("Input file: %s", inputPath)
 
-	// Validate input file exists
-	if _, err := os.Stat(inputPath); os.IsNotExist(err) {
-		return fmt.Errorf("input file not found: %s", inputPath)
+	// Parse Miles & More CSV in strict mode: abort if any parsing errors occurred
+	logger.Infof("Parsing Miles & More statement...")
+	parseResult, err := cliutil.ParseMilesMoreStrict(ctx, inputPath)
+	if err != nil {
+		return fmt.Errorf("%w, aborting transformation", err)
 	}
 
-	// Open input file for parsing
-	inputFile, err := os.Open(inputPath)
+	// Check if there are any transactions to transform
+	if len(parseResult.Transactions) == 0 {
+		logger.Warnf("No transactions found in input file")
+		return fmt.Errorf("no transactions to transform")
+	}
+
+	cfg, err := cliutil.LoadConfig(cmd)
 	if err != nil {
-		return fmt.Errorf("opening input file: %w", err)
+		return err
 	}
-	defer inputFile.Close()
 
-	// Parse Miles & More CSV
-	logger.Infof("Parsing Miles & More statement...")
-	parseResult, err := milesmore.Parse(ctx, inputFile, inputPath)
+	// Refuse re-processing of already transformed statements
+	hash, err := storage.HashFile(inputPath)
 	if err != nil {
-		return fmt.Errorf("parsing Miles & More CSV: %w", err)
+		return err
 	}
 
-	// Strict mode: abort if any parsing errors occurred
-	if len(parseResult.Errors) > 0 {
-		logger.Errorf("Parsing encountered %d errors (strict mode - aborting):", len(parseResult.Errors))
-		for _, parseErr := range parseResult.Errors {
-			logger.Errorf("  Line %d: %v", parseErr.Line, parseErr.Error)
-		}
-		return fmt.Errorf("parsing failed with %d errors, aborting transformation", len(parseResult.Errors))
+	dir, err := cliutil.DataDir()
+	if err != nil {
+		return err
 	}
 
-	logger.Infof("Successfully parsed %d transactions from %d rows",
-		parseResult.SuccessfulRows, parseResult.TotalRows)
+	statementLedger, err := ledger.OpenDir(dir)
+	if err != nil {
+		return fmt.Errorf("opening statement ledger: %w", err)
+	}
 
-	// Check if there are any transactions to transform
-	if len(parseResult.Transactions) == 0 {
-		logger.Warnf("No transactions found in input file")
-		return fmt.Errorf("no transactions to transform")
+	stmt := ledger.NewStatement("milesmore", inputPath, hash, parseResult.BillingDate, parseResult.Transactions)
+	stmt.Action = ledger.ActionTransform
+
+	proceed, err := cliutil.GuardStatement(cliutil.Prompter(), statementLedger, stmt, force)
+	if err != nil {
+		return err
+	}
+	if !proceed {
+		logger.Info("Transformation cancelled")
+		return nil
+	}
+
+	transactions := cliutil.MerchantPayees(cmd, parseResult.Transactions)
+
+	// Clean up payees and drop skipped rows
+	transactions, err = cliutil.ApplyRules(cfg, "milesmore", transactions)
+	if err != nil {
+		return err
+	}
+	if rules.NeedsCategories(transactions) || hasFlagsOrSplits(transactions) {
+		logger.Warnf("Categories, flags and splits set by rules are only applied by mp ynab import")
 	}
 
 	// Generate output path
@@ -92,14 +130,29 @@ func run(cmd *cobra.Command, args []string) error {
 
 	// Transform to YNAB format
 	logger.Infof("Transforming to YNAB format...")
-	transformResult, err := ynab.TransformToCSV(ctx, parseResult.Transactions, outputPath)
+	transformResult, err := ynab.TransformToCSV(ctx, transactions, outputPath)
 	if err != nil {
 		return fmt.Errorf("transforming to YNAB format: %w", err)
 	}
 
+	statementLedger.Record(stmt, time.Now())
+	if err := statementLedger.Save(); err != nil {
+		return err
+	}
+
 	logger.Infof("Transformation complete!")
 	logger.Infof("  Transactions written: %d", transformResult.TransactionCount)
 	logger.Infof("  Output file: %s", transformResult.OutputPath)
 
 	return nil
 }
+
+// hasFlagsOrSplits reports whether rules set a flag or split on any transaction.
+func hasFlagsOrSplits(transactions []domain.Transaction) bool {
+	for _, tx := range transactions {
+		if tx.FlagColor != "" || len(tx.Splits) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -2,14 +2,22 @@
 package ynab
 
 import (
+	"github.com/pgbytes/moneypenny/cmd/cli/cliutil"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/accounts"
 	"github.com/pgbytes/moneypenny/cmd/cli/ynab/budgets"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/importer"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/payees"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/reconcile"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/review"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/suggest"
 	"github.com/pgbytes/moneypenny/cmd/cli/ynab/transactions"
+	"github.com/pgbytes/moneypenny/cmd/cli/ynab/transfers"
 	"github.com/pgbytes/moneypenny/cmd/cli/ynab/transform"
 	"github.com/spf13/cobra"
 )
 
-// configPath holds the path to the config file for all YNAB subcommands.
-var configPath string
+// rulesPath holds the path to the rules file for all YNAB subcommands.
+var rulesPath string
 
 // Cmd is the parent command for YNAB operations.
 var Cmd = &cobra.Command{
@@ -17,16 +25,45 @@ var Cmd = &cobra.Command{
 	Short: "YNAB budget management commands",
 	Long: `Commands for interacting with YNAB (You Need A Budget) API.
 
-These commands allow you to fetch and manage budgets and transactions in your YNAB account.`,
+These commands allow you to fetch and manage budgets and transactions in your YNAB account.
+
+When the config file defines profiles (e.g. a personal and a shared household
+budget), --profile selects the token, budget, account mappings and rules to
+use; without it the config's default_profile applies.
+
+Settings are merged from the built-in defaults, the XDG config file
+(~/.config/moneypenny/config.json, .yaml or .toml), the file given with -f or a
+project-local .moneypenny.json/.yaml/.toml, MONEYPENNY_* environment variables
+(e.g. MONEYPENNY_YNAB_API_KEY) and flags, later layers winning. See the result
+with mp config show --resolved.
+
+--record saves every API request and response to a cassette file with the
+token and account names redacted, so it can be attached to a bug report;
+--replay answers the requests from such a cassette without a token or network.
+--read-only rejects every change to the budget, e.g. to try an import safely.
+
+Example:
+  mp ynab accounts list -f config.json --profile household
+  mp ynab transactions fetch --record bug.json
+  mp ynab transactions fetch --replay bug.json
+  mp ynab review --read-only`,
 }
 
 func init() {
 	// Add persistent flags available to all subcommands
-	Cmd.PersistentFlags().StringVarP(&configPath, "config", "f", "", "path to config file (JSON)")
-	_ = Cmd.MarkPersistentFlagRequired("config")
+	cliutil.AddConfigFlags(Cmd)
+	Cmd.PersistentFlags().StringVar(&rulesPath, "rules", "", "path to rules file (default: rules.yaml/rules.json next to the config file)")
+	cliutil.AddClientFlags(Cmd)
 
 	// Register subcommands
 	Cmd.AddCommand(budgets.Cmd)
+	Cmd.AddCommand(accounts.Cmd)
 	Cmd.AddCommand(transactions.Cmd)
 	Cmd.AddCommand(transform.Cmd)
+	Cmd.AddCommand(importer.Cmd)
+	Cmd.AddCommand(reconcile.Cmd)
+	Cmd.AddCommand(review.Cmd)
+	Cmd.AddCommand(transfers.Cmd)
+	Cmd.AddCommand(suggest.Cmd)
+	Cmd.AddCommand(payees.Cmd)
 }
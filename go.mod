@@ -3,10 +3,14 @@ module github.com/pgbytes/moneypenny
 go 1.25
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/go-resty/resty/v2 v2.17.1
 	github.com/spf13/cobra v0.0.6
 	github.com/stretchr/testify v1.9.0
 	go.uber.org/zap v1.14.0
+	golang.org/x/crypto v0.41.0
+	golang.org/x/term v0.34.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -18,7 +22,7 @@ require (
 	go.uber.org/multierr v1.5.0 // indirect
 	golang.org/x/lint v0.0.0-20200302205851-738671d3881b // indirect
 	golang.org/x/net v0.43.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
 	golang.org/x/tools v0.0.0-20200313205530-4303120df7d8 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 	honnef.co/go/tools v0.0.1-2020.1.3 // indirect
 )
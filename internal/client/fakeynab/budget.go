@@ -0,0 +1,451 @@
+package fakeynab
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+)
+
+// Names of the records every budget starts with, as in YNAB.
+const (
+	InflowGroupName     = "Internal Master Category"
+	InflowCategoryName  = "Inflow: Ready to Assign"
+	StartingBalanceName = "Starting Balance"
+	splitCategoryName   = "Split"
+	transferPayeePrefix = "Transfer : "
+)
+
+// record is a stored entity with the server knowledge of its last change.
+type record[T any] struct {
+	value     T
+	knowledge int64
+}
+
+// budget holds the entities of one budget. Every change increments knowledge
+// and stamps the changed records, which is what delta requests compare.
+type budget struct {
+	summary      ynab.BudgetSummary
+	knowledge    int64
+	accounts     []*record[ynab.Account]
+	groups       []*record[ynab.CategoryGroup]
+	categories   []*record[ynab.Category]
+	payees       []*record[ynab.Payee]
+	transactions []*record[ynab.Transaction]
+}
+
+// since selects the records changed after knowledge; without knowledge all
+// records that are not deleted.
+func since[T any](records []*record[T], knowledge int64, deleted func(T) bool) []T {
+	values := make([]T, 0, len(records))
+	for _, r := range records {
+		if knowledge > 0 && r.knowledge <= knowledge {
+			continue
+		}
+		if knowledge == 0 && deleted(r.value) {
+			continue
+		}
+		values = append(values, r.value)
+	}
+	return values
+}
+
+// touch starts a change and returns its knowledge.
+func (b *budget) touch(now time.Time) int64 {
+	b.knowledge++
+	b.summary.LastModifiedOn = now.UTC().Format(time.RFC3339)
+	return b.knowledge
+}
+
+func (b *budget) account(id string) *record[ynab.Account] {
+	for _, r := range b.accounts {
+		if r.value.ID == id && !r.value.Deleted {
+			return r
+		}
+	}
+	return nil
+}
+
+func (b *budget) category(id string) *record[ynab.Category] {
+	for _, r := range b.categories {
+		if r.value.ID == id && !r.value.Deleted {
+			return r
+		}
+	}
+	return nil
+}
+
+func (b *budget) categoryByName(group, name string) *record[ynab.Category] {
+	for _, r := range b.categories {
+		if strings.EqualFold(r.value.Name, name) && (group == "" || strings.EqualFold(r.value.CategoryGroupName, group)) && !r.value.Deleted {
+			return r
+		}
+	}
+	return nil
+}
+
+func (b *budget) payee(id string) *record[ynab.Payee] {
+	for _, r := range b.payees {
+		if r.value.ID == id && !r.value.Deleted {
+			return r
+		}
+	}
+	return nil
+}
+
+func (b *budget) payeeByName(name string) *record[ynab.Payee] {
+	for _, r := range b.payees {
+		if strings.EqualFold(r.value.Name, name) && !r.value.Deleted {
+			return r
+		}
+	}
+	return nil
+}
+
+func (b *budget) transaction(id string) *record[ynab.Transaction] {
+	for _, r := range b.transactions {
+		if r.value.ID == id {
+			return r
+		}
+	}
+	return nil
+}
+
+func (b *budget) transactionByImportID(accountID, importID string) *record[ynab.Transaction] {
+	for _, r := range b.transactions {
+		if r.value.ImportID == importID && r.value.AccountID == accountID {
+			return r
+		}
+	}
+	return nil
+}
+
+// categoryGroups returns the groups with their categories changed after knowledge.
+func (b *budget) categoryGroups(knowledge int64) []ynab.CategoryGroup {
+	groups := []ynab.CategoryGroup{}
+	for _, g := range b.groups {
+		group := g.value
+		group.Categories = []ynab.Category{}
+		changed := knowledge == 0 || g.knowledge > knowledge
+		for _, c := range b.categories {
+			if c.value.CategoryGroupID != group.ID {
+				continue
+			}
+			if knowledge > 0 && c.knowledge <= knowledge {
+				continue
+			}
+			if knowledge == 0 && c.value.Deleted {
+				continue
+			}
+			group.Categories = append(group.Categories, c.value)
+			changed = true
+		}
+		if changed && (knowledge > 0 || !group.Deleted) {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// addCategory creates the category, and its group when needed.
+func (s *Server) addCategory(b *budget, groupName, name string, k int64) ynab.Category {
+	if r := b.categoryByName(groupName, name); r != nil {
+		return r.value
+	}
+
+	var group *record[ynab.CategoryGroup]
+	for _, g := range b.groups {
+		if strings.EqualFold(g.value.Name, groupName) {
+			group = g
+		}
+	}
+	if group == nil {
+		group = &record[ynab.CategoryGroup]{value: ynab.CategoryGroup{ID: s.nextID("group"), Name: groupName}, knowledge: k}
+		b.groups = append(b.groups, group)
+	}
+
+	c := ynab.Category{ID: s.nextID("cat"), CategoryGroupID: group.value.ID, CategoryGroupName: groupName, Name: name}
+	b.categories = append(b.categories, &record[ynab.Category]{value: c, knowledge: k})
+	return c
+}
+
+// addPayee returns the payee named name, creating it when needed.
+func (s *Server) addPayee(b *budget, name string, k int64) ynab.Payee {
+	if r := b.payeeByName(name); r != nil {
+		return r.value
+	}
+	p := ynab.Payee{ID: s.nextID("payee"), Name: name}
+	b.payees = append(b.payees, &record[ynab.Payee]{value: p, knowledge: k})
+	return p
+}
+
+// createAccount adds an account with its transfer payee and, for a non-zero
+// balance, a starting balance transaction.
+func (s *Server) createAccount(b *budget, save ynab.SaveAccount) (ynab.Account, *apiError) {
+	if strings.TrimSpace(save.Name) == "" {
+		return ynab.Account{}, badRequest("name is required")
+	}
+	if !ynab.IsValidAccountType(save.Type) {
+		return ynab.Account{}, badRequest("invalid account type %q", save.Type)
+	}
+
+	now := s.now()
+	k := b.touch(now)
+	acc := ynab.Account{
+		ID:       s.nextID("acc"),
+		Name:     save.Name,
+		Type:     save.Type,
+		OnBudget: !ynab.IsLiabilityType(save.Type) || save.Type == ynab.AccountTypeCreditCard || save.Type == ynab.AccountTypeLineOfCredit,
+	}
+
+	transfer := ynab.Payee{ID: s.nextID("payee"), Name: transferPayeePrefix + save.Name, TransferAccountID: acc.ID}
+	b.payees = append(b.payees, &record[ynab.Payee]{value: transfer, knowledge: k})
+	acc.TransferPayeeID = transfer.ID
+	b.accounts = append(b.accounts, &record[ynab.Account]{value: acc, knowledge: k})
+
+	if save.Balance != 0 {
+		start := ynab.SaveTransaction{
+			AccountID: acc.ID,
+			Date:      now.Format("2006-01-02"),
+			Amount:    save.Balance,
+			PayeeName: StartingBalanceName,
+			Cleared:   ynab.ClearedStatusCleared,
+			Approved:  true,
+		}
+		if acc.OnBudget && save.Balance > 0 {
+			start.CategoryID = s.addCategory(b, InflowGroupName, InflowCategoryName, k).ID
+		}
+		if _, err := s.insertTransaction(b, start, k); err != nil {
+			return ynab.Account{}, err
+		}
+	}
+
+	return b.account(acc.ID).value, nil
+}
+
+// insertTransaction validates save and stores it as a new transaction,
+// creating the counterpart of a transfer.
+func (s *Server) insertTransaction(b *budget, save ynab.SaveTransaction, k int64) (ynab.Transaction, *apiError) {
+	t, err := s.resolve(b, ynab.Transaction{ID: s.nextID("txn")}, save)
+	if err != nil {
+		return ynab.Transaction{}, err
+	}
+
+	r := &record[ynab.Transaction]{value: t, knowledge: k}
+	b.transactions = append(b.transactions, r)
+	b.apply(t, 1, k)
+	s.linkTransfer(b, r, k)
+	return r.value, nil
+}
+
+// replaceTransaction updates the transaction in r with save.
+func (s *Server) replaceTransaction(b *budget, r *record[ynab.Transaction], save ynab.SaveTransaction, k int64) (ynab.Transaction, *apiError) {
+	if r.value.Deleted {
+		return ynab.Transaction{}, notFound("transaction")
+	}
+	t, err := s.resolve(b, ynab.Transaction{ID: r.value.ID}, save)
+	if err != nil {
+		return ynab.Transaction{}, err
+	}
+
+	// Like YNAB, edits that keep the transfer account update the counterpart
+	// instead of replacing it
+	if counterpart := b.transaction(r.value.TransferTransactionID); counterpart != nil && !counterpart.value.Deleted &&
+		t.TransferAccountID != "" && t.TransferAccountID == r.value.TransferAccountID {
+		b.apply(r.value, -1, k)
+		b.apply(counterpart.value, -1, k)
+		t.TransferTransactionID = counterpart.value.ID
+		counterpart.value.Date, counterpart.value.Amount, counterpart.knowledge = t.Date, -t.Amount, k
+		r.value, r.knowledge = t, k
+		b.apply(t, 1, k)
+		b.apply(counterpart.value, 1, k)
+		return r.value, nil
+	}
+
+	s.unlinkTransfer(b, r, k)
+	b.apply(r.value, -1, k)
+	r.value, r.knowledge = t, k
+	b.apply(t, 1, k)
+	s.linkTransfer(b, r, k)
+	return r.value, nil
+}
+
+// deleteTransaction marks the transaction in r and its transfer counterpart deleted.
+func (s *Server) deleteTransaction(b *budget, r *record[ynab.Transaction], k int64) ynab.Transaction {
+	if r.value.Deleted {
+		return r.value
+	}
+	s.unlinkTransfer(b, r, k)
+	b.apply(r.value, -1, k)
+	r.value.Deleted = true
+	r.knowledge = k
+	return r.value
+}
+
+// resolve validates save and fills in the names of its account, payee and category.
+func (s *Server) resolve(b *budget, t ynab.Transaction, save ynab.SaveTransaction) (ynab.Transaction, *apiError) {
+	acc := b.account(save.AccountID)
+	if acc == nil {
+		return t, badRequest("account_id %q does not exist", save.AccountID)
+	}
+	if _, err := time.Parse("2006-01-02", save.Date); err != nil {
+		return t, badRequest("date %q is not a valid ISO date", save.Date)
+	}
+	if len(save.ImportID) > 36 {
+		return t, badRequest("import_id must not exceed 36 characters")
+	}
+	if len(save.Memo) > 500 {
+		return t, badRequest("memo must not exceed 500 characters")
+	}
+	if save.FlagColor != "" && !slices.Contains(ynab.FlagColors, save.FlagColor) {
+		return t, badRequest("invalid flag_color %q", save.FlagColor)
+	}
+	cleared := save.Cleared
+	switch cleared {
+	case "":
+		cleared = ynab.ClearedStatusUncleared
+	case ynab.ClearedStatusCleared, ynab.ClearedStatusUncleared, ynab.ClearedStatusReconciled:
+	default:
+		return t, badRequest("invalid cleared status %q", save.Cleared)
+	}
+
+	t.AccountID, t.AccountName = acc.value.ID, acc.value.Name
+	t.Date, t.Amount, t.Memo = save.Date, save.Amount, save.Memo
+	t.Cleared, t.Approved, t.FlagColor, t.ImportID = cleared, save.Approved, save.FlagColor, save.ImportID
+
+	payee, err := s.resolvePayee(b, save.PayeeID, save.PayeeName)
+	if err != nil {
+		return t, err
+	}
+	if payee != nil {
+		t.PayeeID, t.PayeeName = payee.ID, payee.Name
+		t.TransferAccountID = payee.TransferAccountID
+		if t.TransferAccountID == t.AccountID {
+			return t, badRequest("cannot transfer to the same account")
+		}
+	}
+
+	if save.CategoryID != "" {
+		c := b.category(save.CategoryID)
+		if c == nil {
+			return t, badRequest("category_id %q does not exist", save.CategoryID)
+		}
+		t.CategoryID, t.CategoryName = c.value.ID, c.value.Name
+	}
+
+	if len(save.Subtransactions) > 0 {
+		var sum int64
+		for _, sub := range save.Subtransactions {
+			st := ynab.SubTransaction{ID: s.nextID("sub"), TransactionID: t.ID, Amount: sub.Amount, Memo: sub.Memo}
+			if p, err := s.resolvePayee(b, sub.PayeeID, sub.PayeeName); err != nil {
+				return t, err
+			} else if p != nil {
+				st.PayeeID, st.PayeeName = p.ID, p.Name
+			}
+			if sub.CategoryID != "" {
+				c := b.category(sub.CategoryID)
+				if c == nil {
+					return t, badRequest("category_id %q does not exist", sub.CategoryID)
+				}
+				st.CategoryID, st.CategoryName = c.value.ID, c.value.Name
+			}
+			sum += sub.Amount
+			t.Subtransactions = append(t.Subtransactions, st)
+		}
+		if sum != t.Amount {
+			return t, badRequest("subtransaction amounts (%d) must add up to the amount (%d)", sum, t.Amount)
+		}
+		t.CategoryID, t.CategoryName = "", splitCategoryName
+	}
+	if t.Subtransactions == nil {
+		t.Subtransactions = []ynab.SubTransaction{}
+	}
+
+	return t, nil
+}
+
+// resolvePayee looks up the payee by ID or by name, creating named payees.
+func (s *Server) resolvePayee(b *budget, id, name string) (*ynab.Payee, *apiError) {
+	if id != "" {
+		p := b.payee(id)
+		if p == nil {
+			return nil, badRequest("payee_id %q does not exist", id)
+		}
+		return &p.value, nil
+	}
+	if strings.TrimSpace(name) == "" {
+		return nil, nil
+	}
+	p := s.addPayee(b, name, b.knowledge)
+	return &p, nil
+}
+
+// linkTransfer creates the counterpart of a transfer in the other account.
+func (s *Server) linkTransfer(b *budget, r *record[ynab.Transaction], k int64) {
+	t := &r.value
+	if t.TransferAccountID == "" {
+		return
+	}
+	other := b.account(t.TransferAccountID)
+	source := b.account(t.AccountID)
+
+	counterpart := ynab.Transaction{
+		ID:                    s.nextID("txn"),
+		Date:                  t.Date,
+		Amount:                -t.Amount,
+		Memo:                  t.Memo,
+		Cleared:               ynab.ClearedStatusUncleared,
+		AccountID:             other.value.ID,
+		AccountName:           other.value.Name,
+		PayeeID:               source.value.TransferPayeeID,
+		PayeeName:             transferPayeePrefix + source.value.Name,
+		TransferAccountID:     t.AccountID,
+		TransferTransactionID: t.ID,
+		Subtransactions:       []ynab.SubTransaction{},
+	}
+	t.TransferTransactionID = counterpart.ID
+	b.transactions = append(b.transactions, &record[ynab.Transaction]{value: counterpart, knowledge: k})
+	b.apply(counterpart, 1, k)
+}
+
+// unlinkTransfer deletes the counterpart of a transfer.
+func (s *Server) unlinkTransfer(b *budget, r *record[ynab.Transaction], k int64) {
+	if r.value.TransferTransactionID == "" {
+		return
+	}
+	if counterpart := b.transaction(r.value.TransferTransactionID); counterpart != nil && !counterpart.value.Deleted {
+		b.apply(counterpart.value, -1, k)
+		counterpart.value.Deleted = true
+		counterpart.knowledge = k
+	}
+	r.value.TransferTransactionID = ""
+}
+
+// apply adds (sign 1) or removes (sign -1) the amounts of t to the balance
+// of its account and the activity of its categories.
+func (b *budget) apply(t ynab.Transaction, sign int64, k int64) {
+	if acc := b.account(t.AccountID); acc != nil {
+		acc.value.Balance += sign * t.Amount
+		if t.Cleared == ynab.ClearedStatusUncleared {
+			acc.value.UnclearedBalance += sign * t.Amount
+		} else {
+			acc.value.ClearedBalance += sign * t.Amount
+		}
+		acc.knowledge = k
+	}
+
+	addActivity := func(categoryID string, amount int64) {
+		if c := b.category(categoryID); c != nil {
+			c.value.Activity += sign * amount
+			c.value.Balance += sign * amount
+			c.knowledge = k
+		}
+	}
+	if len(t.Subtransactions) == 0 {
+		addActivity(t.CategoryID, t.Amount)
+	}
+	for _, sub := range t.Subtransactions {
+		addActivity(sub.CategoryID, sub.Amount)
+	}
+}
@@ -0,0 +1,92 @@
+package fakeynab
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+)
+
+// DemoBudgetID is the ID of the budget created by NewDemo.
+const DemoBudgetID = "demo-budget"
+
+// NewDemo creates a fake with a German euro budget: a checking account, a
+// Miles & More credit card and cash, a few categories and two months of
+// transactions, some of them unapproved and uncategorized for review.
+func NewDemo() (*Server, error) {
+	s := New()
+	id := s.AddBudget(ynab.BudgetSummary{ID: DemoBudgetID, Name: "Demo Budget"})
+
+	checking, err := s.AddAccount(id, ynab.SaveAccount{Name: "Girokonto", Type: ynab.AccountTypeChecking, Balance: 2_500_000})
+	if err != nil {
+		return nil, err
+	}
+	card, err := s.AddAccount(id, ynab.SaveAccount{Name: "Miles & More", Type: ynab.AccountTypeCreditCard})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.AddAccount(id, ynab.SaveAccount{Name: "Cash", Type: ynab.AccountTypeCash, Balance: 80_000}); err != nil {
+		return nil, err
+	}
+
+	categories := make(map[string]string)
+	for _, c := range [][2]string{
+		{"Bills", "Rent"}, {"Bills", "Internet"}, {"Bills", "Insurance"},
+		{"Everyday", "Groceries"}, {"Everyday", "Dining Out"}, {"Everyday", "Transport"},
+		{"Fun", "Travel"}, {"Fun", "Hobbies"},
+	} {
+		cat, err := s.AddCategory(id, c[0], c[1])
+		if err != nil {
+			return nil, err
+		}
+		categories[c[1]] = cat.ID
+	}
+
+	month := time.Now().AddDate(0, -1, 0)
+	day := func(d int) string {
+		return time.Date(month.Year(), month.Month(), d, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+	}
+	txn := func(accountID, date string, amount int64, payee, category string, approved bool) ynab.SaveTransaction {
+		return ynab.SaveTransaction{
+			AccountID:  accountID,
+			Date:       date,
+			Amount:     amount,
+			PayeeName:  payee,
+			CategoryID: categories[category],
+			Cleared:    ynab.ClearedStatusCleared,
+			Approved:   approved,
+			ImportID:   fmt.Sprintf("YNAB:%d:%s:1", amount, date),
+		}
+	}
+
+	_, err = s.AddTransactions(id,
+		txn(checking.ID, day(1), -950_000, "Hausverwaltung Schmidt", "Rent", true),
+		txn(checking.ID, day(3), -39_990, "Telekom", "Internet", true),
+		txn(checking.ID, day(5), -62_340, "REWE", "Groceries", true),
+		txn(checking.ID, day(12), -48_150, "EDEKA", "Groceries", true),
+		txn(checking.ID, day(15), -86_000, "Allianz", "Insurance", true),
+		txn(card.ID, day(8), -24_500, "Vapiano", "Dining Out", true),
+		txn(card.ID, day(19), -89_000, "Deutsche Bahn", "Transport", false),
+		txn(card.ID, day(22), -312_450, "Booking.com", "", false),
+		txn(card.ID, day(24), -17_990, "Amazon", "", false),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Settle the credit card from the checking account
+	_, err = s.AddTransactions(id, ynab.SaveTransaction{
+		AccountID: checking.ID,
+		Date:      day(28),
+		Amount:    -113_500,
+		PayeeID:   card.TransferPayeeID,
+		Memo:      "Kreditkartenabrechnung",
+		Cleared:   ynab.ClearedStatusCleared,
+		Approved:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
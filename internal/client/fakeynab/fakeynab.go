@@ -0,0 +1,190 @@
+// Package fakeynab is an in-memory fake of the YNAB API for tests and for
+// developing the CLI offline (mp dev fake-ynab).
+//
+// It implements budgets, accounts, transactions, categories and payees with
+// the behavior the client relies on: import_id de-duplication, delta
+// requests with last_knowledge_of_server, transfers between accounts, account
+// balances and a simulated rate limit.
+//
+// Example usage:
+//
+//	fake := fakeynab.New()
+//	budgetID := fake.AddBudget(ynab.BudgetSummary{Name: "Test"})
+//	server := httptest.NewServer(fake)
+//	client, err := ynab.NewClient(ynab.Config{APIKey: "any", BudgetID: budgetID, BaseURL: server.URL}, logger)
+package fakeynab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+)
+
+// DefaultRateLimitWindow is the rolling window of the YNAB rate limit.
+const DefaultRateLimitWindow = time.Hour
+
+// Server is the fake API. It implements http.Handler and serves the API with
+// or without the /v1 prefix. All methods are safe for concurrent use.
+type Server struct {
+	mux *http.ServeMux
+	now func() time.Time
+
+	mu       sync.Mutex
+	budgets  []*budget
+	token    string
+	limit    int
+	window   time.Duration
+	hits     []time.Time
+	requests int
+	lastID   int
+}
+
+// New creates an empty fake that accepts any bearer token.
+func New() *Server {
+	s := &Server{mux: http.NewServeMux(), now: time.Now, window: DefaultRateLimitWindow}
+	s.routes()
+	return s
+}
+
+// SetToken makes the fake reject requests without this bearer token. An empty
+// token accepts any.
+func (s *Server) SetToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+}
+
+// SetRateLimit allows requests per window and answers 429 beyond that, like
+// the 200 requests per hour of the real API. Zero disables the limit.
+func (s *Server) SetRateLimit(requests int, window time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limit = requests
+	s.window = window
+	s.hits = nil
+}
+
+// SetClock replaces the clock used for the rate limit (for testing).
+func (s *Server) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// RequestCount returns the number of requests served, including rejected ones.
+func (s *Server) RequestCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := s.admit(w, r); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if rest, ok := strings.CutPrefix(r.URL.Path, "/v1/"); ok {
+		r = r.Clone(r.Context())
+		r.URL.Path = "/" + rest
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// admit checks the bearer token and the rate limit.
+func (s *Server) admit(w http.ResponseWriter, r *http.Request) *apiError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" || (s.token != "" && token != s.token) {
+		return &apiError{status: http.StatusUnauthorized, APIError: ynab.APIError{ID: "401", Name: "unauthorized", Detail: "Unauthorized"}}
+	}
+
+	if s.limit <= 0 {
+		return nil
+	}
+	now := s.now()
+	kept := s.hits[:0]
+	for _, hit := range s.hits {
+		if now.Sub(hit) < s.window {
+			kept = append(kept, hit)
+		}
+	}
+	s.hits = kept
+
+	if len(s.hits) >= s.limit {
+		retryAfter := s.window - now.Sub(s.hits[0])
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+		w.Header().Set("X-Rate-Limit", fmt.Sprintf("%d/%d", len(s.hits), s.limit))
+		return &apiError{status: http.StatusTooManyRequests, APIError: ynab.APIError{ID: "429", Name: "too_many_requests", Detail: "Too many requests"}}
+	}
+	s.hits = append(s.hits, now)
+	w.Header().Set("X-Rate-Limit", fmt.Sprintf("%d/%d", len(s.hits), s.limit))
+	return nil
+}
+
+// nextID returns a new unique ID with the given prefix. The caller holds s.mu.
+func (s *Server) nextID(prefix string) string {
+	s.lastID++
+	return fmt.Sprintf("%s-%d", prefix, s.lastID)
+}
+
+// findBudget returns the budget with id; last-used and default select the
+// first budget. The caller holds s.mu.
+func (s *Server) findBudget(id string) (*budget, *apiError) {
+	for _, b := range s.budgets {
+		if b.summary.ID == id {
+			return b, nil
+		}
+	}
+	if (id == ynab.LastUsedBudgetID || id == "default") && len(s.budgets) > 0 {
+		return s.budgets[0], nil
+	}
+	return nil, notFound("budget")
+}
+
+// apiError is an error response of the fake.
+type apiError struct {
+	status int
+	ynab.APIError
+}
+
+func (e *apiError) Error() string {
+	return e.Detail
+}
+
+func badRequest(format string, args ...any) *apiError {
+	return &apiError{status: http.StatusBadRequest, APIError: ynab.APIError{ID: "400", Name: "bad_request", Detail: fmt.Sprintf(format, args...)}}
+}
+
+func notFound(resource string) *apiError {
+	return &apiError{status: http.StatusNotFound, APIError: ynab.APIError{ID: "404.2", Name: "resource_not_found", Detail: resource + " not found"}}
+}
+
+func conflict(format string, args ...any) *apiError {
+	return &apiError{status: http.StatusConflict, APIError: ynab.APIError{ID: "409", Name: "conflict", Detail: fmt.Sprintf(format, args...)}}
+}
+
+func writeError(w http.ResponseWriter, err *apiError) {
+	writeJSON(w, err.status, ynab.ErrorResponse{Error: err.APIError})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// data wraps a response payload like the real API: {"data": ...}.
+func data(v any) map[string]any {
+	return map[string]any{"data": v}
+}
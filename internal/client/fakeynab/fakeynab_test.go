@@ -0,0 +1,328 @@
+package fakeynab
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+// FakeYNABTestSuite exercises the fake through the real YNAB client.
+type FakeYNABTestSuite struct {
+	suite.Suite
+	fake     *Server
+	server   *httptest.Server
+	client   *ynab.Client
+	budgetID string
+	checking ynab.Account
+	card     ynab.Account
+}
+
+func TestFakeYNABTestSuite(t *testing.T) {
+	suite.Run(t, new(FakeYNABTestSuite))
+}
+
+func (s *FakeYNABTestSuite) SetupTest() {
+	s.fake = New()
+	s.budgetID = s.fake.AddBudget(ynab.BudgetSummary{Name: "Test"})
+
+	var err error
+	s.checking, err = s.fake.AddAccount(s.budgetID, ynab.SaveAccount{Name: "Checking", Type: ynab.AccountTypeChecking, Balance: 100_000})
+	s.Require().NoError(err)
+	s.card, err = s.fake.AddAccount(s.budgetID, ynab.SaveAccount{Name: "Card", Type: ynab.AccountTypeCreditCard})
+	s.Require().NoError(err)
+
+	s.server = httptest.NewServer(s.fake)
+	s.client = s.newClient("any-token")
+}
+
+func (s *FakeYNABTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *FakeYNABTestSuite) newClient(token string) *ynab.Client {
+	client, err := ynab.NewClient(ynab.Config{APIKey: token, BudgetID: s.budgetID, BaseURL: s.server.URL + "/v1"}, zap.NewNop().Sugar())
+	s.Require().NoError(err)
+	return client
+}
+
+func (s *FakeYNABTestSuite) save(importID string, amount int64) ynab.SaveTransaction {
+	return ynab.SaveTransaction{AccountID: s.checking.ID, Date: "2024-03-01", Amount: amount, PayeeName: "REWE", ImportID: importID}
+}
+
+func (s *FakeYNABTestSuite) TestGetBudgets_IncludesAccountsAndStartingBalance() {
+	// Act
+	budgets, err := s.client.GetBudgets(true)
+	accounts, accErr := s.client.GetAccounts()
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().NoError(accErr)
+	s.Require().Len(budgets, 1)
+	s.Len(budgets[0].Accounts, 2)
+	s.Equal(int64(100_000), accounts[0].Balance)
+	s.Equal(int64(100_000), accounts[0].ClearedBalance)
+	s.NotEmpty(accounts[0].TransferPayeeID)
+}
+
+func (s *FakeYNABTestSuite) TestCreateTransactions_SkipsDuplicateImportIDs() {
+	// Arrange
+	_, err := s.client.CreateTransactions([]ynab.SaveTransaction{s.save("YNAB:-1000:2024-03-01:1", -1000)})
+	s.Require().NoError(err)
+
+	// Act
+	resp, err := s.client.CreateTransactions([]ynab.SaveTransaction{
+		s.save("YNAB:-1000:2024-03-01:1", -1000),
+		s.save("YNAB:-2000:2024-03-01:1", -2000),
+		s.save("YNAB:-2000:2024-03-01:1", -2000),
+	})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Len(resp.Data.TransactionIDs, 1)
+	s.Equal([]string{"YNAB:-1000:2024-03-01:1", "YNAB:-2000:2024-03-01:1"}, resp.Data.DuplicateImportIDs)
+	s.Len(s.fake.Transactions(s.budgetID), 3)
+}
+
+func (s *FakeYNABTestSuite) TestCreateTransaction_WithDuplicateImportID_ReturnsConflict() {
+	// Arrange
+	_, err := s.client.CreateTransaction(s.save("dup", -1000))
+	s.Require().NoError(err)
+
+	// Act
+	_, err = s.client.CreateTransaction(s.save("dup", -1000))
+
+	// Assert
+	s.ErrorIs(err, ynab.ErrConflict)
+}
+
+func (s *FakeYNABTestSuite) TestCreateTransactions_WithInvalidTransaction_CreatesNothing() {
+	// Arrange
+	bad := s.save("", -1000)
+	bad.Date = "01.03.2024"
+
+	// Act
+	_, err := s.client.CreateTransactions([]ynab.SaveTransaction{s.save("ok", -500), bad})
+
+	// Assert
+	s.ErrorIs(err, ynab.ErrBadRequest)
+	s.Len(s.fake.Transactions(s.budgetID), 1)
+}
+
+func (s *FakeYNABTestSuite) TestGetTransactions_WithServerKnowledge_ReturnsOnlyChanges() {
+	// Arrange
+	created, err := s.client.CreateTransaction(s.save("", -1000))
+	s.Require().NoError(err)
+	knowledge := created.Data.ServerKnowledge
+	_, err = s.client.CreateTransaction(s.save("", -2000))
+	s.Require().NoError(err)
+	_, err = s.client.DeleteTransaction(created.Data.TransactionIDs[0])
+	s.Require().NoError(err)
+
+	// Act
+	delta, err := s.client.GetTransactions(ynab.TransactionOptions{LastKnowledgeOfServer: knowledge})
+	all, allErr := s.client.GetTransactions(ynab.TransactionOptions{})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().NoError(allErr)
+	s.Len(delta, 2)
+	s.True(delta[0].Deleted)
+	s.Len(all, 2) // starting balance and the remaining transaction
+}
+
+func (s *FakeYNABTestSuite) TestCreateTransaction_ToTransferPayee_CreatesCounterpart() {
+	// Arrange
+	transfer := ynab.SaveTransaction{AccountID: s.checking.ID, Date: "2024-03-28", Amount: -40_000, PayeeID: s.card.TransferPayeeID}
+
+	// Act
+	resp, err := s.client.CreateTransaction(transfer)
+
+	// Assert
+	s.Require().NoError(err)
+	t := resp.Data.Transaction
+	s.Equal(s.card.ID, t.TransferAccountID)
+	cardTxns, err := s.client.GetTransactionsByAccount(s.card.ID, ynab.TransactionOptions{})
+	s.Require().NoError(err)
+	s.Require().Len(cardTxns, 1)
+	s.Equal(int64(40_000), cardTxns[0].Amount)
+	s.Equal(t.ID, cardTxns[0].TransferTransactionID)
+
+	accounts := s.fake.Accounts(s.budgetID)
+	s.Equal(int64(60_000), accounts[0].Balance)
+	s.Equal(int64(40_000), accounts[1].Balance)
+}
+
+func (s *FakeYNABTestSuite) TestUpdateTransactions_WithBothTransferLegs_KeepsCounterpart() {
+	// Arrange
+	resp, err := s.client.CreateTransaction(ynab.SaveTransaction{AccountID: s.checking.ID, Date: "2024-03-28", Amount: -40_000, PayeeID: s.card.TransferPayeeID})
+	s.Require().NoError(err)
+	outflow := resp.Data.Transaction
+
+	// Act
+	updated, err := s.client.UpdateTransactions([]ynab.SaveTransactionWithID{
+		{ID: outflow.ID, Memo: ynab.Ptr("Abrechnung")},
+		{ID: outflow.TransferTransactionID, Memo: ynab.Ptr("Zahlung")},
+	})
+	_, amountErr := s.client.UpdateTransactions([]ynab.SaveTransactionWithID{{ID: outflow.ID, Amount: ynab.Ptr(int64(-45_000))}})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().NoError(amountErr)
+	s.Require().Len(updated.Data.Transactions, 2)
+	s.Equal(outflow.TransferTransactionID, updated.Data.Transactions[0].TransferTransactionID)
+	s.Equal("Zahlung", updated.Data.Transactions[1].Memo)
+	cardTxns, err := s.client.GetTransactionsByAccount(s.card.ID, ynab.TransactionOptions{})
+	s.Require().NoError(err)
+	s.Require().Len(cardTxns, 1)
+	s.Equal(int64(45_000), cardTxns[0].Amount)
+	accounts := s.fake.Accounts(s.budgetID)
+	s.Equal(int64(55_000), accounts[0].Balance)
+	s.Equal(int64(45_000), accounts[1].Balance)
+}
+
+func (s *FakeYNABTestSuite) TestUpdateTransactions_ByImportID_UpdatesCategory() {
+	// Arrange
+	category, err := s.fake.AddCategory(s.budgetID, "Everyday", "Groceries")
+	s.Require().NoError(err)
+	_, err = s.client.CreateTransaction(s.save("imp-1", -5000))
+	s.Require().NoError(err)
+	update := ynab.SaveTransactionWithID{ImportID: "imp-1", CategoryID: ynab.Ptr(category.ID), Approved: ynab.Ptr(true)}
+
+	// Act
+	updated, err := s.client.UpdateTransactions([]ynab.SaveTransactionWithID{update})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(updated.Data.Transactions, 1)
+	s.Equal("Groceries", updated.Data.Transactions[0].CategoryName)
+	s.Equal("REWE", updated.Data.Transactions[0].PayeeName)
+	groups, err := s.client.GetCategories()
+	s.Require().NoError(err)
+	for _, c := range ynab.ActiveCategories(groups) {
+		if c.ID == category.ID {
+			s.Equal(int64(-5000), c.Activity)
+		}
+	}
+}
+
+func (s *FakeYNABTestSuite) TestUpdateTransactions_WithEmptyValues_ClearsFields() {
+	// Arrange
+	flagged := s.save("imp-1", -5000)
+	flagged.Memo = "Wocheneinkauf"
+	flagged.FlagColor = "red"
+	flagged.Approved = true
+	resp, err := s.client.CreateTransaction(flagged)
+	s.Require().NoError(err)
+
+	// Act
+	updated, err := s.client.UpdateTransactions([]ynab.SaveTransactionWithID{{
+		ID:        resp.Data.Transaction.ID,
+		Memo:      ynab.Ptr(""),
+		FlagColor: ynab.Ptr(""),
+		Approved:  ynab.Ptr(false),
+	}})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(updated.Data.Transactions, 1)
+	t := updated.Data.Transactions[0]
+	s.Empty(t.Memo)
+	s.Empty(t.FlagColor)
+	s.False(t.Approved)
+	s.Equal("REWE", t.PayeeName, "fields missing from the update are kept")
+}
+
+func (s *FakeYNABTestSuite) TestCreateTransaction_WithUnbalancedSplit_ReturnsBadRequest() {
+	// Arrange
+	split := s.save("", -1000)
+	split.Subtransactions = []ynab.SaveSubTransaction{{Amount: -400}, {Amount: -500}}
+
+	// Act
+	_, err := s.client.CreateTransaction(split)
+
+	// Assert
+	s.ErrorIs(err, ynab.ErrBadRequest)
+}
+
+func (s *FakeYNABTestSuite) TestGetPayees_CreatesNamedPayeesAndSupportsDeltas() {
+	// Arrange
+	payees, err := s.client.GetPayees()
+	s.Require().NoError(err)
+
+	// Act
+	_, err = s.client.CreateTransaction(s.save("", -1000))
+	s.Require().NoError(err)
+	after, err := s.client.GetPayees()
+
+	// Assert
+	s.Require().NoError(err)
+	s.Len(after, len(payees)+1)
+	s.Equal("REWE", after[len(after)-1].Name)
+}
+
+func (s *FakeYNABTestSuite) TestWrongToken_ReturnsUnauthorized() {
+	// Arrange
+	s.fake.SetToken("secret")
+
+	// Act
+	_, err := s.newClient("wrong").GetAccounts()
+	_, okErr := s.newClient("secret").GetAccounts()
+
+	// Assert
+	s.ErrorIs(err, ynab.ErrUnauthorized)
+	s.NoError(okErr)
+}
+
+func (s *FakeYNABTestSuite) TestRateLimit_RejectsRequestsBeyondTheWindow() {
+	// Arrange
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.fake.SetClock(func() time.Time { return now })
+	s.fake.SetRateLimit(2, time.Minute)
+	get := func() *http.Response {
+		req, err := http.NewRequest(http.MethodGet, s.server.URL+"/v1/budgets", nil)
+		s.Require().NoError(err)
+		req.Header.Set("Authorization", "Bearer token")
+		resp, err := http.DefaultClient.Do(req)
+		s.Require().NoError(err)
+		resp.Body.Close()
+		return resp
+	}
+
+	// Act
+	first, second, third := get(), get(), get()
+	now = now.Add(time.Minute)
+	later := get()
+
+	// Assert
+	s.Equal(http.StatusOK, first.StatusCode)
+	s.Equal("2/2", second.Header.Get("X-Rate-Limit"))
+	s.Equal(http.StatusTooManyRequests, third.StatusCode)
+	s.Equal("61", third.Header.Get("Retry-After"))
+	s.Equal(http.StatusOK, later.StatusCode)
+	s.Equal(4, s.fake.RequestCount())
+}
+
+func (s *FakeYNABTestSuite) TestNewDemo_HasTransactionsToReview() {
+	// Arrange
+	demo, err := NewDemo()
+	s.Require().NoError(err)
+	server := httptest.NewServer(demo)
+	defer server.Close()
+	client, err := ynab.NewClient(ynab.Config{APIKey: "demo", BudgetID: ynab.LastUsedBudgetID, BaseURL: server.URL}, zap.NewNop().Sugar())
+	s.Require().NoError(err)
+
+	// Act
+	unapproved, err := client.GetTransactions(ynab.TransactionOptions{Type: ynab.TransactionTypeUnapproved})
+	uncategorized, uncatErr := client.GetTransactions(ynab.TransactionOptions{Type: ynab.TransactionTypeUncategorized})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().NoError(uncatErr)
+	s.Len(unapproved, 4) // three purchases and the settlement on the card
+	s.Len(uncategorized, 2)
+}
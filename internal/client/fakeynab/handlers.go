@@ -0,0 +1,386 @@
+package fakeynab
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+)
+
+// routes registers the implemented endpoints.
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /budgets", s.getBudgets)
+	s.mux.HandleFunc("GET /budgets/{budget}/settings", s.withBudget(s.getSettings))
+	s.mux.HandleFunc("GET /budgets/{budget}/accounts", s.withBudget(s.getAccounts))
+	s.mux.HandleFunc("POST /budgets/{budget}/accounts", s.withBudget(s.postAccount))
+	s.mux.HandleFunc("GET /budgets/{budget}/accounts/{account}/transactions", s.withBudget(s.getTransactions))
+	s.mux.HandleFunc("GET /budgets/{budget}/categories", s.withBudget(s.getCategories))
+	s.mux.HandleFunc("GET /budgets/{budget}/payees", s.withBudget(s.getPayees))
+	s.mux.HandleFunc("GET /budgets/{budget}/transactions", s.withBudget(s.getTransactions))
+	s.mux.HandleFunc("POST /budgets/{budget}/transactions", s.withBudget(s.postTransactions))
+	s.mux.HandleFunc("PATCH /budgets/{budget}/transactions", s.withBudget(s.patchTransactions))
+	s.mux.HandleFunc("GET /budgets/{budget}/transactions/{transaction}", s.withBudget(s.getTransaction))
+	s.mux.HandleFunc("PUT /budgets/{budget}/transactions/{transaction}", s.withBudget(s.putTransaction))
+	s.mux.HandleFunc("DELETE /budgets/{budget}/transactions/{transaction}", s.withBudget(s.deleteTransactionHandler))
+}
+
+// budgetHandler handles a request for one budget while s.mu is held.
+type budgetHandler func(w http.ResponseWriter, r *http.Request, b *budget) *apiError
+
+// withBudget locks the server, looks up the budget of the path and writes
+// the error of h.
+func (s *Server) withBudget(h budgetHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		b, err := s.findBudget(r.PathValue("budget"))
+		if err == nil {
+			err = h(w, r, b)
+		}
+		if err != nil {
+			writeError(w, err)
+		}
+	}
+}
+
+func (s *Server) getBudgets(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	includeAccounts := r.URL.Query().Get("include_accounts") == "true"
+	budgets := make([]ynab.BudgetSummary, 0, len(s.budgets))
+	for _, b := range s.budgets {
+		summary := b.summary
+		if includeAccounts {
+			summary.Accounts = since(b.accounts, 0, func(a ynab.Account) bool { return a.Deleted })
+		}
+		budgets = append(budgets, summary)
+	}
+	writeJSON(w, http.StatusOK, data(map[string]any{"budgets": budgets}))
+}
+
+func (s *Server) getSettings(w http.ResponseWriter, _ *http.Request, b *budget) *apiError {
+	settings := ynab.BudgetSettings{DateFormat: b.summary.DateFormat, CurrencyFormat: b.summary.CurrencyFormat}
+	writeJSON(w, http.StatusOK, data(map[string]any{"settings": settings}))
+	return nil
+}
+
+func (s *Server) getAccounts(w http.ResponseWriter, r *http.Request, b *budget) *apiError {
+	knowledge, err := lastKnowledge(r)
+	if err != nil {
+		return err
+	}
+	accounts := since(b.accounts, knowledge, func(a ynab.Account) bool { return a.Deleted })
+	writeJSON(w, http.StatusOK, data(map[string]any{"accounts": accounts, "server_knowledge": b.knowledge}))
+	return nil
+}
+
+func (s *Server) postAccount(w http.ResponseWriter, r *http.Request, b *budget) *apiError {
+	var req ynab.SaveAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return badRequest("invalid request body: %v", err)
+	}
+	acc, err := s.createAccount(b, req.Account)
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusCreated, data(map[string]any{"account": acc, "server_knowledge": b.knowledge}))
+	return nil
+}
+
+func (s *Server) getCategories(w http.ResponseWriter, r *http.Request, b *budget) *apiError {
+	knowledge, err := lastKnowledge(r)
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, data(map[string]any{"category_groups": b.categoryGroups(knowledge), "server_knowledge": b.knowledge}))
+	return nil
+}
+
+func (s *Server) getPayees(w http.ResponseWriter, r *http.Request, b *budget) *apiError {
+	knowledge, err := lastKnowledge(r)
+	if err != nil {
+		return err
+	}
+	payees := since(b.payees, knowledge, func(p ynab.Payee) bool { return p.Deleted })
+	writeJSON(w, http.StatusOK, data(map[string]any{"payees": payees, "server_knowledge": b.knowledge}))
+	return nil
+}
+
+// getTransactions lists the transactions of the budget or of one account,
+// filtered by since_date and type.
+func (s *Server) getTransactions(w http.ResponseWriter, r *http.Request, b *budget) *apiError {
+	accountID := r.PathValue("account")
+	if accountID != "" && b.account(accountID) == nil {
+		return notFound("account")
+	}
+
+	knowledge, err := lastKnowledge(r)
+	if err != nil {
+		return err
+	}
+	q := r.URL.Query()
+	sinceDate := q.Get("since_date")
+	if sinceDate != "" {
+		if _, err := time.Parse("2006-01-02", sinceDate); err != nil {
+			return badRequest("since_date %q is not a valid ISO date", sinceDate)
+		}
+	}
+	kind := q.Get("type")
+	if kind != "" && kind != ynab.TransactionTypeUnapproved && kind != ynab.TransactionTypeUncategorized {
+		return badRequest("invalid type %q", kind)
+	}
+
+	transactions := []ynab.Transaction{}
+	for _, t := range since(b.transactions, knowledge, isDeletedTransaction) {
+		switch {
+		case accountID != "" && t.AccountID != accountID:
+		case sinceDate != "" && t.Date < sinceDate:
+		case kind == ynab.TransactionTypeUnapproved && t.Approved:
+		case kind == ynab.TransactionTypeUncategorized && (t.CategoryID != "" || t.TransferAccountID != "" || len(t.Subtransactions) > 0):
+		default:
+			transactions = append(transactions, t)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, data(map[string]any{"transactions": sortedTransactions(transactions), "server_knowledge": b.knowledge}))
+	return nil
+}
+
+// postTransactions creates one or several transactions. Transactions whose
+// import_id already exists in their account are skipped and reported in
+// duplicate_import_ids; for a single transaction that is a conflict.
+func (s *Server) postTransactions(w http.ResponseWriter, r *http.Request, b *budget) *apiError {
+	var req ynab.SaveTransactionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return badRequest("invalid request body: %v", err)
+	}
+	single := req.Transaction != nil
+	saves := req.Transactions
+	if single {
+		saves = []ynab.SaveTransaction{*req.Transaction}
+	}
+	if len(saves) == 0 {
+		return badRequest("transaction or transactions is required")
+	}
+
+	// Validate everything first so a bad transaction creates nothing
+	for _, save := range saves {
+		if _, err := s.resolve(b, ynab.Transaction{}, save); err != nil {
+			return err
+		}
+	}
+
+	k := b.touch(s.now())
+	created := []ynab.Transaction{}
+	ids := []string{}
+	duplicates := []string{}
+	seen := make(map[string]bool)
+	for _, save := range saves {
+		if save.ImportID != "" {
+			key := save.AccountID + "\x00" + save.ImportID
+			if seen[key] || b.transactionByImportID(save.AccountID, save.ImportID) != nil {
+				duplicates = append(duplicates, save.ImportID)
+				continue
+			}
+			seen[key] = true
+		}
+		t, err := s.insertTransaction(b, save, k)
+		if err != nil {
+			return err
+		}
+		created = append(created, t)
+		ids = append(ids, t.ID)
+	}
+
+	payload := map[string]any{"transaction_ids": ids, "duplicate_import_ids": duplicates, "server_knowledge": b.knowledge}
+	if single {
+		if len(created) == 0 {
+			return conflict("a transaction with import_id %s already exists in the account", saves[0].ImportID)
+		}
+		payload["transaction"] = created[0]
+	} else {
+		payload["transactions"] = created
+	}
+	writeJSON(w, http.StatusCreated, data(payload))
+	return nil
+}
+
+func (s *Server) getTransaction(w http.ResponseWriter, r *http.Request, b *budget) *apiError {
+	rec := b.transaction(r.PathValue("transaction"))
+	if rec == nil {
+		return notFound("transaction")
+	}
+	writeJSON(w, http.StatusOK, data(map[string]any{"transaction": rec.value, "server_knowledge": b.knowledge}))
+	return nil
+}
+
+// putTransaction updates one transaction. Fields missing from the body keep
+// their current values.
+func (s *Server) putTransaction(w http.ResponseWriter, r *http.Request, b *budget) *apiError {
+	rec := b.transaction(r.PathValue("transaction"))
+	if rec == nil || rec.value.Deleted {
+		return notFound("transaction")
+	}
+
+	var req struct {
+		Transaction json.RawMessage `json:"transaction"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Transaction == nil {
+		return badRequest("transaction is required")
+	}
+	save, err := merge(rec.value, req.Transaction)
+	if err != nil {
+		return err
+	}
+	if _, err := s.resolve(b, ynab.Transaction{}, save); err != nil {
+		return err
+	}
+
+	t, err := s.replaceTransaction(b, rec, save, b.touch(s.now()))
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, data(map[string]any{"transaction": t, "server_knowledge": b.knowledge}))
+	return nil
+}
+
+// patchTransactions updates several transactions identified by id or import_id.
+func (s *Server) patchTransactions(w http.ResponseWriter, r *http.Request, b *budget) *apiError {
+	var req struct {
+		Transactions []json.RawMessage `json:"transactions"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Transactions) == 0 {
+		return badRequest("transactions is required")
+	}
+
+	type update struct {
+		rec  *record[ynab.Transaction]
+		save ynab.SaveTransaction
+	}
+	updates := make([]update, 0, len(req.Transactions))
+	for _, raw := range req.Transactions {
+		var key struct {
+			ID       string `json:"id"`
+			ImportID string `json:"import_id"`
+		}
+		if err := json.Unmarshal(raw, &key); err != nil {
+			return badRequest("invalid transaction: %v", err)
+		}
+
+		var rec *record[ynab.Transaction]
+		switch {
+		case key.ID != "":
+			rec = b.transaction(key.ID)
+		case key.ImportID != "":
+			for _, t := range b.transactions {
+				if t.value.ImportID == key.ImportID && !t.value.Deleted {
+					rec = t
+				}
+			}
+		default:
+			return badRequest("id or import_id is required")
+		}
+		if rec == nil || rec.value.Deleted {
+			return badRequest("transaction %s does not exist", key.ID+key.ImportID)
+		}
+
+		save, err := merge(rec.value, raw)
+		if err != nil {
+			return err
+		}
+		if _, err := s.resolve(b, ynab.Transaction{}, save); err != nil {
+			return err
+		}
+		updates = append(updates, update{rec: rec, save: save})
+	}
+
+	k := b.touch(s.now())
+	ids := []string{}
+	updated := []ynab.Transaction{}
+	for _, u := range updates {
+		t, err := s.replaceTransaction(b, u.rec, u.save, k)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, t.ID)
+		updated = append(updated, t)
+	}
+
+	// The API answers bulk updates with 209 Saved
+	writeJSON(w, 209, data(map[string]any{"transaction_ids": ids, "transactions": updated, "server_knowledge": b.knowledge}))
+	return nil
+}
+
+func (s *Server) deleteTransactionHandler(w http.ResponseWriter, r *http.Request, b *budget) *apiError {
+	rec := b.transaction(r.PathValue("transaction"))
+	if rec == nil || rec.value.Deleted {
+		return notFound("transaction")
+	}
+	t := s.deleteTransaction(b, rec, b.touch(s.now()))
+	writeJSON(w, http.StatusOK, data(map[string]any{"transaction": t, "server_knowledge": b.knowledge}))
+	return nil
+}
+
+// merge applies the fields present in raw to the current values of t.
+func merge(t ynab.Transaction, raw json.RawMessage) (ynab.SaveTransaction, *apiError) {
+	var present map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &present); err != nil {
+		return ynab.SaveTransaction{}, badRequest("invalid transaction: %v", err)
+	}
+
+	save := t.ToSaveTransaction()
+	for _, sub := range t.Subtransactions {
+		if !sub.Deleted {
+			save.Subtransactions = append(save.Subtransactions, ynab.SaveSubTransaction{Amount: sub.Amount, PayeeID: sub.PayeeID, CategoryID: sub.CategoryID, Memo: sub.Memo})
+		}
+	}
+	if _, ok := present["payee_name"]; ok {
+		if _, ok := present["payee_id"]; !ok {
+			save.PayeeID = ""
+		}
+	}
+	if _, ok := present["subtransactions"]; ok {
+		save.Subtransactions = nil
+	}
+
+	if err := json.Unmarshal(raw, &save); err != nil {
+		return ynab.SaveTransaction{}, badRequest("invalid transaction: %v", err)
+	}
+
+	// null clears a field; json.Unmarshal leaves it unchanged
+	for key, value := range present {
+		if string(value) != "null" {
+			continue
+		}
+		switch key {
+		case "payee_id":
+			save.PayeeID = ""
+		case "payee_name":
+			save.PayeeName = ""
+		case "category_id":
+			save.CategoryID = ""
+		case "memo":
+			save.Memo = ""
+		case "flag_color":
+			save.FlagColor = ""
+		}
+	}
+	return save, nil
+}
+
+// lastKnowledge parses the last_knowledge_of_server query parameter.
+func lastKnowledge(r *http.Request) (int64, *apiError) {
+	value := r.URL.Query().Get("last_knowledge_of_server")
+	if value == "" {
+		return 0, nil
+	}
+	knowledge, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || knowledge < 0 {
+		return 0, badRequest("invalid last_knowledge_of_server %q", value)
+	}
+	return knowledge, nil
+}
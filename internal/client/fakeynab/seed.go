@@ -0,0 +1,141 @@
+package fakeynab
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+)
+
+// AddBudget adds a budget and returns its ID. An empty ID is generated, and
+// the budget gets the inflow category and the starting balance payee.
+func (s *Server) AddBudget(summary ynab.BudgetSummary) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if summary.ID == "" {
+		summary.ID = s.nextID("budget")
+	}
+	if summary.DateFormat == nil {
+		summary.DateFormat = &ynab.DateFormat{Format: "YYYY-MM-DD"}
+	}
+	if summary.CurrencyFormat == nil {
+		summary.CurrencyFormat = &ynab.CurrencyFormat{ISOCode: "EUR", DecimalDigits: 2, DecimalSeparator: ",", GroupSeparator: ".", CurrencySymbol: "€", DisplaySymbol: true}
+	}
+
+	b := &budget{summary: summary}
+	k := b.touch(s.now())
+	s.addCategory(b, InflowGroupName, InflowCategoryName, k)
+	s.addPayee(b, StartingBalanceName, k)
+	s.budgets = append(s.budgets, b)
+	return summary.ID
+}
+
+// AddAccount creates an account like POST /budgets/{id}/accounts.
+func (s *Server) AddAccount(budgetID string, account ynab.SaveAccount) (ynab.Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, apiErr := s.findBudget(budgetID)
+	if apiErr != nil {
+		return ynab.Account{}, apiErr
+	}
+	acc, apiErr := s.createAccount(b, account)
+	if apiErr != nil {
+		return ynab.Account{}, apiErr
+	}
+	return acc, nil
+}
+
+// AddCategory creates a category in the named group, creating the group when needed.
+func (s *Server) AddCategory(budgetID, group, name string) (ynab.Category, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, apiErr := s.findBudget(budgetID)
+	if apiErr != nil {
+		return ynab.Category{}, apiErr
+	}
+	return s.addCategory(b, group, name, b.touch(s.now())), nil
+}
+
+// AddPayee creates a payee, or returns the existing one with that name.
+func (s *Server) AddPayee(budgetID, name string) (ynab.Payee, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, apiErr := s.findBudget(budgetID)
+	if apiErr != nil {
+		return ynab.Payee{}, apiErr
+	}
+	return s.addPayee(b, name, b.touch(s.now())), nil
+}
+
+// AddTransactions creates transactions like POST /budgets/{id}/transactions,
+// except that a duplicate import_id is an error.
+func (s *Server) AddTransactions(budgetID string, transactions ...ynab.SaveTransaction) ([]ynab.Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, apiErr := s.findBudget(budgetID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	k := b.touch(s.now())
+	created := make([]ynab.Transaction, 0, len(transactions))
+	for i, save := range transactions {
+		if save.ImportID != "" && b.transactionByImportID(save.AccountID, save.ImportID) != nil {
+			return created, fmt.Errorf("transaction %d: duplicate import_id %s", i, save.ImportID)
+		}
+		t, apiErr := s.insertTransaction(b, save, k)
+		if apiErr != nil {
+			return created, fmt.Errorf("transaction %d: %w", i, apiErr)
+		}
+		created = append(created, t)
+	}
+	return created, nil
+}
+
+// Transactions returns the transactions of the budget that are not deleted,
+// ordered by date.
+func (s *Server) Transactions(budgetID string) []ynab.Transaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, apiErr := s.findBudget(budgetID)
+	if apiErr != nil {
+		return nil
+	}
+	return sortedTransactions(since(b.transactions, 0, isDeletedTransaction))
+}
+
+// Accounts returns the accounts of the budget that are not deleted.
+func (s *Server) Accounts(budgetID string) []ynab.Account {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, apiErr := s.findBudget(budgetID)
+	if apiErr != nil {
+		return nil
+	}
+	return since(b.accounts, 0, func(a ynab.Account) bool { return a.Deleted })
+}
+
+func isDeletedTransaction(t ynab.Transaction) bool {
+	return t.Deleted
+}
+
+// sortedTransactions orders transactions by date like the API, oldest first.
+func sortedTransactions(transactions []ynab.Transaction) []ynab.Transaction {
+	slices.SortStableFunc(transactions, func(a, b ynab.Transaction) int {
+		if a.Date < b.Date {
+			return -1
+		}
+		if a.Date > b.Date {
+			return 1
+		}
+		return 0
+	})
+	return transactions
+}
@@ -0,0 +1,75 @@
+package ynab
+
+// The interfaces below split the client by concern, so code can depend on
+// the calls it makes and callers can substitute their own implementation,
+// e.g. the CachingClient or ReadOnlyClient decorators or a test fake.
+// *Client satisfies all of them.
+
+// BudgetReader reads the budgets of the token owner and the settings of the
+// configured budget.
+type BudgetReader interface {
+	// BudgetID returns the configured budget ID.
+	BudgetID() string
+	GetBudgets(includeAccounts bool) ([]BudgetSummary, error)
+	GetBudgetSettings() (*BudgetSettings, error)
+}
+
+// AccountReader reads the accounts of the configured budget.
+type AccountReader interface {
+	GetAccounts() ([]Account, error)
+}
+
+// AccountWriter creates accounts in the configured budget.
+type AccountWriter interface {
+	CreateAccount(account SaveAccount) (*Account, error)
+}
+
+// CategoryReader reads the category groups of the configured budget.
+type CategoryReader interface {
+	GetCategories() ([]CategoryGroup, error)
+}
+
+// PayeeReader reads the payees of the configured budget.
+type PayeeReader interface {
+	GetPayees() ([]Payee, error)
+}
+
+// TransactionReader reads transactions of the configured budget.
+type TransactionReader interface {
+	GetTransactions(opts TransactionOptions) ([]Transaction, error)
+	GetTransactionsByAccount(accountID string, opts TransactionOptions) ([]Transaction, error)
+	GetTransaction(transactionID string) (*Transaction, error)
+}
+
+// TransactionWriter creates, updates and deletes transactions of the
+// configured budget.
+type TransactionWriter interface {
+	CreateTransaction(transaction SaveTransaction) (*SaveTransactionsResponse, error)
+	CreateTransactions(transactions []SaveTransaction) (*SaveTransactionsResponse, error)
+	UpdateTransaction(transactionID string, transaction SaveTransaction) (*Transaction, error)
+	UpdateTransactions(transactions []SaveTransactionWithID) (*SaveTransactionsResponse, error)
+	DeleteTransaction(transactionID string) (*Transaction, error)
+}
+
+// Reader combines all read calls.
+type Reader interface {
+	BudgetReader
+	AccountReader
+	CategoryReader
+	PayeeReader
+	TransactionReader
+}
+
+// Writer combines all mutating calls.
+type Writer interface {
+	AccountWriter
+	TransactionWriter
+}
+
+// API is the complete YNAB API used by moneypenny.
+type API interface {
+	Reader
+	Writer
+}
+
+var _ API = (*Client)(nil)
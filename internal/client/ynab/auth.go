@@ -0,0 +1,66 @@
+package ynab
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// TokenSource supplies OAuth access tokens, e.g. an *oauth.Source.
+type TokenSource interface {
+	// Token returns the current access token.
+	Token() (string, error)
+	// Refresh obtains a new access token after the API rejected the current one.
+	Refresh() (string, error)
+}
+
+// tokenTransport sets the bearer token from a TokenSource on every request.
+// When the API answers 401 it refreshes the token once and replays the
+// request, so expired OAuth tokens are renewed transparently.
+type tokenTransport struct {
+	base   http.RoundTripper
+	source TokenSource
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token()
+	if err != nil {
+		return nil, fmt.Errorf("getting access token: %w", err)
+	}
+
+	resp, err := t.base.RoundTrip(withBearer(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	// The body was already sent and cannot be replayed
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	token, refreshErr := t.source.Refresh()
+	if refreshErr != nil {
+		// Keep the 401 so the caller reports ErrUnauthorized
+		return resp, nil
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	retry := withBearer(req, token)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("replaying request body: %w", err)
+		}
+		retry.Body = body
+	}
+	return t.base.RoundTrip(retry)
+}
+
+// withBearer returns a copy of req authorized with token; round trippers
+// must not modify the original request.
+func withBearer(req *http.Request, token string) *http.Request {
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "Bearer "+token)
+	return r
+}
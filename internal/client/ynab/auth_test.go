@@ -0,0 +1,120 @@
+package ynab
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeTokenSource hands out "old" until it is refreshed.
+type fakeTokenSource struct {
+	token      string
+	refreshed  int
+	refreshErr error
+}
+
+func (f *fakeTokenSource) Token() (string, error) {
+	return f.token, nil
+}
+
+func (f *fakeTokenSource) Refresh() (string, error) {
+	f.refreshed++
+	if f.refreshErr != nil {
+		return "", f.refreshErr
+	}
+	f.token = "new"
+	return f.token, nil
+}
+
+// TokenSourceTestSuite groups tests for OAuth token handling.
+type TokenSourceTestSuite struct {
+	suite.Suite
+	server *httptest.Server
+	source *fakeTokenSource
+	client *Client
+}
+
+func TestTokenSourceTestSuite(t *testing.T) {
+	suite.Run(t, new(TokenSourceTestSuite))
+}
+
+func (s *TokenSourceTestSuite) TearDownTest() {
+	if s.server != nil {
+		s.server.Close()
+	}
+}
+
+// setupServerAndClient starts a server that only accepts the "new" token.
+func (s *TokenSourceTestSuite) setupServerAndClient(handler http.HandlerFunc) {
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != "Bearer new" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(ErrorResponse{
+				Error: APIError{ID: "401", Name: "not_authorized", Detail: "Token expired"},
+			})
+			return
+		}
+		handler(w, r)
+	}))
+	s.source = &fakeTokenSource{token: "old"}
+
+	client, err := NewClient(Config{TokenSource: s.source, BudgetID: "test-budget-id", BaseURL: s.server.URL}, &mockLogger{})
+	s.Require().NoError(err)
+	s.client = client
+}
+
+func (s *TokenSourceTestSuite) TestGetAccounts_WithExpiredToken_RefreshesAndRetries() {
+	// Arrange
+	s.setupServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		var response AccountsResponse
+		response.Data.Accounts = []Account{{ID: "acc-1", Name: "Checking"}}
+		_ = json.NewEncoder(w).Encode(response)
+	})
+
+	// Act
+	accounts, err := s.client.GetAccounts()
+
+	// Assert
+	s.NoError(err)
+	s.Len(accounts, 1)
+	s.Equal(1, s.source.refreshed)
+}
+
+func (s *TokenSourceTestSuite) TestCreateAccount_WithExpiredToken_ReplaysBody() {
+	// Arrange
+	var body SaveAccountRequest
+	s.setupServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		s.Require().NoError(json.NewDecoder(r.Body).Decode(&body))
+		var response AccountResponse
+		response.Data.Account = Account{ID: "acc-new", Name: body.Account.Name}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(response)
+	})
+
+	// Act
+	account, err := s.client.CreateAccount(SaveAccount{Name: "Cash", Type: AccountTypeCash})
+
+	// Assert
+	s.NoError(err)
+	s.Equal("acc-new", account.ID)
+	s.Equal("Cash", body.Account.Name)
+}
+
+func (s *TokenSourceTestSuite) TestGetAccounts_WithFailedRefresh_ReturnsUnauthorized() {
+	// Arrange
+	s.setupServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		s.Fail("request must not be authorized")
+	})
+	s.source.refreshErr = errors.New("revoked")
+
+	// Act
+	_, err := s.client.GetAccounts()
+
+	// Assert
+	s.ErrorIs(err, ErrUnauthorized)
+}
@@ -0,0 +1,174 @@
+package ynab
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// CachingClient keeps the responses of read calls in memory and passes
+// mutating calls on to the wrapped API. Every mutating call clears the
+// whole cache, since a transaction change also changes balances, category
+// activity and payees, and a failed call may still have reached the budget.
+// Errors are never cached.
+type CachingClient struct {
+	api API
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value   any
+	expires time.Time
+}
+
+// NewCachingClient wraps api in a CachingClient. Entries expire after ttl;
+// with a zero ttl they are kept until the next mutating call or Invalidate.
+func NewCachingClient(api API, ttl time.Duration) *CachingClient {
+	return &CachingClient{api: api, ttl: ttl, now: time.Now, entries: make(map[string]cacheEntry)}
+}
+
+var _ API = (*CachingClient)(nil)
+
+// Invalidate drops all cached responses, e.g. after the budget was changed
+// outside this client.
+func (c *CachingClient) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
+
+// cached returns the cached value for key, or calls fetch and caches its
+// result. Concurrent misses may fetch twice; the last result wins.
+func cached[T any](c *CachingClient, key string, fetch func() (T, error)) (T, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && (c.ttl == 0 || c.now().Before(entry.expires)) {
+		return entry.value.(T), nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return value, err
+	}
+	c.mu.Lock()
+	c.entries[key] = cacheEntry{value: value, expires: c.now().Add(c.ttl)}
+	c.mu.Unlock()
+	return value, nil
+}
+
+// BudgetID implements BudgetReader.
+func (c *CachingClient) BudgetID() string {
+	return c.api.BudgetID()
+}
+
+// GetBudgets implements BudgetReader.
+func (c *CachingClient) GetBudgets(includeAccounts bool) ([]BudgetSummary, error) {
+	budgets, err := cached(c, fmt.Sprintf("budgets/%t", includeAccounts), func() ([]BudgetSummary, error) {
+		return c.api.GetBudgets(includeAccounts)
+	})
+	return slices.Clone(budgets), err
+}
+
+// GetBudgetSettings implements BudgetReader.
+func (c *CachingClient) GetBudgetSettings() (*BudgetSettings, error) {
+	settings, err := cached(c, "settings", c.api.GetBudgetSettings)
+	if err != nil {
+		return nil, err
+	}
+	copied := *settings
+	return &copied, nil
+}
+
+// GetAccounts implements AccountReader.
+func (c *CachingClient) GetAccounts() ([]Account, error) {
+	accounts, err := cached(c, "accounts", c.api.GetAccounts)
+	return slices.Clone(accounts), err
+}
+
+// GetCategories implements CategoryReader.
+func (c *CachingClient) GetCategories() ([]CategoryGroup, error) {
+	groups, err := cached(c, "categories", c.api.GetCategories)
+	return slices.Clone(groups), err
+}
+
+// GetPayees implements PayeeReader.
+func (c *CachingClient) GetPayees() ([]Payee, error) {
+	payees, err := cached(c, "payees", c.api.GetPayees)
+	return slices.Clone(payees), err
+}
+
+// GetTransactions implements TransactionReader.
+func (c *CachingClient) GetTransactions(opts TransactionOptions) ([]Transaction, error) {
+	transactions, err := cached(c, fmt.Sprintf("transactions/%+v", opts), func() ([]Transaction, error) {
+		return c.api.GetTransactions(opts)
+	})
+	return slices.Clone(transactions), err
+}
+
+// GetTransactionsByAccount implements TransactionReader.
+func (c *CachingClient) GetTransactionsByAccount(accountID string, opts TransactionOptions) ([]Transaction, error) {
+	transactions, err := cached(c, fmt.Sprintf("accounts/%s/transactions/%+v", accountID, opts), func() ([]Transaction, error) {
+		return c.api.GetTransactionsByAccount(accountID, opts)
+	})
+	return slices.Clone(transactions), err
+}
+
+// GetTransaction implements TransactionReader.
+func (c *CachingClient) GetTransaction(transactionID string) (*Transaction, error) {
+	transaction, err := cached(c, "transaction/"+transactionID, func() (*Transaction, error) {
+		return c.api.GetTransaction(transactionID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	copied := *transaction
+	return &copied, nil
+}
+
+// CreateAccount implements AccountWriter.
+func (c *CachingClient) CreateAccount(account SaveAccount) (*Account, error) {
+	created, err := c.api.CreateAccount(account)
+	c.Invalidate()
+	return created, err
+}
+
+// CreateTransaction implements TransactionWriter.
+func (c *CachingClient) CreateTransaction(transaction SaveTransaction) (*SaveTransactionsResponse, error) {
+	resp, err := c.api.CreateTransaction(transaction)
+	c.Invalidate()
+	return resp, err
+}
+
+// CreateTransactions implements TransactionWriter.
+func (c *CachingClient) CreateTransactions(transactions []SaveTransaction) (*SaveTransactionsResponse, error) {
+	resp, err := c.api.CreateTransactions(transactions)
+	c.Invalidate()
+	return resp, err
+}
+
+// UpdateTransaction implements TransactionWriter.
+func (c *CachingClient) UpdateTransaction(transactionID string, transaction SaveTransaction) (*Transaction, error) {
+	updated, err := c.api.UpdateTransaction(transactionID, transaction)
+	c.Invalidate()
+	return updated, err
+}
+
+// UpdateTransactions implements TransactionWriter.
+func (c *CachingClient) UpdateTransactions(transactions []SaveTransactionWithID) (*SaveTransactionsResponse, error) {
+	resp, err := c.api.UpdateTransactions(transactions)
+	c.Invalidate()
+	return resp, err
+}
+
+// DeleteTransaction implements TransactionWriter.
+func (c *CachingClient) DeleteTransaction(transactionID string) (*Transaction, error) {
+	deleted, err := c.api.DeleteTransaction(transactionID)
+	c.Invalidate()
+	return deleted, err
+}
@@ -0,0 +1,126 @@
+package ynab
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// CachingClientTestSuite groups tests of the caching decorator.
+type CachingClientTestSuite struct {
+	suite.Suite
+	server   *httptest.Server
+	requests map[string]int
+	cache    *CachingClient
+}
+
+func TestCachingClientTestSuite(t *testing.T) {
+	suite.Run(t, new(CachingClientTestSuite))
+}
+
+func (s *CachingClientTestSuite) SetupTest() {
+	s.requests = make(map[string]int)
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests[r.Method+" "+r.URL.Path]++
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/budgets/budget-1/accounts":
+			var response AccountsResponse
+			response.Data.Accounts = []Account{{ID: "acc-1", Name: "Checking"}}
+			_ = json.NewEncoder(w).Encode(response)
+		case r.Method == http.MethodGet && r.URL.Path == "/budgets/budget-1/transactions":
+			var response TransactionsResponse
+			response.Data.Transactions = []Transaction{{ID: "txn-1", Amount: -1000}}
+			_ = json.NewEncoder(w).Encode(response)
+		case r.Method == http.MethodPost && r.URL.Path == "/budgets/budget-1/transactions":
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(SaveTransactionsResponse{})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	client, err := NewClient(Config{APIKey: "test-api-key", BudgetID: "budget-1", BaseURL: s.server.URL}, &mockLogger{})
+	s.Require().NoError(err)
+	s.cache = NewCachingClient(client, 0)
+}
+
+func (s *CachingClientTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *CachingClientTestSuite) TestGetAccounts_CallsAPIOnce() {
+	// Act
+	first, err := s.cache.GetAccounts()
+	s.Require().NoError(err)
+	first[0].Name = "modified by the caller"
+	second, err := s.cache.GetAccounts()
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal("Checking", second[0].Name)
+	s.Equal(1, s.requests["GET /budgets/budget-1/accounts"])
+}
+
+func (s *CachingClientTestSuite) TestGetTransactions_CachesPerOptions() {
+	// Act
+	_, err := s.cache.GetTransactions(TransactionOptions{})
+	s.Require().NoError(err)
+	_, err = s.cache.GetTransactions(TransactionOptions{Type: TransactionTypeUnapproved})
+	s.Require().NoError(err)
+	_, err = s.cache.GetTransactions(TransactionOptions{})
+	s.Require().NoError(err)
+
+	// Assert
+	s.Equal(2, s.requests["GET /budgets/budget-1/transactions"])
+}
+
+func (s *CachingClientTestSuite) TestCreateTransactions_InvalidatesCache() {
+	// Arrange
+	_, err := s.cache.GetAccounts()
+	s.Require().NoError(err)
+
+	// Act
+	_, err = s.cache.CreateTransactions([]SaveTransaction{{AccountID: "acc-1", Date: "2024-03-01", Amount: -1000}})
+	s.Require().NoError(err)
+	_, err = s.cache.GetAccounts()
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(2, s.requests["GET /budgets/budget-1/accounts"])
+}
+
+func (s *CachingClientTestSuite) TestGetAccounts_WithExpiredEntry_CallsAPIAgain() {
+	// Arrange
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.cache.ttl = time.Minute
+	s.cache.now = func() time.Time { return now }
+	_, err := s.cache.GetAccounts()
+	s.Require().NoError(err)
+
+	// Act
+	now = now.Add(30 * time.Second)
+	_, err = s.cache.GetAccounts()
+	s.Require().NoError(err)
+	now = now.Add(time.Minute)
+	_, err = s.cache.GetAccounts()
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(2, s.requests["GET /budgets/budget-1/accounts"])
+}
+
+func (s *CachingClientTestSuite) TestGetTransaction_DoesNotCacheErrors() {
+	// Act
+	_, err := s.cache.GetTransaction("missing")
+	_, err2 := s.cache.GetTransaction("missing")
+
+	// Assert
+	s.ErrorIs(err, ErrNotFound)
+	s.ErrorIs(err2, ErrNotFound)
+	s.Equal(2, s.requests["GET /budgets/budget-1/transactions/missing"])
+}
@@ -0,0 +1,343 @@
+package ynab
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pgbytes/moneypenny/internal/storage"
+)
+
+// cassetteVersion is the current cassette file format version.
+const cassetteVersion = 1
+
+// redacted replaces the token in recorded Authorization headers.
+const redacted = "REDACTED"
+
+// ErrNotRecorded is returned in replay mode for a request the cassette does not contain.
+var ErrNotRecorded = errors.New("request not recorded in cassette")
+
+// Cassette is a recording of API requests and responses.
+type Cassette struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request with its response.
+type Interaction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest is a recorded request. URL holds the path and query only,
+// so a cassette replays against any base URL.
+type CassetteRequest struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Header http.Header     `json:"header,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// CassetteResponse is a recorded response.
+type CassetteResponse struct {
+	Status int             `json:"status"`
+	Header http.Header     `json:"header,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// recordedHeaders are the headers kept in cassettes; all others are dropped.
+var recordedHeaders = []string{"Content-Type", "Accept", "Authorization", "Retry-After", "X-Rate-Limit"}
+
+// LoadCassette reads a cassette file.
+func LoadCassette(path string) (*Cassette, error) {
+	var c Cassette
+	found, err := storage.LoadJSON(path, &c)
+	if err != nil {
+		return nil, fmt.Errorf("loading cassette: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("cassette %s does not exist", path)
+	}
+	if c.Version != cassetteVersion {
+		return nil, fmt.Errorf("unsupported cassette version %d", c.Version)
+	}
+	return &c, nil
+}
+
+// BudgetID returns the budget of the first recorded request below
+// /budgets/{id}, or "" when there is none.
+func (c *Cassette) BudgetID() string {
+	for _, in := range c.Interactions {
+		_, rest, ok := strings.Cut(in.Request.URL, "/budgets/")
+		if !ok {
+			continue
+		}
+		if id, _, ok := strings.Cut(rest, "/"); ok && id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// recorder passes requests on to base and appends every exchange to the
+// cassette file, redacting the token and account names. The file is
+// rewritten after each request, so an interrupted run keeps its recording.
+type recorder struct {
+	base http.RoundTripper
+	path string
+
+	mu       sync.Mutex
+	cassette Cassette
+	redactor redactor
+}
+
+func newRecorder(base http.RoundTripper, path string) (*recorder, error) {
+	r := &recorder{base: base, path: path, cassette: Cassette{Version: cassetteVersion, Interactions: []Interaction{}}}
+	// Fail before the first request when the file cannot be written
+	if err := storage.SaveJSON(path, r.cassette); err != nil {
+		return nil, fmt.Errorf("creating cassette: %w", err)
+	}
+	return r, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("reading request body: %w", err)
+		}
+		reqBody, err = io.ReadAll(body)
+		body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading request body: %w", err)
+		}
+	}
+
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Learn the account names of both bodies before redacting either
+	reqJSON, respJSON := r.redactor.learn(reqBody), r.redactor.learn(respBody)
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: CassetteRequest{
+			Method: req.Method,
+			URL:    req.URL.RequestURI(),
+			Header: filterHeader(req.Header),
+			Body:   r.redactor.redact(reqBody, reqJSON),
+		},
+		Response: CassetteResponse{
+			Status: resp.StatusCode,
+			Header: filterHeader(resp.Header),
+			Body:   r.redactor.redact(respBody, respJSON),
+		},
+	})
+	if err := storage.SaveJSON(r.path, r.cassette); err != nil {
+		return nil, fmt.Errorf("writing cassette: %w", err)
+	}
+	return resp, nil
+}
+
+// replayer answers requests from a cassette. Each interaction is used once,
+// in recorded order, so repeated requests (e.g. retries) replay faithfully.
+type replayer struct {
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+func newReplayer(path string) (*replayer, error) {
+	c, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+	return &replayer{cassette: c, used: make([]bool, len(c.Interactions))}, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	uri := req.URL.RequestURI()
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] || in.Request.Method != req.Method || in.Request.URL != uri {
+			continue
+		}
+		r.used[i] = true
+
+		body := []byte(in.Response.Body)
+		header := in.Response.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		var text string
+		if json.Unmarshal(body, &text) == nil {
+			body = []byte(text)
+		} else if len(body) > 0 && header.Get("Content-Type") == "" {
+			// Hand-written cassettes may omit the header of their JSON bodies
+			header.Set("Content-Type", "application/json")
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+			StatusCode:    in.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrNotRecorded, req.Method, uri)
+}
+
+// filterHeader keeps the recorded headers and redacts the token.
+func filterHeader(h http.Header) http.Header {
+	filtered := make(http.Header)
+	for _, name := range recordedHeaders {
+		if values := h.Values(name); len(values) > 0 {
+			filtered[name] = values
+		}
+	}
+	if auth := filtered.Get("Authorization"); auth != "" {
+		scheme, _, _ := strings.Cut(auth, " ")
+		filtered.Set("Authorization", scheme+" "+redacted)
+	}
+	if len(filtered) == 0 {
+		return nil
+	}
+	return filtered
+}
+
+// redactor replaces account names with stable placeholders (Account 1,
+// Account 2, ...) across all interactions of a cassette.
+type redactor struct {
+	names map[string]string
+}
+
+// learn parses a JSON body and collects the account names it contains.
+// It returns the parsed body, or nil when the body is not JSON.
+func (r *redactor) learn(body []byte) any {
+	if len(body) == 0 {
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return nil
+	}
+	r.collect(v, "")
+	return v
+}
+
+// collect finds account names: the name of account objects, account_name
+// fields and the account part of transfer payee names.
+func (r *redactor) collect(v any, parent string) {
+	switch v := v.(type) {
+	case map[string]any:
+		// Visit keys in order so placeholders are numbered deterministically
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			child := v[key]
+			s, isString := child.(string)
+			switch {
+			case isString && key == "name" && (parent == "account" || parent == "accounts"):
+				r.add(s)
+			case isString && key == "account_name":
+				r.add(s)
+			case isString && (key == "name" || key == "payee_name"):
+				if account, ok := strings.CutPrefix(s, transferPayeePrefix); ok {
+					r.add(account)
+				}
+			default:
+				r.collect(child, key)
+			}
+		}
+	case []any:
+		for _, item := range v {
+			r.collect(item, parent)
+		}
+	}
+}
+
+func (r *redactor) add(name string) {
+	if name == "" {
+		return
+	}
+	if r.names == nil {
+		r.names = make(map[string]string)
+	}
+	if _, ok := r.names[name]; !ok {
+		r.names[name] = fmt.Sprintf("Account %d", len(r.names)+1)
+	}
+}
+
+// redact returns the body for the cassette: parsed JSON with the account
+// names replaced, or the raw body as a JSON string.
+func (r *redactor) redact(body []byte, parsed any) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	if parsed == nil {
+		data, _ := json.Marshal(string(body))
+		return data
+	}
+	data, err := json.Marshal(r.replace(parsed))
+	if err != nil {
+		data, _ = json.Marshal(string(body))
+	}
+	return data
+}
+
+func (r *redactor) replace(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, child := range v {
+			v[key] = r.replace(child)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = r.replace(item)
+		}
+		return v
+	case string:
+		if placeholder, ok := r.names[v]; ok {
+			return placeholder
+		}
+		if account, ok := strings.CutPrefix(v, transferPayeePrefix); ok {
+			if placeholder, ok := r.names[account]; ok {
+				return transferPayeePrefix + placeholder
+			}
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// transferPayeePrefix starts the names of the payees YNAB creates for transfers.
+const transferPayeePrefix = "Transfer : "
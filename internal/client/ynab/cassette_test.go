@@ -0,0 +1,161 @@
+package ynab
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pgbytes/moneypenny/internal/storage"
+	"github.com/stretchr/testify/suite"
+)
+
+// CassetteTestSuite groups record and replay tests.
+type CassetteTestSuite struct {
+	suite.Suite
+	logger *mockLogger
+	path   string
+}
+
+func TestCassetteTestSuite(t *testing.T) {
+	suite.Run(t, new(CassetteTestSuite))
+}
+
+func (s *CassetteTestSuite) SetupTest() {
+	s.logger = &mockLogger{}
+	s.path = filepath.Join(s.T().TempDir(), "cassette.json")
+}
+
+// liveServer answers accounts and transactions that mention the account names.
+func (s *CassetteTestSuite) liveServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		switch r.URL.Path {
+		case "/budgets/budget-1/accounts":
+			var response AccountsResponse
+			response.Data.Accounts = []Account{
+				{ID: "acc-1", Name: "Girokonto Max Mustermann", Balance: 1000},
+				{ID: "acc-2", Name: "Miles & More", Balance: -500},
+			}
+			_ = json.NewEncoder(w).Encode(response)
+		case "/budgets/budget-1/transactions":
+			var response TransactionsResponse
+			response.Data.Transactions = []Transaction{
+				{ID: "txn-1", AccountID: "acc-1", AccountName: "Girokonto Max Mustermann", PayeeName: "Transfer : Miles & More", Amount: -500},
+				{ID: "txn-2", AccountID: "acc-1", AccountName: "Girokonto Max Mustermann", PayeeName: "REWE", Amount: -2000},
+			}
+			_ = json.NewEncoder(w).Encode(response)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func (s *CassetteTestSuite) newClient(cfg Config) *Client {
+	cfg.APIKey = "live-token-1234"
+	cfg.BudgetID = "budget-1"
+	client, err := NewClient(cfg, s.logger)
+	s.Require().NoError(err)
+	return client
+}
+
+func (s *CassetteTestSuite) TestRecord_RedactsTokenAndAccountNames() {
+	// Arrange
+	server := s.liveServer()
+	defer server.Close()
+	client := s.newClient(Config{BaseURL: server.URL, RecordPath: s.path})
+
+	// Act
+	accounts, err := client.GetAccounts()
+	s.Require().NoError(err)
+	_, err = client.GetTransactions(TransactionOptions{SinceDate: "2024-03-01"})
+	s.Require().NoError(err)
+
+	// Assert
+	s.Equal("Girokonto Max Mustermann", accounts[0].Name, "the caller sees the live response")
+	data, err := os.ReadFile(s.path)
+	s.Require().NoError(err)
+	s.NotContains(string(data), "live-token")
+	s.NotContains(string(data), "Mustermann")
+	s.NotContains(string(data), "Miles & More")
+	s.NotContains(string(data), "session=secret")
+
+	cassette, err := LoadCassette(s.path)
+	s.Require().NoError(err)
+	s.Require().Len(cassette.Interactions, 2)
+	s.Equal("Bearer REDACTED", cassette.Interactions[0].Request.Header.Get("Authorization"))
+	s.Equal("/budgets/budget-1/transactions?since_date=2024-03-01", cassette.Interactions[1].Request.URL)
+	s.Contains(string(cassette.Interactions[1].Response.Body), `"Transfer : Account 2"`)
+}
+
+func (s *CassetteTestSuite) TestReplay_ReturnsRecordedResponsesWithoutNetwork() {
+	// Arrange
+	server := s.liveServer()
+	recording := s.newClient(Config{BaseURL: server.URL, RecordPath: s.path})
+	_, err := recording.GetTransactions(TransactionOptions{})
+	s.Require().NoError(err)
+	_, err = recording.GetAccounts()
+	s.Require().NoError(err)
+	server.Close()
+
+	client := s.newClient(Config{BaseURL: "http://127.0.0.1:1", ReplayPath: s.path})
+
+	// Act
+	accounts, err := client.GetAccounts()
+	transactions, txnErr := client.GetTransactions(TransactionOptions{})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().NoError(txnErr)
+	s.Equal([]string{"Account 1", "Account 2"}, []string{accounts[0].Name, accounts[1].Name})
+	s.Equal("Account 1", transactions[0].AccountName)
+	s.Equal(int64(-2000), transactions[1].Amount)
+}
+
+func (s *CassetteTestSuite) TestReplay_UsesEachInteractionOnceInOrder() {
+	// Arrange
+	cassette := Cassette{Version: cassetteVersion, Interactions: []Interaction{
+		{Request: CassetteRequest{Method: http.MethodGet, URL: "/v1/budgets/budget-1/payees"}, Response: CassetteResponse{Status: 200, Body: json.RawMessage(`{"data":{"payees":[{"id":"p1"}]}}`)}},
+		{Request: CassetteRequest{Method: http.MethodGet, URL: "/v1/budgets/budget-1/payees"}, Response: CassetteResponse{Status: 200, Body: json.RawMessage(`{"data":{"payees":[{"id":"p1"},{"id":"p2"}]}}`)}},
+	}}
+	s.Require().NoError(storage.SaveJSON(s.path, cassette))
+	client := s.newClient(Config{BaseURL: "https://api.ynab.com/v1", ReplayPath: s.path})
+
+	// Act
+	first, err := client.GetPayees()
+	s.Require().NoError(err)
+	second, err := client.GetPayees()
+	s.Require().NoError(err)
+	_, thirdErr := client.GetPayees()
+
+	// Assert
+	s.Len(first, 1)
+	s.Len(second, 2)
+	s.ErrorIs(thirdErr, ErrNotRecorded)
+}
+
+func (s *CassetteTestSuite) TestNewClient_WithRecordAndReplay_ReturnsError() {
+	// Act
+	_, err := NewClient(Config{APIKey: "token", BudgetID: "budget-1", RecordPath: s.path, ReplayPath: s.path}, s.logger)
+
+	// Assert
+	s.ErrorContains(err, "cannot be combined")
+}
+
+func (s *CassetteTestSuite) TestBudgetID_ReturnsFirstRecordedBudget() {
+	// Arrange
+	cassette := Cassette{Interactions: []Interaction{
+		{Request: CassetteRequest{Method: http.MethodGet, URL: "/v1/budgets?include_accounts=true"}},
+		{Request: CassetteRequest{Method: http.MethodGet, URL: "/v1/budgets/budget-7/accounts"}},
+	}}
+
+	// Act
+	id := cassette.BudgetID()
+
+	// Assert
+	s.Equal("budget-7", id)
+	s.Empty((&Cassette{}).BudgetID())
+}
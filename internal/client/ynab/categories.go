@@ -0,0 +1,70 @@
+package ynab
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetCategories retrieves all category groups and their categories for the configured budget.
+func (c *Client) GetCategories() ([]CategoryGroup, error) {
+	c.logger.Debugf("Fetching categories for budget: %s", c.budgetID)
+
+	var result CategoriesResponse
+	var errResp ErrorResponse
+
+	resp, err := c.httpClient.R().
+		SetResult(&result).
+		SetError(&errResp).
+		Get(fmt.Sprintf("/budgets/%s/categories", c.budgetID))
+
+	if err != nil {
+		return nil, fmt.Errorf("fetching categories: %w", err)
+	}
+
+	if resp.IsError() {
+		return nil, newError(resp, &errResp)
+	}
+
+	c.logger.Debugf("Fetched %d category groups", len(result.Data.CategoryGroups))
+
+	return result.Data.CategoryGroups, nil
+}
+
+// ActiveCategories flattens the groups into the categories that can be assigned,
+// skipping hidden and deleted groups and categories. The group name is filled in
+// on every returned category.
+func ActiveCategories(groups []CategoryGroup) []Category {
+	var categories []Category
+	for _, g := range groups {
+		if g.Hidden || g.Deleted {
+			continue
+		}
+		for _, cat := range g.Categories {
+			if cat.Hidden || cat.Deleted {
+				continue
+			}
+			if cat.CategoryGroupName == "" {
+				cat.CategoryGroupName = g.Name
+			}
+			categories = append(categories, cat)
+		}
+	}
+	return categories
+}
+
+// SearchCategories returns the categories whose name or group name contains query (case-insensitive).
+func SearchCategories(categories []Category, query string) []Category {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return categories
+	}
+
+	var found []Category
+	for _, cat := range categories {
+		if strings.Contains(strings.ToLower(cat.Name), query) ||
+			strings.Contains(strings.ToLower(cat.CategoryGroupName), query) {
+			found = append(found, cat)
+		}
+	}
+	return found
+}
@@ -0,0 +1,144 @@
+package ynab
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// CategoriesTestSuite groups all category-related API tests.
+type CategoriesTestSuite struct {
+	suite.Suite
+	logger *mockLogger
+	server *httptest.Server
+	client *Client
+}
+
+func (s *CategoriesTestSuite) SetupSuite() {
+	s.logger = &mockLogger{}
+}
+
+func (s *CategoriesTestSuite) TearDownTest() {
+	if s.server != nil {
+		s.server.Close()
+	}
+}
+
+func TestCategoriesTestSuite(t *testing.T) {
+	suite.Run(t, new(CategoriesTestSuite))
+}
+
+func (s *CategoriesTestSuite) setupServerAndClient(handler http.HandlerFunc) {
+	s.server = httptest.NewServer(handler)
+
+	cfg := Config{
+		APIKey:   "test-api-key",
+		BudgetID: "test-budget-id",
+		BaseURL:  s.server.URL,
+	}
+
+	client, err := NewClient(cfg, s.logger)
+	s.Require().NoError(err)
+	s.client = client
+}
+
+func (s *CategoriesTestSuite) TestGetCategories_WithValidResponse_ReturnsGroups() {
+	// Arrange
+	var response CategoriesResponse
+	response.Data.CategoryGroups = []CategoryGroup{
+		{ID: "grp-1", Name: "Everyday", Categories: []Category{
+			{ID: "cat-1", CategoryGroupID: "grp-1", Name: "Groceries"},
+			{ID: "cat-2", CategoryGroupID: "grp-1", Name: "Dining Out"},
+		}},
+	}
+
+	s.setupServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("GET", r.Method)
+		s.Equal("/budgets/test-budget-id/categories", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	})
+
+	// Act
+	groups, err := s.client.GetCategories()
+
+	// Assert
+	s.NoError(err)
+	s.Len(groups, 1)
+	s.Len(groups[0].Categories, 2)
+	s.Equal("Groceries", groups[0].Categories[0].Name)
+}
+
+func (s *CategoriesTestSuite) TestGetCategories_WithUnauthorized_ReturnsError() {
+	// Arrange
+	s.setupServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: APIError{ID: "401", Name: "unauthorized", Detail: "Unauthorized"}})
+	})
+
+	// Act
+	groups, err := s.client.GetCategories()
+
+	// Assert
+	s.ErrorIs(err, ErrUnauthorized)
+	s.Nil(groups)
+}
+
+func (s *CategoriesTestSuite) TestActiveCategories_SkipsHiddenAndDeleted() {
+	// Arrange
+	groups := []CategoryGroup{
+		{Name: "Everyday", Categories: []Category{
+			{ID: "cat-1", Name: "Groceries"},
+			{ID: "cat-2", Name: "Old", Hidden: true},
+			{ID: "cat-3", Name: "Gone", Deleted: true},
+		}},
+		{Name: "Hidden Group", Hidden: true, Categories: []Category{{ID: "cat-4", Name: "Secret"}}},
+	}
+
+	// Act
+	categories := ActiveCategories(groups)
+
+	// Assert
+	s.Len(categories, 1)
+	s.Equal("cat-1", categories[0].ID)
+	s.Equal("Everyday", categories[0].CategoryGroupName)
+}
+
+func (s *CategoriesTestSuite) TestSearchCategories_MatchesNameAndGroup() {
+	// Arrange
+	categories := []Category{
+		{ID: "cat-1", Name: "Groceries", CategoryGroupName: "Everyday"},
+		{ID: "cat-2", Name: "Flights", CategoryGroupName: "Travel"},
+		{ID: "cat-3", Name: "Hotels", CategoryGroupName: "Travel"},
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{name: "by name", query: "groc", expected: []string{"cat-1"}},
+		{name: "by group", query: "TRAVEL", expected: []string{"cat-2", "cat-3"}},
+		{name: "empty query", query: " ", expected: []string{"cat-1", "cat-2", "cat-3"}},
+		{name: "no match", query: "rent", expected: nil},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			found := SearchCategories(categories, tt.query)
+
+			// Assert
+			var ids []string
+			for _, c := range found {
+				ids = append(ids, c.ID)
+			}
+			s.Equal(tt.expected, ids)
+		})
+	}
+}
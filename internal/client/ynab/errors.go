@@ -3,20 +3,47 @@ package ynab
 import (
 	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
 )
 
-// Sentinel errors for common error conditions.
+// Sentinel errors for common error conditions. *Error matches them with
+// errors.Is according to its status code.
 var (
 	// ErrNotFound indicates the requested resource was not found.
 	ErrNotFound = errors.New("resource not found")
-	// ErrUnauthorized indicates invalid or missing authentication.
+	// ErrUnauthorized indicates invalid, expired or revoked authentication.
 	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden indicates the token is valid but may not perform the
+	// request, e.g. because the subscription lapsed or the OAuth scope is
+	// read-only.
+	ErrForbidden = errors.New("forbidden")
 	// ErrRateLimited indicates the API rate limit has been exceeded.
 	ErrRateLimited = errors.New("rate limit exceeded")
 	// ErrBadRequest indicates the request was malformed or invalid.
 	ErrBadRequest = errors.New("bad request")
 	// ErrConflict indicates a conflict, such as duplicate import_id.
 	ErrConflict = errors.New("conflict")
+	// ErrServer indicates the API failed to handle a valid request.
+	ErrServer = errors.New("server error")
+	// ErrReadOnly is returned by ReadOnlyClient for every mutating call.
+	ErrReadOnly = errors.New("client is read-only")
+)
+
+// API error IDs and names with a specific meaning, see
+// https://api.ynab.com/#errors.
+const (
+	ErrorIDEndpointNotFound = "404.1"
+	ErrorIDResourceNotFound = "404.2"
+
+	ErrorNameSubscriptionLapsed = "subscription_lapsed"
+	ErrorNameTrialExpired       = "trial_expired"
+	ErrorNameUnauthorizedScope  = "unauthorized_scope"
+	ErrorNameDataLimitReached   = "data_limit_reached"
 )
 
 // APIError represents an error response from the YNAB API.
@@ -36,34 +63,126 @@ type ErrorResponse struct {
 	Error APIError `json:"error"`
 }
 
-// mapHTTPStatusToError maps HTTP status codes to sentinel errors.
-func mapHTTPStatusToError(statusCode int, apiErr *APIError) error {
-	var baseErr error
-
-	switch statusCode {
-	case 400:
-		baseErr = ErrBadRequest
-	case 401:
-		baseErr = ErrUnauthorized
-	case 403:
-		// Could be subscription_lapsed, trial_expired, unauthorized_scope, or data_limit_reached
-		baseErr = ErrUnauthorized
-	case 404:
-		baseErr = ErrNotFound
-	case 409:
-		baseErr = ErrConflict
-	case 429:
-		baseErr = ErrRateLimited
-	default:
-		if apiErr != nil {
-			return apiErr
+// RateLimit is the request allowance reported in the X-Rate-Limit header,
+// e.g. "36/200" for 36 of 200 requests used in the rolling hour.
+type RateLimit struct {
+	Used  int
+	Limit int
+}
+
+// Error is a failed API request. It matches the sentinel errors with
+// errors.Is, e.g. errors.Is(err, ErrNotFound) for any 404.
+type Error struct {
+	// Method and Path identify the request, e.g. GET /budgets/{id}/accounts.
+	Method string
+	Path   string
+	// StatusCode is the HTTP status of the response.
+	StatusCode int
+	// API is the error of the response body, or nil when the body had none.
+	API *APIError
+	// RequestID is the X-Request-Id of the response, when sent.
+	RequestID string
+	// RateLimit is the allowance reported with the response, or nil.
+	RateLimit *RateLimit
+	// RetryAfter is the wait requested with a 429 response, or zero.
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s: %d %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.API != nil {
+		fmt.Fprintf(&b, ": %s", e.API.Name)
+		if e.API.ID != "" {
+			fmt.Fprintf(&b, " (%s)", e.API.ID)
 		}
-		return fmt.Errorf("unexpected status code: %d", statusCode)
+		if e.API.Detail != "" {
+			fmt.Fprintf(&b, ": %s", e.API.Detail)
+		}
+	}
+	return b.String()
+}
+
+// Unwrap returns the sentinel error for the status code, if any.
+func (e *Error) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusBadRequest:
+		return ErrBadRequest
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ErrForbidden
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusConflict:
+		return ErrConflict
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrServer
+	default:
+		return nil
 	}
+}
+
+// Retryable reports whether the same request may succeed later: after a
+// rate limit or a server error.
+func (e *Error) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
 
-	if apiErr != nil {
-		return fmt.Errorf("%w: %s", baseErr, apiErr.Detail)
+// Name returns the API error name, e.g. subscription_lapsed, or "".
+func (e *Error) Name() string {
+	if e.API == nil {
+		return ""
 	}
+	return e.API.Name
+}
 
-	return baseErr
+// newError builds the error of a failed response. errResp holds the decoded
+// error body, if the response had one.
+func newError(resp *resty.Response, errResp *ErrorResponse) error {
+	e := &Error{
+		Method:     resp.Request.Method,
+		Path:       resp.Request.URL,
+		StatusCode: resp.StatusCode(),
+		RequestID:  resp.Header().Get("X-Request-Id"),
+		RateLimit:  parseRateLimit(resp.Header().Get("X-Rate-Limit")),
+		RetryAfter: parseRetryAfter(resp.Header().Get("Retry-After")),
+	}
+	if raw := resp.Request.RawRequest; raw != nil {
+		e.Path = raw.URL.Path
+	}
+	if errResp != nil && errResp.Error != (APIError{}) {
+		apiErr := errResp.Error
+		e.API = &apiErr
+	}
+	return e
+}
+
+// parseRateLimit parses an X-Rate-Limit header such as "36/200".
+func parseRateLimit(value string) *RateLimit {
+	used, limit, ok := strings.Cut(value, "/")
+	if !ok {
+		return nil
+	}
+	u, err := strconv.Atoi(strings.TrimSpace(used))
+	if err != nil {
+		return nil
+	}
+	l, err := strconv.Atoi(strings.TrimSpace(limit))
+	if err != nil {
+		return nil
+	}
+	return &RateLimit{Used: u, Limit: l}
+}
+
+// parseRetryAfter parses a Retry-After header in seconds.
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
 }
@@ -0,0 +1,126 @@
+package ynab
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// ErrorsTestSuite groups tests of the structured API errors.
+type ErrorsTestSuite struct {
+	suite.Suite
+	logger *mockLogger
+	server *httptest.Server
+	client *Client
+}
+
+func (s *ErrorsTestSuite) SetupSuite() {
+	s.logger = &mockLogger{}
+}
+
+func (s *ErrorsTestSuite) TearDownTest() {
+	if s.server != nil {
+		s.server.Close()
+	}
+}
+
+func TestErrorsTestSuite(t *testing.T) {
+	suite.Run(t, new(ErrorsTestSuite))
+}
+
+func (s *ErrorsTestSuite) setupServerAndClient(handler http.HandlerFunc) {
+	s.server = httptest.NewServer(handler)
+
+	client, err := NewClient(Config{APIKey: "test-api-key", BudgetID: "test-budget-id", BaseURL: s.server.URL}, s.logger)
+	s.Require().NoError(err)
+	s.client = client
+}
+
+func (s *ErrorsTestSuite) TestForbidden_IsNotUnauthorized() {
+	// Arrange
+	s.setupServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Rate-Limit", "36/200")
+		w.Header().Set("X-Request-Id", "req-1")
+		w.WriteHeader(http.StatusForbidden)
+		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: APIError{ID: "403.1", Name: ErrorNameSubscriptionLapsed, Detail: "Subscription lapsed"}})
+	})
+
+	// Act
+	_, err := s.client.GetAccounts()
+
+	// Assert
+	s.ErrorIs(err, ErrForbidden)
+	s.NotErrorIs(err, ErrUnauthorized)
+	var apiErr *Error
+	s.Require().True(errors.As(err, &apiErr))
+	s.Equal(http.MethodGet, apiErr.Method)
+	s.Equal("/budgets/test-budget-id/accounts", apiErr.Path)
+	s.Equal(http.StatusForbidden, apiErr.StatusCode)
+	s.Equal(ErrorNameSubscriptionLapsed, apiErr.Name())
+	s.Equal("403.1", apiErr.API.ID)
+	s.Equal("req-1", apiErr.RequestID)
+	s.Equal(&RateLimit{Used: 36, Limit: 200}, apiErr.RateLimit)
+	s.False(apiErr.Retryable())
+	s.Equal("GET /budgets/test-budget-id/accounts: 403 Forbidden: subscription_lapsed (403.1): Subscription lapsed", apiErr.Error())
+}
+
+func (s *ErrorsTestSuite) TestNotFound_WithEmptyBody_HasNoAPIError() {
+	// Arrange
+	s.setupServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	// Act
+	_, err := s.client.GetTransaction("txn-1")
+
+	// Assert
+	s.ErrorIs(err, ErrNotFound)
+	var apiErr *Error
+	s.Require().True(errors.As(err, &apiErr))
+	s.Nil(apiErr.API)
+	s.Empty(apiErr.Name())
+	s.Nil(apiErr.RateLimit)
+	s.Equal("GET /budgets/test-budget-id/transactions/txn-1: 404 Not Found", apiErr.Error())
+}
+
+func (s *ErrorsTestSuite) TestError_MatchesSentinelsAndRetryability() {
+	tests := []struct {
+		status    int
+		sentinel  error
+		retryable bool
+	}{
+		{http.StatusBadRequest, ErrBadRequest, false},
+		{http.StatusUnauthorized, ErrUnauthorized, false},
+		{http.StatusForbidden, ErrForbidden, false},
+		{http.StatusNotFound, ErrNotFound, false},
+		{http.StatusConflict, ErrConflict, false},
+		{http.StatusTooManyRequests, ErrRateLimited, true},
+		{http.StatusServiceUnavailable, ErrServer, true},
+	}
+
+	for _, tt := range tests {
+		s.Run(http.StatusText(tt.status), func() {
+			// Act
+			err := &Error{Method: http.MethodGet, Path: "/budgets", StatusCode: tt.status}
+
+			// Assert
+			s.ErrorIs(err, tt.sentinel)
+			s.Equal(tt.retryable, err.Retryable())
+		})
+	}
+}
+
+func (s *ErrorsTestSuite) TestParseHeaders_WithInvalidValues_ReturnsZero() {
+	// Assert
+	s.Equal(&RateLimit{Used: 1, Limit: 200}, parseRateLimit("1/200"))
+	s.Nil(parseRateLimit("unlimited"))
+	s.Nil(parseRateLimit("x/200"))
+	s.Equal(61*time.Second, parseRetryAfter("61"))
+	s.Zero(parseRetryAfter("Wed, 21 Oct 2015 07:28:00 GMT"))
+}
@@ -0,0 +1,64 @@
+package ynab
+
+import (
+	"regexp"
+	"strings"
+)
+
+// TransactionFilter selects transactions on the client side.
+// Zero-value fields are ignored, so an empty filter matches every transaction.
+type TransactionFilter struct {
+	// AccountID restricts matches to a single account.
+	AccountID string
+	// FromDate is the inclusive lower date bound (ISO format: YYYY-MM-DD).
+	FromDate string
+	// ToDate is the inclusive upper date bound (ISO format: YYYY-MM-DD).
+	ToDate string
+	// ImportIDPrefix matches transactions whose import_id starts with this prefix.
+	ImportIDPrefix string
+	// PayeePattern matches transactions whose payee name matches this expression.
+	PayeePattern *regexp.Regexp
+	// IncludeDeleted keeps deleted transactions in the result.
+	IncludeDeleted bool
+}
+
+// Match reports whether a transaction satisfies every criterion of the filter.
+func (f TransactionFilter) Match(t Transaction) bool {
+	if t.Deleted && !f.IncludeDeleted {
+		return false
+	}
+	if f.AccountID != "" && t.AccountID != f.AccountID {
+		return false
+	}
+	// ISO dates compare correctly as strings
+	if f.FromDate != "" && t.Date < f.FromDate {
+		return false
+	}
+	if f.ToDate != "" && t.Date > f.ToDate {
+		return false
+	}
+	if f.ImportIDPrefix != "" && !strings.HasPrefix(t.ImportID, f.ImportIDPrefix) {
+		return false
+	}
+	if f.PayeePattern != nil && !f.PayeePattern.MatchString(t.PayeeName) {
+		return false
+	}
+	return true
+}
+
+// IsEmpty reports whether the filter has no selection criteria.
+func (f TransactionFilter) IsEmpty() bool {
+	return f.AccountID == "" && f.FromDate == "" && f.ToDate == "" &&
+		f.ImportIDPrefix == "" && f.PayeePattern == nil
+}
+
+// FilterTransactions returns the transactions matching the filter, preserving order.
+func FilterTransactions(transactions []Transaction, filter TransactionFilter) []Transaction {
+	matched := make([]Transaction, 0, len(transactions))
+	for _, t := range transactions {
+		if filter.Match(t) {
+			matched = append(matched, t)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,90 @@
+package ynab
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionFilter_Match(t *testing.T) {
+	transaction := Transaction{
+		ID:        "tx-1",
+		AccountID: "acc-1",
+		Date:      "2026-01-15",
+		PayeeName: "PAYPAL *CRAFTDOCS",
+		ImportID:  "YNAB:-5000:2026-01-15:1",
+	}
+
+	tests := []struct {
+		name        string
+		filter      TransactionFilter
+		transaction Transaction
+		expected    bool
+	}{
+		{name: "empty filter matches", filter: TransactionFilter{}, transaction: transaction, expected: true},
+		{name: "same account", filter: TransactionFilter{AccountID: "acc-1"}, transaction: transaction, expected: true},
+		{name: "other account", filter: TransactionFilter{AccountID: "acc-2"}, transaction: transaction, expected: false},
+		{name: "inside date range", filter: TransactionFilter{FromDate: "2026-01-15", ToDate: "2026-01-15"}, transaction: transaction, expected: true},
+		{name: "before from date", filter: TransactionFilter{FromDate: "2026-01-16"}, transaction: transaction, expected: false},
+		{name: "after to date", filter: TransactionFilter{ToDate: "2026-01-14"}, transaction: transaction, expected: false},
+		{name: "import id prefix", filter: TransactionFilter{ImportIDPrefix: "YNAB:-5000"}, transaction: transaction, expected: true},
+		{name: "other import id prefix", filter: TransactionFilter{ImportIDPrefix: "MM:"}, transaction: transaction, expected: false},
+		{name: "payee regex", filter: TransactionFilter{PayeePattern: regexp.MustCompile(`^PAYPAL`)}, transaction: transaction, expected: true},
+		{name: "payee regex mismatch", filter: TransactionFilter{PayeePattern: regexp.MustCompile(`AMAZON`)}, transaction: transaction, expected: false},
+		{name: "deleted excluded", filter: TransactionFilter{}, transaction: Transaction{Deleted: true}, expected: false},
+		{name: "deleted included", filter: TransactionFilter{IncludeDeleted: true}, transaction: Transaction{Deleted: true}, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			result := tt.filter.Match(tt.transaction)
+
+			// Assert
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestFilterTransactions_WithMixedInput_KeepsMatchesInOrder(t *testing.T) {
+	// Arrange
+	transactions := []Transaction{
+		{ID: "tx-1", AccountID: "acc-1"},
+		{ID: "tx-2", AccountID: "acc-2"},
+		{ID: "tx-3", AccountID: "acc-1"},
+	}
+
+	// Act
+	result := FilterTransactions(transactions, TransactionFilter{AccountID: "acc-1"})
+
+	// Assert
+	assert.Len(t, result, 2)
+	assert.Equal(t, "tx-1", result[0].ID)
+	assert.Equal(t, "tx-3", result[1].ID)
+}
+
+func TestTransaction_ToSaveTransaction_KeepsExistingValues(t *testing.T) {
+	// Arrange
+	transaction := Transaction{
+		AccountID:  "acc-1",
+		Date:       "2026-01-15",
+		Amount:     -5000,
+		PayeeID:    "payee-1",
+		PayeeName:  "Bakery",
+		CategoryID: "cat-1",
+		Cleared:    ClearedStatusCleared,
+		Approved:   true,
+	}
+
+	// Act
+	save := transaction.ToSaveTransaction()
+
+	// Assert
+	assert.Equal(t, "acc-1", save.AccountID)
+	assert.Equal(t, int64(-5000), save.Amount)
+	assert.Equal(t, "payee-1", save.PayeeID)
+	assert.Empty(t, save.PayeeName, "payee name is dropped when payee id is set")
+	assert.Equal(t, ClearedStatusCleared, save.Cleared)
+	assert.True(t, save.Approved)
+}
@@ -0,0 +1,30 @@
+package ynab
+
+import (
+	"fmt"
+)
+
+// GetPayees retrieves all payees for the configured budget.
+func (c *Client) GetPayees() ([]Payee, error) {
+	c.logger.Debugf("Fetching payees for budget: %s", c.budgetID)
+
+	var result PayeesResponse
+	var errResp ErrorResponse
+
+	resp, err := c.httpClient.R().
+		SetResult(&result).
+		SetError(&errResp).
+		Get(fmt.Sprintf("/budgets/%s/payees", c.budgetID))
+
+	if err != nil {
+		return nil, fmt.Errorf("fetching payees: %w", err)
+	}
+
+	if resp.IsError() {
+		return nil, newError(resp, &errResp)
+	}
+
+	c.logger.Debugf("Fetched %d payees", len(result.Data.Payees))
+
+	return result.Data.Payees, nil
+}
@@ -0,0 +1,88 @@
+package ynab
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// PayeesTestSuite groups all payee-related API tests.
+type PayeesTestSuite struct {
+	suite.Suite
+	logger *mockLogger
+	server *httptest.Server
+	client *Client
+}
+
+func (s *PayeesTestSuite) SetupSuite() {
+	s.logger = &mockLogger{}
+}
+
+func (s *PayeesTestSuite) TearDownTest() {
+	if s.server != nil {
+		s.server.Close()
+	}
+}
+
+func TestPayeesTestSuite(t *testing.T) {
+	suite.Run(t, new(PayeesTestSuite))
+}
+
+func (s *PayeesTestSuite) setupServerAndClient(handler http.HandlerFunc) {
+	s.server = httptest.NewServer(handler)
+
+	cfg := Config{
+		APIKey:   "test-api-key",
+		BudgetID: "test-budget-id",
+		BaseURL:  s.server.URL,
+	}
+
+	client, err := NewClient(cfg, s.logger)
+	s.Require().NoError(err)
+	s.client = client
+}
+
+func (s *PayeesTestSuite) TestGetPayees_WithValidResponse_ReturnsPayees() {
+	// Arrange
+	var response PayeesResponse
+	response.Data.Payees = []Payee{
+		{ID: "payee-1", Name: "REWE"},
+		{ID: "payee-2", Name: "Transfer : Savings", TransferAccountID: "acc-2"},
+	}
+
+	s.setupServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("GET", r.Method)
+		s.Equal("/budgets/test-budget-id/payees", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	})
+
+	// Act
+	payees, err := s.client.GetPayees()
+
+	// Assert
+	s.NoError(err)
+	s.Len(payees, 2)
+	s.Equal("REWE", payees[0].Name)
+	s.Equal("acc-2", payees[1].TransferAccountID)
+}
+
+func (s *PayeesTestSuite) TestGetPayees_WithNotFound_ReturnsError() {
+	// Arrange
+	s.setupServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: APIError{ID: "404", Name: "not_found", Detail: "Budget not found"}})
+	})
+
+	// Act
+	payees, err := s.client.GetPayees()
+
+	// Assert
+	s.ErrorIs(err, ErrNotFound)
+	s.Nil(payees)
+}
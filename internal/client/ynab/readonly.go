@@ -0,0 +1,47 @@
+package ynab
+
+import "fmt"
+
+// ReadOnlyClient passes read calls on to a Reader and rejects every
+// mutating call with ErrReadOnly, so a command can be tried against a real
+// budget without changing it.
+type ReadOnlyClient struct {
+	Reader
+}
+
+// NewReadOnlyClient wraps r in a ReadOnlyClient.
+func NewReadOnlyClient(r Reader) *ReadOnlyClient {
+	return &ReadOnlyClient{Reader: r}
+}
+
+var _ API = (*ReadOnlyClient)(nil)
+
+// CreateAccount implements AccountWriter and always fails.
+func (c *ReadOnlyClient) CreateAccount(account SaveAccount) (*Account, error) {
+	return nil, fmt.Errorf("creating account %q: %w", account.Name, ErrReadOnly)
+}
+
+// CreateTransaction implements TransactionWriter and always fails.
+func (c *ReadOnlyClient) CreateTransaction(transaction SaveTransaction) (*SaveTransactionsResponse, error) {
+	return nil, fmt.Errorf("creating transaction: %w", ErrReadOnly)
+}
+
+// CreateTransactions implements TransactionWriter and always fails.
+func (c *ReadOnlyClient) CreateTransactions(transactions []SaveTransaction) (*SaveTransactionsResponse, error) {
+	return nil, fmt.Errorf("creating %d transactions: %w", len(transactions), ErrReadOnly)
+}
+
+// UpdateTransaction implements TransactionWriter and always fails.
+func (c *ReadOnlyClient) UpdateTransaction(transactionID string, transaction SaveTransaction) (*Transaction, error) {
+	return nil, fmt.Errorf("updating transaction %s: %w", transactionID, ErrReadOnly)
+}
+
+// UpdateTransactions implements TransactionWriter and always fails.
+func (c *ReadOnlyClient) UpdateTransactions(transactions []SaveTransactionWithID) (*SaveTransactionsResponse, error) {
+	return nil, fmt.Errorf("updating %d transactions: %w", len(transactions), ErrReadOnly)
+}
+
+// DeleteTransaction implements TransactionWriter and always fails.
+func (c *ReadOnlyClient) DeleteTransaction(transactionID string) (*Transaction, error) {
+	return nil, fmt.Errorf("deleting transaction %s: %w", transactionID, ErrReadOnly)
+}
@@ -0,0 +1,64 @@
+package ynab
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// ReadOnlyClientTestSuite groups tests of the read-only decorator.
+type ReadOnlyClientTestSuite struct {
+	suite.Suite
+	server *httptest.Server
+	client *ReadOnlyClient
+}
+
+func TestReadOnlyClientTestSuite(t *testing.T) {
+	suite.Run(t, new(ReadOnlyClientTestSuite))
+}
+
+func (s *ReadOnlyClientTestSuite) SetupTest() {
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal(http.MethodGet, r.Method, "a read-only client must not send mutating requests")
+		var response PayeesResponse
+		response.Data.Payees = []Payee{{ID: "payee-1", Name: "REWE"}}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+
+	client, err := NewClient(Config{APIKey: "test-api-key", BudgetID: "budget-1", BaseURL: s.server.URL}, &mockLogger{})
+	s.Require().NoError(err)
+	s.client = NewReadOnlyClient(client)
+}
+
+func (s *ReadOnlyClientTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *ReadOnlyClientTestSuite) TestReads_PassThrough() {
+	// Act
+	payees, err := s.client.GetPayees()
+
+	// Assert
+	s.Require().NoError(err)
+	s.Len(payees, 1)
+	s.Equal("budget-1", s.client.BudgetID())
+}
+
+func (s *ReadOnlyClientTestSuite) TestWrites_ReturnErrReadOnly() {
+	// Act
+	_, createErr := s.client.CreateTransactions([]SaveTransaction{{AccountID: "acc-1"}})
+	_, singleErr := s.client.CreateTransaction(SaveTransaction{AccountID: "acc-1"})
+	_, updateErr := s.client.UpdateTransaction("txn-1", SaveTransaction{})
+	_, bulkErr := s.client.UpdateTransactions([]SaveTransactionWithID{{ID: "txn-1"}})
+	_, deleteErr := s.client.DeleteTransaction("txn-1")
+	_, accountErr := s.client.CreateAccount(SaveAccount{Name: "Savings"})
+
+	// Assert
+	for _, err := range []error{createErr, singleErr, updateErr, bulkErr, deleteErr, accountErr} {
+		s.ErrorIs(err, ErrReadOnly)
+	}
+}
@@ -32,7 +32,7 @@ func (c *Client) GetTransactions(opts TransactionOptions) ([]Transaction, error)
 	}
 
 	if resp.IsError() {
-		return nil, mapHTTPStatusToError(resp.StatusCode(), &errResp.Error)
+		return nil, newError(resp, &errResp)
 	}
 
 	c.logger.Debugf("Fetched %d transactions", len(result.Data.Transactions))
@@ -68,7 +68,7 @@ func (c *Client) GetTransactionsByAccount(accountID string, opts TransactionOpti
 	}
 
 	if resp.IsError() {
-		return nil, mapHTTPStatusToError(resp.StatusCode(), &errResp.Error)
+		return nil, newError(resp, &errResp)
 	}
 
 	c.logger.Debugf("Fetched %d transactions for account %s", len(result.Data.Transactions), accountID)
@@ -112,7 +112,7 @@ func (c *Client) createTransactionsInternal(reqBody *SaveTransactionsRequest) (*
 	}
 
 	if resp.IsError() {
-		return nil, mapHTTPStatusToError(resp.StatusCode(), &errResp.Error)
+		return nil, newError(resp, &errResp)
 	}
 
 	c.logger.Debugf("Created %d transactions", len(result.Data.TransactionIDs))
@@ -124,6 +124,115 @@ func (c *Client) createTransactionsInternal(reqBody *SaveTransactionsRequest) (*
 	return &result, nil
 }
 
+// GetTransaction retrieves a single transaction by ID.
+func (c *Client) GetTransaction(transactionID string) (*Transaction, error) {
+	c.logger.Debugf("Fetching transaction: %s in budget: %s", transactionID, c.budgetID)
+
+	var result TransactionResponse
+	var errResp ErrorResponse
+
+	resp, err := c.httpClient.R().
+		SetResult(&result).
+		SetError(&errResp).
+		Get(fmt.Sprintf("/budgets/%s/transactions/%s", c.budgetID, transactionID))
+
+	if err != nil {
+		return nil, fmt.Errorf("fetching transaction: %w", err)
+	}
+
+	if resp.IsError() {
+		return nil, newError(resp, &errResp)
+	}
+
+	return &result.Data.Transaction, nil
+}
+
+// UpdateTransaction replaces a single existing transaction.
+// All fields of the transaction are sent, so callers should start from the
+// existing transaction (see Transaction.ToSaveTransaction) and apply changes.
+func (c *Client) UpdateTransaction(transactionID string, transaction SaveTransaction) (*Transaction, error) {
+	c.logger.Debugf("Updating transaction: %s in budget: %s", transactionID, c.budgetID)
+
+	var result TransactionResponse
+	var errResp ErrorResponse
+
+	resp, err := c.httpClient.R().
+		SetBody(&SaveTransactionsRequest{Transaction: &transaction}).
+		SetResult(&result).
+		SetError(&errResp).
+		Put(fmt.Sprintf("/budgets/%s/transactions/%s", c.budgetID, transactionID))
+
+	if err != nil {
+		return nil, fmt.Errorf("updating transaction: %w", err)
+	}
+
+	if resp.IsError() {
+		return nil, newError(resp, &errResp)
+	}
+
+	return &result.Data.Transaction, nil
+}
+
+// UpdateTransactions updates multiple transactions in a single request.
+// Each transaction must carry either an ID or an ImportID to be identified.
+func (c *Client) UpdateTransactions(transactions []SaveTransactionWithID) (*SaveTransactionsResponse, error) {
+	if len(transactions) == 0 {
+		return nil, fmt.Errorf("at least one transaction is required")
+	}
+
+	for i, t := range transactions {
+		if t.ID == "" && t.ImportID == "" {
+			return nil, fmt.Errorf("transaction %d: id or import_id is required", i)
+		}
+	}
+
+	c.logger.Debugf("Updating %d transactions in budget: %s", len(transactions), c.budgetID)
+
+	var result SaveTransactionsResponse
+	var errResp ErrorResponse
+
+	resp, err := c.httpClient.R().
+		SetBody(&UpdateTransactionsRequest{Transactions: transactions}).
+		SetResult(&result).
+		SetError(&errResp).
+		Patch(fmt.Sprintf("/budgets/%s/transactions", c.budgetID))
+
+	if err != nil {
+		return nil, fmt.Errorf("updating transactions: %w", err)
+	}
+
+	if resp.IsError() {
+		return nil, newError(resp, &errResp)
+	}
+
+	c.logger.Debugf("Updated %d transactions", len(result.Data.TransactionIDs))
+
+	return &result, nil
+}
+
+// DeleteTransaction deletes a single transaction and returns its final state.
+func (c *Client) DeleteTransaction(transactionID string) (*Transaction, error) {
+	c.logger.Debugf("Deleting transaction: %s in budget: %s", transactionID, c.budgetID)
+
+	var result TransactionResponse
+	var errResp ErrorResponse
+
+	resp, err := c.httpClient.R().
+		SetResult(&result).
+		SetError(&errResp).
+		Delete(fmt.Sprintf("/budgets/%s/transactions/%s", c.budgetID, transactionID))
+
+	if err != nil {
+		return nil, fmt.Errorf("deleting transaction: %w", err)
+	}
+
+	if resp.IsError() {
+		return nil, newError(resp, &errResp)
+	}
+
+	return &result.Data.Transaction, nil
+}
+
 // LimitTransactions returns the last n transactions from a slice.
 // If n is greater than the slice length, returns all transactions.
 func LimitTransactions(transactions []Transaction, n int) []Transaction {
@@ -310,3 +310,130 @@ func (s *TransactionsTestSuite) TestGetTransactions_WithValidResponse_ReturnsAll
 	s.NoError(err)
 	s.Len(transactions, 3)
 }
+
+func (s *TransactionsTestSuite) TestGetTransaction_WithValidResponse_ReturnsTransaction() {
+	// Arrange
+	s.setupServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("GET", r.Method)
+		s.Equal("/budgets/test-budget-id/transactions/tx-1", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"transaction":{"id":"tx-1","amount":-5000,"payee_name":"Bakery"}}}`))
+	})
+
+	// Act
+	transaction, err := s.client.GetTransaction("tx-1")
+
+	// Assert
+	s.NoError(err)
+	s.Equal("tx-1", transaction.ID)
+	s.Equal("Bakery", transaction.PayeeName)
+}
+
+func (s *TransactionsTestSuite) TestUpdateTransaction_WithValidData_SendsPut() {
+	// Arrange
+	s.setupServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("PUT", r.Method)
+		s.Equal("/budgets/test-budget-id/transactions/tx-1", r.URL.Path)
+
+		var reqBody SaveTransactionsRequest
+		_ = json.NewDecoder(r.Body).Decode(&reqBody)
+		s.Require().NotNil(reqBody.Transaction)
+		s.Equal("New Memo", reqBody.Transaction.Memo)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"transaction":{"id":"tx-1","memo":"New Memo"}}}`))
+	})
+
+	// Act
+	transaction, err := s.client.UpdateTransaction("tx-1", SaveTransaction{
+		AccountID: "acc-1",
+		Date:      "2026-01-20",
+		Amount:    -10000,
+		Memo:      "New Memo",
+	})
+
+	// Assert
+	s.NoError(err)
+	s.Equal("New Memo", transaction.Memo)
+}
+
+func (s *TransactionsTestSuite) TestUpdateTransactions_WithValidData_SendsPatch() {
+	// Arrange
+	s.setupServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("PATCH", r.Method)
+		s.Equal("/budgets/test-budget-id/transactions", r.URL.Path)
+
+		var reqBody map[string][]map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&reqBody)
+		s.Require().Len(reqBody["transactions"], 2)
+		s.Equal("tx-1", reqBody["transactions"][0]["id"])
+		s.Equal("cleared", reqBody["transactions"][0]["cleared"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"transaction_ids":["tx-1","tx-2"],"server_knowledge":10}}`))
+	})
+
+	// Act
+	result, err := s.client.UpdateTransactions([]SaveTransactionWithID{
+		{ID: "tx-1", Cleared: ClearedStatusCleared},
+		{ID: "tx-2", Cleared: ClearedStatusCleared},
+	})
+
+	// Assert
+	s.NoError(err)
+	s.Len(result.Data.TransactionIDs, 2)
+}
+
+func (s *TransactionsTestSuite) TestUpdateTransactions_WithoutIdentifier_ReturnsError() {
+	// Arrange
+	s.setupServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		s.Fail("Server should not be called without transaction identifiers")
+	})
+
+	// Act
+	result, err := s.client.UpdateTransactions([]SaveTransactionWithID{
+		{AccountID: "acc-1"},
+	})
+
+	// Assert
+	s.Error(err)
+	s.Nil(result)
+	s.Contains(err.Error(), "id or import_id is required")
+}
+
+func (s *TransactionsTestSuite) TestDeleteTransaction_WithValidID_SendsDelete() {
+	// Arrange
+	s.setupServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("DELETE", r.Method)
+		s.Equal("/budgets/test-budget-id/transactions/tx-1", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"transaction":{"id":"tx-1","deleted":true}}}`))
+	})
+
+	// Act
+	transaction, err := s.client.DeleteTransaction("tx-1")
+
+	// Assert
+	s.NoError(err)
+	s.True(transaction.Deleted)
+}
+
+func (s *TransactionsTestSuite) TestDeleteTransaction_WithNotFound_ReturnsError() {
+	// Arrange
+	s.setupServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(ErrorResponse{
+			Error: APIError{ID: "404.2", Name: "resource_not_found", Detail: "Transaction not found"},
+		})
+	})
+
+	// Act
+	transaction, err := s.client.DeleteTransaction("missing")
+
+	// Assert
+	s.Nil(transaction)
+	s.ErrorIs(err, ErrNotFound)
+}
@@ -1,7 +1,12 @@
 // Package ynab provides a client for the YNAB (You Need A Budget) API.
 package ynab
 
-import "time"
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"time"
+)
 
 // Account represents a YNAB account.
 type Account struct {
@@ -20,6 +25,59 @@ type Account struct {
 	Deleted            bool   `json:"deleted"`
 }
 
+// Account types accepted by the YNAB API.
+const (
+	AccountTypeChecking       = "checking"
+	AccountTypeSavings        = "savings"
+	AccountTypeCash           = "cash"
+	AccountTypeCreditCard     = "creditCard"
+	AccountTypeLineOfCredit   = "lineOfCredit"
+	AccountTypeOtherAsset     = "otherAsset"
+	AccountTypeOtherLiability = "otherLiability"
+	AccountTypeMortgage       = "mortgage"
+	AccountTypeAutoLoan       = "autoLoan"
+	AccountTypeStudentLoan    = "studentLoan"
+	AccountTypePersonalLoan   = "personalLoan"
+	AccountTypeMedicalDebt    = "medicalDebt"
+	AccountTypeOtherDebt      = "otherDebt"
+)
+
+// AccountTypes lists every account type, assets first.
+var AccountTypes = []string{
+	AccountTypeChecking, AccountTypeSavings, AccountTypeCash, AccountTypeOtherAsset,
+	AccountTypeCreditCard, AccountTypeLineOfCredit, AccountTypeOtherLiability,
+	AccountTypeMortgage, AccountTypeAutoLoan, AccountTypeStudentLoan,
+	AccountTypePersonalLoan, AccountTypeMedicalDebt, AccountTypeOtherDebt,
+}
+
+// IsLiabilityType reports whether accounts of the given type hold debt.
+func IsLiabilityType(accountType string) bool {
+	switch accountType {
+	case AccountTypeChecking, AccountTypeSavings, AccountTypeCash, AccountTypeOtherAsset:
+		return false
+	default:
+		return true
+	}
+}
+
+// IsValidAccountType reports whether accountType is accepted by the YNAB API.
+func IsValidAccountType(accountType string) bool {
+	for _, t := range AccountTypes {
+		if t == accountType {
+			return true
+		}
+	}
+	return false
+}
+
+// SaveAccount is the request body for creating an account.
+type SaveAccount struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Balance is the starting balance in milliunits.
+	Balance int64 `json:"balance"`
+}
+
 // Transaction represents a YNAB transaction.
 type Transaction struct {
 	ID                    string           `json:"id"`
@@ -62,6 +120,37 @@ type SubTransaction struct {
 	Deleted               bool   `json:"deleted"`
 }
 
+// Payee represents a YNAB payee.
+type Payee struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	TransferAccountID string `json:"transfer_account_id"`
+	Deleted           bool   `json:"deleted"`
+}
+
+// CategoryGroup represents a YNAB category group with its categories.
+type CategoryGroup struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Hidden     bool       `json:"hidden"`
+	Deleted    bool       `json:"deleted"`
+	Categories []Category `json:"categories"`
+}
+
+// Category represents a YNAB budget category.
+type Category struct {
+	ID                string `json:"id"`
+	CategoryGroupID   string `json:"category_group_id"`
+	CategoryGroupName string `json:"category_group_name"`
+	Name              string `json:"name"`
+	Hidden            bool   `json:"hidden"`
+	Note              string `json:"note"`
+	Budgeted          int64  `json:"budgeted"`
+	Activity          int64  `json:"activity"`
+	Balance           int64  `json:"balance"`
+	Deleted           bool   `json:"deleted"`
+}
+
 // SaveTransaction represents a transaction to be created or updated.
 type SaveTransaction struct {
 	AccountID       string               `json:"account_id"`
@@ -78,6 +167,67 @@ type SaveTransaction struct {
 	Subtransactions []SaveSubTransaction `json:"subtransactions,omitempty"`
 }
 
+// SaveTransactionWithID is a partial transaction update identified by ID or
+// ImportID, used by the bulk update endpoint. Nil fields keep their current
+// value; a pointer to an empty string clears the field (sent as null).
+type SaveTransactionWithID struct {
+	ID         string
+	ImportID   string
+	AccountID  string
+	Date       string
+	Amount     *int64
+	PayeeID    *string
+	PayeeName  *string
+	CategoryID *string
+	Memo       *string
+	Cleared    ClearedStatus
+	Approved   *bool
+	FlagColor  *string
+}
+
+// MarshalJSON implements json.Marshaler, sending only the fields to change.
+func (t SaveTransactionWithID) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]any)
+	set := func(key, value string) {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+	clearable := func(key string, value *string) {
+		switch {
+		case value == nil:
+		case *value == "":
+			fields[key] = nil
+		default:
+			fields[key] = *value
+		}
+	}
+
+	set("id", t.ID)
+	set("import_id", t.ImportID)
+	set("account_id", t.AccountID)
+	set("date", t.Date)
+	set("cleared", string(t.Cleared))
+	if t.Amount != nil {
+		fields["amount"] = *t.Amount
+	}
+	if t.Approved != nil {
+		fields["approved"] = *t.Approved
+	}
+	clearable("payee_id", t.PayeeID)
+	clearable("payee_name", t.PayeeName)
+	clearable("category_id", t.CategoryID)
+	clearable("memo", t.Memo)
+	clearable("flag_color", t.FlagColor)
+
+	return json.Marshal(fields)
+}
+
+// Ptr returns a pointer to v, for the optional fields of SaveTransactionWithID.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
 // SaveSubTransaction represents a subtransaction to be created.
 type SaveSubTransaction struct {
 	Amount     int64  `json:"amount"`
@@ -87,6 +237,30 @@ type SaveSubTransaction struct {
 	Memo       string `json:"memo,omitempty"`
 }
 
+// ToSaveTransaction converts an existing transaction into a SaveTransaction
+// carrying all of its current values, ready to be modified and updated.
+func (t Transaction) ToSaveTransaction() SaveTransaction {
+	save := SaveTransaction{
+		AccountID:  t.AccountID,
+		Date:       t.Date,
+		Amount:     t.Amount,
+		PayeeID:    t.PayeeID,
+		CategoryID: t.CategoryID,
+		Memo:       t.Memo,
+		Cleared:    t.Cleared,
+		Approved:   t.Approved,
+		FlagColor:  t.FlagColor,
+		ImportID:   t.ImportID,
+	}
+
+	// Payee name is only needed when the payee is not yet linked by ID.
+	if save.PayeeID == "" {
+		save.PayeeName = t.PayeeName
+	}
+
+	return save
+}
+
 // ClearedStatus represents the cleared state of a transaction.
 type ClearedStatus string
 
@@ -99,6 +273,17 @@ const (
 	ClearedStatusReconciled ClearedStatus = "reconciled"
 )
 
+// FlagColors lists the flag colors accepted by the YNAB API.
+var FlagColors = []string{"red", "orange", "yellow", "green", "blue", "purple"}
+
+// Transaction types accepted by TransactionOptions.Type.
+const (
+	// TransactionTypeUnapproved selects transactions that are not yet approved.
+	TransactionTypeUnapproved = "unapproved"
+	// TransactionTypeUncategorized selects transactions without a category.
+	TransactionTypeUncategorized = "uncategorized"
+)
+
 // TransactionOptions contains optional parameters for fetching transactions.
 type TransactionOptions struct {
 	// SinceDate filters transactions to those on or after this date (ISO format: YYYY-MM-DD).
@@ -119,6 +304,18 @@ type AccountsResponse struct {
 	} `json:"data"`
 }
 
+// SaveAccountRequest wraps an account for the create account request.
+type SaveAccountRequest struct {
+	Account SaveAccount `json:"account"`
+}
+
+// AccountResponse wraps a single account response.
+type AccountResponse struct {
+	Data struct {
+		Account Account `json:"account"`
+	} `json:"data"`
+}
+
 // TransactionsResponse wraps the transactions list response.
 type TransactionsResponse struct {
 	Data struct {
@@ -127,6 +324,22 @@ type TransactionsResponse struct {
 	} `json:"data"`
 }
 
+// PayeesResponse wraps the payees list response.
+type PayeesResponse struct {
+	Data struct {
+		Payees          []Payee `json:"payees"`
+		ServerKnowledge int64   `json:"server_knowledge"`
+	} `json:"data"`
+}
+
+// CategoriesResponse wraps the category groups list response.
+type CategoriesResponse struct {
+	Data struct {
+		CategoryGroups  []CategoryGroup `json:"category_groups"`
+		ServerKnowledge int64           `json:"server_knowledge"`
+	} `json:"data"`
+}
+
 // SaveTransactionsRequest is the request body for creating transactions.
 type SaveTransactionsRequest struct {
 	Transaction  *SaveTransaction  `json:"transaction,omitempty"`
@@ -144,6 +357,19 @@ type SaveTransactionsResponse struct {
 	} `json:"data"`
 }
 
+// TransactionResponse wraps a single transaction response.
+type TransactionResponse struct {
+	Data struct {
+		Transaction     Transaction `json:"transaction"`
+		ServerKnowledge int64       `json:"server_knowledge"`
+	} `json:"data"`
+}
+
+// UpdateTransactionsRequest is the request body for bulk updating transactions.
+type UpdateTransactionsRequest struct {
+	Transactions []SaveTransactionWithID `json:"transactions"`
+}
+
 // BudgetSummary represents a YNAB budget summary.
 type BudgetSummary struct {
 	ID             string          `json:"id"`
@@ -173,6 +399,73 @@ type CurrencyFormat struct {
 	DisplaySymbol    bool   `json:"display_symbol"`
 }
 
+// Format renders milliunits the way the budget displays amounts, e.g.
+// "-1.234,56 €" for a German euro budget. The symbol is left out when
+// DisplaySymbol is false.
+func (f CurrencyFormat) Format(milliunits int64) string {
+	sign := ""
+	if milliunits < 0 {
+		sign = "-"
+		milliunits = -milliunits
+	}
+
+	digits := f.DecimalDigits
+	scale := int64(math.Pow10(3 - min(digits, 3)))
+	units := (milliunits + scale/2) / scale
+	divisor := int64(math.Pow10(min(digits, 3)))
+	whole, fraction := units/divisor, units%divisor
+
+	number := groupThousands(formatInt64(whole), f.GroupSeparator)
+	if digits > 0 {
+		separator := f.DecimalSeparator
+		if separator == "" {
+			separator = "."
+		}
+		frac := formatInt64(fraction)
+		number += separator + strings.Repeat("0", min(digits, 3)-len(frac)) + frac
+	}
+
+	if !f.DisplaySymbol || f.CurrencySymbol == "" {
+		return sign + number
+	}
+	if f.SymbolFirst {
+		return sign + f.CurrencySymbol + number
+	}
+	return sign + number + " " + f.CurrencySymbol
+}
+
+// groupThousands inserts sep between every group of three digits.
+func groupThousands(digits, sep string) string {
+	if sep == "" || len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
+
+// BudgetSettings represents the date and currency settings of a budget.
+type BudgetSettings struct {
+	DateFormat     *DateFormat     `json:"date_format"`
+	CurrencyFormat *CurrencyFormat `json:"currency_format"`
+}
+
+// BudgetSettingsResponse wraps the budget settings response.
+type BudgetSettingsResponse struct {
+	Data struct {
+		Settings BudgetSettings `json:"settings"`
+	} `json:"data"`
+}
+
 // BudgetSummaryResponse wraps the budgets list response.
 type BudgetSummaryResponse struct {
 	Data struct {
@@ -191,9 +484,11 @@ func MilliunitsToFloat(milliunits int64) float64 {
 }
 
 // FloatToMilliunits converts a float64 amount to YNAB milliunits.
+// The result is rounded so that binary floating point error (e.g. 0.29*1000 =
+// 289.999...) does not lose a milliunit.
 // Example: $123.93 = 123930 milliunits
 func FloatToMilliunits(amount float64) int64 {
-	return int64(amount * 1000)
+	return int64(math.Round(amount * 1000))
 }
 
 // GenerateImportID creates a YNAB-compatible import ID for deduplication.
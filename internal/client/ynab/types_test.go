@@ -1,6 +1,7 @@
 package ynab
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -182,3 +183,104 @@ func TestClearedStatus_Constants(t *testing.T) {
 	assert.Equal(t, ClearedStatus("uncleared"), ClearedStatusUncleared)
 	assert.Equal(t, ClearedStatus("reconciled"), ClearedStatusReconciled)
 }
+
+func TestFindAccount(t *testing.T) {
+	accounts := []Account{
+		{ID: "acc-1", Name: "Checking"},
+		{ID: "acc-2", Name: "Miles & More"},
+		{ID: "acc-3", Name: "Savings"},
+		{ID: "acc-4", Name: "Savings"},
+		{ID: "acc-5", Name: "Old Card", Deleted: true},
+	}
+
+	tests := []struct {
+		name       string
+		idOrName   string
+		expectedID string
+		expectErr  string
+	}{
+		{name: "by id", idOrName: "acc-1", expectedID: "acc-1"},
+		{name: "by name case-insensitive", idOrName: "miles & more", expectedID: "acc-2"},
+		{name: "ambiguous name", idOrName: "Savings", expectErr: "not unique"},
+		{name: "deleted name", idOrName: "Old Card", expectErr: "resource not found"},
+		{name: "unknown", idOrName: "nope", expectErr: "resource not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			account, err := FindAccount(accounts, tt.idOrName)
+
+			// Assert
+			if tt.expectErr != "" {
+				assert.ErrorContains(t, err, tt.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedID, account.ID)
+		})
+	}
+}
+
+func TestCurrencyFormat_Format(t *testing.T) {
+	euro := CurrencyFormat{ISOCode: "EUR", DecimalDigits: 2, DecimalSeparator: ",", GroupSeparator: ".", CurrencySymbol: "€", DisplaySymbol: true}
+	dollar := CurrencyFormat{ISOCode: "USD", DecimalDigits: 2, DecimalSeparator: ".", GroupSeparator: ",", CurrencySymbol: "$", SymbolFirst: true, DisplaySymbol: true}
+	yen := CurrencyFormat{ISOCode: "JPY", DecimalDigits: 0, GroupSeparator: ",", CurrencySymbol: "¥", SymbolFirst: true, DisplaySymbol: true}
+
+	tests := []struct {
+		name       string
+		format     CurrencyFormat
+		milliunits int64
+		expected   string
+	}{
+		{name: "euro with grouping", format: euro, milliunits: -1234560, expected: "-1.234,56 €"},
+		{name: "euro small amount", format: euro, milliunits: 50, expected: "0,05 €"},
+		{name: "dollar symbol first", format: dollar, milliunits: 123456789, expected: "$123,456.79"},
+		{name: "no decimals", format: yen, milliunits: -1500000, expected: "-¥1,500"},
+		{name: "symbol hidden", format: CurrencyFormat{DecimalDigits: 2, DecimalSeparator: ".", CurrencySymbol: "€"}, milliunits: 1000, expected: "1.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			result := tt.format.Format(tt.milliunits)
+
+			// Assert
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestIsLiabilityType(t *testing.T) {
+	// Assert
+	assert.False(t, IsLiabilityType(AccountTypeChecking))
+	assert.False(t, IsLiabilityType(AccountTypeOtherAsset))
+	assert.True(t, IsLiabilityType(AccountTypeCreditCard))
+	assert.True(t, IsLiabilityType(AccountTypeMortgage))
+	assert.True(t, IsValidAccountType(AccountTypeStudentLoan))
+	assert.False(t, IsValidAccountType("brokerage"))
+}
+
+func TestSaveTransactionWithID_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		update   SaveTransactionWithID
+		expected string
+	}{
+		{name: "only id", update: SaveTransactionWithID{ID: "tx-1"}, expected: `{"id":"tx-1"}`},
+		{name: "set values", update: SaveTransactionWithID{ID: "tx-1", Memo: Ptr("Miete"), FlagColor: Ptr("red"), Cleared: ClearedStatusCleared}, expected: `{"cleared":"cleared","flag_color":"red","id":"tx-1","memo":"Miete"}`},
+		{name: "cleared values", update: SaveTransactionWithID{ImportID: "imp-1", Memo: Ptr(""), CategoryID: Ptr(""), FlagColor: Ptr("")}, expected: `{"category_id":null,"flag_color":null,"import_id":"imp-1","memo":null}`},
+		{name: "unapprove", update: SaveTransactionWithID{ID: "tx-1", Approved: Ptr(false), Amount: Ptr(int64(0))}, expected: `{"amount":0,"approved":false,"id":"tx-1"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			data, err := json.Marshal(tt.update)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(data))
+		})
+	}
+}
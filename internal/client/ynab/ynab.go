@@ -15,11 +15,18 @@
 //	}
 //
 //	transactions, err := client.GetTransactionsByAccount("account-id", ynab.TransactionOptions{})
+//
+// Code that needs only part of the API should accept one of the narrow
+// interfaces (BudgetReader, TransactionWriter, ...) or API instead of
+// *Client, so it also works with NewCachingClient, NewReadOnlyClient or a
+// substitute implementation.
 package ynab
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-resty/resty/v2"
@@ -41,18 +48,32 @@ const (
 
 	// DefaultRetryMaxWaitTime is the maximum wait time between retries.
 	DefaultRetryMaxWaitTime = 5 * time.Second
+
+	// LastUsedBudgetID is accepted by the API in place of a budget ID and
+	// refers to the budget last used by the token owner. It allows a client
+	// to be created before a budget is chosen, e.g. to list the budgets.
+	LastUsedBudgetID = "last-used"
 )
 
 // Config holds the configuration for the YNAB client.
 type Config struct {
 	// APIKey is the personal access token for authentication.
 	APIKey string
+	// TokenSource supplies OAuth access tokens instead of APIKey. Tokens are
+	// refreshed once when the API rejects them with 401.
+	TokenSource TokenSource
 	// BudgetID is the default budget ID for API operations.
 	BudgetID string
 	// BaseURL overrides the default API base URL (optional, for testing).
 	BaseURL string
 	// Timeout overrides the default request timeout (optional).
 	Timeout time.Duration
+	// RecordPath records every request and response to this cassette file,
+	// with the token and account names redacted (optional, for bug reports).
+	RecordPath string
+	// ReplayPath answers requests from this cassette file instead of the API
+	// (optional). It cannot be combined with RecordPath.
+	ReplayPath string
 }
 
 // Client is a reusable YNAB API client.
@@ -66,14 +87,18 @@ type Client struct {
 
 // NewClient creates a new YNAB API client with the given configuration.
 func NewClient(cfg Config, logger log.Logger) (*Client, error) {
-	if cfg.APIKey == "" {
-		return nil, fmt.Errorf("api key is required")
+	if cfg.APIKey == "" && cfg.TokenSource == nil {
+		return nil, fmt.Errorf("api key is required without a token source")
 	}
 
 	if cfg.BudgetID == "" {
 		return nil, fmt.Errorf("budget id is required")
 	}
 
+	if cfg.RecordPath != "" && cfg.ReplayPath != "" {
+		return nil, fmt.Errorf("record and replay cannot be combined")
+	}
+
 	baseURL := cfg.BaseURL
 	if baseURL == "" {
 		baseURL = DefaultBaseURL
@@ -89,19 +114,44 @@ func NewClient(cfg Config, logger log.Logger) (*Client, error) {
 		SetTimeout(timeout).
 		SetHeader("Content-Type", "application/json").
 		SetHeader("Accept", "application/json").
-		SetAuthToken(cfg.APIKey).
 		SetRetryCount(DefaultRetryCount).
 		SetRetryWaitTime(DefaultRetryWaitTime).
 		SetRetryMaxWaitTime(DefaultRetryMaxWaitTime).
 		AddRetryCondition(func(r *resty.Response, err error) bool {
-			// Retry on network errors or 429 (rate limit) or 5xx errors
+			// Retry on network errors or 429 (rate limit) or 5xx errors;
+			// a request missing from a replayed cassette stays missing
 			if err != nil {
-				return true
+				return !errors.Is(err, ErrNotRecorded)
 			}
 			return r.StatusCode() == http.StatusTooManyRequests ||
 				r.StatusCode() >= http.StatusInternalServerError
 		})
 
+	transport := httpClient.GetClient().Transport
+	switch {
+	case cfg.RecordPath != "":
+		rec, err := newRecorder(transport, cfg.RecordPath)
+		if err != nil {
+			return nil, err
+		}
+		transport = rec
+		logger.Infof("Recording YNAB API traffic to %s", cfg.RecordPath)
+	case cfg.ReplayPath != "":
+		rep, err := newReplayer(cfg.ReplayPath)
+		if err != nil {
+			return nil, err
+		}
+		transport = rep
+		logger.Infof("Replaying YNAB API traffic from %s", cfg.ReplayPath)
+	}
+
+	if cfg.TokenSource != nil {
+		transport = &tokenTransport{base: transport, source: cfg.TokenSource}
+	} else {
+		httpClient.SetAuthToken(cfg.APIKey)
+	}
+	httpClient.SetTransport(transport)
+
 	client := &Client{
 		httpClient: httpClient,
 		baseURL:    baseURL,
@@ -142,7 +192,7 @@ func (c *Client) GetBudgets(includeAccounts bool) ([]BudgetSummary, error) {
 	}
 
 	if resp.IsError() {
-		return nil, mapHTTPStatusToError(resp.StatusCode(), &errResp.Error)
+		return nil, newError(resp, &errResp)
 	}
 
 	c.logger.Debugf("Fetched %d budgets", len(result.Data.Budgets))
@@ -150,6 +200,42 @@ func (c *Client) GetBudgets(includeAccounts bool) ([]BudgetSummary, error) {
 	return result.Data.Budgets, nil
 }
 
+// GetBudgetSettings retrieves the date and currency settings of the configured budget.
+func (c *Client) GetBudgetSettings() (*BudgetSettings, error) {
+	c.logger.Debugf("Fetching settings for budget: %s", c.budgetID)
+
+	var result BudgetSettingsResponse
+	var errResp ErrorResponse
+
+	resp, err := c.httpClient.R().
+		SetResult(&result).
+		SetError(&errResp).
+		Get(fmt.Sprintf("/budgets/%s/settings", c.budgetID))
+
+	if err != nil {
+		return nil, fmt.Errorf("fetching budget settings: %w", err)
+	}
+
+	if resp.IsError() {
+		return nil, newError(resp, &errResp)
+	}
+
+	return &result.Data.Settings, nil
+}
+
+// BudgetCurrencyFormat returns the currency format of the configured budget,
+// or plain two-decimal amounts when the budget has none.
+func BudgetCurrencyFormat(r BudgetReader) (CurrencyFormat, error) {
+	settings, err := r.GetBudgetSettings()
+	if err != nil {
+		return CurrencyFormat{}, fmt.Errorf("fetching budget settings: %w", err)
+	}
+	if settings.CurrencyFormat == nil {
+		return CurrencyFormat{DecimalDigits: 2, DecimalSeparator: "."}, nil
+	}
+	return *settings.CurrencyFormat, nil
+}
+
 // GetAccounts retrieves all accounts for the configured budget.
 func (c *Client) GetAccounts() ([]Account, error) {
 	c.logger.Debugf("Fetching accounts for budget: %s", c.budgetID)
@@ -167,7 +253,7 @@ func (c *Client) GetAccounts() ([]Account, error) {
 	}
 
 	if resp.IsError() {
-		return nil, mapHTTPStatusToError(resp.StatusCode(), &errResp.Error)
+		return nil, newError(resp, &errResp)
 	}
 
 	c.logger.Debugf("Fetched %d accounts", len(result.Data.Accounts))
@@ -175,7 +261,55 @@ func (c *Client) GetAccounts() ([]Account, error) {
 	return result.Data.Accounts, nil
 }
 
-// GetHTTPClient returns the underlying HTTP client for testing purposes.
-func (c *Client) GetHTTPClient() *resty.Client {
-	return c.httpClient
+// CreateAccount creates an account with a starting balance in the configured budget.
+func (c *Client) CreateAccount(account SaveAccount) (*Account, error) {
+	c.logger.Debugf("Creating account %q in budget: %s", account.Name, c.budgetID)
+
+	var result AccountResponse
+	var errResp ErrorResponse
+
+	resp, err := c.httpClient.R().
+		SetBody(&SaveAccountRequest{Account: account}).
+		SetResult(&result).
+		SetError(&errResp).
+		Post(fmt.Sprintf("/budgets/%s/accounts", c.budgetID))
+
+	if err != nil {
+		return nil, fmt.Errorf("creating account: %w", err)
+	}
+
+	if resp.IsError() {
+		return nil, newError(resp, &errResp)
+	}
+
+	c.logger.Debugf("Created account %s", result.Data.Account.ID)
+
+	return &result.Data.Account, nil
+}
+
+// FindAccount returns the open account whose ID or name (case-insensitive) equals idOrName.
+func FindAccount(accounts []Account, idOrName string) (*Account, error) {
+	for i := range accounts {
+		if accounts[i].ID == idOrName {
+			return &accounts[i], nil
+		}
+	}
+
+	var found *Account
+	for i := range accounts {
+		acc := &accounts[i]
+		if acc.Deleted || !strings.EqualFold(acc.Name, idOrName) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("account name %q is not unique, use the account ID", idOrName)
+		}
+		found = acc
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("%w: account %q", ErrNotFound, idOrName)
+	}
+
+	return found, nil
 }
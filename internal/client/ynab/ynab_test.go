@@ -210,6 +210,48 @@ func (s *AccountsTestSuite) TestGetAccounts_WithNotFound_ReturnsError() {
 	s.ErrorIs(err, ErrNotFound)
 }
 
+func (s *AccountsTestSuite) TestCreateAccount_WithValidRequest_ReturnsAccount() {
+	// Arrange
+	var body SaveAccountRequest
+	s.setupServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("POST", r.Method)
+		s.Equal("/budgets/test-budget-id/accounts", r.URL.Path)
+		s.Require().NoError(json.NewDecoder(r.Body).Decode(&body))
+
+		var response AccountResponse
+		response.Data.Account = Account{ID: "acc-new", Name: body.Account.Name, Type: body.Account.Type, Balance: body.Account.Balance}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(response)
+	})
+
+	// Act
+	account, err := s.client.CreateAccount(SaveAccount{Name: "Cash", Type: AccountTypeCash, Balance: 50000})
+
+	// Assert
+	s.NoError(err)
+	s.Equal("acc-new", account.ID)
+	s.Equal(SaveAccount{Name: "Cash", Type: "cash", Balance: 50000}, body.Account)
+}
+
+func (s *AccountsTestSuite) TestCreateAccount_WithBadRequest_ReturnsError() {
+	// Arrange
+	s.setupServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(ErrorResponse{
+			Error: APIError{ID: "400", Name: "bad_request", Detail: "type is invalid"},
+		})
+	})
+
+	// Act
+	account, err := s.client.CreateAccount(SaveAccount{Name: "Broker", Type: "brokerage"})
+
+	// Assert
+	s.Nil(account)
+	s.ErrorIs(err, ErrBadRequest)
+}
+
 // BudgetsTestSuite groups budget-related API tests.
 type BudgetsTestSuite struct {
 	suite.Suite
@@ -365,3 +407,44 @@ func (s *BudgetsTestSuite) TestGetBudgets_WithNotFound_ReturnsError() {
 	s.Nil(budgets)
 	s.ErrorIs(err, ErrNotFound)
 }
+
+func (s *BudgetsTestSuite) TestGetBudgetSettings_WithValidResponse_ReturnsCurrencyFormat() {
+	// Arrange
+	var response BudgetSettingsResponse
+	response.Data.Settings = BudgetSettings{
+		CurrencyFormat: &CurrencyFormat{ISOCode: "EUR", DecimalDigits: 2},
+	}
+
+	s.setupServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("GET", r.Method)
+		s.Equal("/budgets/test-budget-id/settings", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	})
+
+	// Act
+	settings, err := s.client.GetBudgetSettings()
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().NotNil(settings.CurrencyFormat)
+	s.Equal("EUR", settings.CurrencyFormat.ISOCode)
+	s.Equal(2, settings.CurrencyFormat.DecimalDigits)
+}
+
+func (s *BudgetsTestSuite) TestBudgetCurrencyFormat_WithoutCurrencyFormat_DefaultsToTwoDecimals() {
+	// Arrange
+	var response BudgetSettingsResponse
+	s.setupServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	})
+
+	// Act
+	format, err := BudgetCurrencyFormat(s.client)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(CurrencyFormat{DecimalDigits: 2, DecimalSeparator: "."}, format)
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Statement sources that can be mapped to a YNAB account.
+const (
+	// SourceMilesMore identifies Miles & More credit card statements by card number.
+	SourceMilesMore = "milesmore"
+	// SourceSparkasse identifies Sparkasse account statements by IBAN.
+	SourceSparkasse = "sparkasse"
+)
+
+// AccountMapping links the account a statement belongs to with a YNAB account.
+type AccountMapping struct {
+	// Source is the statement source (milesmore or sparkasse).
+	Source string `json:"source"`
+	// CardNumber identifies a Miles & More card. Masked digits ("*") as printed
+	// on the statement are allowed.
+	CardNumber string `json:"card_number,omitempty"`
+	// IBAN identifies a Sparkasse account.
+	IBAN string `json:"iban,omitempty"`
+	// AccountID is the YNAB account the statement is imported into.
+	AccountID string `json:"account_id"`
+	// AccountName is the YNAB account name at the time of mapping (informational).
+	AccountName string `json:"account_name,omitempty"`
+}
+
+// Identifier returns the card number or IBAN, depending on the source.
+func (m *AccountMapping) Identifier() string {
+	if m.Source == SourceSparkasse {
+		return m.IBAN
+	}
+	return m.CardNumber
+}
+
+// AccountLabel returns the account name and ID of a mapping for messages.
+func AccountLabel(m AccountMapping) string {
+	if m.AccountName == "" {
+		return m.AccountID
+	}
+	return fmt.Sprintf("%q (%s)", m.AccountName, m.AccountID)
+}
+
+// Validate checks that the mapping has a known source, a well-formed
+// identifier for it and an account ID.
+func (m *AccountMapping) Validate() error {
+	switch m.Source {
+	case SourceMilesMore:
+		if !ValidCardNumber(m.CardNumber) {
+			return fmt.Errorf("card_number %q is not a card number", m.CardNumber)
+		}
+	case SourceSparkasse:
+		if !ValidIBAN(m.IBAN) {
+			return fmt.Errorf("iban %q is not a valid IBAN", m.IBAN)
+		}
+	default:
+		return fmt.Errorf("unknown source %q (expected %s or %s)", m.Source, SourceMilesMore, SourceSparkasse)
+	}
+
+	if m.AccountID == "" {
+		return fmt.Errorf("account_id is required")
+	}
+	return nil
+}
+
+// AccountFor returns the mapping for the statement account identified by a
+// card number or IBAN.
+func (c *Config) AccountFor(source, identifier string) (AccountMapping, bool) {
+	for _, m := range c.Accounts {
+		if m.Source != source {
+			continue
+		}
+		switch source {
+		case SourceMilesMore:
+			if cardNumbersMatch(m.CardNumber, identifier) {
+				return m, true
+			}
+		case SourceSparkasse:
+			if NormalizeIBAN(m.IBAN) == NormalizeIBAN(identifier) {
+				return m, true
+			}
+		}
+	}
+	return AccountMapping{}, false
+}
+
+// StatementAccount returns the mapping for the statement account identified
+// by a card number or IBAN, or an error telling how to choose the account.
+func (c *Config) StatementAccount(source, identifier string) (AccountMapping, error) {
+	if identifier == "" {
+		return AccountMapping{}, fmt.Errorf("the statement does not identify its account, use --account-id")
+	}
+	m, ok := c.AccountFor(source, identifier)
+	if !ok {
+		return AccountMapping{}, fmt.Errorf("%s is not mapped to a YNAB account, use --account-id or add it with mp config init", identifier)
+	}
+	return m, nil
+}
+
+// NormalizeCardNumber removes spaces and dashes and upper-cases mask characters.
+func NormalizeCardNumber(s string) string {
+	s = strings.NewReplacer(" ", "", "-", "", "x", "*", "X", "*").Replace(s)
+	return strings.TrimSpace(s)
+}
+
+// ValidCardNumber reports whether s looks like a full or masked card number
+// with at least the first and last four digits readable.
+func ValidCardNumber(s string) bool {
+	s = NormalizeCardNumber(s)
+	if len(s) < 12 || len(s) > 19 {
+		return false
+	}
+	for i, r := range s {
+		masked := r == '*' && i >= 4 && i < len(s)-4
+		if (r < '0' || r > '9') && !masked {
+			return false
+		}
+	}
+	return true
+}
+
+// cardNumbersMatch compares two card numbers position by position, treating
+// masked digits as wildcards.
+func cardNumbersMatch(a, b string) bool {
+	a, b = NormalizeCardNumber(a), NormalizeCardNumber(b)
+	if len(a) != len(b) || a == "" {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] && a[i] != '*' && b[i] != '*' {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeIBAN removes spaces and upper-cases the IBAN.
+func NormalizeIBAN(s string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(s), " ", ""))
+}
+
+// ValidIBAN checks the length, characters and ISO 13616 check digits of an IBAN.
+func ValidIBAN(s string) bool {
+	s = NormalizeIBAN(s)
+	if len(s) < 15 || len(s) > 34 {
+		return false
+	}
+
+	// Move the country code and check digits to the end and map letters to 10..35
+	var digits strings.Builder
+	for _, r := range s[4:] + s[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			fmt.Fprintf(&digits, "%d", r-'A'+10)
+		default:
+			return false
+		}
+	}
+
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+	if !ok {
+		return false
+	}
+	return new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
@@ -5,12 +5,39 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 )
 
 // Config represents the application configuration.
 // It is structured to support multiple service configurations.
 type Config struct {
 	YNAB YNABConfig `json:"ynab"`
+	// Transfers configures detection of transfers between accounts (optional).
+	Transfers TransfersConfig `json:"transfers"`
+	// Fees holds the card contract fees used by the fee audit (optional).
+	Fees FeesConfig `json:"fees"`
+	// Currency configures conversion of foreign-currency accounts into the budget currency (optional).
+	Currency CurrencyConfig `json:"currency"`
+	// Accounts maps statement accounts (card numbers, IBANs) to YNAB accounts (optional).
+	Accounts []AccountMapping `json:"accounts,omitempty"`
+	// Rules is the rules file, relative to the config file (optional, default:
+	// rules.yaml/rules.json next to the config file).
+	Rules string `json:"rules,omitempty"`
+	// Profiles holds named budget setups that override the settings above (optional).
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+	// DefaultProfile is the profile used when none is chosen with --profile.
+	DefaultProfile string `json:"default_profile,omitempty"`
+	// ProfileName is the name of the profile the configuration was resolved for.
+	ProfileName string `json:"-"`
+	// Path is the config file with the highest precedence that was loaded.
+	Path string `json:"-"`
+	// Files are all config files that were loaded, lowest precedence first.
+	Files []string `json:"-"`
+
+	// sources maps dotted keys to the layer that set them.
+	sources map[string]string
+	// overrides are environment and flag values, reapplied after profile resolution.
+	overrides []override
 	// Future configurations can be added here:
 	// Sparkasse SparkasseConfig `json:"sparkasse"`
 }
@@ -21,38 +48,201 @@ type YNABConfig struct {
 	APIKey string `json:"api_key"`
 	// BudgetID is the default budget to use for API operations.
 	BudgetID string `json:"budget_id"`
+	// BaseURL overrides the YNAB API URL, e.g. http://127.0.0.1:8080/v1 for
+	// the fake started with mp dev fake-ynab.
+	BaseURL string `json:"base_url,omitempty"`
+	// OAuth configures login with a YNAB OAuth app instead of APIKey.
+	OAuth OAuthConfig `json:"oauth,omitzero"`
+}
+
+// DefaultOAuthRedirectPort is the loopback port of the default redirect URI
+// (http://127.0.0.1:8976/oauth/callback) to register for the OAuth app.
+const DefaultOAuthRedirectPort = 8976
+
+// OAuthConfig identifies a YNAB OAuth app. Access and refresh tokens obtained
+// with `mp auth login` are kept in the secret vault, not in the config.
+type OAuthConfig struct {
+	// ClientID is the app's client ID. When set, OAuth is used instead of api_key.
+	ClientID string `json:"client_id,omitempty"`
+	// ClientSecret is the app's secret; secret references are supported.
+	ClientSecret string `json:"client_secret,omitempty"`
+	// RedirectPort is the loopback port registered in the app's redirect URI
+	// (default 8976).
+	RedirectPort int `json:"redirect_port,omitempty"`
+	// TokenEntry is the vault entry holding the tokens (default ynab-oauth,
+	// or ynab-oauth-<profile> for named profiles).
+	TokenEntry string `json:"token_entry,omitempty"`
+}
+
+// Enabled reports whether an OAuth app is configured.
+func (o *OAuthConfig) Enabled() bool {
+	return o.ClientID != ""
 }
 
-// LoadFromFile reads and parses a JSON configuration file from the given path.
+// Validate checks that the OAuth app settings are complete.
+func (o *OAuthConfig) Validate() error {
+	if o.ClientSecret == "" {
+		return fmt.Errorf("oauth.client_secret is required with oauth.client_id")
+	}
+	if o.RedirectPort < 0 || o.RedirectPort > 65535 {
+		return fmt.Errorf("oauth.redirect_port must be between 1 and 65535")
+	}
+	return nil
+}
+
+// TransfersConfig holds settings for detecting transfers between accounts.
+type TransfersConfig struct {
+	// PayeePatterns are regular expressions matching settlement payees.
+	// When empty, built-in patterns for credit card settlements are used.
+	PayeePatterns []string `json:"payee_patterns,omitempty"`
+}
+
+// FeesConfig holds the fees agreed in the credit card contract.
+type FeesConfig struct {
+	// ForeignRatePercent is the foreign transaction fee in percent of the EUR amount (e.g. 1.75).
+	ForeignRatePercent float64 `json:"foreign_rate_percent"`
+	// Tolerance is the difference in EUR accepted for rounding (default 0.01).
+	Tolerance float64 `json:"tolerance,omitempty"`
+}
+
+// Validate checks that the fee settings are within sensible bounds.
+func (f *FeesConfig) Validate() error {
+	if f.ForeignRatePercent < 0 || f.ForeignRatePercent >= 100 {
+		return fmt.Errorf("foreign_rate_percent must be between 0 and 100")
+	}
+	if f.Tolerance < 0 {
+		return fmt.Errorf("tolerance must not be negative")
+	}
+	return nil
+}
+
+// CurrencyConfig holds the rate source used to convert statements of accounts
+// that are not in the budget currency.
+type CurrencyConfig struct {
+	// RateSource is "ecb" (default) for the ECB reference rate history or
+	// "fixed" for the FixedRates table.
+	RateSource string `json:"rate_source,omitempty"`
+	// RatesFile is the ECB eurofxref-hist CSV or XML file (default: data directory).
+	RatesFile string `json:"rates_file,omitempty"`
+	// FixedRates are units of each currency per unit of the budget currency.
+	FixedRates map[string]float64 `json:"fixed_rates,omitempty"`
+}
+
+// Validate checks the rate source and the fixed rate table.
+func (c *CurrencyConfig) Validate() error {
+	switch c.RateSource {
+	case "", "ecb":
+	case "fixed":
+		if len(c.FixedRates) == 0 {
+			return fmt.Errorf("fixed_rates are required for rate_source fixed")
+		}
+	default:
+		return fmt.Errorf("unknown rate_source %q (expected ecb or fixed)", c.RateSource)
+	}
+
+	for currency, rate := range c.FixedRates {
+		if rate <= 0 {
+			return fmt.Errorf("fixed rate for %s must be positive", currency)
+		}
+	}
+	return nil
+}
+
+// LoadFromFile reads and parses a single JSON, YAML or TOML configuration
+// file from the given path, without the other layers of Load.
 func LoadFromFile(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
+	values, err := readValues(path)
 	if err != nil {
-		return nil, fmt.Errorf("reading config file: %w", err)
+		return nil, err
 	}
 
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("parsing config file: %w", err)
+	cfg, err := decodeValues(values)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.sources = make(map[string]string)
+	mergeValues(make(map[string]any), values, "", path, cfg.sources)
+	cfg.Path = path
+	cfg.Files = []string{path}
+
+	return cfg, nil
+}
+
+// SaveToFile writes the configuration as indented JSON. The file is only
+// readable by the user, since it holds the API token.
+func (c *Config) SaveToFile(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding config: %w", err)
 	}
 
-	return &cfg, nil
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("writing config file: %w", err)
+	}
+	return nil
 }
 
-// Validate checks that the configuration contains all required fields.
+// Validate checks that the configuration contains all required fields and
+// that the optional sections are well-formed. With profiles, every profile is
+// validated with its inherited settings applied.
 func (c *Config) Validate() error {
+	if len(c.Profiles) > 0 {
+		if c.DefaultProfile != "" {
+			if _, ok := c.Profiles[c.DefaultProfile]; !ok {
+				return fmt.Errorf("default_profile %q is not a configured profile", c.DefaultProfile)
+			}
+		}
+		for _, name := range c.ProfileNames() {
+			resolved, err := c.Profile(name)
+			if err != nil {
+				return err
+			}
+			if err := resolved.Validate(); err != nil {
+				return fmt.Errorf("profile %s: %w", name, err)
+			}
+		}
+		return nil
+	}
+
 	if err := c.YNAB.Validate(); err != nil {
 		return fmt.Errorf("ynab config: %w", err)
 	}
+	if err := c.Fees.Validate(); err != nil {
+		return fmt.Errorf("fees config: %w", err)
+	}
+	if err := c.Currency.Validate(); err != nil {
+		return fmt.Errorf("currency config: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for i := range c.Accounts {
+		m := &c.Accounts[i]
+		if err := m.Validate(); err != nil {
+			return fmt.Errorf("accounts[%d]: %w", i, err)
+		}
+		key := m.Source + ":" + NormalizeCardNumber(NormalizeIBAN(m.Identifier()))
+		if seen[key] {
+			return fmt.Errorf("accounts[%d]: %s %s is mapped twice", i, m.Source, m.Identifier())
+		}
+		seen[key] = true
+	}
 	return nil
 }
 
 // Validate checks that the YNAB configuration contains all required fields.
 func (y *YNABConfig) Validate() error {
-	if y.APIKey == "" {
-		return fmt.Errorf("api_key is required")
+	if y.APIKey == "" && !y.OAuth.Enabled() {
+		return fmt.Errorf("api_key or oauth.client_id is required")
 	}
 	if y.BudgetID == "" {
 		return fmt.Errorf("budget_id is required")
 	}
+	if y.OAuth.Enabled() {
+		return y.OAuth.Validate()
+	}
 	return nil
 }
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// ConfigTestSuite groups configuration tests.
+type ConfigTestSuite struct {
+	suite.Suite
+	cfg Config
+}
+
+func TestConfigTestSuite(t *testing.T) {
+	suite.Run(t, new(ConfigTestSuite))
+}
+
+func (s *ConfigTestSuite) SetupTest() {
+	s.cfg = Config{
+		YNAB: YNABConfig{APIKey: "token", BudgetID: "budget-1"},
+		Accounts: []AccountMapping{
+			{Source: SourceMilesMore, CardNumber: "5426********1495", AccountID: "acc-card"},
+			{Source: SourceSparkasse, IBAN: "DE89 3704 0044 0532 0130 00", AccountID: "acc-checking"},
+		},
+	}
+}
+
+func (s *ConfigTestSuite) TestValidate_WithValidAccounts_Succeeds() {
+	// Act
+	err := s.cfg.Validate()
+
+	// Assert
+	s.NoError(err)
+}
+
+func (s *ConfigTestSuite) TestValidate_WithInvalidIBAN_ReturnsError() {
+	// Arrange
+	s.cfg.Accounts[1].IBAN = "DE89370400440532013001"
+
+	// Act
+	err := s.cfg.Validate()
+
+	// Assert
+	s.ErrorContains(err, "accounts[1]: iban")
+}
+
+func (s *ConfigTestSuite) TestValidate_WithDuplicateMapping_ReturnsError() {
+	// Arrange
+	s.cfg.Accounts = append(s.cfg.Accounts, AccountMapping{Source: SourceSparkasse, IBAN: "de89370400440532013000", AccountID: "acc-other"})
+
+	// Act
+	err := s.cfg.Validate()
+
+	// Assert
+	s.ErrorContains(err, "mapped twice")
+}
+
+func (s *ConfigTestSuite) TestValidate_WithUnknownSourceOrMissingAccount_ReturnsError() {
+	// Arrange
+	unknown := AccountMapping{Source: "n26", AccountID: "acc"}
+	missingAccount := AccountMapping{Source: SourceMilesMore, CardNumber: "5426123412341495"}
+
+	// Act & Assert
+	s.ErrorContains(unknown.Validate(), "unknown source")
+	s.ErrorContains(missingAccount.Validate(), "account_id is required")
+}
+
+func (s *ConfigTestSuite) TestAccountFor_MatchesMaskedCardAndFormattedIBAN() {
+	// Act
+	card, cardOK := s.cfg.AccountFor(SourceMilesMore, "5426 1234 5678 1495")
+	iban, ibanOK := s.cfg.AccountFor(SourceSparkasse, "de89370400440532013000")
+	_, otherOK := s.cfg.AccountFor(SourceMilesMore, "5426********9999")
+
+	// Assert
+	s.True(cardOK)
+	s.Equal("acc-card", card.AccountID)
+	s.True(ibanOK)
+	s.Equal("acc-checking", iban.AccountID)
+	s.False(otherOK)
+}
+
+func (s *ConfigTestSuite) TestValidCardNumber() {
+	// Assert
+	s.True(ValidCardNumber("5426********1495"))
+	s.True(ValidCardNumber("5426 1234 5678 1495"))
+	s.False(ValidCardNumber("****************"))
+	s.False(ValidCardNumber("1495"))
+}
+
+func (s *ConfigTestSuite) TestValidate_WithOAuthInsteadOfAPIKey_Succeeds() {
+	// Arrange
+	s.cfg.YNAB.APIKey = ""
+	s.cfg.YNAB.OAuth = OAuthConfig{ClientID: "client", ClientSecret: "vault:ynab-oauth-secret"}
+
+	// Act
+	err := s.cfg.Validate()
+
+	// Assert
+	s.NoError(err)
+}
+
+func (s *ConfigTestSuite) TestValidate_WithOAuthWithoutSecret_ReturnsError() {
+	// Arrange
+	s.cfg.YNAB.OAuth = OAuthConfig{ClientID: "client"}
+
+	// Act
+	err := s.cfg.Validate()
+
+	// Assert
+	s.ErrorContains(err, "oauth.client_secret is required")
+}
+
+func (s *ConfigTestSuite) TestSaveToFile_RoundTrips() {
+	// Arrange
+	path := filepath.Join(s.T().TempDir(), "config.json")
+
+	// Act
+	err := s.cfg.SaveToFile(path)
+	loaded, loadErr := LoadFromFile(path)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().NoError(loadErr)
+	s.Equal(s.cfg.Accounts, loaded.Accounts)
+	s.Equal(s.cfg.YNAB, loaded.YNAB)
+}
+
+func (s *ConfigTestSuite) TestStatementAccount_WithUnknownOrMissingIdentifier_ReturnsError() {
+	// Act
+	m, err := s.cfg.StatementAccount(SourceMilesMore, "5426 1234 5678 1495")
+	_, unmappedErr := s.cfg.StatementAccount(SourceMilesMore, "5426********9999")
+	_, missingErr := s.cfg.StatementAccount(SourceMilesMore, "")
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal("acc-card", m.AccountID)
+	s.ErrorContains(unmappedErr, "is not mapped to a YNAB account")
+	s.ErrorContains(missingErr, "does not identify its account")
+}
@@ -0,0 +1,478 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pgbytes/moneypenny/internal/secrets"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// SourceDefaults labels values that come from the built-in defaults.
+	SourceDefaults = "default"
+
+	// EnvPrefix is the prefix of environment variables that override config values.
+	EnvPrefix = "MONEYPENNY_"
+
+	// configFileName is the config file name in the XDG config directory.
+	configFileName = "config"
+
+	// localFileName is the project-local config file name in the working directory.
+	localFileName = ".moneypenny"
+)
+
+// FileExtensions are the supported config file formats, in lookup order.
+var FileExtensions = []string{".json", ".yaml", ".yml", ".toml"}
+
+// defaults are the built-in values below every other layer.
+var defaults = map[string]any{
+	"currency": map[string]any{"rate_source": "ecb"},
+}
+
+// secretKeys are the last key segments of values masked by Settings.
+var secretKeys = []string{"api_key", "client_secret"}
+
+// LoadOptions selects the layers merged by Load.
+type LoadOptions struct {
+	// File is an explicit config file (-f); it takes the place of the project-local file.
+	File string
+	// WorkDir is searched for the project-local .moneypenny file (default: current directory).
+	WorkDir string
+	// Environ holds the environment in os.Environ form; MONEYPENNY_* variables override values.
+	Environ []string
+	// Flags holds values set on the command line, applied last.
+	Flags []FlagValue
+}
+
+// FlagValue is a config value set by a command line flag.
+type FlagValue struct {
+	// Key is the config key, e.g. "rules" or "ynab.budget_id".
+	Key string
+	// Flag is the flag name used to report the source, e.g. "rules".
+	Flag string
+	// Value is the flag value.
+	Value string
+}
+
+// Setting is a single effective config value and the layer it came from.
+type Setting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// override is an environment or flag value applied on top of the files.
+type override struct {
+	key    string
+	value  string
+	source string
+}
+
+// Load merges the built-in defaults, the XDG config file, the project-local
+// file (or opts.File), MONEYPENNY_* environment variables and flags, in that
+// order. Profiles are not resolved; use Profile on the result.
+func Load(opts LoadOptions) (*Config, error) {
+	merged := make(map[string]any)
+	sources := make(map[string]string)
+	mergeValues(merged, defaults, "", SourceDefaults, sources)
+
+	files, err := layerFiles(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range files {
+		values, err := readValues(path)
+		if err != nil {
+			return nil, err
+		}
+		mergeValues(merged, values, "", path, sources)
+	}
+
+	cfg, err := decodeValues(merged)
+	if err != nil {
+		return nil, err
+	}
+	cfg.sources = sources
+	cfg.Files = files
+	if len(files) > 0 {
+		cfg.Path = files[len(files)-1]
+	}
+
+	cfg.overrides, err = overridesFrom(opts.Environ, opts.Flags)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.applyOverrides(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// layerFiles returns the config files to merge, lowest precedence first.
+func layerFiles(opts LoadOptions) ([]string, error) {
+	var files []string
+
+	dir, err := ConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("resolving config directory: %w", err)
+	}
+	path, err := findFile(dir, configFileName)
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		files = append(files, path)
+	}
+
+	if opts.File != "" {
+		if _, err := os.Stat(opts.File); err != nil {
+			return nil, fmt.Errorf("reading config file: %w", err)
+		}
+		return append(files, opts.File), nil
+	}
+
+	workDir := opts.WorkDir
+	if workDir == "" {
+		workDir = "."
+	}
+	path, err = findFile(workDir, localFileName)
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		files = append(files, path)
+	}
+
+	return files, nil
+}
+
+// findFile returns the config file named base in dir with any supported
+// extension, or "" when there is none.
+func findFile(dir, base string) (string, error) {
+	var found []string
+	for _, ext := range FileExtensions {
+		path := filepath.Join(dir, base+ext)
+		if _, err := os.Stat(path); err == nil {
+			found = append(found, path)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return "", nil
+	case 1:
+		return found[0], nil
+	default:
+		return "", fmt.Errorf("several config files found, keep only one: %s", strings.Join(found, ", "))
+	}
+}
+
+// readValues reads a JSON, YAML or TOML config file into generic values.
+// Relative rules paths are resolved against the directory of the file.
+func readValues(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	values := make(map[string]any)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		err = json.Unmarshal(data, &values)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	if values == nil {
+		values = make(map[string]any)
+	}
+
+	dir := filepath.Dir(path)
+	resolveRulesPath(values, dir)
+	if profiles, ok := values["profiles"].(map[string]any); ok {
+		for _, p := range profiles {
+			if profile, ok := p.(map[string]any); ok {
+				resolveRulesPath(profile, dir)
+			}
+		}
+	}
+
+	return values, nil
+}
+
+// resolveRulesPath makes a relative rules path relative to dir.
+func resolveRulesPath(values map[string]any, dir string) {
+	if rules, ok := values["rules"].(string); ok && rules != "" && !filepath.IsAbs(rules) {
+		values["rules"] = filepath.Join(dir, rules)
+	}
+}
+
+// decodeValues converts merged generic values into a Config.
+func decodeValues(values map[string]any) (*Config, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("encoding config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// mergeValues merges src into dst. Tables are merged key by key, all other
+// values (including lists) replace the previous value. The source of every
+// value is recorded in sources under its dotted key.
+func mergeValues(dst, src map[string]any, prefix, source string, sources map[string]string) {
+	for k, v := range src {
+		key := prefix + k
+		if table, ok := v.(map[string]any); ok {
+			existing, ok := dst[k].(map[string]any)
+			if !ok {
+				existing = make(map[string]any)
+				dst[k] = existing
+			}
+			mergeValues(existing, table, key+".", source, sources)
+			continue
+		}
+		dst[k] = v
+		sources[key] = source
+	}
+}
+
+// OverrideKeys returns the config keys that can be set by environment
+// variables, e.g. "ynab.budget_id" for MONEYPENNY_YNAB_BUDGET_ID.
+func OverrideKeys() []string {
+	var keys []string
+	for key := range scalarFields(reflect.TypeOf(Config{}), "") {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// EnvName returns the environment variable that overrides key.
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// scalarFields maps the dotted json keys of the string, number and boolean
+// fields of t, including those of nested structs, to their field index path.
+func scalarFields(t reflect.Type, prefix string) map[string][]int {
+	fields := make(map[string][]int)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if !f.IsExported() || name == "" || name == "-" {
+			continue
+		}
+
+		switch f.Type.Kind() {
+		case reflect.Struct:
+			for key, index := range scalarFields(f.Type, prefix+name+".") {
+				fields[key] = append([]int{i}, index...)
+			}
+		case reflect.String, reflect.Bool, reflect.Float64, reflect.Int, reflect.Int64:
+			fields[prefix+name] = []int{i}
+		}
+	}
+	return fields
+}
+
+// overridesFrom collects MONEYPENNY_* environment variables and flag values.
+func overridesFrom(environ []string, flags []FlagValue) ([]override, error) {
+	env := make(map[string]string)
+	for _, kv := range environ {
+		if name, value, ok := strings.Cut(kv, "="); ok && strings.HasPrefix(name, EnvPrefix) {
+			env[name] = value
+		}
+	}
+
+	var overrides []override
+	for _, key := range OverrideKeys() {
+		if value, ok := env[EnvName(key)]; ok {
+			overrides = append(overrides, override{key: key, value: value, source: "env " + EnvName(key)})
+		}
+	}
+
+	fields := scalarFields(reflect.TypeOf(Config{}), "")
+	for _, f := range flags {
+		if _, ok := fields[f.Key]; !ok {
+			return nil, fmt.Errorf("flag --%s sets unknown config key %s", f.Flag, f.Key)
+		}
+		overrides = append(overrides, override{key: f.Key, value: f.Value, source: "flag --" + f.Flag})
+	}
+
+	return overrides, nil
+}
+
+// applyOverrides sets the environment and flag values on c.
+func (c *Config) applyOverrides() error {
+	fields := scalarFields(reflect.TypeOf(*c), "")
+	for _, o := range c.overrides {
+		field := reflect.ValueOf(c).Elem().FieldByIndex(fields[o.key])
+		if err := setField(field, o.value); err != nil {
+			return fmt.Errorf("%s: %w", o.source, err)
+		}
+		if c.sources == nil {
+			c.sources = make(map[string]string)
+		}
+		c.sources[o.key] = o.source
+	}
+	return nil
+}
+
+// setField parses value into the string, number or boolean field.
+func setField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		field.SetBool(b)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		field.SetFloat(f)
+	case reflect.Int, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		field.SetInt(i)
+	}
+	return nil
+}
+
+// Settings returns every config value with its source, sorted by key.
+// Secrets are masked unless they are secret references. Empty values that no layer sets are left out.
+func (c *Config) Settings() []Setting {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil
+	}
+	var values map[string]any
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil
+	}
+
+	var settings []Setting
+	flatten(values, "", func(key string, value any) {
+		s := Setting{Key: key, Value: formatValue(value), Source: c.sourceOf(key)}
+		if s.Value == "" && s.Source == SourceDefaults {
+			return
+		}
+		if isSecret(key) && !secrets.IsReference(s.Value) {
+			s.Value = MaskSecret(s.Value)
+		}
+		settings = append(settings, s)
+	})
+
+	sort.Slice(settings, func(a, b int) bool { return settings[a].Key < settings[b].Key })
+	return settings
+}
+
+// sourceOf returns the source of key or of the closest enclosing list or table.
+func (c *Config) sourceOf(key string) string {
+	for k := key; k != ""; {
+		if source, ok := c.sources[k]; ok {
+			return source
+		}
+		i := strings.LastIndexAny(k, ".[")
+		if i < 0 {
+			break
+		}
+		k = k[:i]
+	}
+	return SourceDefaults
+}
+
+// flatten calls fn for every leaf of values with its dotted key. Lists of
+// tables are indexed (accounts[0].iban); lists of plain values are leaves.
+func flatten(values map[string]any, prefix string, fn func(key string, value any)) {
+	for k, v := range values {
+		key := prefix + k
+		switch v := v.(type) {
+		case map[string]any:
+			flatten(v, key+".", fn)
+		case []any:
+			if _, ok := lastTable(v); !ok {
+				fn(key, v)
+				continue
+			}
+			for i, item := range v {
+				if t, ok := item.(map[string]any); ok {
+					flatten(t, fmt.Sprintf("%s[%d].", key, i), fn)
+				}
+			}
+		default:
+			fn(key, v)
+		}
+	}
+}
+
+// lastTable returns the last element of values if it is a table.
+func lastTable(values []any) (map[string]any, bool) {
+	if len(values) == 0 {
+		return nil, false
+	}
+	t, ok := values[len(values)-1].(map[string]any)
+	return t, ok
+}
+
+// formatValue renders a config value for display.
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+func isSecret(key string) bool {
+	for _, suffix := range secretKeys {
+		if key == suffix || strings.HasSuffix(key, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskSecret hides all but the last four characters of a secret.
+func MaskSecret(s string) string {
+	if s == "" {
+		return ""
+	}
+	if len(s) <= 8 {
+		return "********"
+	}
+	return "****" + s[len(s)-4:]
+}
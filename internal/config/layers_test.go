@@ -0,0 +1,227 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// LayersTestSuite groups tests of the layered config loading.
+type LayersTestSuite struct {
+	suite.Suite
+	xdgDir  string
+	workDir string
+}
+
+func TestLayersTestSuite(t *testing.T) {
+	suite.Run(t, new(LayersTestSuite))
+}
+
+func (s *LayersTestSuite) SetupTest() {
+	home := s.T().TempDir()
+	s.T().Setenv("XDG_CONFIG_HOME", home)
+	s.xdgDir = filepath.Join(home, appDirName)
+	s.workDir = s.T().TempDir()
+	s.Require().NoError(os.MkdirAll(s.xdgDir, 0o700))
+}
+
+func (s *LayersTestSuite) write(path, content string) string {
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func (s *LayersTestSuite) settings(cfg *Config) map[string]Setting {
+	byKey := make(map[string]Setting)
+	for _, setting := range cfg.Settings() {
+		byKey[setting.Key] = setting
+	}
+	return byKey
+}
+
+func (s *LayersTestSuite) TestLoad_MergesLayersInOrder() {
+	// Arrange
+	xdg := s.write(filepath.Join(s.xdgDir, "config.yaml"), `
+ynab:
+  api_key: xdg-token-abcd
+  budget_id: xdg-budget
+fees:
+  foreign_rate_percent: 1.75
+`)
+	local := s.write(filepath.Join(s.workDir, ".moneypenny.toml"), `
+rules = "local-rules.yaml"
+
+[ynab]
+budget_id = "local-budget"
+`)
+	opts := LoadOptions{
+		WorkDir: s.workDir,
+		Environ: []string{"MONEYPENNY_FEES_FOREIGN_RATE_PERCENT=2.5", "HOME=/home/user"},
+		Flags:   []FlagValue{{Key: "rules", Flag: "rules", Value: "flag-rules.yaml"}},
+	}
+
+	// Act
+	cfg, err := Load(opts)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal([]string{xdg, local}, cfg.Files)
+	s.Equal(local, cfg.Path)
+	s.Equal(YNABConfig{APIKey: "xdg-token-abcd", BudgetID: "local-budget"}, cfg.YNAB)
+	s.Equal(2.5, cfg.Fees.ForeignRatePercent)
+	s.Equal("flag-rules.yaml", cfg.Rules)
+	s.Equal("ecb", cfg.Currency.RateSource)
+
+	settings := s.settings(cfg)
+	s.Equal(Setting{Key: "ynab.api_key", Value: "****abcd", Source: xdg}, settings["ynab.api_key"])
+	s.Equal(local, settings["ynab.budget_id"].Source)
+	s.Equal("env MONEYPENNY_FEES_FOREIGN_RATE_PERCENT", settings["fees.foreign_rate_percent"].Source)
+	s.Equal("flag --rules", settings["rules"].Source)
+	s.Equal(SourceDefaults, settings["currency.rate_source"].Source)
+}
+
+func (s *LayersTestSuite) TestLoad_WithExplicitFile_SkipsProjectLocalFile() {
+	// Arrange
+	s.write(filepath.Join(s.workDir, ".moneypenny.json"), `{"ynab": {"budget_id": "local"}}`)
+	file := s.write(filepath.Join(s.T().TempDir(), "config.json"), `{"ynab": {"budget_id": "explicit"}, "rules": "rules.yaml"}`)
+
+	// Act
+	cfg, err := Load(LoadOptions{File: file, WorkDir: s.workDir})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal([]string{file}, cfg.Files)
+	s.Equal("explicit", cfg.YNAB.BudgetID)
+	s.Equal(filepath.Join(filepath.Dir(file), "rules.yaml"), cfg.Rules)
+}
+
+func (s *LayersTestSuite) TestLoad_WithTOMLFile_DecodesTablesAndArraysOfTables() {
+	// Arrange
+	file := s.write(filepath.Join(s.T().TempDir(), "config.toml"), `
+default_profile = "personal" # trailing comment
+
+[ynab]
+api_key = 'token'
+
+[transfers]
+payee_patterns = ["(?i)miles\\s*more", 'kreditkarte']
+
+[[accounts]]
+source = "milesmore"
+card_number = "5426********1495"
+account_id = "acc-1"
+
+[[accounts]]
+source = "sparkasse"
+iban = "DE89370400440532013000"
+account_id = "acc-2"
+
+[profiles.personal]
+ynab = { budget_id = "budget-1" }
+fees.foreign_rate_percent = 2
+`)
+
+	// Act
+	cfg, err := Load(LoadOptions{File: file, WorkDir: s.workDir})
+	s.Require().NoError(err)
+	personal, err := cfg.Profile("")
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal("budget-1", personal.YNAB.BudgetID)
+	s.Equal(2.0, personal.Fees.ForeignRatePercent)
+	s.Equal([]string{`(?i)miles\s*more`, "kreditkarte"}, cfg.Transfers.PayeePatterns)
+	s.Require().Len(cfg.Accounts, 2)
+	s.Equal("5426********1495", cfg.Accounts[0].CardNumber)
+	s.Equal("DE89370400440532013000", cfg.Accounts[1].IBAN)
+	s.Equal(file, s.settings(cfg)["accounts[1].iban"].Source)
+}
+
+func (s *LayersTestSuite) TestLoad_WithInvalidTOML_ReturnsFileAndLine() {
+	// Arrange
+	file := s.write(filepath.Join(s.T().TempDir(), "config.toml"), "[ynab]\napi_key = \"x\"\napi_key = \"y\"\n")
+
+	// Act
+	_, err := Load(LoadOptions{File: file, WorkDir: s.workDir})
+
+	// Assert
+	s.ErrorContains(err, "parsing config file "+file)
+	s.ErrorContains(err, "line 3")
+}
+
+func (s *LayersTestSuite) TestLoad_WithSeveralFormatsInOneDirectory_ReturnsError() {
+	// Arrange
+	s.write(filepath.Join(s.xdgDir, "config.json"), `{}`)
+	s.write(filepath.Join(s.xdgDir, "config.toml"), ``)
+
+	// Act
+	_, err := Load(LoadOptions{WorkDir: s.workDir})
+
+	// Assert
+	s.ErrorContains(err, "several config files found")
+}
+
+func (s *LayersTestSuite) TestLoad_WithInvalidEnvironmentNumber_ReturnsError() {
+	// Act
+	_, err := Load(LoadOptions{WorkDir: s.workDir, Environ: []string{"MONEYPENNY_FEES_TOLERANCE=abc"}})
+
+	// Assert
+	s.ErrorContains(err, `env MONEYPENNY_FEES_TOLERANCE: invalid number "abc"`)
+}
+
+func (s *LayersTestSuite) TestProfile_EnvironmentOverridesProfileSettings() {
+	// Arrange
+	s.write(filepath.Join(s.workDir, ".moneypenny.json"), `{
+  "ynab": {"api_key": "token"},
+  "fees": {"foreign_rate_percent": 1.75, "tolerance": 0.02},
+  "profiles": {
+    "household": {"ynab": {"budget_id": "household"}, "fees": {"foreign_rate_percent": 2}}
+  }
+}`)
+	opts := LoadOptions{WorkDir: s.workDir, Environ: []string{"MONEYPENNY_YNAB_BUDGET_ID=from-env"}}
+
+	// Act
+	cfg, err := Load(opts)
+	s.Require().NoError(err)
+	resolved, err := cfg.Profile("household")
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal("from-env", resolved.YNAB.BudgetID)
+	settings := s.settings(resolved)
+	s.Equal("env MONEYPENNY_YNAB_BUDGET_ID", settings["ynab.budget_id"].Source)
+	s.Contains(settings["fees.foreign_rate_percent"].Source, "(profile household)")
+	// The profile replaced the fees section, so the base tolerance is gone
+	s.NotContains(settings, "fees.tolerance")
+}
+
+func (s *LayersTestSuite) TestProfile_MergesOAuthSettingsAndMasksSecret() {
+	// Arrange
+	s.write(filepath.Join(s.workDir, ".moneypenny.json"), `{
+  "ynab": {"budget_id": "personal", "oauth": {"client_id": "family-app", "redirect_port": 9000}},
+  "profiles": {
+    "household": {"ynab": {"budget_id": "household", "oauth": {"token_entry": "household-login"}}}
+  }
+}`)
+	opts := LoadOptions{WorkDir: s.workDir, Environ: []string{"MONEYPENNY_YNAB_OAUTH_CLIENT_SECRET=app-secret-1234"}}
+
+	// Act
+	cfg, err := Load(opts)
+	s.Require().NoError(err)
+	resolved, err := cfg.Profile("household")
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(OAuthConfig{ClientID: "family-app", ClientSecret: "app-secret-1234", RedirectPort: 9000, TokenEntry: "household-login"}, resolved.YNAB.OAuth)
+	s.NoError(resolved.Validate())
+	settings := s.settings(resolved)
+	s.NotContains(settings["ynab.oauth.client_secret"].Value, "app-secret")
+	s.Equal("env MONEYPENNY_YNAB_OAUTH_CLIENT_SECRET", settings["ynab.oauth.client_secret"].Source)
+}
+
+func (s *LayersTestSuite) TestEnvName() {
+	s.Equal("MONEYPENNY_YNAB_API_KEY", EnvName("ynab.api_key"))
+	s.Contains(OverrideKeys(), "currency.rate_source")
+	s.NotContains(OverrideKeys(), "accounts")
+}
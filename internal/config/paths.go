@@ -0,0 +1,49 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// appDirName is the directory name used below the XDG base directories.
+const appDirName = "moneypenny"
+
+// DataDir returns the directory for local application state such as the
+// import journal. It honours $XDG_DATA_HOME and falls back to
+// ~/.local/share/moneypenny.
+func DataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, appDirName), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".local", "share", appDirName), nil
+}
+
+// ConfigDir returns the directory of the user config file. It honours
+// $XDG_CONFIG_HOME and falls back to ~/.config/moneypenny.
+func ConfigDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, appDirName), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".config", appDirName), nil
+}
+
+// DefaultConfigPath returns the path of the JSON config file in ConfigDir.
+func DefaultConfigPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, configFileName+".json"), nil
+}
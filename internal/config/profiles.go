@@ -0,0 +1,179 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Profile holds the settings of one named budget setup, e.g. a personal and a
+// shared household budget. Fields left empty are inherited from the profile
+// named in Inherits, or from the top-level settings of the config file.
+type Profile struct {
+	// Inherits names another profile whose settings this profile extends (optional).
+	Inherits string `json:"inherits,omitempty"`
+	// YNAB overrides the token and budget; empty fields are inherited.
+	YNAB YNABConfig `json:"ynab"`
+	// Rules is the rules file of the profile, relative to the config file.
+	Rules string `json:"rules,omitempty"`
+	// Accounts replaces the inherited account mappings when set.
+	Accounts []AccountMapping `json:"accounts,omitempty"`
+	// Transfers replaces the inherited transfer settings when set.
+	Transfers *TransfersConfig `json:"transfers,omitempty"`
+	// Fees replaces the inherited fee settings when set.
+	Fees *FeesConfig `json:"fees,omitempty"`
+	// Currency replaces the inherited currency settings when set.
+	Currency *CurrencyConfig `json:"currency,omitempty"`
+}
+
+// ProfileNames returns the names of the configured profiles in sorted order.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Profile returns the configuration of the named profile with all inherited
+// settings applied. An empty name selects the default profile; without
+// profiles the top-level settings are returned as they are.
+func (c *Config) Profile(name string) (*Config, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		if len(c.Profiles) > 1 {
+			return nil, fmt.Errorf("several profiles configured (%s), choose one with --profile or set default_profile",
+				strings.Join(c.ProfileNames(), ", "))
+		}
+		if len(c.Profiles) == 1 {
+			name = c.ProfileNames()[0]
+		}
+	}
+
+	resolved := *c
+	resolved.Profiles = nil
+	resolved.DefaultProfile = ""
+	resolved.sources = make(map[string]string, len(c.sources))
+	for key, source := range c.sources {
+		if !strings.HasPrefix(key, "profiles.") && key != "default_profile" {
+			resolved.sources[key] = source
+		}
+	}
+	if name == "" {
+		return &resolved, nil
+	}
+
+	chain, err := c.profileChain(name)
+	if err != nil {
+		return nil, err
+	}
+	// Apply the chain from the most general profile to the requested one
+	for i := len(chain) - 1; i >= 0; i-- {
+		resolved.apply(c.Profiles[chain[i]])
+		resolved.applySources(c.sources, chain[i])
+	}
+	resolved.ProfileName = name
+
+	// Environment and flags take precedence over profile settings
+	if err := resolved.applyOverrides(); err != nil {
+		return nil, err
+	}
+
+	return &resolved, nil
+}
+
+// profileChain returns name followed by the profiles it inherits from.
+func (c *Config) profileChain(name string) ([]string, error) {
+	var chain []string
+	seen := make(map[string]bool)
+	for current := name; current != ""; current = c.Profiles[current].Inherits {
+		if _, ok := c.Profiles[current]; !ok {
+			if current == name {
+				return nil, fmt.Errorf("unknown profile %q (configured: %s)", name, strings.Join(c.ProfileNames(), ", "))
+			}
+			return nil, fmt.Errorf("profile %q inherits unknown profile %q", chain[len(chain)-1], current)
+		}
+		if seen[current] {
+			return nil, fmt.Errorf("profile %q has an inheritance cycle", name)
+		}
+		seen[current] = true
+		chain = append(chain, current)
+	}
+	return chain, nil
+}
+
+// apply overrides the settings of c with those set in p.
+func (c *Config) apply(p Profile) {
+	if p.YNAB.APIKey != "" {
+		c.YNAB.APIKey = p.YNAB.APIKey
+	}
+	if p.YNAB.BudgetID != "" {
+		c.YNAB.BudgetID = p.YNAB.BudgetID
+	}
+	if p.YNAB.OAuth.ClientID != "" {
+		c.YNAB.OAuth.ClientID = p.YNAB.OAuth.ClientID
+	}
+	if p.YNAB.OAuth.ClientSecret != "" {
+		c.YNAB.OAuth.ClientSecret = p.YNAB.OAuth.ClientSecret
+	}
+	if p.YNAB.OAuth.RedirectPort != 0 {
+		c.YNAB.OAuth.RedirectPort = p.YNAB.OAuth.RedirectPort
+	}
+	if p.YNAB.OAuth.TokenEntry != "" {
+		c.YNAB.OAuth.TokenEntry = p.YNAB.OAuth.TokenEntry
+	}
+	if p.Rules != "" {
+		c.Rules = p.Rules
+	}
+	if p.Accounts != nil {
+		c.Accounts = p.Accounts
+	}
+	if p.Transfers != nil {
+		c.Transfers = *p.Transfers
+	}
+	if p.Fees != nil {
+		c.Fees = *p.Fees
+	}
+	if p.Currency != nil {
+		c.Currency = *p.Currency
+	}
+}
+
+// replacedSections are the settings a profile replaces as a whole rather than
+// field by field.
+var replacedSections = []string{"accounts", "transfers", "fees", "currency"}
+
+// applySources records the sources of the values set by profile name.
+func (c *Config) applySources(sources map[string]string, name string) {
+	prefix := "profiles." + name + "."
+	set := make(map[string]string)
+	for key, source := range sources {
+		if rest, ok := strings.CutPrefix(key, prefix); ok && rest != "inherits" {
+			set[rest] = source + " (profile " + name + ")"
+		}
+	}
+
+	for _, section := range replacedSections {
+		replaced := false
+		for key := range set {
+			if key == section || strings.HasPrefix(key, section+".") {
+				replaced = true
+			}
+		}
+		if !replaced {
+			continue
+		}
+		for key := range c.sources {
+			if key == section || strings.HasPrefix(key, section+".") || strings.HasPrefix(key, section+"[") {
+				delete(c.sources, key)
+			}
+		}
+	}
+
+	for key, source := range set {
+		c.sources[key] = source
+	}
+}
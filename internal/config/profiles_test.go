@@ -0,0 +1,130 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// ProfilesTestSuite groups profile resolution tests.
+type ProfilesTestSuite struct {
+	suite.Suite
+	cfg Config
+}
+
+func TestProfilesTestSuite(t *testing.T) {
+	suite.Run(t, new(ProfilesTestSuite))
+}
+
+func (s *ProfilesTestSuite) SetupTest() {
+	s.cfg = Config{
+		YNAB:           YNABConfig{APIKey: "shared-token"},
+		Fees:           FeesConfig{ForeignRatePercent: 1.75},
+		DefaultProfile: "personal",
+		Profiles: map[string]Profile{
+			"personal": {
+				YNAB:     YNABConfig{BudgetID: "budget-personal"},
+				Accounts: []AccountMapping{{Source: SourceMilesMore, CardNumber: "5426********1495", AccountID: "acc-card"}},
+			},
+			"household": {
+				YNAB:  YNABConfig{BudgetID: "budget-household"},
+				Rules: "household-rules.yaml",
+			},
+			"household-partner": {
+				Inherits: "household",
+				YNAB:     YNABConfig{APIKey: "partner-token"},
+				Fees:     &FeesConfig{ForeignRatePercent: 2},
+			},
+		},
+	}
+}
+
+func (s *ProfilesTestSuite) TestProfile_WithoutName_UsesDefaultProfile() {
+	// Act
+	resolved, err := s.cfg.Profile("")
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal("personal", resolved.ProfileName)
+	s.Equal(YNABConfig{APIKey: "shared-token", BudgetID: "budget-personal"}, resolved.YNAB)
+	s.Len(resolved.Accounts, 1)
+	s.Nil(resolved.Profiles)
+}
+
+func (s *ProfilesTestSuite) TestProfile_WithInheritance_AppliesChainInOrder() {
+	// Act
+	resolved, err := s.cfg.Profile("household-partner")
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(YNABConfig{APIKey: "partner-token", BudgetID: "budget-household"}, resolved.YNAB)
+	s.Equal("household-rules.yaml", resolved.Rules)
+	s.Equal(2.0, resolved.Fees.ForeignRatePercent)
+	s.Empty(resolved.Accounts)
+}
+
+func (s *ProfilesTestSuite) TestProfile_WithUnknownName_ReturnsError() {
+	// Act
+	_, err := s.cfg.Profile("business")
+
+	// Assert
+	s.ErrorContains(err, `unknown profile "business"`)
+}
+
+func (s *ProfilesTestSuite) TestProfile_WithCycle_ReturnsError() {
+	// Arrange
+	s.cfg.Profiles["household"] = Profile{Inherits: "household-partner"}
+
+	// Act
+	_, err := s.cfg.Profile("household")
+
+	// Assert
+	s.ErrorContains(err, "inheritance cycle")
+}
+
+func (s *ProfilesTestSuite) TestProfile_WithoutDefaultAndSeveralProfiles_ReturnsError() {
+	// Arrange
+	s.cfg.DefaultProfile = ""
+
+	// Act
+	_, err := s.cfg.Profile("")
+
+	// Assert
+	s.ErrorContains(err, "choose one with --profile")
+}
+
+func (s *ProfilesTestSuite) TestProfile_WithoutProfiles_ReturnsTopLevelSettings() {
+	// Arrange
+	cfg := Config{YNAB: YNABConfig{APIKey: "token", BudgetID: "budget"}}
+
+	// Act
+	resolved, err := cfg.Profile("")
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(cfg.YNAB, resolved.YNAB)
+	s.Empty(resolved.ProfileName)
+}
+
+func (s *ProfilesTestSuite) TestValidate_ValidatesEveryResolvedProfile() {
+	// Arrange
+	s.cfg.Profiles["household"] = Profile{}
+
+	// Act
+	err := s.cfg.Validate()
+
+	// Assert
+	s.ErrorContains(err, "budget_id is required")
+	s.ErrorContains(err, "profile household")
+}
+
+func (s *ProfilesTestSuite) TestValidate_WithUnknownDefaultProfile_ReturnsError() {
+	// Arrange
+	s.cfg.DefaultProfile = "business"
+
+	// Act
+	err := s.cfg.Validate()
+
+	// Assert
+	s.ErrorContains(err, "default_profile")
+}
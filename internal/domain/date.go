@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// DayDelta returns the absolute number of calendar days between two dates,
+// ignoring the time of day and the location.
+func DayDelta(a, b time.Time) int {
+	a = time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	b = time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return Abs(int(a.Sub(b).Hours() / 24))
+}
+
+// Abs returns the absolute value of n.
+func Abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
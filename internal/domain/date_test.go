@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDayDelta(t *testing.T) {
+	berlin := time.FixedZone("CET", 3600)
+	tests := []struct {
+		name string
+		a, b time.Time
+		want int
+	}{
+		{"same day", time.Date(2026, 1, 10, 23, 0, 0, 0, time.UTC), time.Date(2026, 1, 10, 1, 0, 0, 0, time.UTC), 0},
+		{"later first", time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC), 2},
+		{"earlier first", time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC), 2},
+		{"across midnight", time.Date(2026, 1, 11, 0, 30, 0, 0, time.UTC), time.Date(2026, 1, 10, 23, 30, 0, 0, time.UTC), 1},
+		{"other location", time.Date(2026, 1, 11, 0, 30, 0, 0, berlin), time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC), 1},
+		{"across month", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 27, 0, 0, 0, 0, time.UTC), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DayDelta(tt.a, tt.b))
+		})
+	}
+}
+
+func TestAbs(t *testing.T) {
+	assert.Equal(t, 3, Abs(-3))
+	assert.Equal(t, 3, Abs(3))
+	assert.Equal(t, 0, Abs(0))
+}
@@ -7,44 +7,89 @@ import "time"
 // different banking services and import sources.
 type Transaction struct {
 	// Date is the primary transaction date (voucher date/booking date).
-	Date time.Time
+	Date time.Time `json:"date"`
 
 	// PostingDate is when the transaction was posted/received.
-	PostingDate time.Time
+	PostingDate time.Time `json:"posting_date"`
 
 	// Payee is the merchant, recipient, or reason for payment.
-	Payee string
+	Payee string `json:"payee"`
+
+	// Merchant is the merchant name without location details. Empty when the
+	// source does not carry a structured merchant (e.g. fees, transfers).
+	Merchant string `json:"merchant,omitempty"`
+
+	// PostalCode is the merchant's postal code (optional).
+	PostalCode string `json:"postal_code,omitempty"`
+
+	// City is the merchant's city (optional).
+	City string `json:"city,omitempty"`
+
+	// Region is the merchant's state or region code, e.g. "BY" (optional).
+	Region string `json:"region,omitempty"`
+
+	// Country is the merchant's ISO 3166-1 alpha-3 country code, e.g. "DEU" (optional).
+	Country string `json:"country,omitempty"`
 
 	// Memo contains additional transaction description or notes.
-	Memo string
+	Memo string `json:"memo,omitempty"`
 
 	// Amount is the transaction amount in the settlement currency.
 	// Negative values indicate outflows (expenses).
-	Amount float64
+	Amount float64 `json:"amount"`
 
 	// Currency is the settlement currency code (e.g., "EUR", "USD").
-	Currency string
+	Currency string `json:"currency"`
 
 	// ForeignAmount is the original amount in foreign currency (if applicable).
 	// Zero value indicates no foreign currency conversion.
-	ForeignAmount float64
+	ForeignAmount float64 `json:"foreign_amount,omitempty"`
 
 	// ForeignCurrency is the foreign currency code (e.g., "USD", "GBP").
 	// Empty string indicates no foreign currency conversion.
-	ForeignCurrency string
+	ForeignCurrency string `json:"foreign_currency,omitempty"`
 
 	// ExchangeRate is the rate used for currency conversion.
 	// Zero value indicates no conversion or rate not provided.
-	ExchangeRate float64
+	ExchangeRate float64 `json:"exchange_rate,omitempty"`
 
 	// ImportID is a unique identifier for duplicate detection.
 	// Format: "YNAB:[milliunit_amount]:[iso_date]:[occurrence]"
 	// Example: "YNAB:-294230:2015-12-30:1"
-	ImportID string
+	ImportID string `json:"import_id"`
 
 	// SourceFile is the name of the file this transaction was parsed from.
-	SourceFile string
+	SourceFile string `json:"source_file,omitempty"`
 
 	// SourceLine is the line number in the source file (for debugging).
-	SourceLine int
+	SourceLine int `json:"source_line,omitempty"`
+
+	// PayeeID is the existing YNAB payee resolved for Payee. Empty lets YNAB
+	// match or create a payee by name.
+	PayeeID string `json:"payee_id,omitempty"`
+
+	// Category is the category to assign, either a YNAB category ID or a
+	// category name (optionally "Group: Category"). Empty leaves it uncategorized.
+	Category string `json:"category,omitempty"`
+
+	// FlagColor is the YNAB flag color to set (e.g., "red"). Empty sets no flag.
+	FlagColor string `json:"flag_color,omitempty"`
+
+	// Splits divides the amount into parts. Empty for unsplit transactions.
+	Splits []Split `json:"splits,omitempty"`
+}
+
+// Split is one part of a split transaction.
+type Split struct {
+	// Amount is the part of the transaction amount, with the same sign.
+	Amount float64 `json:"amount"`
+
+	// Payee overrides the transaction payee for this part (optional).
+	Payee string `json:"payee,omitempty"`
+
+	// Category is the category for this part (ID or name, see Transaction.Category).
+	Category string `json:"category,omitempty"`
+
+	// Memo is the memo for this part (optional).
+	Memo string `json:"memo,omitempty"`
 }
@@ -0,0 +1,107 @@
+package fx
+
+import (
+	"math"
+
+	"github.com/pgbytes/moneypenny/internal/domain"
+)
+
+const (
+	// DefaultFeeTolerance is the default difference in EUR accepted between the
+	// charged and the expected fee, covering the issuer's rounding.
+	DefaultFeeTolerance = 0.01
+
+	// epsilon absorbs floating point noise when comparing cent amounts.
+	epsilon = 1e-9
+)
+
+// FeeStatus classifies a foreign transaction or fee after the audit.
+type FeeStatus string
+
+const (
+	// FeeOK indicates the fee matches the contract rate within tolerance.
+	FeeOK FeeStatus = "ok"
+	// FeeMissing indicates a foreign purchase without a fee.
+	FeeMissing FeeStatus = "missing"
+	// FeeOvercharged indicates a fee above the contract rate.
+	FeeOvercharged FeeStatus = "overcharged"
+	// FeeUndercharged indicates a fee below the contract rate.
+	FeeUndercharged FeeStatus = "undercharged"
+	// FeeOrphan indicates a fee without a matching foreign transaction.
+	FeeOrphan FeeStatus = "orphan"
+)
+
+// AuditOptions configures the fee audit.
+type AuditOptions struct {
+	// RatePercent is the contract fee rate in percent of the EUR amount (e.g. 1.75).
+	RatePercent float64
+	// Tolerance is the accepted difference in EUR. Zero uses DefaultFeeTolerance.
+	Tolerance float64
+	// Window is the tolerance in days between fee and transaction. Zero uses DefaultFeeWindow.
+	Window int
+}
+
+// FeeFinding is the audit result for one foreign transaction or orphan fee.
+// Amounts are in EUR; Expected, Charged and Difference are positive for costs.
+type FeeFinding struct {
+	Status      FeeStatus           `json:"status"`
+	Transaction *domain.Transaction `json:"transaction,omitempty"`
+	Fee         *domain.Transaction `json:"fee,omitempty"`
+	// Expected is the fee according to the contract rate.
+	Expected float64 `json:"expected"`
+	// Charged is the fee on the statement.
+	Charged float64 `json:"charged"`
+	// Difference is Charged minus Expected.
+	Difference float64 `json:"difference"`
+}
+
+// Disputable reports whether the finding is worth raising with the bank.
+func (f FeeFinding) Disputable() bool {
+	return f.Status == FeeOvercharged || f.Status == FeeOrphan
+}
+
+// AuditFees pairs every foreign fee with its transaction and checks it against
+// the contract rate. Refunds without a fee are not reported as missing, since
+// issuers usually keep the fee of the original purchase.
+func AuditFees(transactions []domain.Transaction, opts AuditOptions) []FeeFinding {
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = DefaultFeeTolerance
+	}
+
+	var findings []FeeFinding
+	for _, link := range LinkFees(transactions, opts.Window) {
+		f := FeeFinding{Transaction: link.Transaction, Fee: link.Fee}
+
+		if link.Fee != nil {
+			f.Charged = -link.Fee.Amount
+		}
+
+		switch {
+		case link.Transaction == nil:
+			f.Status = FeeOrphan
+			f.Difference = f.Charged
+			findings = append(findings, f)
+			continue
+		case link.Fee == nil && link.Transaction.Amount > 0:
+			continue
+		}
+
+		f.Expected = roundCents(-link.Transaction.Amount * opts.RatePercent / 100)
+		f.Difference = roundCents(f.Charged - f.Expected)
+
+		switch {
+		case link.Fee == nil && math.Abs(f.Expected) > opts.Tolerance:
+			f.Status = FeeMissing
+		case f.Difference > opts.Tolerance+epsilon:
+			f.Status = FeeOvercharged
+		case f.Difference < -opts.Tolerance-epsilon:
+			f.Status = FeeUndercharged
+		default:
+			f.Status = FeeOK
+		}
+
+		findings = append(findings, f)
+	}
+
+	return findings
+}
@@ -0,0 +1,69 @@
+package fx
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/domain"
+)
+
+// Conversion compares the EUR amount charged for a foreign-currency
+// transaction with its value at the ECB reference rate.
+type Conversion struct {
+	Transaction domain.Transaction `json:"transaction"`
+	// ReferenceRate is the ECB rate (units of currency per EUR) used.
+	ReferenceRate float64 `json:"reference_rate"`
+	// RateDate is the day the reference rate was published for.
+	RateDate time.Time `json:"rate_date"`
+	// BankRate is the rate the issuer applied (units of currency per EUR).
+	BankRate float64 `json:"bank_rate"`
+	// FairAmount is the foreign amount at the reference rate in EUR, signed like the transaction.
+	FairAmount float64 `json:"fair_amount"`
+	// Markup is the hidden conversion cost in EUR: positive when the issuer
+	// charged more (or refunded less) than the reference rate implies.
+	Markup float64 `json:"markup"`
+	// MarkupPercent is Markup relative to the fair amount.
+	MarkupPercent float64 `json:"markup_percent"`
+}
+
+// IsForeign reports whether tx was paid in a currency other than EUR.
+func IsForeign(tx domain.Transaction) bool {
+	return tx.ForeignCurrency != "" && tx.ForeignCurrency != "EUR" && tx.ForeignAmount != 0
+}
+
+// Convert analyses a foreign-currency transaction against the reference rate
+// of its transaction date.
+func Convert(tx domain.Transaction, rates *Rates) (Conversion, error) {
+	if !IsForeign(tx) {
+		return Conversion{}, fmt.Errorf("transaction %q has no foreign amount", tx.Payee)
+	}
+
+	rate, rateDate, err := rates.Rate(tx.ForeignCurrency, tx.Date)
+	if err != nil {
+		return Conversion{}, err
+	}
+
+	c := Conversion{
+		Transaction:   tx,
+		ReferenceRate: rate,
+		RateDate:      rateDate,
+		BankRate:      tx.ExchangeRate,
+		FairAmount:    roundCents(tx.ForeignAmount / rate),
+	}
+	if c.BankRate == 0 && tx.Amount != 0 {
+		c.BankRate = tx.ForeignAmount / tx.Amount
+	}
+
+	c.Markup = roundCents(c.FairAmount - tx.Amount)
+	if c.FairAmount != 0 {
+		c.MarkupPercent = c.Markup / math.Abs(c.FairAmount) * 100
+	}
+
+	return c, nil
+}
+
+// roundCents rounds an amount to two decimals.
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,221 @@
+package fx
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/domain"
+)
+
+const (
+	// SourceECB selects the ECB reference rate history as rate source.
+	SourceECB = "ecb"
+	// SourceFixed selects a fixed rate table as rate source.
+	SourceFixed = "fixed"
+)
+
+// Source provides exchange rates as units of a currency per unit of its base currency.
+type Source interface {
+	// Name identifies the source in memos and the import journal.
+	Name() string
+	// Base is the currency all rates are quoted against.
+	Base() string
+	// Rate returns the rate of currency on date and the day it applies to.
+	Rate(currency string, date time.Time) (float64, time.Time, error)
+}
+
+// Name returns SourceECB.
+func (r *Rates) Name() string {
+	return SourceECB
+}
+
+// Base returns EUR, the base of the ECB reference rates.
+func (r *Rates) Base() string {
+	return "EUR"
+}
+
+// FixedRates is a rate table independent of the date.
+type FixedRates struct {
+	// BaseCurrency is the currency the rates are quoted against.
+	BaseCurrency string
+	// Rates are units of each currency per unit of BaseCurrency.
+	Rates map[string]float64
+}
+
+// Name returns SourceFixed.
+func (f FixedRates) Name() string {
+	return SourceFixed
+}
+
+// Base returns the base currency of the table.
+func (f FixedRates) Base() string {
+	return strings.ToUpper(f.BaseCurrency)
+}
+
+// Rate returns the fixed rate of currency; date is returned unchanged.
+func (f FixedRates) Rate(currency string, date time.Time) (float64, time.Time, error) {
+	currency = strings.ToUpper(currency)
+	if currency == f.Base() {
+		return 1, date, nil
+	}
+	for c, rate := range f.Rates {
+		if strings.EqualFold(c, currency) && rate > 0 {
+			return rate, date, nil
+		}
+	}
+	return 0, time.Time{}, fmt.Errorf("%w for %s in the fixed rate table", ErrNoRate, currency)
+}
+
+// CrossRate returns the units of from per unit of to on date, derived from the
+// source's base rates. The returned time is the older of the two rate days.
+func CrossRate(src Source, from, to string, date time.Time) (float64, time.Time, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return 1, date, nil
+	}
+
+	rateFrom, dateFrom, err := baseRate(src, from, date)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	rateTo, dateTo, err := baseRate(src, to, date)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	rateDate := dateFrom
+	if dateTo.Before(rateDate) {
+		rateDate = dateTo
+	}
+	return rateFrom / rateTo, rateDate, nil
+}
+
+// baseRate returns the rate of currency against the source's base currency.
+func baseRate(src Source, currency string, date time.Time) (float64, time.Time, error) {
+	if currency == src.Base() {
+		return 1, date, nil
+	}
+	return src.Rate(currency, date)
+}
+
+// Exchange records the conversion of a transaction into another currency.
+type Exchange struct {
+	// FromAmount and FromCurrency are the original statement amount.
+	FromAmount   float64 `json:"from_amount"`
+	FromCurrency string  `json:"from_currency"`
+	// ToAmount and ToCurrency are the converted amount.
+	ToAmount   float64 `json:"to_amount"`
+	ToCurrency string  `json:"to_currency"`
+	// Rate is the units of FromCurrency per unit of ToCurrency.
+	Rate float64 `json:"rate"`
+	// RateDate is the day the rate applies to.
+	RateDate time.Time `json:"rate_date"`
+	// Source is the name of the rate source.
+	Source string `json:"source"`
+	// ImportID is the import ID of the converted transaction.
+	ImportID string `json:"import_id,omitempty"`
+}
+
+// Memo describes the exchange for a transaction memo, e.g.
+// "-10.00 USD @ 1.1850 USD/EUR (ecb 2026-01-28)".
+func (e Exchange) Memo() string {
+	return fmt.Sprintf("%.2f %s @ %.4f %s/%s (%s %s)",
+		e.FromAmount, e.FromCurrency, e.Rate, e.FromCurrency, e.ToCurrency, e.Source, e.RateDate.Format(isoDate))
+}
+
+// ToCurrency converts a transaction into the target currency at the source's
+// rate of its date, rounded to decimals digits. Splits are converted by the
+// same rate with the rounding difference put on the last split. The original
+// amount and rate are added to the memo and, unless the transaction already
+// carries a foreign amount, kept as foreign amount and exchange rate.
+// Transactions already in the target currency are returned unchanged with a nil Exchange.
+func ToCurrency(tx domain.Transaction, target string, decimals int, src Source) (domain.Transaction, *Exchange, error) {
+	from := strings.ToUpper(tx.Currency)
+	target = strings.ToUpper(target)
+	if from == "" || from == target {
+		return tx, nil, nil
+	}
+
+	rate, rateDate, err := CrossRate(src, from, target, tx.Date)
+	if err != nil {
+		return tx, nil, err
+	}
+
+	e := &Exchange{
+		FromAmount:   tx.Amount,
+		FromCurrency: from,
+		ToAmount:     round(tx.Amount/rate, decimals),
+		ToCurrency:   target,
+		Rate:         rate,
+		RateDate:     rateDate,
+		Source:       src.Name(),
+		ImportID:     tx.ImportID,
+	}
+
+	if len(tx.Splits) > 0 {
+		splits := make([]domain.Split, len(tx.Splits))
+		copy(splits, tx.Splits)
+		remaining := e.ToAmount
+		for i := range splits {
+			if i == len(splits)-1 {
+				splits[i].Amount = round(remaining, decimals)
+				break
+			}
+			splits[i].Amount = round(splits[i].Amount/rate, decimals)
+			remaining -= splits[i].Amount
+		}
+		tx.Splits = splits
+	}
+
+	if tx.ForeignCurrency == "" {
+		tx.ForeignCurrency = from
+		tx.ForeignAmount = tx.Amount
+		tx.ExchangeRate = rate
+	}
+
+	tx.Amount = e.ToAmount
+	tx.Currency = target
+	if tx.Memo == "" {
+		tx.Memo = e.Memo()
+	} else {
+		tx.Memo = tx.Memo + "; " + e.Memo()
+	}
+
+	return tx, e, nil
+}
+
+// NeedsConversion reports whether any transaction is in another currency than target.
+func NeedsConversion(transactions []domain.Transaction, target string) bool {
+	for _, tx := range transactions {
+		if tx.Currency != "" && !strings.EqualFold(tx.Currency, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ConvertAll converts every transaction into the target currency with
+// ToCurrency and returns the exchanges of the converted ones in order.
+func ConvertAll(transactions []domain.Transaction, target string, decimals int, src Source) ([]domain.Transaction, []Exchange, error) {
+	converted := make([]domain.Transaction, 0, len(transactions))
+	var exchanges []Exchange
+	for _, tx := range transactions {
+		result, e, err := ToCurrency(tx, target, decimals, src)
+		if err != nil {
+			return nil, nil, fmt.Errorf("converting %q on %s to %s: %w", tx.Payee, tx.Date.Format(isoDate), strings.ToUpper(target), err)
+		}
+		converted = append(converted, result)
+		if e != nil {
+			exchanges = append(exchanges, *e)
+		}
+	}
+	return converted, exchanges, nil
+}
+
+// round rounds amount to the given number of decimal digits.
+func round(amount float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(amount*scale) / scale
+}
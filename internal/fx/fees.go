@@ -0,0 +1,109 @@
+package fx
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pgbytes/moneypenny/internal/domain"
+)
+
+const (
+	// ForeignFeePayee is the payee of the foreign transaction fee rows on
+	// Miles & More statements.
+	ForeignFeePayee = "AUSLANDSEINSATZENTGELT"
+
+	// DefaultFeeWindow is the default tolerance in days between a fee and its transaction.
+	DefaultFeeWindow = 3
+)
+
+// FeeLink pairs a foreign-currency transaction with its foreign fee.
+// Either side may be nil: a transaction without a fee, or an orphan fee.
+type FeeLink struct {
+	Transaction *domain.Transaction
+	Fee         *domain.Transaction
+}
+
+// IsForeignFee reports whether tx is a foreign transaction fee.
+func IsForeignFee(tx domain.Transaction) bool {
+	return strings.Contains(strings.ToUpper(tx.Payee), ForeignFeePayee)
+}
+
+// LinkFees pairs every foreign fee with the foreign-currency transaction it
+// was charged for: same sign, fee date within window days of the
+// transaction's voucher or posting date, closest date first and then the
+// closest statement line. The result has one entry per foreign transaction
+// and per orphan fee, in statement order.
+func LinkFees(transactions []domain.Transaction, window int) []FeeLink {
+	if window <= 0 {
+		window = DefaultFeeWindow
+	}
+
+	var foreign, fees []int
+	for i, tx := range transactions {
+		switch {
+		case IsForeignFee(tx):
+			fees = append(fees, i)
+		case IsForeign(tx):
+			foreign = append(foreign, i)
+		}
+	}
+
+	type pair struct {
+		tx, fee     int
+		days, lines int
+	}
+	var pairs []pair
+	for _, t := range foreign {
+		for _, f := range fees {
+			tx, fee := transactions[t], transactions[f]
+			if (tx.Amount < 0) != (fee.Amount < 0) {
+				continue
+			}
+			days := domain.DayDelta(fee.Date, tx.Date)
+			if !tx.PostingDate.IsZero() {
+				if d := domain.DayDelta(fee.Date, tx.PostingDate); d < days {
+					days = d
+				}
+			}
+			if days > window {
+				continue
+			}
+			pairs = append(pairs, pair{tx: t, fee: f, days: days, lines: domain.Abs(t - f)})
+		}
+	}
+
+	sort.SliceStable(pairs, func(a, b int) bool {
+		if pairs[a].days != pairs[b].days {
+			return pairs[a].days < pairs[b].days
+		}
+		return pairs[a].lines < pairs[b].lines
+	})
+
+	feeOf := make(map[int]int)
+	linked := make(map[int]bool)
+	for _, p := range pairs {
+		if _, ok := feeOf[p.tx]; ok || linked[p.fee] {
+			continue
+		}
+		feeOf[p.tx] = p.fee
+		linked[p.fee] = true
+	}
+
+	var links []FeeLink
+	for i := range transactions {
+		switch {
+		case IsForeignFee(transactions[i]):
+			if !linked[i] {
+				links = append(links, FeeLink{Fee: &transactions[i]})
+			}
+		case IsForeign(transactions[i]):
+			link := FeeLink{Transaction: &transactions[i]}
+			if f, ok := feeOf[i]; ok {
+				link.Fee = &transactions[f]
+			}
+			links = append(links, link)
+		}
+	}
+
+	return links
+}
@@ -0,0 +1,295 @@
+package fx
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/stretchr/testify/suite"
+)
+
+// FXTestSuite groups reference rate and conversion tests.
+type FXTestSuite struct {
+	suite.Suite
+}
+
+func TestFXTestSuite(t *testing.T) {
+	suite.Run(t, new(FXTestSuite))
+}
+
+// date returns midnight UTC of the given day in January 2026.
+func date(day int) time.Time {
+	return time.Date(2026, 1, day, 0, 0, 0, 0, time.UTC)
+}
+
+// TestLoadFile_WithCSVAndXML_LoadsSameRates tests both ECB file formats.
+func (s *FXTestSuite) TestLoadFile_WithCSVAndXML_LoadsSameRates() {
+	for _, name := range DefaultFileNames {
+		s.Run(name, func() {
+			// Act
+			rates, err := LoadFile(filepath.Join("testdata", name))
+
+			// Assert
+			s.Require().NoError(err)
+			rate, rateDate, err := rates.Rate("usd", date(28))
+			s.Require().NoError(err)
+			s.Equal(1.1850, rate)
+			s.Equal(date(28), rateDate)
+			s.Contains(rates.Currencies(), "GBP")
+		})
+	}
+}
+
+// TestParseCSV_WithMissingRates_SkipsThem tests N/A handling.
+func (s *FXTestSuite) TestParseCSV_WithMissingRates_SkipsThem() {
+	// Act
+	rates, err := LoadFile(filepath.Join("testdata", "eurofxref-hist.csv"))
+
+	// Assert
+	s.Require().NoError(err)
+	s.NotContains(rates.Currencies(), "ISK")
+	first, last := rates.Range()
+	s.Equal(date(23), first)
+	s.Equal(date(28), last)
+}
+
+// TestParseCSV_WithInvalidHeader_ReturnsError tests header validation.
+func (s *FXTestSuite) TestParseCSV_WithInvalidHeader_ReturnsError() {
+	// Act
+	_, err := ParseCSV(strings.NewReader("Currency,Rate\nUSD,1.1\n"))
+
+	// Assert
+	s.Error(err)
+}
+
+// TestRate_OnWeekend_UsesPreviousPublication tests the lookback.
+func (s *FXTestSuite) TestRate_OnWeekend_UsesPreviousPublication() {
+	// Arrange
+	rates, err := LoadFile(filepath.Join("testdata", "eurofxref-hist.csv"))
+	s.Require().NoError(err)
+
+	// Act
+	rate, rateDate, err := rates.Rate("USD", date(25))
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(1.1765, rate)
+	s.Equal(date(23), rateDate)
+
+	_, _, err = rates.Rate("USD", date(31).AddDate(0, 0, MaxLookbackDays+1))
+	s.True(errors.Is(err, ErrNoRate))
+	_, _, err = rates.Rate("ISK", date(28))
+	s.True(errors.Is(err, ErrNoRate))
+}
+
+// TestConvert_WithBankRate_ComputesFairAmountAndMarkup tests markup analysis.
+func (s *FXTestSuite) TestConvert_WithBankRate_ComputesFairAmountAndMarkup() {
+	// Arrange
+	rates := NewRates()
+	rates.Set("USD", date(28), 1.2)
+	tx := domain.Transaction{Date: date(28), Amount: -8.44, ForeignAmount: -10, ForeignCurrency: "USD", ExchangeRate: 1.18483}
+
+	// Act
+	c, err := Convert(tx, rates)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(-8.33, c.FairAmount)
+	s.InDelta(0.11, c.Markup, 0.0001)
+	s.InDelta(1.32, c.MarkupPercent, 0.01)
+	s.Equal(1.18483, c.BankRate)
+}
+
+// TestConvert_WithRefund_CountsShortfallAsMarkup tests inflows.
+func (s *FXTestSuite) TestConvert_WithRefund_CountsShortfallAsMarkup() {
+	// Arrange
+	rates := NewRates()
+	rates.Set("USD", date(28), 1.2)
+	tx := domain.Transaction{Date: date(28), Amount: 8.2, ForeignAmount: 10, ForeignCurrency: "USD"}
+
+	// Act
+	c, err := Convert(tx, rates)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(8.33, c.FairAmount)
+	s.InDelta(0.13, c.Markup, 0.0001)
+	s.InDelta(10/8.2, c.BankRate, 0.0001)
+}
+
+// TestLinkFees_WithStatementRows_PairsFeesAndReportsOrphans tests fee linking.
+func (s *FXTestSuite) TestLinkFees_WithStatementRows_PairsFeesAndReportsOrphans() {
+	// Arrange
+	transactions := []domain.Transaction{
+		{Payee: ForeignFeePayee, Date: date(29), Amount: -0.16},
+		{Payee: "RECALL", Date: date(28), PostingDate: date(29), Amount: -8.44, ForeignAmount: -10, ForeignCurrency: "USD"},
+		{Payee: "HANDYPARKEN", Date: date(26), Amount: -6.3},
+		{Payee: "LONDON CAB", Date: date(20), PostingDate: date(21), Amount: -20, ForeignAmount: -17, ForeignCurrency: "GBP"},
+		{Payee: ForeignFeePayee, Date: date(10), Amount: -0.5},
+	}
+
+	// Act
+	links := LinkFees(transactions, DefaultFeeWindow)
+
+	// Assert
+	s.Require().Len(links, 3)
+	s.Equal("RECALL", links[0].Transaction.Payee)
+	s.Require().NotNil(links[0].Fee)
+	s.Equal(-0.16, links[0].Fee.Amount)
+	s.Equal("LONDON CAB", links[1].Transaction.Payee)
+	s.Nil(links[1].Fee)
+	s.Nil(links[2].Transaction)
+	s.Equal(-0.5, links[2].Fee.Amount)
+}
+
+// TestAuditFees_WithContractRate_ClassifiesFindings tests the fee audit.
+func (s *FXTestSuite) TestAuditFees_WithContractRate_ClassifiesFindings() {
+	// Arrange
+	transactions := []domain.Transaction{
+		{Payee: "RECALL", Date: date(28), Amount: -8.44, ForeignAmount: -10, ForeignCurrency: "USD"},
+		{Payee: ForeignFeePayee, Date: date(28), Amount: -0.16},
+		{Payee: "HOTEL", Date: date(20), Amount: -200, ForeignAmount: -190, ForeignCurrency: "CHF"},
+		{Payee: ForeignFeePayee, Date: date(20), Amount: -4.5},
+		{Payee: "TAXI", Date: date(15), Amount: -30, ForeignAmount: -28, ForeignCurrency: "CHF"},
+		{Payee: "HOTEL REFUND", Date: date(12), Amount: 50, ForeignAmount: 47, ForeignCurrency: "CHF"},
+		{Payee: ForeignFeePayee, Date: date(5), Amount: -1},
+	}
+
+	// Act
+	findings := AuditFees(transactions, AuditOptions{RatePercent: 1.75})
+
+	// Assert
+	s.Require().Len(findings, 4)
+
+	s.Equal(FeeOK, findings[0].Status)
+	s.Equal(0.15, findings[0].Expected)
+	s.Equal(0.16, findings[0].Charged)
+
+	s.Equal(FeeOvercharged, findings[1].Status)
+	s.Equal(3.5, findings[1].Expected)
+	s.InDelta(1.0, findings[1].Difference, 0.0001)
+	s.True(findings[1].Disputable())
+
+	s.Equal(FeeMissing, findings[2].Status)
+	s.Equal("TAXI", findings[2].Transaction.Payee)
+	s.Nil(findings[2].Fee)
+
+	s.Equal(FeeOrphan, findings[3].Status)
+	s.Nil(findings[3].Transaction)
+	s.Equal(1.0, findings[3].Charged)
+}
+
+// TestToCurrency_WithECBRates_ConvertsToBudgetCurrency tests conversion into the budget currency.
+func (s *FXTestSuite) TestToCurrency_WithECBRates_ConvertsToBudgetCurrency() {
+	// Arrange
+	rates := NewRates()
+	rates.Set("USD", date(23), 1.25)
+	tx := domain.Transaction{
+		Date: date(25), Payee: "Wise", Amount: -100, Currency: "USD", Memo: "Rent share",
+		Splits: []domain.Split{{Amount: -33.33}, {Amount: -66.67}},
+	}
+
+	// Act
+	result, e, err := ToCurrency(tx, "EUR", 2, rates)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().NotNil(e)
+	s.Equal(-80.0, result.Amount)
+	s.Equal("EUR", result.Currency)
+	s.Equal("USD", result.ForeignCurrency)
+	s.Equal(-100.0, result.ForeignAmount)
+	s.Equal(1.25, result.ExchangeRate)
+	s.Equal(date(23), e.RateDate)
+	s.Equal("Rent share; -100.00 USD @ 1.2500 USD/EUR (ecb 2026-01-23)", result.Memo)
+	s.Equal(-26.66, result.Splits[0].Amount)
+	s.InDelta(-53.34, result.Splits[1].Amount, 0.0001)
+	s.Equal(-33.33, tx.Splits[0].Amount, "original splits are not modified")
+}
+
+// TestToCurrency_WithFixedCrossRate_ConvertsBetweenNonBaseCurrencies tests cross rates.
+func (s *FXTestSuite) TestToCurrency_WithFixedCrossRate_ConvertsBetweenNonBaseCurrencies() {
+	// Arrange
+	fixed := FixedRates{BaseCurrency: "EUR", Rates: map[string]float64{"USD": 1.2, "GBP": 0.8}}
+	tx := domain.Transaction{Date: date(25), Amount: -30, Currency: "GBP", ForeignAmount: -5000, ForeignCurrency: "JPY"}
+
+	// Act
+	result, e, err := ToCurrency(tx, "USD", 2, fixed)
+
+	// Assert
+	s.Require().NoError(err)
+	s.InDelta(0.8/1.2, e.Rate, 0.0001)
+	s.Equal(-45.0, result.Amount)
+	s.Equal("JPY", result.ForeignCurrency, "an existing foreign amount is kept")
+	s.Equal(SourceFixed, e.Source)
+}
+
+// TestToCurrency_WithSameCurrencyOrMissingRate_HandlesEdgeCases tests no-ops and errors.
+func (s *FXTestSuite) TestToCurrency_WithSameCurrencyOrMissingRate_HandlesEdgeCases() {
+	// Arrange
+	fixed := FixedRates{BaseCurrency: "EUR", Rates: map[string]float64{}}
+	tx := domain.Transaction{Date: date(25), Amount: -30, Currency: "EUR"}
+
+	// Act
+	same, e, err := ToCurrency(tx, "EUR", 2, fixed)
+	tx.Currency = "CHF"
+	_, _, missingErr := ToCurrency(tx, "EUR", 2, fixed)
+
+	// Assert
+	s.NoError(err)
+	s.Nil(e)
+	s.Equal(-30.0, same.Amount)
+	s.True(errors.Is(missingErr, ErrNoRate))
+}
+
+// TestLoadRates_WithoutPath_FindsFileInDir tests the rates file lookup.
+func (s *FXTestSuite) TestLoadRates_WithoutPath_FindsFileInDir() {
+	// Act
+	rates, err := LoadRates("", "testdata")
+	_, missingErr := LoadRates("", s.T().TempDir())
+
+	// Assert
+	s.Require().NoError(err)
+	s.NotEmpty(rates.Currencies())
+	s.ErrorContains(missingErr, "no ECB rates file found")
+}
+
+// TestConvertAll_WithMixedCurrencies_ReturnsExchangesOfConvertedRows tests batch conversion.
+func (s *FXTestSuite) TestConvertAll_WithMixedCurrencies_ReturnsExchangesOfConvertedRows() {
+	// Arrange
+	fixed := FixedRates{BaseCurrency: "EUR", Rates: map[string]float64{"USD": 1.25}}
+	transactions := []domain.Transaction{
+		{Date: date(25), Amount: -10, Currency: "EUR", ImportID: "id-eur"},
+		{Date: date(25), Amount: -100, Currency: "USD", ImportID: "id-usd"},
+	}
+
+	// Act
+	needed := NeedsConversion(transactions, "eur")
+	converted, exchanges, err := ConvertAll(transactions, "EUR", 2, fixed)
+
+	// Assert
+	s.True(needed)
+	s.False(NeedsConversion(transactions[:1], "EUR"))
+	s.Require().NoError(err)
+	s.Equal(-10.0, converted[0].Amount)
+	s.Equal(-80.0, converted[1].Amount)
+	s.Require().Len(exchanges, 1)
+	s.Equal("id-usd", exchanges[0].ImportID)
+}
+
+// TestConvertAll_WithMissingRate_NamesTheTransaction tests the error context.
+func (s *FXTestSuite) TestConvertAll_WithMissingRate_NamesTheTransaction() {
+	// Arrange
+	fixed := FixedRates{BaseCurrency: "EUR", Rates: map[string]float64{}}
+	transactions := []domain.Transaction{{Date: date(25), Payee: "Migros", Amount: -30, Currency: "CHF"}}
+
+	// Act
+	_, _, err := ConvertAll(transactions, "EUR", 2, fixed)
+
+	// Assert
+	s.ErrorIs(err, ErrNoRate)
+	s.ErrorContains(err, `converting "Migros" on 2026-01-25 to EUR`)
+}
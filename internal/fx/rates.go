@@ -0,0 +1,233 @@
+// Package fx loads ECB euro reference rates and analyses the currency
+// conversions applied by card issuers.
+package fx
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxLookbackDays is how many days before a date a rate is looked up when
+	// the ECB published none on that day (weekends, holidays).
+	MaxLookbackDays = 7
+
+	// isoDate is the date format used in ECB files.
+	isoDate = "2006-01-02"
+)
+
+// DefaultFileNames are the names of the ECB history file looked up in the data directory.
+var DefaultFileNames = []string{"eurofxref-hist.csv", "eurofxref-hist.xml"}
+
+// ErrNoRate indicates that no reference rate is available for a currency and date.
+var ErrNoRate = errors.New("no reference rate")
+
+// Rates holds euro reference rates: units of a currency per 1 EUR, per day.
+type Rates struct {
+	byCurrency map[string]map[string]float64
+	first      time.Time
+	last       time.Time
+}
+
+// NewRates creates an empty rate table.
+func NewRates() *Rates {
+	return &Rates{byCurrency: make(map[string]map[string]float64)}
+}
+
+// Set stores the rate of a currency on a day.
+func (r *Rates) Set(currency string, date time.Time, rate float64) {
+	currency = strings.ToUpper(currency)
+	if r.byCurrency[currency] == nil {
+		r.byCurrency[currency] = make(map[string]float64)
+	}
+	r.byCurrency[currency][date.Format(isoDate)] = rate
+
+	if r.first.IsZero() || date.Before(r.first) {
+		r.first = date
+	}
+	if date.After(r.last) {
+		r.last = date
+	}
+}
+
+// Currencies returns the currencies with rates, sorted.
+func (r *Rates) Currencies() []string {
+	currencies := make([]string, 0, len(r.byCurrency))
+	for c := range r.byCurrency {
+		currencies = append(currencies, c)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// Range returns the first and last day with rates.
+func (r *Rates) Range() (time.Time, time.Time) {
+	return r.first, r.last
+}
+
+// Rate returns the reference rate of currency for date, falling back to the
+// closest earlier publication day within MaxLookbackDays. EUR is always 1.
+// The returned time is the day the rate was published for.
+func (r *Rates) Rate(currency string, date time.Time) (float64, time.Time, error) {
+	currency = strings.ToUpper(currency)
+	if currency == "EUR" {
+		return 1, date, nil
+	}
+
+	days, ok := r.byCurrency[currency]
+	if !ok {
+		return 0, time.Time{}, fmt.Errorf("%w for %s", ErrNoRate, currency)
+	}
+
+	for i := 0; i <= MaxLookbackDays; i++ {
+		day := date.AddDate(0, 0, -i)
+		if rate, ok := days[day.Format(isoDate)]; ok {
+			return rate, day, nil
+		}
+	}
+
+	return 0, time.Time{}, fmt.Errorf("%w for %s on %s", ErrNoRate, currency, date.Format(isoDate))
+}
+
+// FindFile returns the first ECB history file in dir, or "" if there is none.
+func FindFile(dir string) string {
+	for _, name := range DefaultFileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// LoadRates loads the ECB history file at path, or the one FindFile finds in
+// dir when path is empty.
+func LoadRates(path, dir string) (*Rates, error) {
+	if path == "" {
+		path = FindFile(dir)
+		if path == "" {
+			return nil, fmt.Errorf("no ECB rates file found, download eurofxref-hist.zip from the ECB website and unzip it into %s, or use --rates", dir)
+		}
+	}
+	return LoadFile(path)
+}
+
+// LoadFile loads an ECB history file. Files ending in .xml are read as the
+// ECB XML feed, everything else as the eurofxref-hist CSV.
+func LoadFile(path string) (*Rates, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening rates file: %w", err)
+	}
+	defer f.Close()
+
+	var rates *Rates
+	if strings.EqualFold(filepath.Ext(path), ".xml") {
+		rates, err = ParseXML(f)
+	} else {
+		rates, err = ParseCSV(f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Base(path), err)
+	}
+
+	return rates, nil
+}
+
+// ParseCSV reads the ECB eurofxref-hist CSV: a "Date,USD,JPY,..." header and
+// one row per day. Missing rates ("N/A" or empty) are skipped.
+func ParseCSV(reader io.Reader) (*Rates, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	if len(header) < 2 || !strings.EqualFold(strings.TrimSpace(header[0]), "Date") {
+		return nil, fmt.Errorf("unexpected header, expected Date column first")
+	}
+
+	rates := NewRates()
+	line := 1
+	for {
+		record, err := csvReader.Read()
+		line++
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		date, err := time.Parse(isoDate, strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date: %w", line, err)
+		}
+
+		for i := 1; i < len(record) && i < len(header); i++ {
+			currency := strings.TrimSpace(header[i])
+			value := strings.TrimSpace(record[i])
+			if currency == "" || value == "" || value == "N/A" {
+				continue
+			}
+			rate, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid %s rate: %w", line, currency, err)
+			}
+			rates.Set(currency, date, rate)
+		}
+	}
+
+	return rates, nil
+}
+
+// xmlEnvelope is the structure of the ECB XML feed.
+type xmlEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// ParseXML reads the ECB eurofxref-hist XML feed.
+func ParseXML(reader io.Reader) (*Rates, error) {
+	var envelope xmlEnvelope
+	if err := xml.NewDecoder(reader).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("decoding xml: %w", err)
+	}
+
+	rates := NewRates()
+	for _, day := range envelope.Days {
+		date, err := time.Parse(isoDate, day.Time)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q: %w", day.Time, err)
+		}
+		for _, r := range day.Rates {
+			rate, err := strconv.ParseFloat(r.Rate, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s rate on %s: %w", r.Currency, day.Time, err)
+			}
+			rates.Set(r.Currency, date, rate)
+		}
+	}
+
+	if len(rates.byCurrency) == 0 {
+		return nil, fmt.Errorf("no rates found")
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,233 @@
+// Package importer posts parsed statement transactions to YNAB and records
+// every import run in the local journal so it can be reverted later.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/pgbytes/moneypenny/internal/journal"
+	"github.com/pgbytes/moneypenny/internal/log"
+	transform "github.com/pgbytes/moneypenny/internal/transform/ynab"
+)
+
+// Client is the subset of the YNAB client used by the importer.
+type Client interface {
+	BudgetID() string
+	CreateTransactions(transactions []ynab.SaveTransaction) (*ynab.SaveTransactionsResponse, error)
+	GetTransaction(transactionID string) (*ynab.Transaction, error)
+	DeleteTransaction(transactionID string) (*ynab.Transaction, error)
+}
+
+// Importer creates transactions in YNAB and journals the result.
+type Importer struct {
+	client  Client
+	journal *journal.Journal
+	logger  log.Logger
+	now     func() time.Time
+}
+
+// New creates an Importer using the given client and journal.
+func New(client Client, j *journal.Journal, logger log.Logger) *Importer {
+	return &Importer{
+		client:  client,
+		journal: j,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// Request describes a statement to import.
+type Request struct {
+	// Source is the statement format, e.g. "milesmore".
+	Source string
+	// SourceFile is the path of the statement file.
+	SourceFile string
+	// SourceHash is the SHA-256 of the statement file contents.
+	SourceHash string
+	// AccountID is the YNAB account to create the transactions in.
+	AccountID string
+	// Transactions are the parsed statement transactions.
+	Transactions []domain.Transaction
+	// Conversions holds the currency conversion of transactions by import ID (optional).
+	Conversions map[string]journal.Conversion
+}
+
+// Import creates the request's transactions in YNAB and records the run in the journal.
+// The journal is saved before returning so the run can be reverted even if the caller fails later.
+func (i *Importer) Import(req Request) (*journal.Run, error) {
+	if req.AccountID == "" {
+		return nil, fmt.Errorf("account id is required")
+	}
+	if len(req.Transactions) == 0 {
+		return nil, fmt.Errorf("no transactions to import")
+	}
+
+	resp, err := i.client.CreateTransactions(transform.ToSaveTransactions(req.Transactions, req.AccountID))
+	if err != nil {
+		return nil, fmt.Errorf("creating transactions: %w", err)
+	}
+
+	run := journal.Run{
+		ID:                 journal.NewRunID(i.now()),
+		Source:             req.Source,
+		SourceFile:         req.SourceFile,
+		SourceHash:         req.SourceHash,
+		BudgetID:           i.client.BudgetID(),
+		AccountID:          req.AccountID,
+		CreatedAt:          i.now().UTC(),
+		Status:             journal.RunStatusCompleted,
+		Transactions:       make([]journal.Entry, 0, len(resp.Data.Transactions)),
+		DuplicateImportIDs: resp.Data.DuplicateImportIDs,
+	}
+
+	for _, t := range resp.Data.Transactions {
+		entry := journal.Entry{
+			TransactionID: t.ID,
+			ImportID:      t.ImportID,
+			Date:          t.Date,
+			Amount:        t.Amount,
+			PayeeName:     t.PayeeName,
+			Memo:          t.Memo,
+			CategoryID:    t.CategoryID,
+			FlagColor:     t.FlagColor,
+		}
+		if c, ok := req.Conversions[t.ImportID]; ok && t.ImportID != "" {
+			entry.Conversion = &c
+		}
+		run.Transactions = append(run.Transactions, entry)
+	}
+
+	if err := i.journal.Add(run); err != nil {
+		return nil, fmt.Errorf("recording run: %w", err)
+	}
+	if err := i.journal.Save(); err != nil {
+		return nil, fmt.Errorf("saving journal: %w", err)
+	}
+
+	i.logger.Debugf("Recorded import run %s with %d transactions", run.ID, len(run.Transactions))
+
+	return &run, nil
+}
+
+// UndoWarning describes why deleting a journaled transaction may lose data.
+type UndoWarning struct {
+	Entry   journal.Entry
+	Reasons []string
+}
+
+// UndoPlan lists what reverting a run will do.
+type UndoPlan struct {
+	// Run is the run to revert.
+	Run *journal.Run
+	// ToDelete are the transactions that still exist in YNAB.
+	ToDelete []journal.Entry
+	// Missing are transactions that were already deleted in YNAB.
+	Missing []journal.Entry
+	// Warnings lists transactions edited or reconciled since the import.
+	Warnings []UndoWarning
+}
+
+// PlanUndo inspects the current state in YNAB of every transaction created by a run.
+func (i *Importer) PlanUndo(runID string) (*UndoPlan, error) {
+	run, err := i.journal.Get(runID)
+	if err != nil {
+		return nil, err
+	}
+	if run.Status == journal.RunStatusReverted {
+		return nil, fmt.Errorf("run %s was already reverted", runID)
+	}
+	if run.BudgetID != i.client.BudgetID() {
+		return nil, fmt.Errorf("run %s belongs to budget %s, not %s", runID, run.BudgetID, i.client.BudgetID())
+	}
+
+	plan := &UndoPlan{Run: run}
+
+	for _, entry := range run.Transactions {
+		current, err := i.client.GetTransaction(entry.TransactionID)
+		if errors.Is(err, ynab.ErrNotFound) || (err == nil && current.Deleted) {
+			plan.Missing = append(plan.Missing, entry)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("fetching transaction %s: %w", entry.TransactionID, err)
+		}
+
+		plan.ToDelete = append(plan.ToDelete, entry)
+
+		if reasons := changesSinceImport(entry, *current); len(reasons) > 0 {
+			plan.Warnings = append(plan.Warnings, UndoWarning{Entry: entry, Reasons: reasons})
+		}
+	}
+
+	return plan, nil
+}
+
+// ExecuteUndo deletes the planned transactions and marks the run as reverted.
+// The run is only marked reverted when every deletion succeeded.
+func (i *Importer) ExecuteUndo(plan *UndoPlan) (int, error) {
+	deleted := 0
+	var failed []string
+
+	for _, entry := range plan.ToDelete {
+		if _, err := i.client.DeleteTransaction(entry.TransactionID); err != nil {
+			if errors.Is(err, ynab.ErrNotFound) {
+				continue
+			}
+			i.logger.Errorf("Deleting transaction %s: %v", entry.TransactionID, err)
+			failed = append(failed, entry.TransactionID)
+			continue
+		}
+		deleted++
+	}
+
+	if len(failed) > 0 {
+		return deleted, fmt.Errorf("%d transactions could not be deleted, run %s left unchanged", len(failed), plan.Run.ID)
+	}
+
+	if err := i.journal.MarkReverted(plan.Run.ID, i.now().UTC()); err != nil {
+		return deleted, err
+	}
+	if err := i.journal.Save(); err != nil {
+		return deleted, fmt.Errorf("saving journal: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// changesSinceImport lists the differences between the journaled snapshot and the current transaction.
+func changesSinceImport(entry journal.Entry, current ynab.Transaction) []string {
+	var reasons []string
+
+	if current.Cleared == ynab.ClearedStatusReconciled {
+		reasons = append(reasons, "reconciled")
+	}
+	if current.Amount != entry.Amount {
+		reasons = append(reasons, fmt.Sprintf("amount changed from %.2f to %.2f",
+			ynab.MilliunitsToFloat(entry.Amount), ynab.MilliunitsToFloat(current.Amount)))
+	}
+	if current.Date != entry.Date {
+		reasons = append(reasons, fmt.Sprintf("date changed from %s to %s", entry.Date, current.Date))
+	}
+	if current.PayeeName != entry.PayeeName {
+		reasons = append(reasons, fmt.Sprintf("payee changed from %q to %q", entry.PayeeName, current.PayeeName))
+	}
+	if current.Memo != entry.Memo {
+		reasons = append(reasons, "memo changed")
+	}
+	if current.CategoryID != entry.CategoryID {
+		if current.CategoryID == "" {
+			reasons = append(reasons, "category removed")
+		} else {
+			reasons = append(reasons, fmt.Sprintf("categorized as %q", current.CategoryName))
+		}
+	}
+	if current.FlagColor != entry.FlagColor {
+		reasons = append(reasons, fmt.Sprintf("flag changed from %q to %q", entry.FlagColor, current.FlagColor))
+	}
+
+	return reasons
+}
@@ -0,0 +1,290 @@
+package importer
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/pgbytes/moneypenny/internal/journal"
+	"github.com/stretchr/testify/suite"
+)
+
+// mockLogger implements log.Logger interface for testing.
+type mockLogger struct{}
+
+func (m *mockLogger) Warn(args ...interface{})                    {}
+func (m *mockLogger) Warnf(template string, args ...interface{})  {}
+func (m *mockLogger) Info(args ...interface{})                    {}
+func (m *mockLogger) Infof(template string, args ...interface{})  {}
+func (m *mockLogger) Debug(args ...interface{})                   {}
+func (m *mockLogger) Debugf(template string, args ...interface{}) {}
+func (m *mockLogger) Error(args ...interface{})                   {}
+func (m *mockLogger) Errorf(template string, args ...interface{}) {}
+func (m *mockLogger) Fatal(args ...interface{})                   {}
+func (m *mockLogger) Fatalf(template string, args ...interface{}) {}
+
+// fakeClient is an in-memory stand-in for the YNAB client.
+type fakeClient struct {
+	transactions map[string]ynab.Transaction
+	deleted      []string
+	nextID       int
+	deleteErr    error
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{transactions: make(map[string]ynab.Transaction)}
+}
+
+func (f *fakeClient) BudgetID() string { return "budget-1" }
+
+func (f *fakeClient) CreateTransactions(transactions []ynab.SaveTransaction) (*ynab.SaveTransactionsResponse, error) {
+	resp := &ynab.SaveTransactionsResponse{}
+	for _, t := range transactions {
+		f.nextID++
+		created := ynab.Transaction{
+			ID:         fmt.Sprintf("tx-%d", f.nextID),
+			AccountID:  t.AccountID,
+			Date:       t.Date,
+			Amount:     t.Amount,
+			PayeeName:  t.PayeeName,
+			Memo:       t.Memo,
+			ImportID:   t.ImportID,
+			Cleared:    t.Cleared,
+			CategoryID: t.CategoryID,
+			FlagColor:  t.FlagColor,
+		}
+		f.transactions[created.ID] = created
+		resp.Data.TransactionIDs = append(resp.Data.TransactionIDs, created.ID)
+		resp.Data.Transactions = append(resp.Data.Transactions, created)
+	}
+	return resp, nil
+}
+
+func (f *fakeClient) GetTransaction(id string) (*ynab.Transaction, error) {
+	t, ok := f.transactions[id]
+	if !ok {
+		return nil, ynab.ErrNotFound
+	}
+	return &t, nil
+}
+
+func (f *fakeClient) DeleteTransaction(id string) (*ynab.Transaction, error) {
+	if f.deleteErr != nil {
+		return nil, f.deleteErr
+	}
+	t, ok := f.transactions[id]
+	if !ok {
+		return nil, ynab.ErrNotFound
+	}
+	delete(f.transactions, id)
+	f.deleted = append(f.deleted, id)
+	t.Deleted = true
+	return &t, nil
+}
+
+// ImporterTestSuite groups all importer tests.
+type ImporterTestSuite struct {
+	suite.Suite
+	client   *fakeClient
+	journal  *journal.Journal
+	importer *Importer
+}
+
+func TestImporterTestSuite(t *testing.T) {
+	suite.Run(t, new(ImporterTestSuite))
+}
+
+func (s *ImporterTestSuite) SetupTest() {
+	j, err := journal.Open(filepath.Join(s.T().TempDir(), journal.DefaultFileName))
+	s.Require().NoError(err)
+
+	s.client = newFakeClient()
+	s.journal = j
+	s.importer = New(s.client, j, &mockLogger{})
+}
+
+func (s *ImporterTestSuite) importSample() *journal.Run {
+	run, err := s.importer.Import(Request{
+		Source:     "milesmore",
+		SourceFile: "statement.csv",
+		SourceHash: "abc",
+		AccountID:  "acc-1",
+		Transactions: []domain.Transaction{
+			{Date: time.Date(2026, 1, 28, 0, 0, 0, 0, time.UTC), Payee: "Coffee", Amount: -3.5, ImportID: "YNAB:-3500:2026-01-28:1"},
+			{Date: time.Date(2026, 1, 29, 0, 0, 0, 0, time.UTC), Payee: "Bakery", Amount: -2.2, ImportID: "YNAB:-2200:2026-01-29:1"},
+		},
+	})
+	s.Require().NoError(err)
+	return run
+}
+
+func (s *ImporterTestSuite) TestImport_WithTransactions_RecordsRunInJournal() {
+	// Act
+	run := s.importSample()
+
+	// Assert
+	s.Equal(journal.RunStatusCompleted, run.Status)
+	s.Equal("budget-1", run.BudgetID)
+	s.Len(run.Transactions, 2)
+	s.Equal("YNAB:-3500:2026-01-28:1", run.Transactions[0].ImportID)
+
+	reopened, err := journal.Open(s.journal.Path())
+	s.Require().NoError(err)
+	_, err = reopened.Get(run.ID)
+	s.NoError(err, "journal should be persisted")
+}
+
+func (s *ImporterTestSuite) TestImport_WithConversions_RecordsConversionPerEntry() {
+	// Arrange
+	conversion := journal.Conversion{
+		OriginalAmount: -10, OriginalCurrency: "USD", Currency: "EUR",
+		Rate: 1.25, RateDate: "2026-01-28", RateSource: "ecb",
+	}
+
+	// Act
+	run, err := s.importer.Import(Request{
+		AccountID: "acc-1",
+		Transactions: []domain.Transaction{
+			{Date: time.Date(2026, 1, 28, 0, 0, 0, 0, time.UTC), Payee: "Wise", Amount: -8, Currency: "EUR", ImportID: "YNAB:-10000:2026-01-28:1"},
+			{Date: time.Date(2026, 1, 29, 0, 0, 0, 0, time.UTC), Payee: "Bakery", Amount: -2.2, ImportID: "YNAB:-2200:2026-01-29:1"},
+		},
+		Conversions: map[string]journal.Conversion{"YNAB:-10000:2026-01-28:1": conversion},
+	})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().NotNil(run.Transactions[0].Conversion)
+	s.Equal(conversion, *run.Transactions[0].Conversion)
+	s.Nil(run.Transactions[1].Conversion)
+}
+
+func (s *ImporterTestSuite) TestImport_WithoutAccount_ReturnsError() {
+	// Act
+	run, err := s.importer.Import(Request{Transactions: []domain.Transaction{{Payee: "x"}}})
+
+	// Assert
+	s.Nil(run)
+	s.Contains(err.Error(), "account id is required")
+}
+
+func (s *ImporterTestSuite) TestUndo_WithUnchangedTransactions_DeletesAllAndMarksReverted() {
+	// Arrange
+	run := s.importSample()
+
+	// Act
+	plan, err := s.importer.PlanUndo(run.ID)
+	s.Require().NoError(err)
+	deleted, err := s.importer.ExecuteUndo(plan)
+
+	// Assert
+	s.NoError(err)
+	s.Equal(2, deleted)
+	s.Empty(plan.Warnings)
+	s.Empty(s.client.transactions)
+	reverted, _ := s.journal.Get(run.ID)
+	s.Equal(journal.RunStatusReverted, reverted.Status)
+}
+
+func (s *ImporterTestSuite) TestPlanUndo_WithEditedAndReconciledTransactions_ReturnsWarnings() {
+	// Arrange
+	run := s.importSample()
+	edited := s.client.transactions["tx-1"]
+	edited.PayeeName = "Renamed"
+	s.client.transactions["tx-1"] = edited
+	reconciled := s.client.transactions["tx-2"]
+	reconciled.Cleared = ynab.ClearedStatusReconciled
+	s.client.transactions["tx-2"] = reconciled
+
+	// Act
+	plan, err := s.importer.PlanUndo(run.ID)
+
+	// Assert
+	s.NoError(err)
+	s.Len(plan.Warnings, 2)
+	s.Contains(plan.Warnings[0].Reasons[0], "payee changed")
+	s.Equal([]string{"reconciled"}, plan.Warnings[1].Reasons)
+}
+
+func (s *ImporterTestSuite) TestPlanUndo_WithCategoryAndFlagFromImport_ReturnsNoWarning() {
+	// Arrange
+	run, err := s.importer.Import(Request{
+		Source:    "milesmore",
+		AccountID: "acc-1",
+		Transactions: []domain.Transaction{
+			{Date: time.Date(2026, 1, 28, 0, 0, 0, 0, time.UTC), Payee: "Coffee", Amount: -3.5, Category: "cat-1", FlagColor: "red", ImportID: "YNAB:-3500:2026-01-28:1"},
+		},
+	})
+	s.Require().NoError(err)
+
+	// Act
+	plan, err := s.importer.PlanUndo(run.ID)
+
+	// Assert
+	s.NoError(err)
+	s.Empty(plan.Warnings)
+	s.Equal("cat-1", run.Transactions[0].CategoryID)
+}
+
+func (s *ImporterTestSuite) TestPlanUndo_WithCategoryChangedAfterImport_ReturnsWarning() {
+	// Arrange
+	run := s.importSample()
+	categorized := s.client.transactions["tx-1"]
+	categorized.CategoryID = "cat-2"
+	categorized.CategoryName = "Eating Out"
+	s.client.transactions["tx-1"] = categorized
+
+	// Act
+	plan, err := s.importer.PlanUndo(run.ID)
+
+	// Assert
+	s.NoError(err)
+	s.Require().Len(plan.Warnings, 1)
+	s.Equal([]string{`categorized as "Eating Out"`}, plan.Warnings[0].Reasons)
+}
+
+func (s *ImporterTestSuite) TestPlanUndo_WithAlreadyDeletedTransaction_ReportsMissing() {
+	// Arrange
+	run := s.importSample()
+	delete(s.client.transactions, "tx-1")
+
+	// Act
+	plan, err := s.importer.PlanUndo(run.ID)
+
+	// Assert
+	s.NoError(err)
+	s.Len(plan.Missing, 1)
+	s.Len(plan.ToDelete, 1)
+}
+
+func (s *ImporterTestSuite) TestPlanUndo_WithRevertedRun_ReturnsError() {
+	// Arrange
+	run := s.importSample()
+	s.Require().NoError(s.journal.MarkReverted(run.ID, time.Now()))
+
+	// Act
+	plan, err := s.importer.PlanUndo(run.ID)
+
+	// Assert
+	s.Nil(plan)
+	s.Contains(err.Error(), "already reverted")
+}
+
+func (s *ImporterTestSuite) TestExecuteUndo_WithDeleteFailure_LeavesRunCompleted() {
+	// Arrange
+	run := s.importSample()
+	plan, err := s.importer.PlanUndo(run.ID)
+	s.Require().NoError(err)
+	s.client.deleteErr = ynab.ErrRateLimited
+
+	// Act
+	deleted, err := s.importer.ExecuteUndo(plan)
+
+	// Assert
+	s.Error(err)
+	s.Equal(0, deleted)
+	current, _ := s.journal.Get(run.ID)
+	s.Equal(journal.RunStatusCompleted, current.Status)
+}
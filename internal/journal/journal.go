@@ -0,0 +1,196 @@
+// Package journal records import runs in a local JSON file so they can be
+// listed and reverted later.
+package journal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/fx"
+	"github.com/pgbytes/moneypenny/internal/storage"
+)
+
+const (
+	// DefaultFileName is the journal file name inside the data directory.
+	DefaultFileName = "journal.json"
+
+	// journalVersion is the current on-disk format version.
+	journalVersion = 1
+)
+
+// ErrRunNotFound indicates that no run with the given ID exists in the journal.
+var ErrRunNotFound = errors.New("import run not found")
+
+// RunStatus is the lifecycle state of an import run.
+type RunStatus string
+
+const (
+	// RunStatusCompleted indicates the run created its transactions successfully.
+	RunStatusCompleted RunStatus = "completed"
+	// RunStatusReverted indicates the run's transactions were deleted again.
+	RunStatusReverted RunStatus = "reverted"
+)
+
+// Run describes a single import of a statement file into a YNAB account.
+type Run struct {
+	// ID uniquely identifies the run.
+	ID string `json:"id"`
+	// Source is the statement format, e.g. "milesmore".
+	Source string `json:"source"`
+	// SourceFile is the path of the imported statement file.
+	SourceFile string `json:"source_file"`
+	// SourceHash is the SHA-256 of the statement file contents.
+	SourceHash string `json:"source_hash"`
+	// BudgetID is the budget the transactions were created in.
+	BudgetID string `json:"budget_id"`
+	// AccountID is the account the transactions were created in.
+	AccountID string `json:"account_id"`
+	// CreatedAt is when the run was recorded.
+	CreatedAt time.Time `json:"created_at"`
+	// Status is the current lifecycle state.
+	Status RunStatus `json:"status"`
+	// RevertedAt is when the run was reverted (nil unless reverted).
+	RevertedAt *time.Time `json:"reverted_at,omitempty"`
+	// Transactions are the transactions created by the run.
+	Transactions []Entry `json:"transactions"`
+	// DuplicateImportIDs are import IDs YNAB skipped because they already existed.
+	DuplicateImportIDs []string `json:"duplicate_import_ids,omitempty"`
+}
+
+// Entry is a snapshot of a transaction as it was created by a run.
+// The snapshot is used to detect edits made after the import.
+type Entry struct {
+	TransactionID string `json:"transaction_id"`
+	ImportID      string `json:"import_id"`
+	Date          string `json:"date"`
+	Amount        int64  `json:"amount"`
+	PayeeName     string `json:"payee_name"`
+	Memo          string `json:"memo"`
+	CategoryID    string `json:"category_id,omitempty"`
+	FlagColor     string `json:"flag_color,omitempty"`
+	// Conversion is set when the amount was converted into the budget currency.
+	Conversion *Conversion `json:"conversion,omitempty"`
+}
+
+// Conversion records how a statement amount was converted into the budget currency.
+type Conversion struct {
+	OriginalAmount   float64 `json:"original_amount"`
+	OriginalCurrency string  `json:"original_currency"`
+	Currency         string  `json:"currency"`
+	// Rate is the units of OriginalCurrency per unit of Currency.
+	Rate       float64 `json:"rate"`
+	RateDate   string  `json:"rate_date"`
+	RateSource string  `json:"rate_source"`
+}
+
+// NewConversion records the exchange of a converted statement amount.
+func NewConversion(e fx.Exchange) Conversion {
+	return Conversion{
+		OriginalAmount:   e.FromAmount,
+		OriginalCurrency: e.FromCurrency,
+		Currency:         e.ToCurrency,
+		Rate:             e.Rate,
+		RateDate:         e.RateDate.Format("2006-01-02"),
+		RateSource:       e.Source,
+	}
+}
+
+// Journal is the collection of recorded import runs backed by a file.
+type Journal struct {
+	path string
+	Runs []Run
+}
+
+// fileFormat is the on-disk representation of the journal.
+type fileFormat struct {
+	Version int   `json:"version"`
+	Runs    []Run `json:"runs"`
+}
+
+// Open loads the journal at path. A missing file yields an empty journal.
+func Open(path string) (*Journal, error) {
+	j := &Journal{path: path}
+
+	var f fileFormat
+	if _, err := storage.LoadJSON(path, &f); err != nil {
+		return nil, fmt.Errorf("loading journal: %w", err)
+	}
+	j.Runs = f.Runs
+
+	return j, nil
+}
+
+// OpenDir opens the journal stored as DefaultFileName in dir, e.g. the data directory.
+func OpenDir(dir string) (*Journal, error) {
+	return Open(filepath.Join(dir, DefaultFileName))
+}
+
+// Path returns the file the journal is stored in.
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// Add appends a run to the journal. The journal must be saved afterwards.
+func (j *Journal) Add(run Run) error {
+	if run.ID == "" {
+		return fmt.Errorf("run id is required")
+	}
+	if _, err := j.Get(run.ID); err == nil {
+		return fmt.Errorf("run %s already exists", run.ID)
+	}
+
+	j.Runs = append(j.Runs, run)
+	return nil
+}
+
+// Get returns the run with the given ID.
+func (j *Journal) Get(id string) (*Run, error) {
+	for i := range j.Runs {
+		if j.Runs[i].ID == id {
+			return &j.Runs[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrRunNotFound, id)
+}
+
+// List returns all runs, most recent first.
+func (j *Journal) List() []Run {
+	runs := make([]Run, len(j.Runs))
+	copy(runs, j.Runs)
+	sort.SliceStable(runs, func(a, b int) bool {
+		return runs[a].CreatedAt.After(runs[b].CreatedAt)
+	})
+	return runs
+}
+
+// MarkReverted marks a run as reverted at the given time.
+func (j *Journal) MarkReverted(id string, at time.Time) error {
+	run, err := j.Get(id)
+	if err != nil {
+		return err
+	}
+
+	run.Status = RunStatusReverted
+	run.RevertedAt = &at
+	return nil
+}
+
+// Save writes the journal atomically to its file, creating parent directories as needed.
+func (j *Journal) Save() error {
+	if err := storage.SaveJSON(j.path, fileFormat{Version: journalVersion, Runs: j.Runs}); err != nil {
+		return fmt.Errorf("saving journal: %w", err)
+	}
+	return nil
+}
+
+// NewRunID creates a sortable, unique run ID such as "20260203-141502-a1b2c3".
+func NewRunID(now time.Time) string {
+	suffix := make([]byte, 3)
+	_, _ = rand.Read(suffix)
+	return now.UTC().Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+}
@@ -0,0 +1,160 @@
+package journal
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/fx"
+	"github.com/stretchr/testify/suite"
+)
+
+// JournalTestSuite groups all journal tests.
+type JournalTestSuite struct {
+	suite.Suite
+	tempDir string
+}
+
+func TestJournalTestSuite(t *testing.T) {
+	suite.Run(t, new(JournalTestSuite))
+}
+
+func (s *JournalTestSuite) SetupTest() {
+	s.tempDir = s.T().TempDir()
+}
+
+func (s *JournalTestSuite) TestOpen_WithMissingFile_ReturnsEmptyJournal() {
+	// Act
+	j, err := Open(filepath.Join(s.tempDir, "missing.json"))
+
+	// Assert
+	s.NoError(err)
+	s.Empty(j.List())
+}
+
+func (s *JournalTestSuite) TestSave_ThenOpen_RoundTripsRuns() {
+	// Arrange
+	path := filepath.Join(s.tempDir, "nested", DefaultFileName)
+	j, err := Open(path)
+	s.Require().NoError(err)
+	s.Require().NoError(j.Add(Run{
+		ID:        "run-1",
+		AccountID: "acc-1",
+		CreatedAt: time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC),
+		Status:    RunStatusCompleted,
+		Transactions: []Entry{
+			{TransactionID: "tx-1", ImportID: "YNAB:-1000:2026-01-01:1", Amount: -1000},
+		},
+	}))
+
+	// Act
+	err = j.Save()
+	reopened, openErr := Open(path)
+
+	// Assert
+	s.NoError(err)
+	s.NoError(openErr)
+	run, err := reopened.Get("run-1")
+	s.Require().NoError(err)
+	s.Equal("acc-1", run.AccountID)
+	s.Len(run.Transactions, 1)
+	s.Equal("tx-1", run.Transactions[0].TransactionID)
+}
+
+func (s *JournalTestSuite) TestAdd_WithDuplicateID_ReturnsError() {
+	// Arrange
+	j, _ := Open(filepath.Join(s.tempDir, DefaultFileName))
+	s.Require().NoError(j.Add(Run{ID: "run-1"}))
+
+	// Act
+	err := j.Add(Run{ID: "run-1"})
+
+	// Assert
+	s.Error(err)
+	s.Contains(err.Error(), "already exists")
+}
+
+func (s *JournalTestSuite) TestGet_WithUnknownID_ReturnsErrRunNotFound() {
+	// Arrange
+	j, _ := Open(filepath.Join(s.tempDir, DefaultFileName))
+
+	// Act
+	run, err := j.Get("unknown")
+
+	// Assert
+	s.Nil(run)
+	s.ErrorIs(err, ErrRunNotFound)
+}
+
+func (s *JournalTestSuite) TestMarkReverted_WithExistingRun_UpdatesStatus() {
+	// Arrange
+	j, _ := Open(filepath.Join(s.tempDir, DefaultFileName))
+	s.Require().NoError(j.Add(Run{ID: "run-1", Status: RunStatusCompleted}))
+	at := time.Date(2026, 2, 4, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	err := j.MarkReverted("run-1", at)
+
+	// Assert
+	s.NoError(err)
+	run, _ := j.Get("run-1")
+	s.Equal(RunStatusReverted, run.Status)
+	s.Equal(at, *run.RevertedAt)
+}
+
+func (s *JournalTestSuite) TestList_WithMultipleRuns_ReturnsMostRecentFirst() {
+	// Arrange
+	j, _ := Open(filepath.Join(s.tempDir, DefaultFileName))
+	s.Require().NoError(j.Add(Run{ID: "old", CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}))
+	s.Require().NoError(j.Add(Run{ID: "new", CreatedAt: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)}))
+
+	// Act
+	runs := j.List()
+
+	// Assert
+	s.Equal("new", runs[0].ID)
+	s.Equal("old", runs[1].ID)
+}
+
+func (s *JournalTestSuite) TestNewRunID_StartsWithTimestamp() {
+	// Act
+	id := NewRunID(time.Date(2026, 2, 3, 14, 15, 2, 0, time.UTC))
+
+	// Assert
+	s.True(strings.HasPrefix(id, "20260203-141502-"))
+}
+
+func (s *JournalTestSuite) TestOpenDir_UsesDefaultFileName() {
+	// Arrange
+	j, err := OpenDir(s.tempDir)
+	s.Require().NoError(err)
+	s.Require().NoError(j.Add(Run{ID: "run-1"}))
+
+	// Act
+	err = j.Save()
+	reopened, openErr := Open(filepath.Join(s.tempDir, DefaultFileName))
+
+	// Assert
+	s.NoError(err)
+	s.NoError(openErr)
+	s.Equal(filepath.Join(s.tempDir, DefaultFileName), j.Path())
+	s.Len(reopened.List(), 1)
+}
+
+func (s *JournalTestSuite) TestNewConversion_RecordsExchange() {
+	// Arrange
+	e := fx.Exchange{
+		FromAmount: -100, FromCurrency: "USD", ToAmount: -80, ToCurrency: "EUR",
+		Rate: 1.25, RateDate: time.Date(2026, 1, 23, 0, 0, 0, 0, time.UTC), Source: fx.SourceECB,
+	}
+
+	// Act
+	c := NewConversion(e)
+
+	// Assert
+	s.Equal(Conversion{
+		OriginalAmount: -100, OriginalCurrency: "USD", Currency: "EUR",
+		Rate: 1.25, RateDate: "2026-01-23", RateSource: fx.SourceECB,
+	}, c)
+}
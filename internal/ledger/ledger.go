@@ -0,0 +1,217 @@
+// Package ledger keeps a local record of processed statement files so the
+// same statement is not transformed or imported twice by accident.
+package ledger
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/pgbytes/moneypenny/internal/storage"
+)
+
+const (
+	// DefaultFileName is the ledger file name inside the data directory.
+	DefaultFileName = "ledger.json"
+
+	// ledgerVersion is the current on-disk format version.
+	ledgerVersion = 1
+
+	// isoDate is the date format used for stored dates.
+	isoDate = "2006-01-02"
+)
+
+// Action describes how a statement was processed.
+type Action string
+
+const (
+	// ActionTransform indicates the statement was converted to a YNAB CSV file.
+	ActionTransform Action = "transform"
+	// ActionImport indicates the statement was posted to YNAB via the API.
+	ActionImport Action = "import"
+)
+
+// Statement records one processing of a statement file.
+type Statement struct {
+	// Hash is the SHA-256 of the statement file contents.
+	Hash string `json:"hash"`
+	// Source is the statement format, e.g. "milesmore".
+	Source string `json:"source"`
+	// FileName is the path of the statement file when it was processed.
+	FileName string `json:"file_name"`
+	// BillingDate is the statement billing date (YYYY-MM-DD), if known.
+	BillingDate string `json:"billing_date,omitempty"`
+	// AccountID is the YNAB account the statement was imported into, if any.
+	AccountID string `json:"account_id,omitempty"`
+	// FromDate is the earliest transaction date (YYYY-MM-DD).
+	FromDate string `json:"from_date"`
+	// ToDate is the latest transaction date (YYYY-MM-DD).
+	ToDate string `json:"to_date"`
+	// TransactionCount is the number of transactions in the statement.
+	TransactionCount int `json:"transaction_count"`
+	// Action is how the statement was processed.
+	Action Action `json:"action"`
+	// RunID links an import to its run in the import journal.
+	RunID string `json:"run_id,omitempty"`
+	// ProcessedAt is when the statement was processed.
+	ProcessedAt time.Time `json:"processed_at"`
+}
+
+// NewStatement describes a parsed statement ready to be checked and recorded.
+// Dates are derived from the transactions; billingDate may be zero.
+func NewStatement(source, fileName, hash string, billingDate time.Time, transactions []domain.Transaction) Statement {
+	stmt := Statement{
+		Hash:             hash,
+		Source:           source,
+		FileName:         fileName,
+		TransactionCount: len(transactions),
+	}
+
+	if !billingDate.IsZero() {
+		stmt.BillingDate = billingDate.Format(isoDate)
+	}
+
+	for i, tx := range transactions {
+		date := tx.Date.Format(isoDate)
+		if i == 0 || date < stmt.FromDate {
+			stmt.FromDate = date
+		}
+		if i == 0 || date > stmt.ToDate {
+			stmt.ToDate = date
+		}
+	}
+
+	return stmt
+}
+
+// ConflictKind classifies a conflict with a previously processed statement.
+type ConflictKind string
+
+const (
+	// ConflictExact indicates the identical file was already processed.
+	ConflictExact ConflictKind = "exact"
+	// ConflictOverlap indicates a different file covering overlapping dates was processed.
+	ConflictOverlap ConflictKind = "overlap"
+)
+
+// Conflict describes a previously processed statement clashing with a new one.
+type Conflict struct {
+	Kind     ConflictKind
+	Existing Statement
+}
+
+// Ledger is the collection of processed statements backed by a file.
+type Ledger struct {
+	path       string
+	Statements []Statement
+}
+
+// fileFormat is the on-disk representation of the ledger.
+type fileFormat struct {
+	Version    int         `json:"version"`
+	Statements []Statement `json:"statements"`
+}
+
+// Open loads the ledger at path. A missing file yields an empty ledger.
+func Open(path string) (*Ledger, error) {
+	l := &Ledger{path: path}
+
+	var f fileFormat
+	if _, err := storage.LoadJSON(path, &f); err != nil {
+		return nil, fmt.Errorf("loading ledger: %w", err)
+	}
+	l.Statements = f.Statements
+
+	return l, nil
+}
+
+// OpenDir opens the ledger stored as DefaultFileName in dir, e.g. the data directory.
+func OpenDir(dir string) (*Ledger, error) {
+	return Open(filepath.Join(dir, DefaultFileName))
+}
+
+// Check returns the conflicts between stmt and already processed statements.
+// Exact conflicts match on content hash and action. Overlap conflicts match
+// statements of the same source and action, and of the same account for
+// imports, whose date ranges intersect.
+func (l *Ledger) Check(stmt Statement) []Conflict {
+	var conflicts []Conflict
+
+	for _, existing := range l.Statements {
+		if existing.Action != stmt.Action {
+			continue
+		}
+
+		if existing.Hash == stmt.Hash {
+			conflicts = append(conflicts, Conflict{Kind: ConflictExact, Existing: existing})
+			continue
+		}
+
+		if existing.Source != stmt.Source || existing.AccountID != stmt.AccountID {
+			continue
+		}
+
+		if rangesOverlap(existing.FromDate, existing.ToDate, stmt.FromDate, stmt.ToDate) {
+			conflicts = append(conflicts, Conflict{Kind: ConflictOverlap, Existing: existing})
+		}
+	}
+
+	return conflicts
+}
+
+// Record appends a processed statement. The ledger must be saved afterwards.
+func (l *Ledger) Record(stmt Statement, processedAt time.Time) {
+	stmt.ProcessedAt = processedAt.UTC()
+	l.Statements = append(l.Statements, stmt)
+}
+
+// RemoveRun removes the statement recorded for an import run, so a reverted
+// import can be imported again. It reports whether a statement was removed.
+func (l *Ledger) RemoveRun(runID string) bool {
+	for i, stmt := range l.Statements {
+		if runID != "" && stmt.RunID == runID {
+			l.Statements = append(l.Statements[:i], l.Statements[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// List returns all processed statements, most recent first.
+func (l *Ledger) List() []Statement {
+	statements := make([]Statement, len(l.Statements))
+	copy(statements, l.Statements)
+	sort.SliceStable(statements, func(a, b int) bool {
+		return statements[a].ProcessedAt.After(statements[b].ProcessedAt)
+	})
+	return statements
+}
+
+// Save writes the ledger atomically to its file.
+func (l *Ledger) Save() error {
+	if err := storage.SaveJSON(l.path, fileFormat{Version: ledgerVersion, Statements: l.Statements}); err != nil {
+		return fmt.Errorf("saving ledger: %w", err)
+	}
+	return nil
+}
+
+// HasExact reports whether any conflict is an exact re-processing.
+func HasExact(conflicts []Conflict) bool {
+	for _, c := range conflicts {
+		if c.Kind == ConflictExact {
+			return true
+		}
+	}
+	return false
+}
+
+// rangesOverlap reports whether two inclusive ISO date ranges intersect.
+// Empty ranges never overlap.
+func rangesOverlap(fromA, toA, fromB, toB string) bool {
+	if fromA == "" || toA == "" || fromB == "" || toB == "" {
+		return false
+	}
+	return fromA <= toB && fromB <= toA
+}
@@ -0,0 +1,160 @@
+package ledger
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/stretchr/testify/suite"
+)
+
+// LedgerTestSuite groups all ledger tests.
+type LedgerTestSuite struct {
+	suite.Suite
+	ledger *Ledger
+}
+
+func TestLedgerTestSuite(t *testing.T) {
+	suite.Run(t, new(LedgerTestSuite))
+}
+
+func (s *LedgerTestSuite) SetupTest() {
+	l, err := Open(filepath.Join(s.T().TempDir(), DefaultFileName))
+	s.Require().NoError(err)
+	s.ledger = l
+}
+
+func januaryStatement() Statement {
+	return Statement{
+		Hash:      "hash-jan",
+		Source:    "milesmore",
+		FromDate:  "2026-01-03",
+		ToDate:    "2026-02-02",
+		Action:    ActionImport,
+		AccountID: "acc-1",
+	}
+}
+
+func (s *LedgerTestSuite) TestNewStatement_WithTransactions_DerivesDateRange() {
+	// Arrange
+	transactions := []domain.Transaction{
+		{Date: time.Date(2026, 1, 29, 0, 0, 0, 0, time.UTC)},
+		{Date: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{Date: time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	// Act
+	stmt := NewStatement("milesmore", "statement.csv", "abc", time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC), transactions)
+
+	// Assert
+	s.Equal("2026-01-03", stmt.FromDate)
+	s.Equal("2026-02-02", stmt.ToDate)
+	s.Equal("2026-02-03", stmt.BillingDate)
+	s.Equal(3, stmt.TransactionCount)
+}
+
+func (s *LedgerTestSuite) TestCheck_WithSameHash_ReturnsExactConflict() {
+	// Arrange
+	s.ledger.Record(januaryStatement(), time.Now())
+
+	// Act
+	conflicts := s.ledger.Check(januaryStatement())
+
+	// Assert
+	s.Len(conflicts, 1)
+	s.Equal(ConflictExact, conflicts[0].Kind)
+	s.True(HasExact(conflicts))
+}
+
+func (s *LedgerTestSuite) TestCheck_WithOverlappingRange_ReturnsOverlapConflict() {
+	// Arrange
+	s.ledger.Record(januaryStatement(), time.Now())
+	next := januaryStatement()
+	next.Hash = "hash-feb"
+	next.FromDate = "2026-01-30"
+	next.ToDate = "2026-03-02"
+
+	// Act
+	conflicts := s.ledger.Check(next)
+
+	// Assert
+	s.Len(conflicts, 1)
+	s.Equal(ConflictOverlap, conflicts[0].Kind)
+	s.False(HasExact(conflicts))
+}
+
+func (s *LedgerTestSuite) TestCheck_WithAdjacentRange_ReturnsNoConflict() {
+	// Arrange
+	s.ledger.Record(januaryStatement(), time.Now())
+	next := januaryStatement()
+	next.Hash = "hash-feb"
+	next.FromDate = "2026-02-03"
+	next.ToDate = "2026-03-02"
+
+	// Act
+	conflicts := s.ledger.Check(next)
+
+	// Assert
+	s.Empty(conflicts)
+}
+
+func (s *LedgerTestSuite) TestCheck_WithOtherAccountOrAction_IgnoresOverlap() {
+	// Arrange
+	s.ledger.Record(januaryStatement(), time.Now())
+	otherAccount := januaryStatement()
+	otherAccount.Hash = "other"
+	otherAccount.AccountID = "acc-2"
+	transform := januaryStatement()
+	transform.Action = ActionTransform
+	transform.AccountID = ""
+
+	// Act
+	accountConflicts := s.ledger.Check(otherAccount)
+	transformConflicts := s.ledger.Check(transform)
+
+	// Assert
+	s.Empty(accountConflicts)
+	s.Empty(transformConflicts)
+}
+
+func (s *LedgerTestSuite) TestRemoveRun_WithRecordedRun_AllowsReimport() {
+	// Arrange
+	stmt := januaryStatement()
+	stmt.RunID = "run-1"
+	s.ledger.Record(stmt, time.Now())
+
+	// Act
+	removed := s.ledger.RemoveRun("run-1")
+
+	// Assert
+	s.True(removed)
+	s.Empty(s.ledger.Check(januaryStatement()))
+}
+
+func (s *LedgerTestSuite) TestSave_ThenOpen_RoundTripsStatements() {
+	// Arrange
+	s.ledger.Record(januaryStatement(), time.Date(2026, 2, 4, 0, 0, 0, 0, time.UTC))
+
+	// Act
+	err := s.ledger.Save()
+	reopened, openErr := Open(s.ledger.path)
+
+	// Assert
+	s.NoError(err)
+	s.NoError(openErr)
+	s.Len(reopened.List(), 1)
+	s.Equal("hash-jan", reopened.List()[0].Hash)
+}
+
+func (s *LedgerTestSuite) TestOpenDir_UsesDefaultFileName() {
+	// Arrange
+	dir := s.T().TempDir()
+
+	// Act
+	l, err := OpenDir(dir)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(filepath.Join(dir, DefaultFileName), l.path)
+}
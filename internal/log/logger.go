@@ -118,7 +118,8 @@ func getConfig(encoding string, minLogLevel zapcore.Level) zap.Config {
 	loggerConfig.EncoderConfig.EncodeCaller = zapcore.ShortCallerEncoder
 	loggerConfig.EncoderConfig.NameKey = "log-name"
 	loggerConfig.EncoderConfig.EncodeName = zapcore.FullNameEncoder
-	loggerConfig.OutputPaths = []string{"stdout"}
+	// Logs go to stderr so command results on stdout can be piped
+	loggerConfig.OutputPaths = []string{"stderr"}
 	loggerConfig.Encoding = "json"
 	if encoding == "console" {
 		loggerConfig.Encoding = "console"
@@ -0,0 +1,330 @@
+// Package matcher compares parsed statement transactions with transactions
+// already present in YNAB, so that rows imported by other means (e.g. YNAB
+// direct import) are not created twice.
+package matcher
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/domain"
+)
+
+const (
+	// DefaultDateWindow is the default tolerance in days between statement and YNAB dates.
+	DefaultDateWindow = 3
+
+	// DefaultMinPayeeSimilarity is the default payee similarity required for a confident match.
+	DefaultMinPayeeSimilarity = 0.3
+
+	// DefaultAmbiguityMargin is the default score difference below which two candidates are
+	// considered equally plausible.
+	DefaultAmbiguityMargin = 0.1
+
+	// isoDate is the date format used by the YNAB API.
+	isoDate = "2006-01-02"
+)
+
+// Status classifies a statement row after matching.
+type Status string
+
+const (
+	// StatusNew indicates no existing YNAB transaction corresponds to the row.
+	StatusNew Status = "new"
+	// StatusMatched indicates exactly one plausible YNAB transaction corresponds to the row.
+	StatusMatched Status = "matched"
+	// StatusAmbiguous indicates candidates exist but none is clearly the right one.
+	StatusAmbiguous Status = "ambiguous"
+)
+
+// Options configures the matcher. Zero values are replaced by defaults.
+type Options struct {
+	// DateWindow is the maximum number of days between statement and YNAB dates.
+	DateWindow int
+	// MinPayeeSimilarity is the payee similarity (0..1) required for a confident match.
+	MinPayeeSimilarity float64
+	// AmbiguityMargin is the score difference below which candidates are equally plausible.
+	AmbiguityMargin float64
+}
+
+// withDefaults returns a copy of the options with zero values replaced by defaults.
+func (o Options) withDefaults() Options {
+	if o.DateWindow <= 0 {
+		o.DateWindow = DefaultDateWindow
+	}
+	if o.MinPayeeSimilarity <= 0 {
+		o.MinPayeeSimilarity = DefaultMinPayeeSimilarity
+	}
+	if o.AmbiguityMargin <= 0 {
+		o.AmbiguityMargin = DefaultAmbiguityMargin
+	}
+	return o
+}
+
+// Candidate is an existing YNAB transaction that could correspond to a statement row.
+type Candidate struct {
+	Transaction ynab.Transaction `json:"transaction"`
+	// DayDelta is the absolute difference in days between the two dates.
+	DayDelta int `json:"day_delta"`
+	// PayeeSimilarity is the similarity (0..1) between the two payee names.
+	PayeeSimilarity float64 `json:"payee_similarity"`
+	// Score combines payee similarity and date distance; higher is better.
+	Score float64 `json:"score"`
+}
+
+// Result is the classification of a single statement row.
+type Result struct {
+	Transaction domain.Transaction `json:"transaction"`
+	Status      Status             `json:"status"`
+	// Match is the YNAB transaction the row was matched to (nil for new rows).
+	Match *Candidate `json:"match,omitempty"`
+	// Candidates lists every plausible YNAB transaction, best first.
+	Candidates []Candidate `json:"candidates,omitempty"`
+}
+
+// Summary counts results by status.
+type Summary struct {
+	New       int
+	Matched   int
+	Ambiguous int
+}
+
+// Summarize counts the results by status.
+func Summarize(results []Result) Summary {
+	var s Summary
+	for _, r := range results {
+		switch r.Status {
+		case StatusNew:
+			s.New++
+		case StatusMatched:
+			s.Matched++
+		case StatusAmbiguous:
+			s.Ambiguous++
+		}
+	}
+	return s
+}
+
+// FilterByStatus returns the statement transactions whose result has one of the given statuses.
+func FilterByStatus(results []Result, statuses ...Status) []domain.Transaction {
+	var transactions []domain.Transaction
+	for _, r := range results {
+		for _, s := range statuses {
+			if r.Status == s {
+				transactions = append(transactions, r.Transaction)
+				break
+			}
+		}
+	}
+	return transactions
+}
+
+// pair links a statement row to a candidate during assignment.
+type pair struct {
+	row       int
+	candidate Candidate
+}
+
+// Match classifies every statement row as new, matched or ambiguous against
+// the existing YNAB transactions. Rows are matched by identical import ID
+// first; remaining rows require an identical amount and a date within the
+// window. Each YNAB transaction is matched to at most one row, best score first.
+func Match(statement []domain.Transaction, existing []ynab.Transaction, opts Options) []Result {
+	opts = opts.withDefaults()
+
+	results := make([]Result, len(statement))
+	claimed := make(map[string]bool)
+
+	// Pass 1: identical import IDs are certain matches
+	byImportID := make(map[string]ynab.Transaction)
+	for _, t := range existing {
+		if !t.Deleted && t.ImportID != "" {
+			byImportID[t.ImportID] = t
+		}
+	}
+	for i, tx := range statement {
+		results[i] = Result{Transaction: tx, Status: StatusNew}
+		if t, ok := byImportID[tx.ImportID]; ok && tx.ImportID != "" && !claimed[t.ID] {
+			c := Candidate{Transaction: t, PayeeSimilarity: PayeeSimilarity(tx.Payee, t.PayeeName), Score: 2}
+			results[i].Status = StatusMatched
+			results[i].Match = &c
+			results[i].Candidates = []Candidate{c}
+			claimed[t.ID] = true
+		}
+	}
+
+	// Pass 2: collect amount and date window candidates
+	var pairs []pair
+	for i, tx := range statement {
+		if results[i].Status == StatusMatched {
+			continue
+		}
+		for _, t := range existing {
+			if t.Deleted || claimed[t.ID] {
+				continue
+			}
+			c, ok := scoreCandidate(tx, t, opts)
+			if !ok {
+				continue
+			}
+			results[i].Candidates = append(results[i].Candidates, c)
+			pairs = append(pairs, pair{row: i, candidate: c})
+		}
+		sortCandidates(results[i].Candidates)
+	}
+
+	// Pass 3: greedy assignment, best score first
+	sort.SliceStable(pairs, func(a, b int) bool {
+		return pairs[a].candidate.Score > pairs[b].candidate.Score
+	})
+	for _, p := range pairs {
+		if results[p.row].Match != nil || claimed[p.candidate.Transaction.ID] {
+			continue
+		}
+		c := p.candidate
+		results[p.row].Match = &c
+		claimed[c.Transaction.ID] = true
+	}
+
+	// Pass 4: classify assigned rows
+	for i := range results {
+		r := &results[i]
+		if r.Status == StatusMatched {
+			continue
+		}
+		if len(r.Candidates) == 0 {
+			continue
+		}
+		if r.Match == nil {
+			// Every candidate was taken by a better scoring row
+			r.Status = StatusAmbiguous
+			continue
+		}
+
+		r.Status = StatusMatched
+		if r.Match.PayeeSimilarity < opts.MinPayeeSimilarity {
+			r.Status = StatusAmbiguous
+			continue
+		}
+		for _, c := range r.Candidates {
+			if c.Transaction.ID == r.Match.Transaction.ID || claimed[c.Transaction.ID] {
+				continue
+			}
+			// An unclaimed alternative is just as plausible
+			if r.Match.Score-c.Score < opts.AmbiguityMargin {
+				r.Status = StatusAmbiguous
+				break
+			}
+		}
+	}
+
+	return results
+}
+
+// scoreCandidate checks whether t can correspond to tx and scores the pair.
+func scoreCandidate(tx domain.Transaction, t ynab.Transaction, opts Options) (Candidate, bool) {
+	if ynab.FloatToMilliunits(tx.Amount) != t.Amount {
+		return Candidate{}, false
+	}
+
+	date, err := time.Parse(isoDate, t.Date)
+	if err != nil {
+		return Candidate{}, false
+	}
+
+	delta := domain.DayDelta(tx.Date, date)
+	if delta > opts.DateWindow {
+		return Candidate{}, false
+	}
+
+	similarity := PayeeSimilarity(tx.Payee, t.PayeeName)
+	if t.ImportPayeeOriginal != "" {
+		if original := PayeeSimilarity(tx.Payee, t.ImportPayeeOriginal); original > similarity {
+			similarity = original
+		}
+	}
+
+	// Date closeness contributes up to half as much as payee similarity
+	closeness := 1 - float64(delta)/float64(opts.DateWindow+1)
+
+	return Candidate{
+		Transaction:     t,
+		DayDelta:        delta,
+		PayeeSimilarity: similarity,
+		Score:           similarity + closeness/2,
+	}, true
+}
+
+// sortCandidates orders candidates by descending score.
+func sortCandidates(candidates []Candidate) {
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return candidates[a].Score > candidates[b].Score
+	})
+}
+
+// PayeeSimilarity returns the Dice coefficient (0..1) of the character bigrams
+// of two payee names after lower-casing and removing punctuation.
+func PayeeSimilarity(a, b string) float64 {
+	a, b = normalize(a), normalize(b)
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	bigramsA := bigrams(a)
+	bigramsB := bigrams(b)
+	if len(bigramsA) == 0 || len(bigramsB) == 0 {
+		return 0
+	}
+
+	counts := make(map[string]int, len(bigramsA))
+	for _, bg := range bigramsA {
+		counts[bg]++
+	}
+
+	shared := 0
+	for _, bg := range bigramsB {
+		if counts[bg] > 0 {
+			counts[bg]--
+			shared++
+		}
+	}
+
+	return 2 * float64(shared) / float64(len(bigramsA)+len(bigramsB))
+}
+
+// normalize lower-cases s and collapses everything but letters and digits to single spaces.
+func normalize(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			space = false
+			continue
+		}
+		if !space && b.Len() > 0 {
+			b.WriteRune(' ')
+			space = true
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// bigrams returns the adjacent rune pairs of s.
+func bigrams(s string) []string {
+	runes := []rune(s)
+	if len(runes) < 2 {
+		return nil
+	}
+	out := make([]string, 0, len(runes)-1)
+	for i := 0; i < len(runes)-1; i++ {
+		out = append(out, string(runes[i:i+2]))
+	}
+	return out
+}
@@ -0,0 +1,221 @@
+package matcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// MatcherTestSuite groups all matcher tests.
+type MatcherTestSuite struct {
+	suite.Suite
+}
+
+func TestMatcherTestSuite(t *testing.T) {
+	suite.Run(t, new(MatcherTestSuite))
+}
+
+func date(day int) time.Time {
+	return time.Date(2026, 1, day, 0, 0, 0, 0, time.UTC)
+}
+
+func (s *MatcherTestSuite) TestMatch_WithoutExistingTransactions_ReturnsAllNew() {
+	// Arrange
+	statement := []domain.Transaction{
+		{Date: date(10), Payee: "REWE Markt", Amount: -25.30},
+	}
+
+	// Act
+	results := Match(statement, nil, Options{})
+
+	// Assert
+	s.Len(results, 1)
+	s.Equal(StatusNew, results[0].Status)
+	s.Nil(results[0].Match)
+}
+
+func (s *MatcherTestSuite) TestMatch_WithSameImportID_MatchesRegardlessOfDate() {
+	// Arrange
+	statement := []domain.Transaction{
+		{Date: date(10), Payee: "REWE", Amount: -25.30, ImportID: "YNAB:-25300:2026-01-10:1"},
+	}
+	existing := []ynab.Transaction{
+		{ID: "tx-1", Date: "2026-01-25", Amount: -25300, PayeeName: "Groceries", ImportID: "YNAB:-25300:2026-01-10:1"},
+	}
+
+	// Act
+	results := Match(statement, existing, Options{})
+
+	// Assert
+	s.Equal(StatusMatched, results[0].Status)
+	s.Equal("tx-1", results[0].Match.Transaction.ID)
+}
+
+func (s *MatcherTestSuite) TestMatch_WithAmountAndDateInWindow_MatchesSimilarPayee() {
+	// Arrange
+	statement := []domain.Transaction{
+		{Date: date(10), Payee: "REWE Markt GmbH Berlin", Amount: -25.30},
+	}
+	existing := []ynab.Transaction{
+		{ID: "tx-1", Date: "2026-01-12", Amount: -25300, PayeeName: "REWE Markt"},
+	}
+
+	// Act
+	results := Match(statement, existing, Options{})
+
+	// Assert
+	s.Equal(StatusMatched, results[0].Status)
+	s.Equal(2, results[0].Match.DayDelta)
+}
+
+func (s *MatcherTestSuite) TestMatch_WithDateOutsideWindow_ReturnsNew() {
+	// Arrange
+	statement := []domain.Transaction{
+		{Date: date(10), Payee: "REWE", Amount: -25.30},
+	}
+	existing := []ynab.Transaction{
+		{ID: "tx-1", Date: "2026-01-20", Amount: -25300, PayeeName: "REWE"},
+	}
+
+	// Act
+	results := Match(statement, existing, Options{DateWindow: 3})
+
+	// Assert
+	s.Equal(StatusNew, results[0].Status)
+}
+
+func (s *MatcherTestSuite) TestMatch_WithDifferentAmount_ReturnsNew() {
+	// Arrange
+	statement := []domain.Transaction{
+		{Date: date(10), Payee: "REWE", Amount: -25.30},
+	}
+	existing := []ynab.Transaction{
+		{ID: "tx-1", Date: "2026-01-10", Amount: -25310, PayeeName: "REWE"},
+	}
+
+	// Act
+	results := Match(statement, existing, Options{})
+
+	// Assert
+	s.Equal(StatusNew, results[0].Status)
+}
+
+func (s *MatcherTestSuite) TestMatch_WithUnrelatedPayee_ReturnsAmbiguous() {
+	// Arrange
+	statement := []domain.Transaction{
+		{Date: date(10), Payee: "REWE", Amount: -10.00},
+	}
+	existing := []ynab.Transaction{
+		{ID: "tx-1", Date: "2026-01-10", Amount: -10000, PayeeName: "Shell Tankstelle"},
+	}
+
+	// Act
+	results := Match(statement, existing, Options{})
+
+	// Assert
+	s.Equal(StatusAmbiguous, results[0].Status)
+	s.Len(results[0].Candidates, 1)
+}
+
+func (s *MatcherTestSuite) TestMatch_WithTwoEquallyPlausibleCandidates_ReturnsAmbiguous() {
+	// Arrange
+	statement := []domain.Transaction{
+		{Date: date(10), Payee: "Coffee Shop", Amount: -3.50},
+	}
+	existing := []ynab.Transaction{
+		{ID: "tx-1", Date: "2026-01-10", Amount: -3500, PayeeName: "Coffee Shop"},
+		{ID: "tx-2", Date: "2026-01-10", Amount: -3500, PayeeName: "Coffee Shop"},
+	}
+
+	// Act
+	results := Match(statement, existing, Options{})
+
+	// Assert
+	s.Equal(StatusAmbiguous, results[0].Status)
+	s.Len(results[0].Candidates, 2)
+}
+
+func (s *MatcherTestSuite) TestMatch_WithIdenticalTwinRows_MatchesEachOnce() {
+	// Arrange
+	statement := []domain.Transaction{
+		{Date: date(10), Payee: "Coffee Shop", Amount: -3.50},
+		{Date: date(10), Payee: "Coffee Shop", Amount: -3.50},
+	}
+	existing := []ynab.Transaction{
+		{ID: "tx-1", Date: "2026-01-10", Amount: -3500, PayeeName: "Coffee Shop"},
+		{ID: "tx-2", Date: "2026-01-10", Amount: -3500, PayeeName: "Coffee Shop"},
+	}
+
+	// Act
+	results := Match(statement, existing, Options{})
+
+	// Assert
+	s.Equal(StatusMatched, results[0].Status)
+	s.Equal(StatusMatched, results[1].Status)
+	s.NotEqual(results[0].Match.Transaction.ID, results[1].Match.Transaction.ID)
+}
+
+func (s *MatcherTestSuite) TestMatch_WithDeletedExisting_IgnoresIt() {
+	// Arrange
+	statement := []domain.Transaction{
+		{Date: date(10), Payee: "REWE", Amount: -25.30},
+	}
+	existing := []ynab.Transaction{
+		{ID: "tx-1", Date: "2026-01-10", Amount: -25300, PayeeName: "REWE", Deleted: true},
+	}
+
+	// Act
+	results := Match(statement, existing, Options{})
+
+	// Assert
+	s.Equal(StatusNew, results[0].Status)
+}
+
+func (s *MatcherTestSuite) TestSummarizeAndFilter_WithMixedResults_CountsByStatus() {
+	// Arrange
+	results := []Result{
+		{Status: StatusNew, Transaction: domain.Transaction{Payee: "a"}},
+		{Status: StatusMatched, Transaction: domain.Transaction{Payee: "b"}},
+		{Status: StatusAmbiguous, Transaction: domain.Transaction{Payee: "c"}},
+		{Status: StatusNew, Transaction: domain.Transaction{Payee: "d"}},
+	}
+
+	// Act
+	summary := Summarize(results)
+	newRows := FilterByStatus(results, StatusNew)
+
+	// Assert
+	s.Equal(Summary{New: 2, Matched: 1, Ambiguous: 1}, summary)
+	s.Len(newRows, 2)
+	s.Equal("d", newRows[1].Payee)
+}
+
+func TestPayeeSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		min  float64
+		max  float64
+	}{
+		{name: "identical after normalization", a: "REWE Markt", b: "rewe-markt", min: 1, max: 1},
+		{name: "prefix", a: "REWE Markt GmbH", b: "REWE", min: 0.3, max: 0.8},
+		{name: "unrelated", a: "REWE", b: "Shell", min: 0, max: 0.1},
+		{name: "empty", a: "", b: "REWE", min: 0, max: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			result := PayeeSimilarity(tt.a, tt.b)
+
+			// Assert
+			assert.GreaterOrEqual(t, result, tt.min)
+			assert.LessOrEqual(t, result, tt.max)
+		})
+	}
+}
@@ -0,0 +1,265 @@
+// Package oauth implements the OAuth 2.0 authorization code flow used by YNAB
+// apps: a loopback redirect listener for the login, the code exchange and the
+// refresh of expired access tokens.
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAuthURL is the YNAB authorization endpoint.
+	DefaultAuthURL = "https://app.ynab.com/oauth/authorize"
+
+	// DefaultTokenURL is the YNAB token endpoint.
+	DefaultTokenURL = "https://app.ynab.com/oauth/token"
+
+	// CallbackPath is the path of the loopback redirect URI.
+	CallbackPath = "/oauth/callback"
+
+	// DefaultLoginTimeout is how long Login waits for the browser redirect.
+	DefaultLoginTimeout = 5 * time.Minute
+)
+
+// ErrInvalidGrant is returned when the token endpoint rejects a code or a
+// refresh token, e.g. because access was revoked.
+var ErrInvalidGrant = errors.New("authorization was rejected or revoked")
+
+// Config describes an OAuth app registered with YNAB.
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	// AuthURL and TokenURL override the YNAB endpoints (optional, for testing).
+	AuthURL  string
+	TokenURL string
+	// RedirectPort is the loopback port of the redirect URI registered for the
+	// app. Zero listens on any free port.
+	RedirectPort int
+	// HTTPClient is used for token requests (default: http.DefaultClient).
+	HTTPClient *http.Client
+}
+
+// Token is an access token with the refresh token that renews it.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	TokenType    string    `json:"token_type,omitempty"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// Expired reports whether the access token expires before now plus margin.
+// Tokens without an expiry never expire.
+func (t *Token) Expired(now time.Time, margin time.Duration) bool {
+	return !t.Expiry.IsZero() && now.Add(margin).After(t.Expiry)
+}
+
+// tokenResponse is the body returned by the token endpoint.
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (c Config) authURL() string {
+	if c.AuthURL != "" {
+		return c.AuthURL
+	}
+	return DefaultAuthURL
+}
+
+func (c Config) tokenURL() string {
+	if c.TokenURL != "" {
+		return c.TokenURL
+	}
+	return DefaultTokenURL
+}
+
+func (c Config) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// AuthCodeURL returns the URL the user opens to authorize the app.
+func (c Config) AuthCodeURL(redirectURL, state string) string {
+	q := url.Values{
+		"client_id":     {c.ClientID},
+		"redirect_uri":  {redirectURL},
+		"response_type": {"code"},
+		"state":         {state},
+	}
+	return c.authURL() + "?" + q.Encode()
+}
+
+// Exchange trades an authorization code for a token.
+func (c Config) Exchange(ctx context.Context, code, redirectURL string) (*Token, error) {
+	return c.requestToken(ctx, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {redirectURL},
+	})
+}
+
+// Refresh obtains a new token with a refresh token.
+func (c Config) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	if refreshToken == "" {
+		return nil, fmt.Errorf("no refresh token: %w", ErrInvalidGrant)
+	}
+	return c.requestToken(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+}
+
+// requestToken posts a grant to the token endpoint.
+func (c Config) requestToken(ctx context.Context, form url.Values) (*Token, error) {
+	form.Set("client_id", c.ClientID)
+	form.Set("client_secret", c.ClientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("creating token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading token response: %w", err)
+	}
+
+	var tr tokenResponse
+	if err := json.Unmarshal(body, &tr); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("parsing token response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || tr.Error != "" {
+		detail := tr.Error
+		if tr.ErrorDescription != "" {
+			detail += ": " + tr.ErrorDescription
+		}
+		if detail == "" {
+			detail = resp.Status
+		}
+		if tr.Error == "invalid_grant" || resp.StatusCode == http.StatusUnauthorized {
+			return nil, fmt.Errorf("token endpoint: %s: %w", detail, ErrInvalidGrant)
+		}
+		return nil, fmt.Errorf("token endpoint: %s", detail)
+	}
+	if tr.AccessToken == "" {
+		return nil, fmt.Errorf("token endpoint returned no access token")
+	}
+
+	token := &Token{AccessToken: tr.AccessToken, RefreshToken: tr.RefreshToken, TokenType: tr.TokenType}
+	if tr.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// callbackResult is the outcome of the browser redirect.
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// Login runs the authorization code flow. It listens on the loopback
+// redirect URI, calls open with the authorization URL (e.g. to start a
+// browser), waits for the redirect and exchanges the code.
+func Login(ctx context.Context, cfg Config, open func(authURL string) error) (*Token, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", cfg.RedirectPort))
+	if err != nil {
+		return nil, fmt.Errorf("listening for the redirect: %w", err)
+	}
+	defer listener.Close()
+
+	redirectURL := fmt.Sprintf("http://%s%s", listener.Addr().String(), CallbackPath)
+	state, err := randomState()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(chan callbackResult, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(CallbackPath, func(w http.ResponseWriter, r *http.Request) {
+		result := parseCallback(r.URL.Query(), state)
+		if result.err != nil {
+			http.Error(w, "Login failed: "+result.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "MoneyPenny is authorized. You can close this window.")
+		}
+		select {
+		case results <- result:
+		default:
+		}
+	})
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = server.Serve(listener) }()
+	defer server.Close()
+
+	if err := open(cfg.AuthCodeURL(redirectURL, state)); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultLoginTimeout)
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for the authorization redirect: %w", ctx.Err())
+	case result := <-results:
+		if result.err != nil {
+			return nil, result.err
+		}
+		return cfg.Exchange(ctx, result.code, redirectURL)
+	}
+}
+
+// parseCallback checks the state and extracts the code from the redirect query.
+func parseCallback(q url.Values, state string) callbackResult {
+	if q.Get("state") != state {
+		return callbackResult{err: fmt.Errorf("state mismatch in the authorization redirect")}
+	}
+	if e := q.Get("error"); e != "" {
+		if d := q.Get("error_description"); d != "" {
+			e += ": " + d
+		}
+		return callbackResult{err: fmt.Errorf("authorization denied: %s", e)}
+	}
+	code := q.Get("code")
+	if code == "" {
+		return callbackResult{err: fmt.Errorf("no code in the authorization redirect")}
+	}
+	return callbackResult{code: code}
+}
+
+// randomState returns an unguessable value binding the redirect to this login.
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating state: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
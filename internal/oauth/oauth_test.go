@@ -0,0 +1,241 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeAuthServer is a local authorization server. It authorizes every login
+// with a fixed code and rotates tokens on each refresh.
+type fakeAuthServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	issued    int
+	forms     []url.Values
+	revoked   bool
+	expiresIn int64
+}
+
+func newFakeAuthServer() *fakeAuthServer {
+	f := &fakeAuthServer{expiresIn: 7200}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/authorize", f.authorize)
+	mux.HandleFunc("/oauth/token", f.token)
+	f.Server = httptest.NewServer(mux)
+	return f
+}
+
+// authorize redirects back to the client like a user granting access.
+func (f *fakeAuthServer) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirect := q.Get("redirect_uri") + "?" + url.Values{"code": {"the-code"}, "state": {q.Get("state")}}.Encode()
+	http.Redirect(w, r, redirect, http.StatusFound)
+}
+
+func (f *fakeAuthServer) token(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_ = r.ParseForm()
+	f.forms = append(f.forms, r.PostForm)
+	w.Header().Set("Content-Type", "application/json")
+
+	valid := r.PostForm.Get("client_id") == "client" && r.PostForm.Get("client_secret") == "secret"
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		valid = valid && r.PostForm.Get("code") == "the-code"
+	case "refresh_token":
+		valid = valid && !f.revoked && r.PostForm.Get("refresh_token") != ""
+	default:
+		valid = false
+	}
+	if !valid {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant", "error_description": "The grant is invalid"})
+		return
+	}
+
+	f.issued++
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"access_token":  fmt.Sprintf("access-%d", f.issued),
+		"refresh_token": fmt.Sprintf("refresh-%d", f.issued),
+		"token_type":    "Bearer",
+		"expires_in":    f.expiresIn,
+	})
+}
+
+func (f *fakeAuthServer) config() Config {
+	return Config{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		AuthURL:      f.URL + "/oauth/authorize",
+		TokenURL:     f.URL + "/oauth/token",
+	}
+}
+
+// memoryStore keeps tokens in memory.
+type memoryStore struct {
+	token *Token
+	saves int
+}
+
+func (m *memoryStore) Load() (*Token, error) {
+	return m.token, nil
+}
+
+func (m *memoryStore) Save(token *Token) error {
+	m.token = token
+	m.saves++
+	return nil
+}
+
+// OAuthTestSuite groups authorization code flow tests.
+type OAuthTestSuite struct {
+	suite.Suite
+	server *fakeAuthServer
+}
+
+func TestOAuthTestSuite(t *testing.T) {
+	suite.Run(t, new(OAuthTestSuite))
+}
+
+func (s *OAuthTestSuite) SetupTest() {
+	s.server = newFakeAuthServer()
+}
+
+func (s *OAuthTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *OAuthTestSuite) TestLogin_FollowsRedirectAndExchangesCode() {
+	// Arrange
+	browser := func(authURL string) error {
+		// The browser follows the redirect to the loopback listener
+		go func() {
+			resp, err := http.Get(authURL)
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+		return nil
+	}
+
+	// Act
+	token, err := Login(context.Background(), s.server.config(), browser)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal("access-1", token.AccessToken)
+	s.Equal("refresh-1", token.RefreshToken)
+	s.WithinDuration(time.Now().Add(2*time.Hour), token.Expiry, time.Minute)
+	s.Require().Len(s.server.forms, 1)
+	s.Contains(s.server.forms[0].Get("redirect_uri"), "http://127.0.0.1:")
+}
+
+func (s *OAuthTestSuite) TestLogin_WithWrongState_Fails() {
+	// Arrange
+	browser := func(authURL string) error {
+		u, err := url.Parse(authURL)
+		s.Require().NoError(err)
+		redirect := u.Query().Get("redirect_uri") + "?code=the-code&state=forged"
+		go func() {
+			resp, err := http.Get(redirect)
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+		return nil
+	}
+
+	// Act
+	token, err := Login(context.Background(), s.server.config(), browser)
+
+	// Assert
+	s.ErrorContains(err, "state mismatch")
+	s.Nil(token)
+	s.Empty(s.server.forms)
+}
+
+func (s *OAuthTestSuite) TestLogin_WithCancelledContext_Fails() {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	browser := func(string) error {
+		cancel()
+		return nil
+	}
+
+	// Act
+	_, err := Login(ctx, s.server.config(), browser)
+
+	// Assert
+	s.ErrorIs(err, context.Canceled)
+}
+
+func (s *OAuthTestSuite) TestRefresh_WithRevokedGrant_ReturnsErrInvalidGrant() {
+	// Arrange
+	s.server.revoked = true
+
+	// Act
+	_, err := s.server.config().Refresh(context.Background(), "refresh-1")
+
+	// Assert
+	s.ErrorIs(err, ErrInvalidGrant)
+	s.ErrorContains(err, "The grant is invalid")
+}
+
+func (s *OAuthTestSuite) TestSourceToken_WithExpiredToken_RefreshesAndSaves() {
+	// Arrange
+	store := &memoryStore{token: &Token{AccessToken: "stale", RefreshToken: "refresh-0", Expiry: time.Now().Add(-time.Hour)}}
+	source := NewSource(s.server.config(), store)
+
+	// Act
+	token, err := source.Token()
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal("access-1", token)
+	s.Equal(1, store.saves)
+	s.Equal("refresh-1", store.token.RefreshToken)
+}
+
+func (s *OAuthTestSuite) TestSourceToken_WithValidToken_DoesNotRefresh() {
+	// Arrange
+	store := &memoryStore{token: &Token{AccessToken: "current", RefreshToken: "refresh-0", Expiry: time.Now().Add(time.Hour)}}
+	source := NewSource(s.server.config(), store)
+
+	// Act
+	token, err := source.Token()
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal("current", token)
+	s.Empty(s.server.forms)
+}
+
+func (s *OAuthTestSuite) TestSourceRefresh_KeepsRefreshTokenWhenNotRotated() {
+	// Arrange
+	s.server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "access-new", "expires_in": 60})
+	})
+	store := &memoryStore{token: &Token{AccessToken: "current", RefreshToken: "refresh-0"}}
+	source := NewSource(s.server.config(), store)
+
+	// Act
+	token, err := source.Refresh()
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal("access-new", token)
+	s.Equal("refresh-0", store.token.RefreshToken)
+}
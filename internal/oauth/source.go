@@ -0,0 +1,91 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// expiryMargin refreshes access tokens shortly before they expire.
+const expiryMargin = time.Minute
+
+// Store persists tokens between runs, e.g. in the secret vault.
+type Store interface {
+	Load() (*Token, error)
+	Save(token *Token) error
+}
+
+// Source supplies access tokens from a Store and refreshes them when they
+// expire or are rejected. Refreshed tokens are saved back to the store.
+type Source struct {
+	cfg   Config
+	store Store
+	now   func() time.Time
+
+	mu    sync.Mutex
+	token *Token
+}
+
+// NewSource creates a Source for the app cfg with tokens kept in store.
+func NewSource(cfg Config, store Store) *Source {
+	return &Source{cfg: cfg, store: store, now: time.Now}
+}
+
+// Token returns a valid access token, refreshing an expired one first.
+func (s *Source) Token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == nil {
+		token, err := s.store.Load()
+		if err != nil {
+			return "", err
+		}
+		s.token = token
+	}
+
+	if s.token.Expired(s.now(), expiryMargin) {
+		if err := s.refresh(); err != nil {
+			return "", err
+		}
+	}
+	return s.token.AccessToken, nil
+}
+
+// Refresh obtains a new access token, e.g. after the API rejected the current one.
+func (s *Source) Refresh() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == nil {
+		token, err := s.store.Load()
+		if err != nil {
+			return "", err
+		}
+		s.token = token
+	}
+
+	if err := s.refresh(); err != nil {
+		return "", err
+	}
+	return s.token.AccessToken, nil
+}
+
+// refresh renews s.token and saves it. The caller holds s.mu.
+func (s *Source) refresh() error {
+	token, err := s.cfg.Refresh(context.Background(), s.token.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("refreshing access token: %w", err)
+	}
+	// Some servers only issue a new refresh token when the old one is rotated
+	if token.RefreshToken == "" {
+		token.RefreshToken = s.token.RefreshToken
+	}
+
+	if err := s.store.Save(token); err != nil {
+		return fmt.Errorf("saving refreshed token: %w", err)
+	}
+	s.token = token
+	return nil
+}
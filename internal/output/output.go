@@ -0,0 +1,211 @@
+// Package output renders command results as a table, JSON, NDJSON, CSV or
+// YAML. Results go to stdout so they can be piped into other tools; logs and
+// prompts go to stderr.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is an output format.
+type Format string
+
+const (
+	// FormatTable renders an aligned, human-readable table.
+	FormatTable Format = "table"
+	// FormatJSON renders a JSON array.
+	FormatJSON Format = "json"
+	// FormatNDJSON renders one JSON object per line.
+	FormatNDJSON Format = "ndjson"
+	// FormatCSV renders comma-separated values with a header row.
+	FormatCSV Format = "csv"
+	// FormatYAML renders a YAML sequence.
+	FormatYAML Format = "yaml"
+)
+
+// Formats lists every supported format.
+var Formats = []Format{FormatTable, FormatJSON, FormatNDJSON, FormatCSV, FormatYAML}
+
+// Stdout is where Print writes results.
+var Stdout io.Writer = os.Stdout
+
+// Stderr is where Preview writes in structured formats.
+var Stderr io.Writer = os.Stderr
+
+// current is the format used by Print.
+var current = FormatTable
+
+// Truncate shortens s to maxLen runes, ending in "..." when it was cut.
+func Truncate(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+	if maxLen <= 3 {
+		return string(runes[:maxLen])
+	}
+	return string(runes[:maxLen-3]) + "..."
+}
+
+// ParseFormat parses a format name (case-insensitive).
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("unknown output format %q (expected one of %s)", s, strings.Join(names, ", "))
+}
+
+// SetFormat sets the format used by Print.
+func SetFormat(f Format) {
+	current = f
+}
+
+// CurrentFormat returns the format used by Print.
+func CurrentFormat() Format {
+	return current
+}
+
+// IsTable reports whether results are rendered for humans.
+func IsTable() bool {
+	return current == FormatTable
+}
+
+// Table describes how records are shown in table and CSV output.
+type Table[T any] struct {
+	// Headers are the column titles.
+	Headers []string
+	// Row returns the cells of a record, one per header.
+	Row func(T) []string
+}
+
+// Print writes records to Stdout in the current format.
+func Print[T any](records []T, table Table[T]) error {
+	return Write(Stdout, current, records, table)
+}
+
+// Preview writes an intermediate table for the user to review before a
+// command acts, such as matched rows ahead of an import. It is always a
+// table; in structured formats it goes to Stderr so that Stdout only carries
+// the command result.
+func Preview[T any](records []T, table Table[T]) error {
+	w := Stdout
+	if !IsTable() {
+		w = Stderr
+	}
+	return writeTable(w, records, table)
+}
+
+// Write renders records in the given format. JSON, NDJSON and YAML marshal
+// the records themselves; table and CSV output use table.
+func Write[T any](w io.Writer, format Format, records []T, table Table[T]) error {
+	switch format {
+	case FormatJSON:
+		if records == nil {
+			records = []T{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	case FormatNDJSON:
+		enc := json.NewEncoder(w)
+		for _, r := range records {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatYAML:
+		return writeYAML(w, records)
+	case FormatCSV:
+		return writeCSV(w, records, table)
+	case FormatTable, "":
+		return writeTable(w, records, table)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// writeYAML renders records as YAML with the same keys as the JSON output.
+func writeYAML[T any](w io.Writer, records []T) error {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+
+	var generic []interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+	if generic == nil {
+		generic = []interface{}{}
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(generic); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// writeCSV renders records as CSV with a header row.
+func writeCSV[T any](w io.Writer, records []T, table Table[T]) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(table.Headers); err != nil {
+		return err
+	}
+	for _, r := range records {
+		if err := cw.Write(table.Row(r)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeTable renders records as an aligned table with an underlined header.
+func writeTable[T any](w io.Writer, records []T, table Table[T]) error {
+	rows := make([][]string, 0, len(records))
+	widths := make([]int, len(table.Headers))
+	for i, h := range table.Headers {
+		widths[i] = utf8.RuneCountInString(h)
+	}
+	for _, r := range records {
+		row := table.Row(r)
+		for i, cell := range row {
+			if i < len(widths) && utf8.RuneCountInString(cell) > widths[i] {
+				widths[i] = utf8.RuneCountInString(cell)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	underline := make([]string, len(widths))
+	for i, width := range widths {
+		underline[i] = strings.Repeat("═", width)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(table.Headers, "\t"))
+	fmt.Fprintln(tw, strings.Join(underline, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
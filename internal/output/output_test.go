@@ -0,0 +1,145 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// OutputTestSuite groups output rendering tests.
+type OutputTestSuite struct {
+	suite.Suite
+	records []record
+	table   Table[record]
+}
+
+// record is a sample result row.
+type record struct {
+	Name   string  `json:"name"`
+	Amount float64 `json:"amount"`
+}
+
+func TestOutputTestSuite(t *testing.T) {
+	suite.Run(t, new(OutputTestSuite))
+}
+
+func (s *OutputTestSuite) SetupTest() {
+	s.records = []record{{Name: "REWE", Amount: -12.5}, {Name: "Café, Berlin", Amount: 3}}
+	s.table = Table[record]{
+		Headers: []string{"NAME", "AMOUNT"},
+		Row: func(r record) []string {
+			return []string{r.Name, fmt.Sprintf("%.2f", r.Amount)}
+		},
+	}
+}
+
+func (s *OutputTestSuite) render(format Format) string {
+	var b bytes.Buffer
+	s.Require().NoError(Write(&b, format, s.records, s.table))
+	return b.String()
+}
+
+func (s *OutputTestSuite) TestWrite_Table_AlignsAndUnderlinesColumns() {
+	// Act
+	out := s.render(FormatTable)
+
+	// Assert
+	s.Equal("NAME          AMOUNT\n"+
+		"════════════  ══════\n"+
+		"REWE          -12.50\n"+
+		"Café, Berlin  3.00\n", out)
+}
+
+func (s *OutputTestSuite) TestWrite_JSONAndNDJSON_UseJSONTags() {
+	// Act
+	jsonOut := s.render(FormatJSON)
+	ndjsonOut := s.render(FormatNDJSON)
+
+	// Assert
+	s.JSONEq(`[{"name":"REWE","amount":-12.5},{"name":"Café, Berlin","amount":3}]`, jsonOut)
+	s.Equal("{\"name\":\"REWE\",\"amount\":-12.5}\n{\"name\":\"Café, Berlin\",\"amount\":3}\n", ndjsonOut)
+}
+
+func (s *OutputTestSuite) TestWrite_CSV_QuotesCells() {
+	// Act
+	out := s.render(FormatCSV)
+
+	// Assert
+	s.Equal("NAME,AMOUNT\nREWE,-12.50\n\"Café, Berlin\",3.00\n", out)
+}
+
+func (s *OutputTestSuite) TestWrite_YAML_UsesJSONKeys() {
+	// Act
+	out := s.render(FormatYAML)
+
+	// Assert
+	s.Equal("- amount: -12.5\n  name: REWE\n- amount: 3\n  name: Café, Berlin\n", out)
+}
+
+func (s *OutputTestSuite) TestWrite_WithNoRecords_WritesEmptyCollections() {
+	// Arrange
+	var b bytes.Buffer
+
+	// Act
+	s.Require().NoError(Write(&b, FormatJSON, []record(nil), s.table))
+
+	// Assert
+	s.Equal("[]\n", b.String())
+}
+
+func (s *OutputTestSuite) TestParseFormat_WithUnknownFormat_ReturnsError() {
+	// Act
+	f, err := ParseFormat("JSON")
+	_, unknownErr := ParseFormat("xml")
+
+	// Assert
+	s.NoError(err)
+	s.Equal(FormatJSON, f)
+	s.Error(unknownErr)
+}
+
+func (s *OutputTestSuite) TestPreview_InStructuredFormat_WritesTableToStderr() {
+	// Arrange
+	var stdout, stderr bytes.Buffer
+	Stdout, Stderr = &stdout, &stderr
+	SetFormat(FormatJSON)
+	defer func() {
+		Stdout, Stderr = os.Stdout, os.Stderr
+		SetFormat(FormatTable)
+	}()
+
+	// Act
+	s.Require().NoError(Preview(s.records, s.table))
+
+	// Assert
+	s.Empty(stdout.String())
+	s.Contains(stderr.String(), "NAME")
+	s.Contains(stderr.String(), "REWE")
+}
+
+func (s *OutputTestSuite) TestTruncate() {
+	tests := []struct {
+		name     string
+		input    string
+		maxLen   int
+		expected string
+	}{
+		{name: "short", input: "REWE", maxLen: 10, expected: "REWE"},
+		{name: "cut with ellipsis", input: "HANDYPARKEN MUENCHEN", maxLen: 10, expected: "HANDYPA..."},
+		{name: "too short for ellipsis", input: "HANDYPARKEN", maxLen: 3, expected: "HAN"},
+		{name: "multibyte kept whole", input: "Bäckerei Süßwaren", maxLen: 15, expected: "Bäckerei Süß..."},
+		{name: "multibyte fits", input: "Bäckerei Süß", maxLen: 12, expected: "Bäckerei Süß"},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			got := Truncate(tt.input, tt.maxLen)
+			s.Equal(tt.expected, got)
+			s.True(utf8.ValidString(got))
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package milesmore
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pgbytes/moneypenny/internal/domain"
+)
+
+// unknownLocation is the placeholder Miles & More uses for a missing postal code.
+const unknownLocation = "UNKNOWN"
+
+// legalFormCodes are country codes that on German statements are far more
+// likely a truncated legal form ("GMB" for GmbH) than the country. Keeping
+// such payees whole only loses the location of the rare real transaction.
+var legalFormCodes = map[string]bool{"GMB": true}
+
+// Location is the merchant and location encoded in a card payee string.
+type Location struct {
+	Merchant   string
+	PostalCode string
+	City       string
+	Region     string
+	// Country is the ISO 3166-1 alpha-3 country code.
+	Country string
+}
+
+// ParseLocation splits a card payee string of the form
+// "MERCHANT, POSTAL CITY, REGION, COUNTRY" into its parts, e.g.
+// "RECALL, 19709 MIDDLETOWN, DE, USA". The region is dropped when it repeats
+// the country, and numeric tokens after the postal code (merchant phone
+// numbers) are not taken as city. It reports false when the string does not
+// end in an ISO 3166-1 alpha-3 country code (so suffixes like ", LTD" or
+// ", EUR" are kept), in which case the payee has no location.
+func ParseLocation(payee string) (Location, bool) {
+	parts := strings.Split(payee, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+
+	n := len(parts)
+	if n < 3 || !domain.IsCountryCode(parts[n-1]) || legalFormCodes[parts[n-1]] {
+		return Location{}, false
+	}
+
+	loc := Location{Country: parts[n-1]}
+	place := parts[n-2]
+	merchant := parts[:n-2]
+	if n >= 4 {
+		place = parts[n-3]
+		merchant = parts[:n-3]
+		if region := parts[n-2]; region != loc.Country {
+			loc.Region = region
+		}
+	}
+
+	loc.Merchant = strings.Join(merchant, ", ")
+	if loc.Merchant == "" {
+		return Location{}, false
+	}
+
+	loc.PostalCode, loc.City = splitPlace(place)
+	return loc, true
+}
+
+// splitPlace splits "19709 MIDDLETOWN" into postal code and city. The first
+// token is a postal code when it contains a digit or is the UNKNOWN placeholder.
+func splitPlace(place string) (postalCode, city string) {
+	tokens := strings.Fields(place)
+	if len(tokens) == 0 {
+		return "", ""
+	}
+
+	if tokens[0] == unknownLocation || strings.IndexFunc(tokens[0], unicode.IsDigit) >= 0 {
+		if tokens[0] != unknownLocation {
+			postalCode = tokens[0]
+		}
+		tokens = tokens[1:]
+	}
+
+	var words []string
+	for _, t := range tokens {
+		if strings.IndexFunc(t, unicode.IsLetter) < 0 {
+			continue
+		}
+		words = append(words, t)
+	}
+
+	return postalCode, strings.Join(words, " ")
+}
@@ -4,8 +4,11 @@ package milesmore
 import (
 	"context"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -30,8 +33,17 @@ const (
 
 	// Foreign transaction fee identifier.
 	feeIdentifier = "AUSLANDSEINSATZENTGELT"
+
+	// Prefix of the metadata line carrying the statement billing date.
+	billingDatePrefix = "Billing date:"
+
+	// Prefix of the closing balance line at the end of the statement.
+	balancePrefix = "Balance:"
 )
 
+// cardNumberPattern matches the masked card number in the metadata header.
+var cardNumberPattern = regexp.MustCompile(`^[0-9]{4}[0-9*]{4,11}[0-9]{4}$`)
+
 // ParseResult contains the parsed transactions, any non-fatal errors encountered,
 // and summary information.
 type ParseResult struct {
@@ -46,6 +58,21 @@ type ParseResult struct {
 
 	// SuccessfulRows is the number of successfully parsed rows.
 	SuccessfulRows int
+
+	// BillingDate is the statement billing date from the metadata header.
+	// Zero value indicates the header did not contain a billing date.
+	BillingDate time.Time
+
+	// ClosingBalance is the statement balance from the "Balance:" line.
+	// Only meaningful when HasClosingBalance is true.
+	ClosingBalance float64
+
+	// HasClosingBalance reports whether the statement contained a balance line.
+	HasClosingBalance bool
+
+	// CardNumber is the (masked) card number from the metadata header, e.g.
+	// "5426********1495". Empty when the header did not contain one.
+	CardNumber string
 }
 
 // ParseError represents a non-fatal error encountered while parsing a specific row.
@@ -60,6 +87,35 @@ type ParseError struct {
 	Error error
 }
 
+// ParseFile parses the statement at path in strict mode: any row error fails
+// the whole file, so partially parsed statements never reach YNAB. The error
+// lists the line of every failed row.
+func ParseFile(ctx context.Context, path string) (*ParseResult, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("input file not found: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening input file: %w", err)
+	}
+	defer file.Close()
+
+	result, err := Parse(ctx, file, path)
+	if err != nil {
+		return nil, fmt.Errorf("parsing Miles & More CSV: %w", err)
+	}
+
+	if len(result.Errors) > 0 {
+		lines := make([]string, 0, len(result.Errors))
+		for _, parseErr := range result.Errors {
+			lines = append(lines, fmt.Sprintf("line %d: %v", parseErr.Line, parseErr.Error))
+		}
+		return nil, fmt.Errorf("parsing %s failed with %d errors: %s", path, len(result.Errors), strings.Join(lines, "; "))
+	}
+
+	return result, nil
+}
+
 // Parse reads a Miles & More credit card CSV statement and returns domain transactions.
 // The parser is lenient: it skips invalid rows and collects errors for reporting.
 //
@@ -119,6 +175,7 @@ func Parse(ctx context.Context, reader io.Reader, sourceFile string) (*ParseResu
 		// Skip metadata header rows (first 4 lines)
 		if !headerSkipped {
 			if lineNumber <= 4 {
+				parseMetadata(record, result)
 				continue
 			}
 			// Check if this is the column header row
@@ -135,8 +192,9 @@ func Parse(ctx context.Context, reader io.Reader, sourceFile string) (*ParseResu
 			continue
 		}
 
-		// Skip balance row (last line in Miles & More statements)
-		if len(record) > 0 && strings.HasPrefix(strings.TrimSpace(record[0]), "Balance:") {
+		// Balance row (last line in Miles & More statements) carries the closing balance
+		if len(record) > 0 && strings.HasPrefix(strings.TrimSpace(record[0]), balancePrefix) {
+			parseBalance(record, result)
 			continue
 		}
 
@@ -168,6 +226,8 @@ func Parse(ctx context.Context, reader io.Reader, sourceFile string) (*ParseResu
 			// Associate fee with previous foreign transaction if applicable
 			if previousTransaction.ForeignCurrency != "" && previousTransaction.ForeignAmount != 0 {
 				transaction.Memo = fmt.Sprintf("Fee for transaction: %s", previousTransaction.Payee)
+				// The fee is spent where the transaction it belongs to was
+				transaction.Country = previousTransaction.Country
 			}
 		}
 
@@ -183,6 +243,42 @@ func Parse(ctx context.Context, reader io.Reader, sourceFile string) (*ParseResu
 	return result, nil
 }
 
+// parseMetadata extracts statement-level information from a metadata header row.
+func parseMetadata(record []string, result *ParseResult) {
+	if len(record) == 0 {
+		return
+	}
+
+	for _, f := range record {
+		if f = strings.TrimSpace(f); cardNumberPattern.MatchString(f) {
+			result.CardNumber = f
+		}
+	}
+
+	field := strings.TrimSpace(record[0])
+	if strings.HasPrefix(field, billingDatePrefix) {
+		if billingDate, err := parseDate(strings.TrimSpace(strings.TrimPrefix(field, billingDatePrefix))); err == nil {
+			result.BillingDate = billingDate
+		}
+	}
+}
+
+// parseBalance extracts the closing balance from a balance row such as
+// "Balance:;;;;;-30.50;EUR". The balance is the first numeric field after the label.
+func parseBalance(record []string, result *ParseResult) {
+	for _, field := range record[1:] {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if balance, err := parseAmount(field); err == nil {
+			result.ClosingBalance = balance
+			result.HasClosingBalance = true
+			return
+		}
+	}
+}
+
 // parseTransaction parses a single CSV row into a domain.Transaction.
 func parseTransaction(record []string, lineNumber int, sourceFile string) (*domain.Transaction, error) {
 	transaction := &domain.Transaction{
@@ -211,6 +307,15 @@ func parseTransaction(record []string, lineNumber int, sourceFile string) (*doma
 		return nil, fmt.Errorf("payee is required")
 	}
 
+	// Split merchant and location (optional)
+	if loc, ok := ParseLocation(transaction.Payee); ok {
+		transaction.Merchant = loc.Merchant
+		transaction.PostalCode = loc.PostalCode
+		transaction.City = loc.City
+		transaction.Region = loc.Region
+		transaction.Country = loc.Country
+	}
+
 	// Parse amount (EUR) - required
 	amount, err := parseAmount(strings.TrimSpace(record[colAmount]))
 	if err != nil {
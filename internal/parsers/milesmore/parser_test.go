@@ -59,6 +59,9 @@ func (s *ParserTestSuite) TestParse_WithValidCSV_ParsesAllTransactions() {
 	s.Equal("USD", secondTx.ForeignCurrency)
 	s.Equal(-10.0, secondTx.ForeignAmount)
 	s.Equal(1.18483, secondTx.ExchangeRate)
+	s.Equal("RECALL", secondTx.Merchant)
+	s.Equal("MIDDLETOWN", secondTx.City)
+	s.Equal("USA", secondTx.Country)
 	s.Equal("valid.csv", secondTx.SourceFile)
 	s.Greater(secondTx.SourceLine, 0)
 
@@ -173,6 +176,52 @@ func (s *ParserTestSuite) TestParse_WithBalanceLine_SkipsBalanceLine() {
 	s.Equal(-20.00, secondTx.Amount)
 }
 
+// TestParse_WithBalanceLine_ExtractsClosingBalance tests closing balance extraction.
+func (s *ParserTestSuite) TestParse_WithBalanceLine_ExtractsClosingBalance() {
+	// Arrange
+	file, err := os.Open(filepath.Join("testdata", "with_balance.csv"))
+	s.Require().NoError(err)
+	defer file.Close()
+
+	// Act
+	result, err := Parse(context.Background(), file, "with_balance.csv")
+
+	// Assert
+	s.NoError(err)
+	s.True(result.HasClosingBalance)
+	s.Equal(-30.50, result.ClosingBalance)
+}
+
+// TestParse_WithBillingDateHeader_ExtractsBillingDate tests metadata extraction.
+func (s *ParserTestSuite) TestParse_WithBillingDateHeader_ExtractsBillingDate() {
+	// Arrange
+	file, err := os.Open(filepath.Join("testdata", "valid.csv"))
+	s.Require().NoError(err)
+	defer file.Close()
+
+	// Act
+	result, err := Parse(context.Background(), file, "valid.csv")
+
+	// Assert
+	s.NoError(err)
+	s.Equal(time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC), result.BillingDate)
+}
+
+// TestParse_WithCardHeader_ExtractsCardNumber tests card number extraction from the metadata header.
+func (s *ParserTestSuite) TestParse_WithCardHeader_ExtractsCardNumber() {
+	// Arrange
+	file, err := os.Open(filepath.Join("testdata", "valid.csv"))
+	s.Require().NoError(err)
+	defer file.Close()
+
+	// Act
+	result, err := Parse(context.Background(), file, "valid.csv")
+
+	// Assert
+	s.NoError(err)
+	s.Equal("5426********1495", result.CardNumber)
+}
+
 // TestParse_WithCancelledContext_ReturnsError tests context cancellation.
 func (s *ParserTestSuite) TestParse_WithCancelledContext_ReturnsError() {
 	// Arrange
@@ -418,3 +467,94 @@ func (s *ParserTestSuite) TestParseExchangeRate_WithVariousInputs_ParsesCorrectl
 		})
 	}
 }
+
+// TestParseLocation_WithVariousPayees_SplitsMerchantAndLocation tests merchant and location extraction.
+func (s *ParserTestSuite) TestParseLocation_WithVariousPayees_SplitsMerchantAndLocation() {
+	tests := []struct {
+		name     string
+		input    string
+		expected Location
+		ok       bool
+	}{
+		{
+			name:     "us merchant with state",
+			input:    "RECALL, 19709 MIDDLETOWN, DE, USA",
+			expected: Location{Merchant: "RECALL", PostalCode: "19709", City: "MIDDLETOWN", Region: "DE", Country: "USA"},
+			ok:       true,
+		},
+		{
+			name:     "city with umlaut and region",
+			input:    "HANDYPARKEN MUENCHEN, 80992 MÜNCHEN, BY, DEU",
+			expected: Location{Merchant: "HANDYPARKEN MUENCHEN", PostalCode: "80992", City: "MÜNCHEN", Region: "BY", Country: "DEU"},
+			ok:       true,
+		},
+		{
+			name:     "phone number instead of city and region repeating country",
+			input:    "PAYPAL *CRAFTDOCSLT, EC4A3BF 35314369001, GBR, GBR",
+			expected: Location{Merchant: "PAYPAL *CRAFTDOCSLT", PostalCode: "EC4A3BF", Country: "GBR"},
+			ok:       true,
+		},
+		{
+			name:     "unknown postal code",
+			input:    "APPLE.COM/BILL, UNKNOWN CORK, IRL, IRL",
+			expected: Location{Merchant: "APPLE.COM/BILL", City: "CORK", Country: "IRL"},
+			ok:       true,
+		},
+		{
+			name:     "without region",
+			input:    "CAFE CENTRAL, 1010 WIEN, AUT",
+			expected: Location{Merchant: "CAFE CENTRAL", PostalCode: "1010", City: "WIEN", Country: "AUT"},
+			ok:       true,
+		},
+		{
+			name:  "fee without location",
+			input: "AUSLANDSEINSATZENTGELT",
+		},
+		{
+			name:  "free text with commas",
+			input: "Transfer, thanks, see you",
+		},
+		{
+			name:  "company suffix is not a country",
+			input: "JETBRAINS, PRAGUE, LTD",
+		},
+		{
+			name:  "currency is not a country",
+			input: "BOOKING.COM, AMSTERDAM, EUR",
+		},
+		{
+			name:  "incorporated suffix is not a country",
+			input: "GITHUB, SAN FRANCISCO, CA, INC",
+		},
+		{
+			name:  "truncated GmbH is not a country",
+			input: "MUELLER HANDELS, 80331 MUENCHEN, GMB",
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			result, ok := ParseLocation(tt.input)
+
+			// Assert
+			s.Equal(tt.ok, ok)
+			s.Equal(tt.expected, result)
+		})
+	}
+}
+
+// TestParseFile_WithInvalidRows_FailsListingLines tests strict file parsing.
+func (s *ParserTestSuite) TestParseFile_WithInvalidRows_FailsListingLines() {
+	// Act
+	valid, err := ParseFile(context.Background(), filepath.Join("testdata", "valid.csv"))
+	_, invalidErr := ParseFile(context.Background(), filepath.Join("testdata", "invalid_rows.csv"))
+	_, missingErr := ParseFile(context.Background(), filepath.Join("testdata", "missing.csv"))
+
+	// Assert
+	s.Require().NoError(err)
+	s.NotEmpty(valid.Transactions)
+	s.ErrorContains(invalidErr, "failed with")
+	s.ErrorContains(invalidErr, "line ")
+	s.ErrorContains(missingErr, "input file not found")
+}
@@ -0,0 +1,98 @@
+package payee
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/storage"
+)
+
+const (
+	// DefaultFileName is the mappings file name inside the data directory.
+	DefaultFileName = "payees.json"
+
+	// mappingsVersion is the current on-disk format version.
+	mappingsVersion = 1
+)
+
+// Mapping links a normalized import payee name to a YNAB payee.
+type Mapping struct {
+	PayeeID     string    `json:"payee_id"`
+	PayeeName   string    `json:"payee_name"`
+	ConfirmedAt time.Time `json:"confirmed_at"`
+}
+
+// Mappings holds confirmed payee mappings per budget, backed by a file.
+type Mappings struct {
+	path    string
+	Budgets map[string]map[string]Mapping
+}
+
+// fileFormat is the on-disk representation of the mappings.
+type fileFormat struct {
+	Version int                           `json:"version"`
+	Budgets map[string]map[string]Mapping `json:"budgets"`
+}
+
+// OpenMappings loads the mappings at path. A missing file yields no mappings.
+func OpenMappings(path string) (*Mappings, error) {
+	var f fileFormat
+	if _, err := storage.LoadJSON(path, &f); err != nil {
+		return nil, fmt.Errorf("loading payee mappings: %w", err)
+	}
+
+	m := &Mappings{path: path, Budgets: f.Budgets}
+	if m.Budgets == nil {
+		m.Budgets = make(map[string]map[string]Mapping)
+	}
+	return m, nil
+}
+
+// OpenMappingsDir opens the mappings stored as DefaultFileName in dir, e.g.
+// the data directory.
+func OpenMappingsDir(dir string) (*Mappings, error) {
+	return OpenMappings(filepath.Join(dir, DefaultFileName))
+}
+
+// Get returns the mapping of a normalized name in a budget.
+func (m *Mappings) Get(budgetID, normalized string) (Mapping, bool) {
+	mapping, ok := m.Budgets[budgetID][normalized]
+	return mapping, ok
+}
+
+// Set stores the mapping of a normalized name in a budget.
+func (m *Mappings) Set(budgetID, normalized string, mapping Mapping) {
+	if m.Budgets[budgetID] == nil {
+		m.Budgets[budgetID] = make(map[string]Mapping)
+	}
+	m.Budgets[budgetID][normalized] = mapping
+}
+
+// Delete removes the mapping of a normalized name. It reports whether one existed.
+func (m *Mappings) Delete(budgetID, normalized string) bool {
+	if _, ok := m.Budgets[budgetID][normalized]; !ok {
+		return false
+	}
+	delete(m.Budgets[budgetID], normalized)
+	return true
+}
+
+// Names returns the mapped normalized names of a budget in sorted order.
+func (m *Mappings) Names(budgetID string) []string {
+	names := make([]string, 0, len(m.Budgets[budgetID]))
+	for name := range m.Budgets[budgetID] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Save writes the mappings atomically to their file.
+func (m *Mappings) Save() error {
+	if err := storage.SaveJSON(m.path, fileFormat{Version: mappingsVersion, Budgets: m.Budgets}); err != nil {
+		return fmt.Errorf("saving payee mappings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package payee
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pgbytes/moneypenny/internal/domain"
+)
+
+// foldings replaces German umlauts and common accented letters by ASCII.
+var foldings = strings.NewReplacer(
+	"ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss",
+	"á", "a", "à", "a", "â", "a", "å", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ø", "o",
+	"ú", "u", "ù", "u", "û", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// suffixCountries are the countries whose codes card statements commonly
+// append to merchant names. Only these are stripped, since short codes of
+// all countries would eat real name tokens.
+var suffixCountries = []string{
+	"DEU", "AUT", "CHE", "USA", "GBR", "IRL", "FRA", "NLD", "BEL", "LUX",
+	"ESP", "ITA", "PRT", "DNK", "SWE", "NOR", "FIN", "POL", "CZE", "HUN",
+	"GRC", "CAN", "AUS", "JPN", "IND", "SGP", "ARE",
+}
+
+// countryCodes holds the lower-case ISO 3166-1 alpha-2 and alpha-3 codes of
+// suffixCountries, plus the common "uk".
+var countryCodes = func() map[string]bool {
+	codes := map[string]bool{"uk": true}
+	for _, alpha3 := range suffixCountries {
+		alpha2, ok := domain.CountryAlpha2(alpha3)
+		if !ok {
+			panic("payee: unknown country code " + alpha3)
+		}
+		codes[strings.ToLower(alpha3)] = true
+		codes[strings.ToLower(alpha2)] = true
+	}
+	return codes
+}()
+
+// cities are common city names that statements append to merchant names
+// (after umlaut folding).
+var cities = map[string]bool{
+	"berlin": true, "hamburg": true, "muenchen": true, "munich": true, "koeln": true,
+	"frankfurt": true, "stuttgart": true, "duesseldorf": true, "dortmund": true,
+	"essen": true, "leipzig": true, "bremen": true, "dresden": true, "hannover": true,
+	"nuernberg": true, "duisburg": true, "bochum": true, "wuppertal": true,
+	"bielefeld": true, "bonn": true, "muenster": true, "karlsruhe": true,
+	"mannheim": true, "augsburg": true, "wiesbaden": true, "mainz": true,
+	"wien": true, "vienna": true, "zuerich": true, "zurich": true, "london": true,
+	"dublin": true, "cork": true, "paris": true, "amsterdam": true, "luxembourg": true,
+	"unknown": true,
+}
+
+// Normalize reduces a payee name to a canonical form for matching: lower
+// case, umlauts and accents folded, only the part before the first comma
+// (statements append location details there), tokens containing digits
+// (store and terminal numbers) dropped, and trailing city and country
+// tokens removed.
+func Normalize(name string) string {
+	name = foldings.Replace(strings.ToLower(name))
+
+	if idx := strings.Index(name, ","); idx > 0 {
+		name = name[:idx]
+	}
+
+	fields := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if strings.IndexFunc(f, unicode.IsDigit) >= 0 {
+			continue
+		}
+		tokens = append(tokens, f)
+	}
+
+	// Keep at least one token so names like "Berlin Burger" stay meaningful
+	for len(tokens) > 1 {
+		last := tokens[len(tokens)-1]
+		if !countryCodes[last] && !cities[last] {
+			break
+		}
+		tokens = tokens[:len(tokens)-1]
+	}
+
+	return strings.Join(tokens, " ")
+}
@@ -0,0 +1,216 @@
+// Package payee maps payee names from statements to the existing payees of a
+// YNAB budget, so imports reuse payees instead of creating near-duplicates.
+package payee
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/pgbytes/moneypenny/internal/matcher"
+)
+
+const (
+	// DefaultMinSimilarity is the default similarity required for a fuzzy match.
+	DefaultMinSimilarity = 0.75
+
+	// ambiguityMargin is the score difference below which two payees are equally plausible.
+	ambiguityMargin = 0.05
+)
+
+// Source describes how a payee was resolved.
+type Source string
+
+const (
+	// SourceMapping indicates a previously confirmed mapping.
+	SourceMapping Source = "mapping"
+	// SourceExact indicates identical normalized names.
+	SourceExact Source = "exact"
+	// SourceFuzzy indicates a similar normalized name.
+	SourceFuzzy Source = "fuzzy"
+	// SourceNone indicates no payee was found.
+	SourceNone Source = "none"
+)
+
+// Resolution is the result of resolving one import payee name.
+type Resolution struct {
+	// Name is the payee name as imported.
+	Name string `json:"name"`
+	// PayeeID is the matched YNAB payee (empty when unresolved).
+	PayeeID string `json:"payee_id,omitempty"`
+	// PayeeName is the name of the matched YNAB payee.
+	PayeeName string `json:"payee_name,omitempty"`
+	// Score is the similarity (0..1) of the match.
+	Score  float64 `json:"score"`
+	Source Source  `json:"source"`
+}
+
+// Resolved reports whether a YNAB payee was found.
+func (r Resolution) Resolved() bool {
+	return r.PayeeID != ""
+}
+
+// Resolver matches import payee names against a budget's payees.
+type Resolver struct {
+	payees        []candidate
+	byID          map[string]ynab.Payee
+	mappings      *Mappings
+	budgetID      string
+	minSimilarity float64
+}
+
+// candidate is a budget payee with its normalized name.
+type candidate struct {
+	payee      ynab.Payee
+	normalized string
+}
+
+// Client reads the payees of the configured budget.
+type Client interface {
+	ynab.BudgetReader
+	ynab.PayeeReader
+}
+
+// LoadResolver creates a resolver for the payees of the client's budget and
+// the confirmed mappings stored in dir, e.g. the data directory. The mappings
+// are returned so remembered resolutions can be saved.
+func LoadResolver(client Client, dir string) (*Resolver, *Mappings, error) {
+	payees, err := client.GetPayees()
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetching payees: %w", err)
+	}
+
+	mappings, err := OpenMappingsDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return NewResolver(client.BudgetID(), payees, mappings, DefaultMinSimilarity), mappings, nil
+}
+
+// NewResolver creates a resolver for the budget's payees. Transfer and
+// deleted payees are never matched. mappings may be nil.
+func NewResolver(budgetID string, payees []ynab.Payee, mappings *Mappings, minSimilarity float64) *Resolver {
+	if minSimilarity <= 0 {
+		minSimilarity = DefaultMinSimilarity
+	}
+
+	r := &Resolver{
+		byID:          make(map[string]ynab.Payee, len(payees)),
+		mappings:      mappings,
+		budgetID:      budgetID,
+		minSimilarity: minSimilarity,
+	}
+
+	for _, p := range payees {
+		if p.Deleted || p.TransferAccountID != "" {
+			continue
+		}
+		r.byID[p.ID] = p
+		if n := Normalize(p.Name); n != "" {
+			r.payees = append(r.payees, candidate{payee: p, normalized: n})
+		}
+	}
+
+	return r
+}
+
+// Resolve finds the YNAB payee for an import payee name. Confirmed mappings
+// take precedence, then identical normalized names, then the most similar
+// name when it is clearly better than the runner-up.
+func (r *Resolver) Resolve(name string) Resolution {
+	res := Resolution{Name: name, Source: SourceNone}
+	normalized := Normalize(name)
+	if normalized == "" {
+		return res
+	}
+
+	if r.mappings != nil {
+		if m, ok := r.mappings.Get(r.budgetID, normalized); ok {
+			if p, exists := r.byID[m.PayeeID]; exists {
+				res.PayeeID, res.PayeeName, res.Score, res.Source = p.ID, p.Name, 1, SourceMapping
+				return res
+			}
+		}
+	}
+
+	type scored struct {
+		candidate
+		score float64
+	}
+	var matches []scored
+	for _, c := range r.payees {
+		score := similarity(normalized, c.normalized)
+		if score >= r.minSimilarity {
+			matches = append(matches, scored{candidate: c, score: score})
+		}
+	}
+	if len(matches) == 0 {
+		return res
+	}
+
+	sort.SliceStable(matches, func(a, b int) bool {
+		return matches[a].score > matches[b].score
+	})
+
+	best := matches[0]
+	if best.score < 1 && len(matches) > 1 && best.score-matches[1].score < ambiguityMargin {
+		return res
+	}
+
+	res.PayeeID, res.PayeeName, res.Score = best.payee.ID, best.payee.Name, best.score
+	res.Source = SourceFuzzy
+	if best.score == 1 {
+		res.Source = SourceExact
+	}
+	return res
+}
+
+// ResolveAll sets PayeeID on every transaction whose payee matches a YNAB
+// payee and returns the resolutions, one per distinct payee name in order.
+func (r *Resolver) ResolveAll(transactions []domain.Transaction) []Resolution {
+	seen := make(map[string]bool)
+	var resolutions []Resolution
+	for i := range transactions {
+		res := r.Resolve(transactions[i].Payee)
+		transactions[i].PayeeID = res.PayeeID
+
+		if !seen[res.Name] {
+			seen[res.Name] = true
+			resolutions = append(resolutions, res)
+		}
+	}
+	return resolutions
+}
+
+// Remember stores a confirmed resolution as a mapping. The mappings must be saved afterwards.
+func (r *Resolver) Remember(res Resolution, at time.Time) {
+	if r.mappings == nil || !res.Resolved() {
+		return
+	}
+	r.mappings.Set(r.budgetID, Normalize(res.Name), Mapping{
+		PayeeID:     res.PayeeID,
+		PayeeName:   res.PayeeName,
+		ConfirmedAt: at.UTC(),
+	})
+}
+
+// similarity scores two normalized names. Identical names score 1; a name
+// whose words all start the other name (e.g. "rewe" in "rewe markt") scores
+// at least 0.9; otherwise the bigram similarity is used.
+func similarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	score := matcher.PayeeSimilarity(a, b)
+	if strings.HasPrefix(a, b+" ") || strings.HasPrefix(b, a+" ") {
+		if score < 0.9 {
+			score = 0.9
+		}
+	}
+	return score
+}
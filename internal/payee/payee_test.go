@@ -0,0 +1,184 @@
+package payee
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/stretchr/testify/suite"
+)
+
+// PayeeTestSuite groups all payee resolution tests.
+type PayeeTestSuite struct {
+	suite.Suite
+	payees []ynab.Payee
+	now    time.Time
+}
+
+func TestPayeeTestSuite(t *testing.T) {
+	suite.Run(t, new(PayeeTestSuite))
+}
+
+func (s *PayeeTestSuite) SetupTest() {
+	s.now = time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	s.payees = []ynab.Payee{
+		{ID: "p-rewe", Name: "REWE"},
+		{ID: "p-handy", Name: "Handyparken"},
+		{ID: "p-apple", Name: "Apple.com/Bill"},
+		{ID: "p-muller", Name: "Müller Drogerie"},
+		{ID: "p-transfer", Name: "Transfer : Savings", TransferAccountID: "acc-2"},
+		{ID: "p-deleted", Name: "Old Shop", Deleted: true},
+	}
+}
+
+func (s *PayeeTestSuite) TestNormalize() {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "location after comma", input: "HANDYPARKEN MUENCHEN, 80992 MÜNCHEN, BY, DEU", expected: "handyparken"},
+		{name: "store number", input: "REWE Markt 4711", expected: "rewe markt"},
+		{name: "umlauts", input: "Müller Drogerie", expected: "mueller drogerie"},
+		{name: "country suffix", input: "AMAZON MARKETPLACE DEU", expected: "amazon marketplace"},
+		{name: "city suffix", input: "Rossmann Berlin", expected: "rossmann"},
+		{name: "single city token kept", input: "Berlin", expected: "berlin"},
+		{name: "punctuation", input: "APPLE.COM/BILL", expected: "apple com bill"},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.expected, Normalize(tt.input))
+		})
+	}
+}
+
+func (s *PayeeTestSuite) TestResolve_MatchesExistingPayees() {
+	// Arrange
+	resolver := NewResolver("budget-1", s.payees, nil, 0)
+
+	tests := []struct {
+		name     string
+		input    string
+		payeeID  string
+		source   Source
+		resolved bool
+	}{
+		{name: "exact after normalization", input: "HANDYPARKEN MUENCHEN, 80992 MÜNCHEN, BY, DEU", payeeID: "p-handy", source: SourceExact, resolved: true},
+		{name: "prefix", input: "REWE Markt 4711", payeeID: "p-rewe", source: SourceFuzzy, resolved: true},
+		{name: "umlaut folding", input: "MUELLER DROGERIE", payeeID: "p-muller", source: SourceExact, resolved: true},
+		{name: "statement location", input: "APPLE.COM/BILL, UNKNOWN CORK, IRL, IRL", payeeID: "p-apple", source: SourceExact, resolved: true},
+		{name: "unknown", input: "Completely Different", source: SourceNone},
+		{name: "transfer payees are ignored", input: "Transfer Savings", source: SourceNone},
+		{name: "deleted payees are ignored", input: "Old Shop", source: SourceNone},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			res := resolver.Resolve(tt.input)
+
+			// Assert
+			s.Equal(tt.resolved, res.Resolved())
+			s.Equal(tt.payeeID, res.PayeeID)
+			s.Equal(tt.source, res.Source)
+		})
+	}
+}
+
+func (s *PayeeTestSuite) TestResolve_WithEquallySimilarPayees_IsUnresolved() {
+	// Arrange
+	payees := []ynab.Payee{{ID: "p-1", Name: "Shop North"}, {ID: "p-2", Name: "Shop South"}}
+	resolver := NewResolver("budget-1", payees, nil, 0.5)
+
+	// Act
+	res := resolver.Resolve("Shop")
+
+	// Assert
+	s.False(res.Resolved())
+}
+
+func (s *PayeeTestSuite) TestRemember_PersistsMappingUsedOnNextResolve() {
+	// Arrange
+	path := filepath.Join(s.T().TempDir(), DefaultFileName)
+	mappings, err := OpenMappings(path)
+	s.Require().NoError(err)
+	resolver := NewResolver("budget-1", s.payees, mappings, 0)
+
+	// Act
+	resolver.Remember(Resolution{Name: "PAYPAL *DROGERIE123", PayeeID: "p-muller", PayeeName: "Müller Drogerie"}, s.now)
+	s.Require().NoError(mappings.Save())
+
+	reopened, err := OpenMappings(path)
+	s.Require().NoError(err)
+	res := NewResolver("budget-1", s.payees, reopened, 0).Resolve("PAYPAL *DROGERIE456")
+	other := NewResolver("budget-2", s.payees, reopened, 0).Resolve("PAYPAL *DROGERIE456")
+
+	// Assert
+	s.Equal("p-muller", res.PayeeID)
+	s.Equal(SourceMapping, res.Source)
+	s.NotEqual(SourceMapping, other.Source)
+}
+
+func (s *PayeeTestSuite) TestMappings_DeleteAndNames() {
+	// Arrange
+	mappings, err := OpenMappings(filepath.Join(s.T().TempDir(), DefaultFileName))
+	s.Require().NoError(err)
+	mappings.Set("budget-1", "b", Mapping{PayeeID: "p-2"})
+	mappings.Set("budget-1", "a", Mapping{PayeeID: "p-1"})
+
+	// Act
+	deleted := mappings.Delete("budget-1", "b")
+	missing := mappings.Delete("budget-1", "c")
+
+	// Assert
+	s.True(deleted)
+	s.False(missing)
+	s.Equal([]string{"a"}, mappings.Names("budget-1"))
+}
+
+// fakeClient serves a fixed list of payees for a budget.
+type fakeClient struct {
+	ynab.BudgetReader
+	budgetID string
+	payees   []ynab.Payee
+}
+
+func (c *fakeClient) BudgetID() string                 { return c.budgetID }
+func (c *fakeClient) GetPayees() ([]ynab.Payee, error) { return c.payees, nil }
+
+func (s *PayeeTestSuite) TestLoadResolver_UsesStoredMappings() {
+	// Arrange
+	dir := s.T().TempDir()
+	mappings, err := OpenMappingsDir(dir)
+	s.Require().NoError(err)
+	mappings.Set("budget-1", Normalize("PAYPAL *DROGERIE123"), Mapping{PayeeID: "p-muller"})
+	s.Require().NoError(mappings.Save())
+
+	// Act
+	resolver, loaded, err := LoadResolver(&fakeClient{budgetID: "budget-1", payees: s.payees}, dir)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal([]string{Normalize("PAYPAL *DROGERIE123")}, loaded.Names("budget-1"))
+	s.Equal(SourceMapping, resolver.Resolve("PAYPAL *DROGERIE123").Source)
+}
+
+func (s *PayeeTestSuite) TestResolveAll_SetsPayeeIDsAndReturnsDistinctNames() {
+	// Arrange
+	resolver := NewResolver("budget-1", s.payees, nil, 0)
+	transactions := []domain.Transaction{{Payee: "REWE"}, {Payee: "Unknown Shop"}, {Payee: "REWE"}}
+
+	// Act
+	resolutions := resolver.ResolveAll(transactions)
+
+	// Assert
+	s.Require().Len(resolutions, 2)
+	s.Equal("REWE", resolutions[0].Name)
+	s.Equal(SourceNone, resolutions[1].Source)
+	s.Equal("p-rewe", transactions[0].PayeeID)
+	s.Empty(transactions[1].PayeeID)
+	s.Equal("p-rewe", transactions[2].PayeeID)
+}
@@ -0,0 +1,98 @@
+// Package prompt provides simple line-based interactive prompts for the CLI.
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Prompter reads answers from an input stream and writes questions to an output stream.
+// A single Prompter should be reused for a whole interactive session so that
+// buffered input is not lost between questions.
+type Prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+	// terminal is set when in is a terminal, so secrets can be read without echo.
+	terminal *os.File
+}
+
+// New creates a Prompter reading from in and writing to out.
+func New(in io.Reader, out io.Writer) *Prompter {
+	p := &Prompter{
+		in:  bufio.NewReader(in),
+		out: out,
+	}
+	if f, ok := in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		p.terminal = f
+	}
+	return p
+}
+
+// Ask writes the question and returns the trimmed answer line.
+// An empty answer is returned as an empty string; io.EOF is returned only when
+// the input is exhausted before any answer is given.
+func (p *Prompter) Ask(question string) (string, error) {
+	fmt.Fprintf(p.out, "%s ", question)
+
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+// AskSecret is like Ask but does not echo the answer when reading from a terminal.
+func (p *Prompter) AskSecret(question string) (string, error) {
+	if p.terminal == nil {
+		return p.Ask(question)
+	}
+
+	fmt.Fprintf(p.out, "%s ", question)
+	answer, err := term.ReadPassword(int(p.terminal.Fd()))
+	fmt.Fprintln(p.out)
+	if err != nil {
+		return "", fmt.Errorf("reading answer: %w", err)
+	}
+	return strings.TrimSpace(string(answer)), nil
+}
+
+// AskDefault is like Ask but returns def when the answer is empty.
+func (p *Prompter) AskDefault(question, def string) (string, error) {
+	if def != "" {
+		question = fmt.Sprintf("%s [%s]", question, def)
+	}
+
+	answer, err := p.Ask(question)
+	if err != nil {
+		return "", err
+	}
+	if answer == "" {
+		return def, nil
+	}
+	return answer, nil
+}
+
+// Confirm asks a yes/no question and returns true only for an explicit yes.
+// A closed input stream counts as no.
+func (p *Prompter) Confirm(question string) (bool, error) {
+	answer, err := p.Ask(question + " [y/N]")
+	if err == io.EOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("reading answer: %w", err)
+	}
+
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,67 @@
+package prompt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{name: "yes short", input: "y\n", expected: true},
+		{name: "yes long mixed case", input: "Yes\n", expected: true},
+		{name: "no", input: "n\n", expected: false},
+		{name: "empty answer defaults to no", input: "\n", expected: false},
+		{name: "closed input defaults to no", input: "", expected: false},
+		{name: "answer without newline", input: "y", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			out := &bytes.Buffer{}
+			p := New(strings.NewReader(tt.input), out)
+
+			// Act
+			result, err := p.Confirm("Proceed?")
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+			assert.Contains(t, out.String(), "Proceed? [y/N]")
+		})
+	}
+}
+
+func TestAsk_WithMultipleQuestions_ReadsSequentialLines(t *testing.T) {
+	// Arrange
+	p := New(strings.NewReader("first\n  second  \n"), &bytes.Buffer{})
+
+	// Act
+	first, err1 := p.Ask("One?")
+	second, err2 := p.Ask("Two?")
+
+	// Assert
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.Equal(t, "first", first)
+	assert.Equal(t, "second", second)
+}
+
+func TestAskDefault_WithEmptyAnswer_ReturnsDefault(t *testing.T) {
+	// Arrange
+	p := New(strings.NewReader("\n"), &bytes.Buffer{})
+
+	// Act
+	answer, err := p.AskDefault("Name?", "fallback")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "fallback", answer)
+}
@@ -0,0 +1,174 @@
+// Package reconcile compares a statement's closing balance with the cleared
+// balance of a YNAB account and explains the difference.
+package reconcile
+
+import (
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/pgbytes/moneypenny/internal/matcher"
+)
+
+const (
+	// AdjustmentPayee is the payee YNAB itself uses for reconciliation adjustments.
+	AdjustmentPayee = "Reconciliation Balance Adjustment"
+
+	// isoDate is the date format used by the YNAB API.
+	isoDate = "2006-01-02"
+)
+
+// Statement is the statement side of a reconciliation.
+type Statement struct {
+	// Transactions are the parsed statement rows.
+	Transactions []domain.Transaction
+	// ClosingBalance is the statement balance in the account currency.
+	ClosingBalance float64
+	// Date is the statement date (billing date); the YNAB balance is taken as of this day.
+	Date time.Time
+}
+
+// Report explains the difference between statement and YNAB balances.
+// All amounts are in milliunits.
+type Report struct {
+	// AccountID is the reconciled account.
+	AccountID string `json:"account_id"`
+	// StatementDate is the date the balances are compared at (YYYY-MM-DD).
+	StatementDate string `json:"statement_date"`
+	// StatementBalance is the statement closing balance.
+	StatementBalance int64 `json:"statement_balance"`
+	// ClearedBalance is the YNAB cleared balance as of the statement date.
+	ClearedBalance int64 `json:"cleared_balance"`
+	// Difference is StatementBalance minus ClearedBalance.
+	Difference int64 `json:"difference"`
+
+	// Matched are statement rows with a corresponding YNAB transaction.
+	Matched []matcher.Result `json:"matched"`
+	// MissingInYNAB are statement rows without a confident YNAB match.
+	MissingInYNAB []matcher.Result `json:"missing_in_ynab"`
+	// UnclearedMatches are matched YNAB transactions not yet marked cleared.
+	UnclearedMatches []ynab.Transaction `json:"uncleared_matches"`
+	// NotOnStatement are cleared, unreconciled YNAB transactions in the statement
+	// period that do not appear on the statement.
+	NotOnStatement []ynab.Transaction `json:"not_on_statement"`
+
+	// Explained is the part of the difference accounted for by the lists above.
+	Explained int64 `json:"explained"`
+	// Unexplained is the remaining difference.
+	Unexplained int64 `json:"unexplained"`
+}
+
+// Build compares the statement with the account and its transactions.
+// existing should contain the account's transactions from shortly before the
+// first statement row up to today.
+func Build(stmt Statement, account ynab.Account, existing []ynab.Transaction, opts matcher.Options) *Report {
+	statementDate := stmt.Date.Format(isoDate)
+	fromDate := statementDate
+	for _, tx := range stmt.Transactions {
+		if d := tx.Date.Format(isoDate); d < fromDate {
+			fromDate = d
+		}
+	}
+
+	report := &Report{
+		AccountID:        account.ID,
+		StatementDate:    statementDate,
+		StatementBalance: ynab.FloatToMilliunits(stmt.ClosingBalance),
+		ClearedBalance:   clearedBalanceAt(account, existing, statementDate),
+	}
+	report.Difference = report.StatementBalance - report.ClearedBalance
+
+	results := matcher.Match(stmt.Transactions, existing, opts)
+	matchedIDs := make(map[string]bool)
+
+	for _, r := range results {
+		if r.Status != matcher.StatusMatched {
+			report.MissingInYNAB = append(report.MissingInYNAB, r)
+			report.Explained += ynab.FloatToMilliunits(r.Transaction.Amount)
+			continue
+		}
+
+		report.Matched = append(report.Matched, r)
+		matchedIDs[r.Match.Transaction.ID] = true
+
+		// Matched transactions dated after the statement date are not part of
+		// the cleared balance computed above, whatever their status.
+		if isCleared(r.Match.Transaction) && r.Match.Transaction.Date <= statementDate {
+			continue
+		}
+		if !isCleared(r.Match.Transaction) {
+			report.UnclearedMatches = append(report.UnclearedMatches, r.Match.Transaction)
+		}
+		report.Explained += r.Match.Transaction.Amount
+	}
+
+	for _, t := range existing {
+		if t.Deleted || matchedIDs[t.ID] || t.Cleared != ynab.ClearedStatusCleared {
+			continue
+		}
+		if t.Date < fromDate || t.Date > statementDate {
+			continue
+		}
+		report.NotOnStatement = append(report.NotOnStatement, t)
+		report.Explained -= t.Amount
+	}
+
+	report.Unexplained = report.Difference - report.Explained
+
+	return report
+}
+
+// Balanced reports whether the statement and YNAB balances agree.
+func (r *Report) Balanced() bool {
+	return r.Difference == 0
+}
+
+// ReconcileUpdates returns bulk updates marking every matched transaction as reconciled.
+// Transactions already reconciled are skipped.
+func (r *Report) ReconcileUpdates() []ynab.SaveTransactionWithID {
+	var updates []ynab.SaveTransactionWithID
+	for _, m := range r.Matched {
+		t := m.Match.Transaction
+		if t.Cleared == ynab.ClearedStatusReconciled {
+			continue
+		}
+		updates = append(updates, ynab.SaveTransactionWithID{ID: t.ID, Cleared: ynab.ClearedStatusReconciled})
+	}
+	return updates
+}
+
+// Adjustment returns a reconciled transaction that closes the remaining difference.
+// It returns false when no adjustment is needed.
+func (r *Report) Adjustment() (ynab.SaveTransaction, bool) {
+	if r.Unexplained == 0 {
+		return ynab.SaveTransaction{}, false
+	}
+
+	return ynab.SaveTransaction{
+		AccountID: r.AccountID,
+		Date:      r.StatementDate,
+		Amount:    r.Unexplained,
+		PayeeName: AdjustmentPayee,
+		Memo:      "Entered automatically by moneypenny reconcile",
+		Cleared:   ynab.ClearedStatusReconciled,
+		Approved:  true,
+	}, true
+}
+
+// clearedBalanceAt derives the account's cleared balance as of date by removing
+// cleared transactions dated after it from the current cleared balance.
+func clearedBalanceAt(account ynab.Account, existing []ynab.Transaction, date string) int64 {
+	balance := account.ClearedBalance
+	for _, t := range existing {
+		if t.Deleted || !isCleared(t) || t.Date <= date {
+			continue
+		}
+		balance -= t.Amount
+	}
+	return balance
+}
+
+// isCleared reports whether a transaction counts towards the cleared balance.
+func isCleared(t ynab.Transaction) bool {
+	return t.Cleared == ynab.ClearedStatusCleared || t.Cleared == ynab.ClearedStatusReconciled
+}
@@ -0,0 +1,143 @@
+package reconcile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/pgbytes/moneypenny/internal/matcher"
+	"github.com/stretchr/testify/suite"
+)
+
+// ReconcileTestSuite groups all reconciliation tests.
+type ReconcileTestSuite struct {
+	suite.Suite
+	statement Statement
+}
+
+func TestReconcileTestSuite(t *testing.T) {
+	suite.Run(t, new(ReconcileTestSuite))
+}
+
+func (s *ReconcileTestSuite) SetupTest() {
+	s.statement = Statement{
+		Transactions: []domain.Transaction{
+			{Date: time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC), Payee: "Coffee Shop", Amount: -10.50},
+			{Date: time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC), Payee: "Book Store", Amount: -20.00},
+		},
+		ClosingBalance: -30.50,
+		Date:           time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func (s *ReconcileTestSuite) TestBuild_WithAllClearedMatches_IsBalanced() {
+	// Arrange
+	account := ynab.Account{ID: "acc-1", ClearedBalance: -30500}
+	existing := []ynab.Transaction{
+		{ID: "tx-1", Date: "2026-01-10", Amount: -10500, PayeeName: "Coffee Shop", Cleared: ynab.ClearedStatusCleared},
+		{ID: "tx-2", Date: "2026-01-20", Amount: -20000, PayeeName: "Book Store", Cleared: ynab.ClearedStatusCleared},
+	}
+
+	// Act
+	report := Build(s.statement, account, existing, matcher.Options{})
+
+	// Assert
+	s.True(report.Balanced())
+	s.Equal(int64(0), report.Unexplained)
+	s.Len(report.Matched, 2)
+	s.Len(report.ReconcileUpdates(), 2)
+	_, needed := report.Adjustment()
+	s.False(needed)
+}
+
+func (s *ReconcileTestSuite) TestBuild_WithMissingAndUnclearedTransactions_ExplainsDifference() {
+	// Arrange
+	account := ynab.Account{ID: "acc-1", ClearedBalance: 0}
+	existing := []ynab.Transaction{
+		{ID: "tx-1", Date: "2026-01-10", Amount: -10500, PayeeName: "Coffee Shop", Cleared: ynab.ClearedStatusUncleared},
+	}
+
+	// Act
+	report := Build(s.statement, account, existing, matcher.Options{})
+
+	// Assert
+	s.Equal(int64(-30500), report.Difference)
+	s.Len(report.MissingInYNAB, 1)
+	s.Len(report.UnclearedMatches, 1)
+	s.Equal(int64(-30500), report.Explained)
+	s.Equal(int64(0), report.Unexplained)
+}
+
+func (s *ReconcileTestSuite) TestBuild_WithClearedTransactionNotOnStatement_ListsIt() {
+	// Arrange
+	account := ynab.Account{ID: "acc-1", ClearedBalance: -35500}
+	existing := []ynab.Transaction{
+		{ID: "tx-1", Date: "2026-01-10", Amount: -10500, PayeeName: "Coffee Shop", Cleared: ynab.ClearedStatusCleared},
+		{ID: "tx-2", Date: "2026-01-20", Amount: -20000, PayeeName: "Book Store", Cleared: ynab.ClearedStatusCleared},
+		{ID: "tx-3", Date: "2026-01-25", Amount: -5000, PayeeName: "Typo Entry", Cleared: ynab.ClearedStatusCleared},
+	}
+
+	// Act
+	report := Build(s.statement, account, existing, matcher.Options{})
+
+	// Assert
+	s.Equal(int64(5000), report.Difference)
+	s.Len(report.NotOnStatement, 1)
+	s.Equal("tx-3", report.NotOnStatement[0].ID)
+	s.Equal(int64(0), report.Unexplained)
+}
+
+func (s *ReconcileTestSuite) TestBuild_WithClearedTransactionAfterStatementDate_ExcludesItFromBalance() {
+	// Arrange
+	account := ynab.Account{ID: "acc-1", ClearedBalance: -40500}
+	existing := []ynab.Transaction{
+		{ID: "tx-1", Date: "2026-01-10", Amount: -10500, PayeeName: "Coffee Shop", Cleared: ynab.ClearedStatusCleared},
+		{ID: "tx-2", Date: "2026-01-20", Amount: -20000, PayeeName: "Book Store", Cleared: ynab.ClearedStatusCleared},
+		{ID: "tx-3", Date: "2026-02-10", Amount: -10000, PayeeName: "Next Month", Cleared: ynab.ClearedStatusCleared},
+	}
+
+	// Act
+	report := Build(s.statement, account, existing, matcher.Options{})
+
+	// Assert
+	s.Equal(int64(-30500), report.ClearedBalance)
+	s.True(report.Balanced())
+}
+
+func (s *ReconcileTestSuite) TestAdjustment_WithUnexplainedDifference_ReturnsReconciledTransaction() {
+	// Arrange
+	account := ynab.Account{ID: "acc-1", ClearedBalance: -30000}
+	existing := []ynab.Transaction{
+		{ID: "tx-1", Date: "2026-01-10", Amount: -10500, PayeeName: "Coffee Shop", Cleared: ynab.ClearedStatusCleared},
+		{ID: "tx-2", Date: "2026-01-20", Amount: -20000, PayeeName: "Book Store", Cleared: ynab.ClearedStatusCleared},
+	}
+
+	// Act
+	report := Build(s.statement, account, existing, matcher.Options{})
+	adjustment, needed := report.Adjustment()
+
+	// Assert
+	s.True(needed)
+	s.Equal(int64(-500), adjustment.Amount)
+	s.Equal("2026-02-03", adjustment.Date)
+	s.Equal(AdjustmentPayee, adjustment.PayeeName)
+	s.Equal(ynab.ClearedStatusReconciled, adjustment.Cleared)
+}
+
+func (s *ReconcileTestSuite) TestReconcileUpdates_WithAlreadyReconciled_SkipsIt() {
+	// Arrange
+	account := ynab.Account{ID: "acc-1", ClearedBalance: -30500}
+	existing := []ynab.Transaction{
+		{ID: "tx-1", Date: "2026-01-10", Amount: -10500, PayeeName: "Coffee Shop", Cleared: ynab.ClearedStatusReconciled},
+		{ID: "tx-2", Date: "2026-01-20", Amount: -20000, PayeeName: "Book Store", Cleared: ynab.ClearedStatusCleared},
+	}
+
+	// Act
+	updates := Build(s.statement, account, existing, matcher.Options{}).ReconcileUpdates()
+
+	// Assert
+	s.Len(updates, 1)
+	s.Equal("tx-2", updates[0].ID)
+	s.Equal(ynab.ClearedStatusReconciled, updates[0].Cleared)
+}
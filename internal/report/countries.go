@@ -0,0 +1,75 @@
+// Package report aggregates statement transactions and account balances for reports.
+package report
+
+import (
+	"sort"
+
+	"github.com/pgbytes/moneypenny/internal/domain"
+)
+
+// CountryTotal sums the transactions of one country.
+// Amounts are in the settlement currency.
+type CountryTotal struct {
+	// Country is the ISO 3166-1 alpha-3 code; empty for rows without a location.
+	Country string `json:"country"`
+	// Transactions is the number of transactions.
+	Transactions int `json:"transactions"`
+	// Spent is the sum of outflows as a positive amount.
+	Spent float64 `json:"spent"`
+	// Received is the sum of inflows (refunds).
+	Received float64 `json:"received"`
+	// Currencies lists the foreign currencies paid in, sorted.
+	Currencies []string `json:"currencies,omitempty"`
+}
+
+// Net returns received minus spent.
+func (c CountryTotal) Net() float64 {
+	return c.Received - c.Spent
+}
+
+// ByCountry groups transactions by merchant country, highest spending first.
+// Transactions without a country are grouped under an empty country last.
+func ByCountry(transactions []domain.Transaction) []CountryTotal {
+	byCountry := make(map[string]*CountryTotal)
+	currencies := make(map[string]map[string]bool)
+
+	for _, tx := range transactions {
+		total, ok := byCountry[tx.Country]
+		if !ok {
+			total = &CountryTotal{Country: tx.Country}
+			byCountry[tx.Country] = total
+			currencies[tx.Country] = make(map[string]bool)
+		}
+
+		total.Transactions++
+		if tx.Amount < 0 {
+			total.Spent -= tx.Amount
+		} else {
+			total.Received += tx.Amount
+		}
+		if tx.ForeignCurrency != "" {
+			currencies[tx.Country][tx.ForeignCurrency] = true
+		}
+	}
+
+	result := make([]CountryTotal, 0, len(byCountry))
+	for country, total := range byCountry {
+		for currency := range currencies[country] {
+			total.Currencies = append(total.Currencies, currency)
+		}
+		sort.Strings(total.Currencies)
+		result = append(result, *total)
+	}
+
+	sort.Slice(result, func(a, b int) bool {
+		if (result[a].Country == "") != (result[b].Country == "") {
+			return result[b].Country == ""
+		}
+		if result[a].Spent != result[b].Spent {
+			return result[a].Spent > result[b].Spent
+		}
+		return result[a].Country < result[b].Country
+	})
+
+	return result
+}
@@ -0,0 +1,54 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/stretchr/testify/suite"
+)
+
+// CountriesTestSuite groups country report tests.
+type CountriesTestSuite struct {
+	suite.Suite
+}
+
+func TestCountriesTestSuite(t *testing.T) {
+	suite.Run(t, new(CountriesTestSuite))
+}
+
+// TestByCountry_WithMixedTransactions_GroupsAndSorts tests grouping by country.
+func (s *CountriesTestSuite) TestByCountry_WithMixedTransactions_GroupsAndSorts() {
+	// Arrange
+	transactions := []domain.Transaction{
+		{Payee: "RECALL", Amount: -8.44, Country: "USA", ForeignCurrency: "USD"},
+		{Payee: "AUSLANDSEINSATZENTGELT", Amount: -0.16, Country: "USA"},
+		{Payee: "HANDYPARKEN", Amount: -6.3, Country: "DEU"},
+		{Payee: "Refund", Amount: 2, Country: "DEU"},
+		{Payee: "Lastschrift", Amount: 500},
+		{Payee: "CRAFTDOCS", Amount: -47.99, Country: "GBR"},
+	}
+
+	// Act
+	result := ByCountry(transactions)
+
+	// Assert
+	s.Require().Len(result, 4)
+	s.Equal("GBR", result[0].Country)
+	s.Equal("USA", result[1].Country)
+	s.Equal(2, result[1].Transactions)
+	s.InDelta(8.60, result[1].Spent, 0.001)
+	s.Equal([]string{"USD"}, result[1].Currencies)
+	s.Equal("DEU", result[2].Country)
+	s.InDelta(-4.3, result[2].Net(), 0.001)
+	s.Equal("", result[3].Country)
+	s.InDelta(500, result[3].Received, 0.001)
+}
+
+// TestByCountry_WithNoTransactions_ReturnsEmpty tests the empty case.
+func (s *CountriesTestSuite) TestByCountry_WithNoTransactions_ReturnsEmpty() {
+	// Act
+	result := ByCountry(nil)
+
+	// Assert
+	s.Empty(result)
+}
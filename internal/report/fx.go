@@ -0,0 +1,103 @@
+package report
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/pgbytes/moneypenny/internal/fx"
+)
+
+// FXTotal sums the conversion cost of foreign-currency transactions in one
+// month and currency. EUR amounts are absolute values; Markup and Fees are
+// positive when they cost money.
+type FXTotal struct {
+	// Month is the transaction month (YYYY-MM).
+	Month string `json:"month"`
+	// Currency is the foreign currency; empty for fees without a transaction.
+	Currency string `json:"currency"`
+	// Transactions is the number of transactions with a reference rate.
+	Transactions int `json:"transactions"`
+	// Unrated is the number of transactions without a reference rate.
+	Unrated int `json:"unrated"`
+	// ForeignAmount is the sum of foreign amounts.
+	ForeignAmount float64 `json:"foreign_amount"`
+	// Charged is the EUR amount charged by the issuer.
+	Charged float64 `json:"charged"`
+	// Fair is the EUR amount at the ECB reference rate.
+	Fair float64 `json:"fair"`
+	// Markup is the hidden conversion cost (Fair minus charged, per transaction).
+	Markup float64 `json:"markup"`
+	// Fees is the sum of foreign transaction fees.
+	Fees float64 `json:"fees"`
+}
+
+// Cost returns markup plus fees.
+func (t FXTotal) Cost() float64 {
+	return t.Markup + t.Fees
+}
+
+// CostPercent returns the cost relative to the fair amount.
+func (t FXTotal) CostPercent() float64 {
+	if t.Fair == 0 {
+		return 0
+	}
+	return t.Cost() / t.Fair * 100
+}
+
+// FX summarizes markup and foreign fees per month and currency, ordered by
+// month and currency. Fees count towards the month and currency of the
+// transaction they were charged for. Transactions without a reference rate
+// are counted as Unrated; an error is returned only for unexpected failures.
+func FX(transactions []domain.Transaction, rates *fx.Rates) ([]FXTotal, error) {
+	byKey := make(map[[2]string]*FXTotal)
+	total := func(month, currency string) *FXTotal {
+		key := [2]string{month, currency}
+		if byKey[key] == nil {
+			byKey[key] = &FXTotal{Month: month, Currency: currency}
+		}
+		return byKey[key]
+	}
+
+	for _, link := range fx.LinkFees(transactions, fx.DefaultFeeWindow) {
+		if link.Transaction == nil {
+			total(link.Fee.Date.Format("2006-01"), "").Fees -= link.Fee.Amount
+			continue
+		}
+
+		tx := *link.Transaction
+		t := total(tx.Date.Format("2006-01"), tx.ForeignCurrency)
+		if link.Fee != nil {
+			t.Fees -= link.Fee.Amount
+		}
+
+		c, err := fx.Convert(tx, rates)
+		if errors.Is(err, fx.ErrNoRate) {
+			t.Unrated++
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		t.Transactions++
+		t.ForeignAmount += math.Abs(tx.ForeignAmount)
+		t.Charged += math.Abs(tx.Amount)
+		t.Fair += math.Abs(c.FairAmount)
+		t.Markup += c.Markup
+	}
+
+	result := make([]FXTotal, 0, len(byKey))
+	for _, t := range byKey {
+		result = append(result, *t)
+	}
+	sort.Slice(result, func(a, b int) bool {
+		if result[a].Month != result[b].Month {
+			return result[a].Month < result[b].Month
+		}
+		return result[a].Currency < result[b].Currency
+	})
+
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/pgbytes/moneypenny/internal/fx"
+	"github.com/stretchr/testify/suite"
+)
+
+// FXReportTestSuite groups FX report tests.
+type FXReportTestSuite struct {
+	suite.Suite
+	rates *fx.Rates
+}
+
+func TestFXReportTestSuite(t *testing.T) {
+	suite.Run(t, new(FXReportTestSuite))
+}
+
+func (s *FXReportTestSuite) SetupTest() {
+	s.rates = fx.NewRates()
+	s.rates.Set("USD", time.Date(2026, 1, 28, 0, 0, 0, 0, time.UTC), 1.2)
+	s.rates.Set("USD", time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC), 1.25)
+}
+
+// TestFX_WithFeesAndMarkup_GroupsByMonthAndCurrency tests the aggregation.
+func (s *FXReportTestSuite) TestFX_WithFeesAndMarkup_GroupsByMonthAndCurrency() {
+	// Arrange
+	jan := func(day int) time.Time { return time.Date(2026, 1, day, 0, 0, 0, 0, time.UTC) }
+	transactions := []domain.Transaction{
+		{Payee: fx.ForeignFeePayee, Date: jan(29), Amount: -0.15},
+		{Payee: "RECALL", Date: jan(28), PostingDate: jan(29), Amount: -8.44, ForeignAmount: -10, ForeignCurrency: "USD"},
+		{Payee: "DINER", Date: time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC), Amount: -16.2, ForeignAmount: -20, ForeignCurrency: "USD"},
+		{Payee: "HOTEL", Date: jan(28), Amount: -90, ForeignAmount: -100, ForeignCurrency: "CHF"},
+		{Payee: "REWE", Date: jan(27), Amount: -12},
+	}
+
+	// Act
+	result, err := FX(transactions, s.rates)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(result, 3)
+
+	s.Equal("2026-01", result[0].Month)
+	s.Equal("CHF", result[0].Currency)
+	s.Equal(1, result[0].Unrated)
+	s.Equal(0, result[0].Transactions)
+
+	s.Equal("USD", result[1].Currency)
+	s.Equal(1, result[1].Transactions)
+	s.InDelta(8.33, result[1].Fair, 0.001)
+	s.InDelta(0.11, result[1].Markup, 0.001)
+	s.InDelta(0.15, result[1].Fees, 0.001)
+	s.InDelta(0.26/8.33*100, result[1].CostPercent(), 0.001)
+
+	s.Equal("2026-02", result[2].Month)
+	s.InDelta(0.2, result[2].Markup, 0.001)
+	s.Zero(result[2].Fees)
+}
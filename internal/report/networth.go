@@ -0,0 +1,87 @@
+package report
+
+import (
+	"sort"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+)
+
+// NetWorthGroup sums the balances of the open accounts of one type.
+// Amounts are in milliunits; liabilities are negative.
+type NetWorthGroup struct {
+	// Type is the YNAB account type (e.g. checking, creditCard).
+	Type string `json:"type"`
+	// Liability reports whether the type holds debt.
+	Liability bool `json:"liability"`
+	// Accounts is the number of accounts.
+	Accounts int `json:"accounts"`
+	// Balance is the sum of the account balances.
+	Balance int64 `json:"balance"`
+}
+
+// NetWorthSummary groups account balances into assets and liabilities.
+type NetWorthSummary struct {
+	// Groups lists assets first, then liabilities, each in account type order.
+	Groups []NetWorthGroup `json:"groups"`
+	// Assets is the sum of all asset balances.
+	Assets int64 `json:"assets"`
+	// Liabilities is the sum of all liability balances (zero or negative).
+	Liabilities int64 `json:"liabilities"`
+	// NetWorth is assets plus liabilities.
+	NetWorth int64 `json:"net_worth"`
+}
+
+// NetWorth sums the balances of open accounts by type. Closed and deleted
+// accounts are ignored; tracking (off-budget) accounts are included.
+func NetWorth(accounts []ynab.Account) NetWorthSummary {
+	byType := make(map[string]*NetWorthGroup)
+
+	var summary NetWorthSummary
+	for _, acc := range accounts {
+		if acc.Closed || acc.Deleted {
+			continue
+		}
+
+		group, ok := byType[acc.Type]
+		if !ok {
+			group = &NetWorthGroup{Type: acc.Type, Liability: ynab.IsLiabilityType(acc.Type)}
+			byType[acc.Type] = group
+		}
+		group.Accounts++
+		group.Balance += acc.Balance
+
+		if group.Liability {
+			summary.Liabilities += acc.Balance
+		} else {
+			summary.Assets += acc.Balance
+		}
+	}
+	summary.NetWorth = summary.Assets + summary.Liabilities
+
+	order := make(map[string]int, len(ynab.AccountTypes))
+	for i, t := range ynab.AccountTypes {
+		order[t] = i
+	}
+
+	summary.Groups = make([]NetWorthGroup, 0, len(byType))
+	for _, group := range byType {
+		summary.Groups = append(summary.Groups, *group)
+	}
+	sort.Slice(summary.Groups, func(a, b int) bool {
+		ga, gb := summary.Groups[a], summary.Groups[b]
+		if ga.Liability != gb.Liability {
+			return gb.Liability
+		}
+		oa, okA := order[ga.Type]
+		ob, okB := order[gb.Type]
+		if okA != okB {
+			return okA
+		}
+		if oa != ob {
+			return oa < ob
+		}
+		return ga.Type < gb.Type
+	})
+
+	return summary
+}
@@ -0,0 +1,57 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/stretchr/testify/suite"
+)
+
+// NetWorthTestSuite groups net worth tests.
+type NetWorthTestSuite struct {
+	suite.Suite
+}
+
+func TestNetWorthTestSuite(t *testing.T) {
+	suite.Run(t, new(NetWorthTestSuite))
+}
+
+// TestNetWorth_WithMixedAccounts_GroupsAssetsAndLiabilities tests grouping by account type.
+func (s *NetWorthTestSuite) TestNetWorth_WithMixedAccounts_GroupsAssetsAndLiabilities() {
+	// Arrange
+	accounts := []ynab.Account{
+		{Name: "Miles & More", Type: ynab.AccountTypeCreditCard, Balance: -450000},
+		{Name: "Checking", Type: ynab.AccountTypeChecking, Balance: 2500000},
+		{Name: "Savings", Type: ynab.AccountTypeSavings, Balance: 10000000, OnBudget: false},
+		{Name: "Second Checking", Type: ynab.AccountTypeChecking, Balance: 500000},
+		{Name: "Car", Type: ynab.AccountTypeAutoLoan, Balance: -8000000},
+		{Name: "Old Card", Type: ynab.AccountTypeCreditCard, Balance: -100000, Closed: true},
+		{Name: "Gone", Type: ynab.AccountTypeCash, Balance: 100000, Deleted: true},
+	}
+
+	// Act
+	summary := NetWorth(accounts)
+
+	// Assert
+	s.Require().Len(summary.Groups, 4)
+	s.Equal(ynab.AccountTypeChecking, summary.Groups[0].Type)
+	s.Equal(2, summary.Groups[0].Accounts)
+	s.Equal(int64(3000000), summary.Groups[0].Balance)
+	s.Equal(ynab.AccountTypeSavings, summary.Groups[1].Type)
+	s.Equal(ynab.AccountTypeCreditCard, summary.Groups[2].Type)
+	s.True(summary.Groups[2].Liability)
+	s.Equal(ynab.AccountTypeAutoLoan, summary.Groups[3].Type)
+	s.Equal(int64(13000000), summary.Assets)
+	s.Equal(int64(-8450000), summary.Liabilities)
+	s.Equal(int64(4550000), summary.NetWorth)
+}
+
+// TestNetWorth_WithNoAccounts_ReturnsZero tests the empty summary.
+func (s *NetWorthTestSuite) TestNetWorth_WithNoAccounts_ReturnsZero() {
+	// Act
+	summary := NetWorth(nil)
+
+	// Assert
+	s.Empty(summary.Groups)
+	s.Zero(summary.NetWorth)
+}
@@ -0,0 +1,181 @@
+// Package review collects the decisions made while walking through unapproved
+// or uncategorized YNAB transactions, so they can be sent back in bulk.
+package review
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+)
+
+// DefaultBatchSize is the number of transactions sent per bulk update request.
+const DefaultBatchSize = 100
+
+// Edit is the set of pending changes for one transaction.
+type Edit struct {
+	// Transaction is the transaction as it was fetched.
+	Transaction ynab.Transaction
+	// Approve marks the transaction as approved.
+	Approve bool
+	// Category is the newly assigned category (nil if unchanged).
+	Category *ynab.Category
+	// PayeeName is the new payee name (empty if unchanged).
+	PayeeName string
+	// FlagColor is the new flag color (empty if unchanged).
+	FlagColor string
+}
+
+// Summary counts the decisions of a review session.
+type Summary struct {
+	Reviewed    int
+	Approved    int
+	Categorized int
+	Renamed     int
+	Flagged     int
+	Skipped     int
+}
+
+// Session tracks the pending edits of a review.
+// Edits are kept in the order transactions were first changed.
+type Session struct {
+	edits   map[string]*Edit
+	order   []string
+	skipped map[string]bool
+}
+
+// NewSession creates an empty review session.
+func NewSession() *Session {
+	return &Session{
+		edits:   make(map[string]*Edit),
+		skipped: make(map[string]bool),
+	}
+}
+
+// edit returns the pending edit for t, creating it if needed.
+func (s *Session) edit(t ynab.Transaction) *Edit {
+	if e, ok := s.edits[t.ID]; ok {
+		return e
+	}
+	e := &Edit{Transaction: t}
+	s.edits[t.ID] = e
+	s.order = append(s.order, t.ID)
+	delete(s.skipped, t.ID)
+	return e
+}
+
+// Approve marks t as approved.
+func (s *Session) Approve(t ynab.Transaction) {
+	s.edit(t).Approve = true
+}
+
+// SetCategory assigns category to t.
+func (s *Session) SetCategory(t ynab.Transaction, category ynab.Category) {
+	s.edit(t).Category = &category
+}
+
+// RenamePayee sets the payee name of t.
+func (s *Session) RenamePayee(t ynab.Transaction, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("payee name must not be empty")
+	}
+	s.edit(t).PayeeName = name
+	return nil
+}
+
+// Flag sets the flag color of t.
+func (s *Session) Flag(t ynab.Transaction, color string) error {
+	color = strings.ToLower(strings.TrimSpace(color))
+	for _, valid := range ynab.FlagColors {
+		if color == valid {
+			s.edit(t).FlagColor = color
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid flag color %q, use one of %s", color, strings.Join(ynab.FlagColors, ", "))
+}
+
+// Skip records that t was looked at without changes.
+// Transactions with pending edits are not counted as skipped.
+func (s *Session) Skip(t ynab.Transaction) {
+	if _, ok := s.edits[t.ID]; !ok {
+		s.skipped[t.ID] = true
+	}
+}
+
+// Pending returns the pending edit for the transaction with the given ID, if any.
+func (s *Session) Pending(id string) (*Edit, bool) {
+	e, ok := s.edits[id]
+	return e, ok
+}
+
+// Edits returns the pending edits in the order they were made.
+func (s *Session) Edits() []Edit {
+	edits := make([]Edit, 0, len(s.order))
+	for _, id := range s.order {
+		edits = append(edits, *s.edits[id])
+	}
+	return edits
+}
+
+// Updates converts the pending edits into bulk update requests.
+func (s *Session) Updates() []ynab.SaveTransactionWithID {
+	updates := make([]ynab.SaveTransactionWithID, 0, len(s.order))
+	for _, e := range s.Edits() {
+		update := ynab.SaveTransactionWithID{ID: e.Transaction.ID}
+		if e.Approve {
+			update.Approved = ynab.Ptr(true)
+		}
+		if e.Category != nil {
+			update.CategoryID = ynab.Ptr(e.Category.ID)
+		}
+		if e.PayeeName != "" {
+			update.PayeeID = ynab.Ptr("")
+			update.PayeeName = ynab.Ptr(e.PayeeName)
+		}
+		if e.FlagColor != "" {
+			update.FlagColor = ynab.Ptr(e.FlagColor)
+		}
+		updates = append(updates, update)
+	}
+	return updates
+}
+
+// Summary counts the decisions made so far.
+func (s *Session) Summary() Summary {
+	sum := Summary{Skipped: len(s.skipped)}
+	for _, e := range s.edits {
+		if e.Approve {
+			sum.Approved++
+		}
+		if e.Category != nil {
+			sum.Categorized++
+		}
+		if e.PayeeName != "" {
+			sum.Renamed++
+		}
+		if e.FlagColor != "" {
+			sum.Flagged++
+		}
+	}
+	sum.Reviewed = len(s.edits) + sum.Skipped
+	return sum
+}
+
+// Batches splits updates into chunks of at most size transactions.
+func Batches(updates []ynab.SaveTransactionWithID, size int) [][]ynab.SaveTransactionWithID {
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+
+	var batches [][]ynab.SaveTransactionWithID
+	for start := 0; start < len(updates); start += size {
+		end := start + size
+		if end > len(updates) {
+			end = len(updates)
+		}
+		batches = append(batches, updates[start:end])
+	}
+	return batches
+}
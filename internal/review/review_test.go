@@ -0,0 +1,148 @@
+package review
+
+import (
+	"testing"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/stretchr/testify/suite"
+)
+
+// ReviewTestSuite groups all review session tests.
+type ReviewTestSuite struct {
+	suite.Suite
+	tx1 ynab.Transaction
+	tx2 ynab.Transaction
+}
+
+func TestReviewTestSuite(t *testing.T) {
+	suite.Run(t, new(ReviewTestSuite))
+}
+
+func (s *ReviewTestSuite) SetupTest() {
+	s.tx1 = ynab.Transaction{ID: "tx-1", AccountID: "acc-1", Date: "2026-01-10", Amount: -10500, PayeeID: "payee-1", PayeeName: "REWE 1234"}
+	s.tx2 = ynab.Transaction{ID: "tx-2", AccountID: "acc-1", Date: "2026-01-11", Amount: -4000, PayeeName: "Coffee"}
+}
+
+func (s *ReviewTestSuite) TestUpdates_AppliesAllEditsToTransaction() {
+	// Arrange
+	session := NewSession()
+	session.Approve(s.tx1)
+	session.SetCategory(s.tx1, ynab.Category{ID: "cat-1", Name: "Groceries"})
+	s.Require().NoError(session.RenamePayee(s.tx1, " REWE "))
+	s.Require().NoError(session.Flag(s.tx1, "Red"))
+
+	// Act
+	updates := session.Updates()
+
+	// Assert
+	s.Require().Len(updates, 1)
+	u := updates[0]
+	s.Equal("tx-1", u.ID)
+	s.Equal(ynab.Ptr(true), u.Approved)
+	s.Equal(ynab.Ptr("cat-1"), u.CategoryID)
+	s.Equal(ynab.Ptr(""), u.PayeeID)
+	s.Equal(ynab.Ptr("REWE"), u.PayeeName)
+	s.Equal(ynab.Ptr("red"), u.FlagColor)
+	s.Nil(u.Amount, "unchanged fields are not sent")
+	s.Nil(u.Memo)
+}
+
+func (s *ReviewTestSuite) TestUpdates_KeepsOrderOfFirstEdit() {
+	// Arrange
+	session := NewSession()
+	session.Approve(s.tx2)
+	session.Approve(s.tx1)
+	session.SetCategory(s.tx2, ynab.Category{ID: "cat-2"})
+
+	// Act
+	updates := session.Updates()
+
+	// Assert
+	s.Require().Len(updates, 2)
+	s.Equal("tx-2", updates[0].ID)
+	s.Equal(ynab.Ptr("cat-2"), updates[0].CategoryID)
+	s.Equal("tx-1", updates[1].ID)
+}
+
+func (s *ReviewTestSuite) TestFlag_WithInvalidColor_ReturnsError() {
+	// Arrange
+	session := NewSession()
+
+	// Act
+	err := session.Flag(s.tx1, "pink")
+
+	// Assert
+	s.Error(err)
+	s.Empty(session.Updates())
+}
+
+func (s *ReviewTestSuite) TestRenamePayee_WithEmptyName_ReturnsError() {
+	// Arrange
+	session := NewSession()
+
+	// Act
+	err := session.RenamePayee(s.tx1, "  ")
+
+	// Assert
+	s.Error(err)
+	s.Empty(session.Updates())
+}
+
+func (s *ReviewTestSuite) TestSummary_CountsDecisions() {
+	// Arrange
+	session := NewSession()
+	session.Approve(s.tx1)
+	session.SetCategory(s.tx1, ynab.Category{ID: "cat-1"})
+	session.Skip(s.tx2)
+	session.Skip(s.tx1)
+
+	// Act
+	summary := session.Summary()
+
+	// Assert
+	s.Equal(Summary{Reviewed: 2, Approved: 1, Categorized: 1, Skipped: 1}, summary)
+}
+
+func (s *ReviewTestSuite) TestSummary_EditAfterSkip_IsNotSkipped() {
+	// Arrange
+	session := NewSession()
+	session.Skip(s.tx1)
+	session.Approve(s.tx1)
+
+	// Act
+	summary := session.Summary()
+
+	// Assert
+	s.Equal(0, summary.Skipped)
+	s.Equal(1, summary.Approved)
+	s.Equal(1, summary.Reviewed)
+}
+
+func (s *ReviewTestSuite) TestBatches_SplitsUpdates() {
+	// Arrange
+	updates := make([]ynab.SaveTransactionWithID, 5)
+
+	tests := []struct {
+		name     string
+		size     int
+		expected []int
+	}{
+		{name: "exact multiple", size: 5, expected: []int{5}},
+		{name: "remainder", size: 2, expected: []int{2, 2, 1}},
+		{name: "default size", size: 0, expected: []int{5}},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			batches := Batches(updates, tt.size)
+
+			// Assert
+			var sizes []int
+			for _, b := range batches {
+				sizes = append(sizes, len(b))
+			}
+			s.Equal(tt.expected, sizes)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/domain"
+)
+
+// ResolveCategory finds the category referenced by ref: a category ID,
+// "Group: Category" or a category name that is unique across groups.
+func ResolveCategory(categories []ynab.Category, ref string) (*ynab.Category, error) {
+	ref = strings.TrimSpace(ref)
+
+	for i := range categories {
+		if categories[i].ID == ref {
+			return &categories[i], nil
+		}
+	}
+
+	group, name := "", ref
+	if idx := strings.Index(ref, ":"); idx >= 0 {
+		group, name = strings.TrimSpace(ref[:idx]), strings.TrimSpace(ref[idx+1:])
+	}
+
+	var found *ynab.Category
+	for i := range categories {
+		c := &categories[i]
+		if !strings.EqualFold(c.Name, name) {
+			continue
+		}
+		if group != "" && !strings.EqualFold(c.CategoryGroupName, group) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("category %q is not unique, use \"Group: Category\"", ref)
+		}
+		found = c
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("%w: category %q", ynab.ErrNotFound, ref)
+	}
+
+	return found, nil
+}
+
+// ResolveCategories replaces the category references of the transactions and
+// their splits with YNAB category IDs.
+func ResolveCategories(transactions []domain.Transaction, categories []ynab.Category) error {
+	resolve := func(ref string) (string, error) {
+		if ref == "" {
+			return "", nil
+		}
+		c, err := ResolveCategory(categories, ref)
+		if err != nil {
+			return "", err
+		}
+		return c.ID, nil
+	}
+
+	for i := range transactions {
+		tx := &transactions[i]
+
+		id, err := resolve(tx.Category)
+		if err != nil {
+			return err
+		}
+		tx.Category = id
+
+		for j := range tx.Splits {
+			id, err := resolve(tx.Splits[j].Category)
+			if err != nil {
+				return err
+			}
+			tx.Splits[j].Category = id
+		}
+	}
+
+	return nil
+}
+
+// NeedsCategories reports whether any transaction or split references a category.
+func NeedsCategories(transactions []domain.Transaction) bool {
+	for _, tx := range transactions {
+		if tx.Category != "" {
+			return true
+		}
+		for _, s := range tx.Splits {
+			if s.Category != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
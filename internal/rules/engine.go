@@ -0,0 +1,336 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/domain"
+)
+
+// weekdays maps accepted weekday spellings to time.Weekday.
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// Engine applies compiled rules to transactions.
+type Engine struct {
+	rules []compiledRule
+}
+
+// compiledRule is a validated rule with its regular expression and weekdays resolved.
+type compiledRule struct {
+	Rule
+	payee    *regexp.Regexp
+	weekdays map[time.Weekday]bool
+}
+
+// Outcome is the result of applying the rules to one transaction.
+type Outcome struct {
+	// Original is the transaction before any rule was applied.
+	Original domain.Transaction `json:"original"`
+	// Transaction is the transaction after all fired rules were applied.
+	Transaction domain.Transaction `json:"transaction"`
+	// Fired lists the names of the rules that matched, in order.
+	Fired []string `json:"fired"`
+	// Skipped reports whether a rule dropped the transaction.
+	Skipped bool `json:"skipped"`
+}
+
+// Count returns how many outcomes were changed by a rule and how many were skipped.
+func Count(outcomes []Outcome) (changed, skipped int) {
+	for _, o := range outcomes {
+		switch {
+		case o.Skipped:
+			skipped++
+		case len(o.Fired) > 0:
+			changed++
+		}
+	}
+	return changed, skipped
+}
+
+// New validates and compiles rules into an engine.
+func New(rules []Rule) (*Engine, error) {
+	e := &Engine{rules: make([]compiledRule, 0, len(rules))}
+
+	for i, r := range rules {
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rule-%d", i+1)
+		}
+
+		c, err := compile(r)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+		e.rules = append(e.rules, c)
+	}
+
+	return e, nil
+}
+
+// Len returns the number of rules.
+func (e *Engine) Len() int {
+	return len(e.rules)
+}
+
+// compile validates a rule and prepares its conditions.
+func compile(r Rule) (compiledRule, error) {
+	c := compiledRule{Rule: r}
+
+	if r.When.Payee != "" {
+		re, err := regexp.Compile(r.When.Payee)
+		if err != nil {
+			return c, fmt.Errorf("invalid payee pattern: %w", err)
+		}
+		c.payee = re
+	}
+
+	if r.When.AmountMin != nil && r.When.AmountMax != nil && *r.When.AmountMin > *r.When.AmountMax {
+		return c, fmt.Errorf("amount_min is greater than amount_max")
+	}
+
+	if len(r.When.Weekdays) > 0 {
+		c.weekdays = make(map[time.Weekday]bool)
+		for _, name := range r.When.Weekdays {
+			day, ok := weekdays[strings.ToLower(strings.TrimSpace(name))]
+			if !ok {
+				return c, fmt.Errorf("invalid weekday %q", name)
+			}
+			c.weekdays[day] = true
+		}
+	}
+
+	if r.Then.Flag != "" && !validFlag(r.Then.Flag) {
+		return c, fmt.Errorf("invalid flag color %q, use one of %s", r.Then.Flag, strings.Join(ynab.FlagColors, ", "))
+	}
+
+	if err := validateSplit(r.Then.Split); err != nil {
+		return c, err
+	}
+
+	if r.Then.Skip && (r.Then.Payee != "" || r.Then.Category != "" || r.Then.Memo != "" || r.Then.Flag != "" || len(r.Then.Split) > 0) {
+		return c, fmt.Errorf("skip cannot be combined with other actions")
+	}
+
+	return c, nil
+}
+
+// validateSplit checks that split parts are well-formed.
+func validateSplit(parts []SplitAction) error {
+	if len(parts) == 1 {
+		return fmt.Errorf("split needs at least two parts")
+	}
+
+	remainders := 0
+	percent := 0.0
+	for i, p := range parts {
+		switch {
+		case p.Amount != nil && p.Percent != nil:
+			return fmt.Errorf("split part %d: set either amount or percent", i+1)
+		case p.Amount != nil:
+			if *p.Amount <= 0 {
+				return fmt.Errorf("split part %d: amount must be positive", i+1)
+			}
+		case p.Percent != nil:
+			if *p.Percent <= 0 || *p.Percent > 100 {
+				return fmt.Errorf("split part %d: percent must be between 0 and 100", i+1)
+			}
+			percent += *p.Percent
+		default:
+			remainders++
+		}
+	}
+
+	if remainders > 1 {
+		return fmt.Errorf("split: only one part may take the remainder")
+	}
+	if percent > 100 {
+		return fmt.Errorf("split: percentages add up to more than 100")
+	}
+
+	return nil
+}
+
+// Apply runs the rules over a single transaction from the given source.
+func (e *Engine) Apply(source string, tx domain.Transaction) (Outcome, error) {
+	out := Outcome{Original: tx, Transaction: tx}
+
+	for _, r := range e.rules {
+		if !r.matches(source, out.Transaction) {
+			continue
+		}
+
+		out.Fired = append(out.Fired, r.Name)
+
+		if r.Then.Skip {
+			out.Skipped = true
+			return out, nil
+		}
+
+		if err := r.apply(&out.Transaction); err != nil {
+			return out, fmt.Errorf("rule %s on %q: %w", r.Name, tx.Payee, err)
+		}
+
+		if r.Stop {
+			break
+		}
+	}
+
+	return out, nil
+}
+
+// ApplyAll runs the rules over every transaction. It returns the transactions
+// that were not skipped together with the outcome of every transaction.
+func (e *Engine) ApplyAll(source string, transactions []domain.Transaction) ([]domain.Transaction, []Outcome, error) {
+	kept := make([]domain.Transaction, 0, len(transactions))
+	outcomes := make([]Outcome, 0, len(transactions))
+
+	for _, tx := range transactions {
+		out, err := e.Apply(source, tx)
+		if err != nil {
+			return nil, nil, err
+		}
+		outcomes = append(outcomes, out)
+		if !out.Skipped {
+			kept = append(kept, out.Transaction)
+		}
+	}
+
+	return kept, outcomes, nil
+}
+
+// matches reports whether all conditions of the rule hold for tx.
+func (r compiledRule) matches(source string, tx domain.Transaction) bool {
+	w := r.When
+
+	if r.payee != nil && !r.payee.MatchString(tx.Payee) {
+		return false
+	}
+	if w.AmountMin != nil && tx.Amount < *w.AmountMin {
+		return false
+	}
+	if w.AmountMax != nil && tx.Amount > *w.AmountMax {
+		return false
+	}
+	if w.Currency != "" && !strings.EqualFold(w.Currency, tx.Currency) && !strings.EqualFold(w.Currency, tx.ForeignCurrency) {
+		return false
+	}
+	if w.Source != "" && !strings.EqualFold(w.Source, source) {
+		return false
+	}
+	if r.weekdays != nil && !r.weekdays[tx.Date.Weekday()] {
+		return false
+	}
+
+	return true
+}
+
+// apply performs the rule's actions on tx.
+func (r compiledRule) apply(tx *domain.Transaction) error {
+	a := r.Then
+
+	if a.Payee != "" {
+		tx.Payee = r.expandPayee(tx.Payee)
+	}
+	if a.Category != "" {
+		tx.Category = a.Category
+	}
+	if a.Memo != "" {
+		tx.Memo = a.Memo
+	}
+	if a.Flag != "" {
+		tx.FlagColor = strings.ToLower(a.Flag)
+	}
+	if len(a.Split) > 0 {
+		splits, err := split(tx.Amount, a.Split)
+		if err != nil {
+			return err
+		}
+		tx.Splits = splits
+	}
+
+	return nil
+}
+
+// expandPayee builds the new payee, replacing $1 etc. with groups of the payee condition.
+func (r compiledRule) expandPayee(payee string) string {
+	if r.payee == nil {
+		return r.Then.Payee
+	}
+
+	match := r.payee.FindStringSubmatchIndex(payee)
+	if match == nil {
+		return r.Then.Payee
+	}
+
+	return strings.TrimSpace(string(r.payee.ExpandString(nil, r.Then.Payee, payee, match)))
+}
+
+// split divides amount into parts. Amounts are rounded to cents and the
+// remainder part, if any, absorbs rounding differences.
+func split(amount float64, parts []SplitAction) ([]domain.Split, error) {
+	sign := 1.0
+	if amount < 0 {
+		sign = -1.0
+	}
+	total := math.Abs(amount)
+
+	splits := make([]domain.Split, len(parts))
+	remainderIndex := -1
+	assigned := 0.0
+
+	for i, p := range parts {
+		splits[i] = domain.Split{Payee: p.Payee, Category: p.Category, Memo: p.Memo}
+		switch {
+		case p.Amount != nil:
+			splits[i].Amount = roundCents(*p.Amount)
+		case p.Percent != nil:
+			splits[i].Amount = roundCents(total * *p.Percent / 100)
+		default:
+			remainderIndex = i
+			continue
+		}
+		assigned += splits[i].Amount
+	}
+
+	rest := roundCents(total - assigned)
+	switch {
+	case rest < 0:
+		return nil, fmt.Errorf("split parts exceed the amount %.2f", amount)
+	case remainderIndex >= 0:
+		splits[remainderIndex].Amount = rest
+	case rest != 0:
+		return nil, fmt.Errorf("split parts add up to %.2f, not %.2f", assigned, total)
+	}
+
+	for i := range splits {
+		splits[i].Amount *= sign
+	}
+
+	return splits, nil
+}
+
+// roundCents rounds to two decimal places.
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// validFlag reports whether color is an accepted YNAB flag color.
+func validFlag(color string) bool {
+	for _, c := range ynab.FlagColors {
+		if strings.EqualFold(c, color) {
+			return true
+		}
+	}
+	return false
+}
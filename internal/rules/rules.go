@@ -0,0 +1,164 @@
+// Package rules cleans up and categorizes parsed statement transactions using
+// user-defined rules loaded from a YAML or JSON file.
+//
+// Rules are applied in file order. Every rule whose conditions match applies
+// its actions; later rules see the changes of earlier ones. A rule with
+// "stop: true" ends processing for the transaction, and a "skip" action drops
+// the transaction entirely.
+//
+// Example rules.yaml:
+//
+//	rules:
+//	  - name: paypal-merchant
+//	    when:
+//	      payee: '^PAYPAL \*(\w+)'
+//	    then:
+//	      payee: 'PayPal $1'
+//	  - name: groceries
+//	    when:
+//	      payee: '(?i)rewe|edeka'
+//	      amount_max: 0
+//	    then:
+//	      category: 'Everyday: Groceries'
+//	    stop: true
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileNames are the rule file names looked up next to the config file, in order.
+var FileNames = []string{"rules.yaml", "rules.yml", "rules.json"}
+
+// File is the on-disk representation of a rules file.
+type File struct {
+	Rules []Rule `yaml:"rules" json:"rules"`
+}
+
+// Rule is a set of conditions and the actions applied when all of them match.
+type Rule struct {
+	// Name identifies the rule in output; defaults to "rule-<n>".
+	Name string `yaml:"name" json:"name"`
+	// When lists the conditions; an empty condition set matches every transaction.
+	When Conditions `yaml:"when" json:"when"`
+	// Then lists the actions.
+	Then Actions `yaml:"then" json:"then"`
+	// Stop ends rule processing for a transaction after this rule fired.
+	Stop bool `yaml:"stop" json:"stop"`
+}
+
+// Conditions restrict which transactions a rule applies to. All set conditions must match.
+type Conditions struct {
+	// Payee is a regular expression matched against the payee.
+	Payee string `yaml:"payee" json:"payee"`
+	// AmountMin is the smallest matching signed amount (outflows are negative).
+	AmountMin *float64 `yaml:"amount_min" json:"amount_min"`
+	// AmountMax is the largest matching signed amount.
+	AmountMax *float64 `yaml:"amount_max" json:"amount_max"`
+	// Currency matches the settlement or the foreign currency (case-insensitive).
+	Currency string `yaml:"currency" json:"currency"`
+	// Source matches the statement format, e.g. "milesmore".
+	Source string `yaml:"source" json:"source"`
+	// Weekdays matches the transaction weekday, e.g. ["sat", "sunday"].
+	Weekdays []string `yaml:"weekdays" json:"weekdays"`
+}
+
+// Actions change a matching transaction.
+type Actions struct {
+	// Payee renames the payee. $1 etc. refer to groups of the payee condition.
+	Payee string `yaml:"payee" json:"payee"`
+	// Category sets the category (YNAB category ID or name, optionally "Group: Category").
+	Category string `yaml:"category" json:"category"`
+	// Memo sets the memo.
+	Memo string `yaml:"memo" json:"memo"`
+	// Flag sets the flag color.
+	Flag string `yaml:"flag" json:"flag"`
+	// Split divides the transaction into parts.
+	Split []SplitAction `yaml:"split" json:"split"`
+	// Skip drops the transaction.
+	Skip bool `yaml:"skip" json:"skip"`
+}
+
+// SplitAction describes one part of a split. Exactly one part may leave both
+// Amount and Percent unset to receive the remainder.
+type SplitAction struct {
+	// Amount is the absolute amount of this part; the sign follows the transaction.
+	Amount *float64 `yaml:"amount" json:"amount"`
+	// Percent is the share of the transaction amount (0-100).
+	Percent *float64 `yaml:"percent" json:"percent"`
+	// Payee overrides the payee for this part.
+	Payee string `yaml:"payee" json:"payee"`
+	// Category sets the category for this part.
+	Category string `yaml:"category" json:"category"`
+	// Memo sets the memo for this part.
+	Memo string `yaml:"memo" json:"memo"`
+}
+
+// FindFile returns the first rules file present in dir.
+func FindFile(dir string) (string, bool) {
+	for _, name := range FileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// LoadFor loads the engine from rulesPath, or from the rules file next to
+// configPath when rulesPath is empty. It returns nil when there is no rules file.
+func LoadFor(rulesPath, configPath string) (*Engine, error) {
+	if rulesPath == "" && configPath != "" {
+		path, ok := FindFile(filepath.Dir(configPath))
+		if !ok {
+			return nil, nil
+		}
+		rulesPath = path
+	}
+	if rulesPath == "" {
+		return nil, nil
+	}
+	return Load(rulesPath)
+}
+
+// LoadFile reads and parses a rules file. The format is chosen by extension:
+// .json for JSON, anything else for YAML.
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading rules file: %w", err)
+	}
+
+	var f File
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &f)
+	} else {
+		err = yaml.Unmarshal(data, &f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing rules file %s: %w", path, err)
+	}
+
+	return &f, nil
+}
+
+// Load reads a rules file and compiles it into an engine.
+func Load(path string) (*Engine, error) {
+	f, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	engine, err := New(f.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("rules file %s: %w", path, err)
+	}
+
+	return engine, nil
+}
@@ -0,0 +1,309 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/stretchr/testify/suite"
+)
+
+// RulesTestSuite groups all rules engine tests.
+type RulesTestSuite struct {
+	suite.Suite
+	tx domain.Transaction
+}
+
+func TestRulesTestSuite(t *testing.T) {
+	suite.Run(t, new(RulesTestSuite))
+}
+
+func (s *RulesTestSuite) SetupTest() {
+	s.tx = domain.Transaction{
+		// A Tuesday
+		Date:     time.Date(2026, 1, 27, 0, 0, 0, 0, time.UTC),
+		Payee:    "PAYPAL *CRAFTDOCSLT, EC4A3BF 35314369001, GBR, GBR",
+		Amount:   -47.99,
+		Currency: "EUR",
+	}
+}
+
+func (s *RulesTestSuite) TestLoad_WithYAMLFile_ParsesRules() {
+	// Act
+	engine, err := Load("testdata/rules.yaml")
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(2, engine.Len())
+}
+
+func (s *RulesTestSuite) TestLoad_WithJSONFile_ParsesRules() {
+	// Act
+	engine, err := Load("testdata/rules.json")
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(1, engine.Len())
+}
+
+func (s *RulesTestSuite) TestFindFile_ReturnsFirstExistingFile() {
+	// Arrange
+	dir := s.T().TempDir()
+	s.Require().NoError(os.WriteFile(filepath.Join(dir, "rules.json"), []byte(`{"rules":[]}`), 0o600))
+
+	// Act
+	path, ok := FindFile(dir)
+	_, missing := FindFile(filepath.Join(dir, "none"))
+
+	// Assert
+	s.True(ok)
+	s.Equal(filepath.Join(dir, "rules.json"), path)
+	s.False(missing)
+}
+
+func (s *RulesTestSuite) TestApply_WithPayeeGroup_RenamesPayee() {
+	// Arrange
+	engine, err := Load("testdata/rules.yaml")
+	s.Require().NoError(err)
+
+	// Act
+	out, err := engine.Apply("milesmore", s.tx)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal("PayPal CRAFTDOCSLT", out.Transaction.Payee)
+	s.Equal([]string{"paypal-merchant"}, out.Fired)
+	s.Equal(s.tx.Payee, out.Original.Payee)
+}
+
+func (s *RulesTestSuite) TestApply_Conditions() {
+	// Arrange
+	minus50, minus40, minus10 := -50.0, -40.0, -10.0
+
+	tests := []struct {
+		name     string
+		when     Conditions
+		expected bool
+	}{
+		{name: "empty matches all", when: Conditions{}, expected: true},
+		{name: "amount in range", when: Conditions{AmountMin: &minus50, AmountMax: &minus40}, expected: true},
+		{name: "amount above range", when: Conditions{AmountMax: &minus50}, expected: false},
+		{name: "amount below range", when: Conditions{AmountMin: &minus10}, expected: false},
+		{name: "currency", when: Conditions{Currency: "eur"}, expected: true},
+		{name: "other currency", when: Conditions{Currency: "USD"}, expected: false},
+		{name: "source", when: Conditions{Source: "milesmore"}, expected: true},
+		{name: "other source", when: Conditions{Source: "sparkasse"}, expected: false},
+		{name: "weekday", when: Conditions{Weekdays: []string{"Tue", "friday"}}, expected: true},
+		{name: "other weekday", when: Conditions{Weekdays: []string{"sat", "sun"}}, expected: false},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			engine, err := New([]Rule{{Name: "r", When: tt.when, Then: Actions{Memo: "hit"}}})
+			s.Require().NoError(err)
+
+			// Act
+			out, err := engine.Apply("milesmore", s.tx)
+
+			// Assert
+			s.Require().NoError(err)
+			s.Equal(tt.expected, len(out.Fired) == 1)
+		})
+	}
+}
+
+func (s *RulesTestSuite) TestApply_WithStop_SkipsLaterRules() {
+	// Arrange
+	engine, err := New([]Rule{
+		{Name: "first", Then: Actions{Category: "Shopping"}, Stop: true},
+		{Name: "second", Then: Actions{Category: "Other"}},
+	})
+	s.Require().NoError(err)
+
+	// Act
+	out, err := engine.Apply("milesmore", s.tx)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal([]string{"first"}, out.Fired)
+	s.Equal("Shopping", out.Transaction.Category)
+}
+
+func (s *RulesTestSuite) TestApply_LaterRulesSeeEarlierChanges() {
+	// Arrange
+	engine, err := New([]Rule{
+		{Name: "rename", When: Conditions{Payee: "^PAYPAL"}, Then: Actions{Payee: "Craft"}},
+		{Name: "categorize", When: Conditions{Payee: "^Craft$"}, Then: Actions{Category: "Software", Flag: "Blue"}},
+	})
+	s.Require().NoError(err)
+
+	// Act
+	out, err := engine.Apply("milesmore", s.tx)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal([]string{"rename", "categorize"}, out.Fired)
+	s.Equal("Software", out.Transaction.Category)
+	s.Equal("blue", out.Transaction.FlagColor)
+}
+
+func (s *RulesTestSuite) TestApplyAll_WithSkip_DropsTransaction() {
+	// Arrange
+	engine, err := New([]Rule{{When: Conditions{Payee: "^PAYPAL"}, Then: Actions{Skip: true}}})
+	s.Require().NoError(err)
+	other := s.tx
+	other.Payee = "REWE"
+
+	// Act
+	kept, outcomes, err := engine.ApplyAll("milesmore", []domain.Transaction{s.tx, other})
+
+	// Assert
+	s.Require().NoError(err)
+	s.Len(kept, 1)
+	s.Equal("REWE", kept[0].Payee)
+	s.True(outcomes[0].Skipped)
+	s.Equal([]string{"rule-1"}, outcomes[0].Fired)
+}
+
+func (s *RulesTestSuite) TestApply_WithSplit_DividesAmount() {
+	// Arrange
+	ten, fifty := 10.0, 50.0
+	s.tx.Amount = -47.99
+
+	engine, err := New([]Rule{{Then: Actions{Split: []SplitAction{
+		{Amount: &ten, Category: "A"},
+		{Percent: &fifty, Category: "B"},
+		{Category: "C", Memo: "rest"},
+	}}}})
+	s.Require().NoError(err)
+
+	// Act
+	out, err := engine.Apply("milesmore", s.tx)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Require().Len(out.Transaction.Splits, 3)
+	s.Equal(-10.0, out.Transaction.Splits[0].Amount)
+	s.Equal(-24.0, out.Transaction.Splits[1].Amount)
+	s.InDelta(-13.99, out.Transaction.Splits[2].Amount, 0.001)
+}
+
+func (s *RulesTestSuite) TestApply_WithSplitExceedingAmount_ReturnsError() {
+	// Arrange
+	hundred := 100.0
+	engine, err := New([]Rule{{Then: Actions{Split: []SplitAction{{Amount: &hundred}, {}}}}})
+	s.Require().NoError(err)
+
+	// Act
+	_, err = engine.Apply("milesmore", s.tx)
+
+	// Assert
+	s.Error(err)
+}
+
+func (s *RulesTestSuite) TestNew_WithInvalidRules_ReturnsError() {
+	// Arrange
+	one, two := 1.0, 2.0
+
+	tests := []struct {
+		name string
+		rule Rule
+	}{
+		{name: "invalid regex", rule: Rule{When: Conditions{Payee: "("}}},
+		{name: "inverted amount range", rule: Rule{When: Conditions{AmountMin: &two, AmountMax: &one}}},
+		{name: "invalid weekday", rule: Rule{When: Conditions{Weekdays: []string{"someday"}}}},
+		{name: "invalid flag", rule: Rule{Then: Actions{Flag: "pink"}}},
+		{name: "single split part", rule: Rule{Then: Actions{Split: []SplitAction{{}}}}},
+		{name: "two remainders", rule: Rule{Then: Actions{Split: []SplitAction{{}, {}}}}},
+		{name: "skip with actions", rule: Rule{Then: Actions{Skip: true, Memo: "x"}}},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			engine, err := New([]Rule{tt.rule})
+
+			// Assert
+			s.Error(err)
+			s.Nil(engine)
+		})
+	}
+}
+
+func (s *RulesTestSuite) TestResolveCategories_ReplacesReferencesWithIDs() {
+	// Arrange
+	categories := []ynab.Category{
+		{ID: "cat-1", Name: "Fees", CategoryGroupName: "Bank"},
+		{ID: "cat-2", Name: "Fees", CategoryGroupName: "Travel"},
+		{ID: "cat-3", Name: "Software", CategoryGroupName: "Subscriptions"},
+	}
+	transactions := []domain.Transaction{
+		{Category: "bank: fees"},
+		{Category: "Software", Splits: []domain.Split{{Category: "cat-2"}}},
+		{},
+	}
+
+	// Act
+	err := ResolveCategories(transactions, categories)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal("cat-1", transactions[0].Category)
+	s.Equal("cat-3", transactions[1].Category)
+	s.Equal("cat-2", transactions[1].Splits[0].Category)
+	s.Empty(transactions[2].Category)
+}
+
+func (s *RulesTestSuite) TestResolveCategory_WithAmbiguousOrUnknownName_ReturnsError() {
+	// Arrange
+	categories := []ynab.Category{
+		{ID: "cat-1", Name: "Fees", CategoryGroupName: "Bank"},
+		{ID: "cat-2", Name: "Fees", CategoryGroupName: "Travel"},
+	}
+
+	// Act
+	_, ambiguous := ResolveCategory(categories, "Fees")
+	_, unknown := ResolveCategory(categories, "Rent")
+
+	// Assert
+	s.Error(ambiguous)
+	s.ErrorIs(unknown, ynab.ErrNotFound)
+}
+
+func (s *RulesTestSuite) TestLoadFor_FindsRulesNextToConfig() {
+	// Arrange
+	dir := s.T().TempDir()
+	s.Require().NoError(os.WriteFile(filepath.Join(dir, "rules.json"), []byte(`{"rules":[]}`), 0o600))
+
+	// Act
+	found, err := LoadFor("", filepath.Join(dir, "config.json"))
+	explicit, explicitErr := LoadFor("testdata/rules.yaml", filepath.Join(dir, "config.json"))
+	none, noneErr := LoadFor("", filepath.Join(s.T().TempDir(), "config.json"))
+
+	// Assert
+	s.Require().NoError(err)
+	s.NotNil(found)
+	s.Require().NoError(explicitErr)
+	s.Equal(2, explicit.Len())
+	s.NoError(noneErr)
+	s.Nil(none)
+}
+
+func (s *RulesTestSuite) TestCount_SeparatesChangedAndSkipped() {
+	// Arrange
+	outcomes := []Outcome{
+		{Fired: []string{"payee"}},
+		{Fired: []string{"skip"}, Skipped: true},
+		{},
+	}
+
+	// Act
+	changed, skipped := Count(outcomes)
+
+	// Assert
+	s.Equal(1, changed)
+	s.Equal(1, skipped)
+}
@@ -0,0 +1,179 @@
+// Package secrets resolves secret references in the configuration, so tokens
+// can live in the environment, a file, a password manager or an encrypted
+// vault instead of the plain config file.
+//
+// A reference has the form scheme:value:
+//
+//	env:YNAB_TOKEN           environment variable
+//	file:~/.ynab-token       first line of a file
+//	cmd:pass show ynab       first line of a command's output
+//	vault:ynab               entry of the encrypted vault file
+//
+// Values without a known scheme are plain secrets and returned unchanged.
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Reference schemes.
+const (
+	SchemeEnv   = "env"
+	SchemeFile  = "file"
+	SchemeCmd   = "cmd"
+	SchemeVault = "vault"
+)
+
+// DefaultCommandTimeout bounds how long a cmd: helper may run.
+const DefaultCommandTimeout = 30 * time.Second
+
+// ErrNotFound is returned when a reference points to a secret that does not exist.
+var ErrNotFound = errors.New("secret not found")
+
+// Resolver returns the secret a reference value (the part after the scheme) points to.
+type Resolver interface {
+	Resolve(ctx context.Context, value string) (string, error)
+}
+
+// ResolverFunc adapts a function to the Resolver interface.
+type ResolverFunc func(ctx context.Context, value string) (string, error)
+
+// Resolve calls f.
+func (f ResolverFunc) Resolve(ctx context.Context, value string) (string, error) {
+	return f(ctx, value)
+}
+
+// Store resolves references with one resolver per scheme.
+type Store struct {
+	resolvers map[string]Resolver
+}
+
+// NewStore creates a Store with the env, file and cmd resolvers. The vault
+// resolver needs a passphrase and is added with Register.
+func NewStore() *Store {
+	return &Store{resolvers: map[string]Resolver{
+		SchemeEnv:  ResolverFunc(resolveEnv),
+		SchemeFile: ResolverFunc(resolveFile),
+		SchemeCmd:  ResolverFunc(resolveCommand),
+	}}
+}
+
+// Register adds or replaces the resolver for scheme.
+func (s *Store) Register(scheme string, r Resolver) {
+	s.resolvers[scheme] = r
+}
+
+// Resolve returns the secret ref points to, or ref itself when it is not a reference.
+func (s *Store) Resolve(ctx context.Context, ref string) (string, error) {
+	scheme, value, ok := Parse(ref)
+	if !ok {
+		return ref, nil
+	}
+
+	r, ok := s.resolvers[scheme]
+	if !ok {
+		return "", fmt.Errorf("no resolver for %s: references", scheme)
+	}
+
+	secret, err := r.Resolve(ctx, value)
+	if err != nil {
+		return "", fmt.Errorf("resolving %s: %w", Describe(ref), err)
+	}
+	if secret == "" {
+		return "", fmt.Errorf("resolving %s: %w", Describe(ref), ErrNotFound)
+	}
+	return secret, nil
+}
+
+// Parse splits a reference into scheme and value. It returns false for plain secrets.
+func Parse(ref string) (scheme, value string, ok bool) {
+	scheme, value, found := strings.Cut(ref, ":")
+	if !found {
+		return "", "", false
+	}
+	switch scheme {
+	case SchemeEnv, SchemeFile, SchemeCmd, SchemeVault:
+		return scheme, strings.TrimSpace(value), true
+	default:
+		return "", "", false
+	}
+}
+
+// IsReference reports whether s is a secret reference rather than a plain secret.
+func IsReference(s string) bool {
+	_, _, ok := Parse(s)
+	return ok
+}
+
+// Describe returns a reference for messages; plain secrets are never included.
+func Describe(ref string) string {
+	if IsReference(ref) {
+		return ref
+	}
+	return "plain value"
+}
+
+func resolveEnv(_ context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set: %w", name, ErrNotFound)
+	}
+	return strings.TrimSpace(value), nil
+}
+
+func resolveFile(_ context.Context, path string) (string, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, rest)
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%s does not exist: %w", path, ErrNotFound)
+	}
+	if err != nil {
+		return "", err
+	}
+	return firstLine(data), nil
+}
+
+// resolveCommand runs the command without a shell and returns the first line
+// of its output, the convention of password managers such as pass.
+func resolveCommand(ctx context.Context, command string) (string, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", fmt.Errorf("empty command")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultCommandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	c := exec.CommandContext(ctx, args[0], args[1:]...)
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	c.Stdin = os.Stdin // password managers may ask for their own passphrase
+	if err := c.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return firstLine(stdout.Bytes()), nil
+}
+
+func firstLine(data []byte) string {
+	line, _, _ := strings.Cut(string(data), "\n")
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,99 @@
+package secrets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// StoreTestSuite groups secret reference resolution tests.
+type StoreTestSuite struct {
+	suite.Suite
+	store *Store
+	ctx   context.Context
+}
+
+func TestStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(StoreTestSuite))
+}
+
+func (s *StoreTestSuite) SetupTest() {
+	s.store = NewStore()
+	s.ctx = context.Background()
+}
+
+func (s *StoreTestSuite) TestResolve_WithPlainValue_ReturnsValue() {
+	// Act
+	secret, err := s.store.Resolve(s.ctx, "plain-token:with-colon")
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal("plain-token:with-colon", secret)
+}
+
+func (s *StoreTestSuite) TestResolve_WithEnvReference_ReadsVariable() {
+	// Arrange
+	s.T().Setenv("TEST_YNAB_TOKEN", " env-token \n")
+
+	// Act
+	secret, err := s.store.Resolve(s.ctx, "env:TEST_YNAB_TOKEN")
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal("env-token", secret)
+}
+
+func (s *StoreTestSuite) TestResolve_WithUnsetEnv_ReturnsNotFound() {
+	// Act
+	_, err := s.store.Resolve(s.ctx, "env:TEST_UNSET_TOKEN_VARIABLE")
+
+	// Assert
+	s.ErrorIs(err, ErrNotFound)
+	s.ErrorContains(err, "resolving env:TEST_UNSET_TOKEN_VARIABLE")
+}
+
+func (s *StoreTestSuite) TestResolve_WithFileReference_ReadsFirstLine() {
+	// Arrange
+	path := filepath.Join(s.T().TempDir(), "token")
+	s.Require().NoError(os.WriteFile(path, []byte("file-token\ncomment\n"), 0o600))
+
+	// Act
+	secret, err := s.store.Resolve(s.ctx, "file:"+path)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal("file-token", secret)
+}
+
+func (s *StoreTestSuite) TestResolve_WithCommandReference_ReadsFirstOutputLine() {
+	// Act
+	secret, err := s.store.Resolve(s.ctx, "cmd:printf cmd-token\\nsecond")
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal("cmd-token", secret)
+}
+
+func (s *StoreTestSuite) TestResolve_WithFailingCommand_ReturnsError() {
+	// Act
+	_, err := s.store.Resolve(s.ctx, "cmd:false")
+
+	// Assert
+	s.ErrorContains(err, "resolving cmd:false")
+}
+
+func (s *StoreTestSuite) TestResolve_WithVaultWithoutResolver_ReturnsError() {
+	// Act
+	_, err := s.store.Resolve(s.ctx, "vault:ynab")
+
+	// Assert
+	s.ErrorContains(err, "no resolver for vault")
+}
+
+func (s *StoreTestSuite) TestDescribe_NeverIncludesPlainSecrets() {
+	s.Equal("plain value", Describe("abcdef123456"))
+	s.Equal("env:TOKEN", Describe("env:TOKEN"))
+}
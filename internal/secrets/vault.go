@@ -0,0 +1,208 @@
+package secrets
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/pgbytes/moneypenny/internal/storage"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// DefaultVaultFileName is the vault file name inside the config directory.
+	DefaultVaultFileName = "secrets.vault"
+
+	// vaultVersion is the current on-disk format version.
+	vaultVersion = 1
+
+	// kdfScrypt and cipherXChaCha identify the algorithms in the vault file.
+	kdfScrypt     = "scrypt"
+	cipherXChaCha = "xchacha20-poly1305"
+)
+
+// DefaultScryptParams are the scrypt cost parameters for new vault files
+// (N=2^15, r=8, p=1: about 32 MiB and 100 ms per unlock).
+var DefaultScryptParams = ScryptParams{N: 1 << 15, R: 8, P: 1}
+
+// ErrWrongPassphrase is returned when the vault cannot be decrypted.
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted vault")
+
+// ScryptParams are the cost parameters of the key derivation.
+type ScryptParams struct {
+	N int `json:"n"`
+	R int `json:"r"`
+	P int `json:"p"`
+}
+
+// vaultFile is the on-disk format. The secrets, including their names, are
+// encrypted as one JSON document; the KDF parameters and the cipher name are
+// bound to the ciphertext as additional data.
+type vaultFile struct {
+	Version    int          `json:"version"`
+	KDF        string       `json:"kdf"`
+	Params     ScryptParams `json:"params"`
+	Salt       []byte       `json:"salt"`
+	Cipher     string       `json:"cipher"`
+	Nonce      []byte       `json:"nonce"`
+	Ciphertext []byte       `json:"ciphertext"`
+}
+
+// Vault is a passphrase-protected file of named secrets.
+type Vault struct {
+	path    string
+	params  ScryptParams
+	secrets map[string]string
+}
+
+// OpenVault decrypts the vault at path. A missing file yields an empty vault
+// that is created on Save.
+func OpenVault(path string, passphrase []byte) (*Vault, error) {
+	v := &Vault{path: path, params: DefaultScryptParams, secrets: make(map[string]string)}
+
+	var f vaultFile
+	found, err := storage.LoadJSON(path, &f)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return v, nil
+	}
+
+	if f.Version != vaultVersion || f.KDF != kdfScrypt || f.Cipher != cipherXChaCha {
+		return nil, fmt.Errorf("unsupported vault format (version %d, %s, %s)", f.Version, f.KDF, f.Cipher)
+	}
+
+	aead, err := newAEAD(passphrase, f.Salt, f.Params)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, f.Nonce, f.Ciphertext, f.additionalData())
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	if err := json.Unmarshal(plaintext, &v.secrets); err != nil {
+		return nil, fmt.Errorf("parsing vault: %w", err)
+	}
+
+	v.params = f.Params
+	return v, nil
+}
+
+// Path returns the location of the vault file.
+func (v *Vault) Path() string {
+	return v.path
+}
+
+// Get returns the secret stored under name.
+func (v *Vault) Get(name string) (string, bool) {
+	secret, ok := v.secrets[name]
+	return secret, ok
+}
+
+// Set stores secret under name.
+func (v *Vault) Set(name, secret string) {
+	v.secrets[name] = secret
+}
+
+// Names returns the names of all stored secrets in sorted order.
+func (v *Vault) Names() []string {
+	names := make([]string, 0, len(v.secrets))
+	for name := range v.secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Save encrypts the vault with passphrase and writes it atomically. Every
+// save uses a fresh salt and nonce, so saving with a new passphrase rotates
+// the key.
+func (v *Vault) Save(passphrase []byte) error {
+	if len(passphrase) == 0 {
+		return fmt.Errorf("passphrase must not be empty")
+	}
+
+	f := vaultFile{
+		Version: vaultVersion,
+		KDF:     kdfScrypt,
+		Params:  v.params,
+		Salt:    make([]byte, 16),
+		Cipher:  cipherXChaCha,
+		Nonce:   make([]byte, chacha20poly1305.NonceSizeX),
+	}
+	if _, err := rand.Read(f.Salt); err != nil {
+		return fmt.Errorf("generating salt: %w", err)
+	}
+	if _, err := rand.Read(f.Nonce); err != nil {
+		return fmt.Errorf("generating nonce: %w", err)
+	}
+
+	plaintext, err := json.Marshal(v.secrets)
+	if err != nil {
+		return fmt.Errorf("encoding vault: %w", err)
+	}
+	aead, err := newAEAD(passphrase, f.Salt, f.Params)
+	if err != nil {
+		return err
+	}
+	f.Ciphertext = aead.Seal(nil, f.Nonce, plaintext, f.additionalData())
+
+	return storage.SaveJSON(v.path, f)
+}
+
+// additionalData authenticates the header fields together with the ciphertext.
+func (f *vaultFile) additionalData() []byte {
+	return []byte(fmt.Sprintf("moneypenny-vault:%d:%s:%d:%d:%d:%s", f.Version, f.KDF, f.Params.N, f.Params.R, f.Params.P, f.Cipher))
+}
+
+// newAEAD derives the key from passphrase and salt.
+func newAEAD(passphrase, salt []byte, params ScryptParams) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, params.N, params.R, params.P, chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("deriving vault key: %w", err)
+	}
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	return aead, nil
+}
+
+// VaultResolver resolves vault: references. The vault is opened on first use
+// with the passphrase returned by Passphrase.
+type VaultResolver struct {
+	Path       string
+	Passphrase func() ([]byte, error)
+
+	once  sync.Once
+	vault *Vault
+	err   error
+}
+
+// Resolve returns the vault entry name.
+func (r *VaultResolver) Resolve(_ context.Context, name string) (string, error) {
+	r.once.Do(func() {
+		passphrase, err := r.Passphrase()
+		if err != nil {
+			r.err = fmt.Errorf("reading vault passphrase: %w", err)
+			return
+		}
+		r.vault, r.err = OpenVault(r.Path, passphrase)
+	})
+	if r.err != nil {
+		return "", r.err
+	}
+
+	secret, ok := r.vault.Get(name)
+	if !ok {
+		return "", fmt.Errorf("no entry %q in %s: %w", name, r.Path, ErrNotFound)
+	}
+	return secret, nil
+}
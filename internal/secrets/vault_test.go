@@ -0,0 +1,132 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// VaultTestSuite groups encrypted vault tests.
+type VaultTestSuite struct {
+	suite.Suite
+	path   string
+	params ScryptParams
+}
+
+func TestVaultTestSuite(t *testing.T) {
+	suite.Run(t, new(VaultTestSuite))
+}
+
+func (s *VaultTestSuite) SetupTest() {
+	s.path = filepath.Join(s.T().TempDir(), DefaultVaultFileName)
+	// Cheap key derivation keeps the tests fast
+	s.params = DefaultScryptParams
+	DefaultScryptParams = ScryptParams{N: 1 << 10, R: 8, P: 1}
+}
+
+func (s *VaultTestSuite) TearDownTest() {
+	DefaultScryptParams = s.params
+}
+
+func (s *VaultTestSuite) saveVault(passphrase string, secrets map[string]string) {
+	v, err := OpenVault(s.path, []byte(passphrase))
+	s.Require().NoError(err)
+	for name, secret := range secrets {
+		v.Set(name, secret)
+	}
+	s.Require().NoError(v.Save([]byte(passphrase)))
+}
+
+func (s *VaultTestSuite) TestSaveAndOpen_RoundTripsSecrets() {
+	// Arrange
+	s.saveVault("correct horse", map[string]string{"ynab": "token-1", "household": "token-2"})
+
+	// Act
+	v, err := OpenVault(s.path, []byte("correct horse"))
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal([]string{"household", "ynab"}, v.Names())
+	secret, ok := v.Get("ynab")
+	s.True(ok)
+	s.Equal("token-1", secret)
+
+	data, err := os.ReadFile(s.path)
+	s.Require().NoError(err)
+	s.NotContains(string(data), "token-1")
+	s.NotContains(string(data), "household")
+}
+
+func (s *VaultTestSuite) TestOpen_WithWrongPassphrase_ReturnsErrWrongPassphrase() {
+	// Arrange
+	s.saveVault("correct horse", map[string]string{"ynab": "token"})
+
+	// Act
+	_, err := OpenVault(s.path, []byte("battery staple"))
+
+	// Assert
+	s.ErrorIs(err, ErrWrongPassphrase)
+}
+
+func (s *VaultTestSuite) TestOpen_WithTamperedParameters_ReturnsErrWrongPassphrase() {
+	// Arrange
+	s.saveVault("correct horse", map[string]string{"ynab": "token"})
+	var f vaultFile
+	data, err := os.ReadFile(s.path)
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(data, &f))
+	f.Params.R = 1
+	data, err = json.Marshal(f)
+	s.Require().NoError(err)
+	s.Require().NoError(os.WriteFile(s.path, data, 0o600))
+
+	// Act
+	_, err = OpenVault(s.path, []byte("correct horse"))
+
+	// Assert
+	s.ErrorIs(err, ErrWrongPassphrase)
+}
+
+func (s *VaultTestSuite) TestSave_WithNewPassphrase_RotatesKey() {
+	// Arrange
+	s.saveVault("old passphrase", map[string]string{"ynab": "token"})
+	v, err := OpenVault(s.path, []byte("old passphrase"))
+	s.Require().NoError(err)
+
+	// Act
+	err = v.Save([]byte("new passphrase"))
+
+	// Assert
+	s.Require().NoError(err)
+	_, err = OpenVault(s.path, []byte("old passphrase"))
+	s.ErrorIs(err, ErrWrongPassphrase)
+	rotated, err := OpenVault(s.path, []byte("new passphrase"))
+	s.Require().NoError(err)
+	secret, _ := rotated.Get("ynab")
+	s.Equal("token", secret)
+}
+
+func (s *VaultTestSuite) TestVaultResolver_AsksForPassphraseOnce() {
+	// Arrange
+	s.saveVault("correct horse", map[string]string{"ynab": "token"})
+	asked := 0
+	store := NewStore()
+	store.Register(SchemeVault, &VaultResolver{Path: s.path, Passphrase: func() ([]byte, error) {
+		asked++
+		return []byte("correct horse"), nil
+	}})
+
+	// Act
+	first, err1 := store.Resolve(context.Background(), "vault:ynab")
+	_, err2 := store.Resolve(context.Background(), "vault:missing")
+
+	// Assert
+	s.Require().NoError(err1)
+	s.Equal("token", first)
+	s.ErrorIs(err2, ErrNotFound)
+	s.Equal(1, asked)
+}
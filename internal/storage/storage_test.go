@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sample struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestLoadJSON_WithMissingFile_ReturnsNotFound(t *testing.T) {
+	// Arrange
+	var v sample
+
+	// Act
+	found, err := LoadJSON(filepath.Join(t.TempDir(), "missing.json"), &v)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestSaveJSON_ThenLoadJSON_RoundTrips(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "nested", "state.json")
+	var loaded sample
+
+	// Act
+	err := SaveJSON(path, sample{Name: "ledger", Count: 3})
+	require.NoError(t, err)
+	found, err := LoadJSON(path, &loaded)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, sample{Name: "ledger", Count: 3}, loaded)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestLoadJSON_WithInvalidContent_ReturnsError(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "broken.json")
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o600))
+	var v sample
+
+	// Act
+	_, err := LoadJSON(path, &v)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "parsing broken.json")
+}
+
+func TestHashFile_WithSameContent_ReturnsSameHash(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	first := filepath.Join(dir, "a.csv")
+	second := filepath.Join(dir, "b.csv")
+	require.NoError(t, os.WriteFile(first, []byte("same"), 0o600))
+	require.NoError(t, os.WriteFile(second, []byte("same"), 0o600))
+
+	// Act
+	firstHash, err1 := HashFile(first)
+	secondHash, err2 := HashFile(second)
+
+	// Assert
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.Equal(t, firstHash, secondHash)
+	assert.Len(t, firstHash, 64)
+}
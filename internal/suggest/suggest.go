@@ -0,0 +1,299 @@
+// Package suggest learns category suggestions from categorized YNAB history.
+//
+// The classifier is a multinomial naive Bayes model over payee and memo
+// tokens plus an amount bucket. It is trained offline from GetTransactions
+// output and stored as JSON in the local data directory.
+package suggest
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/pgbytes/moneypenny/internal/storage"
+)
+
+const (
+	// DefaultFileName is the model file name inside the data directory.
+	DefaultFileName = "category-model.json"
+
+	// DefaultThreshold is the default confidence above which suggestions are applied automatically.
+	DefaultThreshold = 0.9
+
+	// modelVersion is the current on-disk format version.
+	modelVersion = 1
+
+	// amountFeaturePrefix marks the amount bucket feature.
+	amountFeaturePrefix = "amount:"
+)
+
+// amountBuckets are the upper bounds (exclusive) of the amount buckets in currency units.
+var amountBuckets = []float64{5, 20, 50, 100, 250, 1000}
+
+// CategoryStats are the per-category counts learned during training.
+type CategoryStats struct {
+	// Name is the category name at training time.
+	Name string `json:"name"`
+	// Documents is the number of training transactions in the category.
+	Documents int `json:"documents"`
+	// Features counts feature occurrences in the category.
+	Features map[string]int `json:"features"`
+	// TotalFeatures is the sum of all feature counts.
+	TotalFeatures int `json:"total_features"`
+}
+
+// Model is a trained category classifier.
+type Model struct {
+	Version int `json:"version"`
+	// BudgetID is the budget the model was trained on.
+	BudgetID string `json:"budget_id"`
+	// TrainedAt is when the model was trained.
+	TrainedAt time.Time `json:"trained_at"`
+	// Documents is the number of training transactions.
+	Documents int `json:"documents"`
+	// Vocabulary is the number of distinct features.
+	Vocabulary int `json:"vocabulary"`
+	// Categories holds the statistics per category ID.
+	Categories map[string]*CategoryStats `json:"categories"`
+}
+
+// Suggestion is a suggested category for a transaction.
+type Suggestion struct {
+	CategoryID   string `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	// Confidence is the posterior probability (0..1) of the category.
+	Confidence float64 `json:"confidence"`
+}
+
+// Train builds a model from categorized transactions. Deleted, uncategorized,
+// split and transfer transactions are ignored.
+func Train(budgetID string, transactions []ynab.Transaction, now time.Time) (*Model, error) {
+	m := &Model{
+		Version:    modelVersion,
+		BudgetID:   budgetID,
+		TrainedAt:  now.UTC(),
+		Categories: make(map[string]*CategoryStats),
+	}
+	vocabulary := make(map[string]bool)
+
+	for _, t := range transactions {
+		if t.Deleted || t.CategoryID == "" || t.TransferAccountID != "" || len(t.Subtransactions) > 0 {
+			continue
+		}
+
+		stats, ok := m.Categories[t.CategoryID]
+		if !ok {
+			stats = &CategoryStats{Name: t.CategoryName, Features: make(map[string]int)}
+			m.Categories[t.CategoryID] = stats
+		}
+
+		stats.Documents++
+		m.Documents++
+
+		for _, f := range features(payeeText(t), t.Memo, ynab.MilliunitsToFloat(t.Amount)) {
+			stats.Features[f]++
+			stats.TotalFeatures++
+			vocabulary[f] = true
+		}
+	}
+
+	if m.Documents == 0 {
+		return nil, fmt.Errorf("no categorized transactions to train on")
+	}
+	m.Vocabulary = len(vocabulary)
+
+	return m, nil
+}
+
+// Suggest returns the most likely category for a transaction, or false when
+// the model has no categories.
+func (m *Model) Suggest(tx domain.Transaction) (Suggestion, bool) {
+	ranked := m.Rank(tx)
+	if len(ranked) == 0 {
+		return Suggestion{}, false
+	}
+	return ranked[0], true
+}
+
+// Rank returns all categories ordered by descending confidence.
+func (m *Model) Rank(tx domain.Transaction) []Suggestion {
+	if len(m.Categories) == 0 {
+		return nil
+	}
+
+	feats := features(tx.Payee, tx.Memo, tx.Amount)
+	vocabulary := float64(m.Vocabulary + 1)
+
+	type score struct {
+		id  string
+		log float64
+	}
+	scores := make([]score, 0, len(m.Categories))
+
+	for id, stats := range m.Categories {
+		logP := math.Log(float64(stats.Documents) / float64(m.Documents))
+		denominator := float64(stats.TotalFeatures) + vocabulary
+		for _, f := range feats {
+			// Laplace smoothing keeps unseen features from zeroing the probability
+			logP += math.Log((float64(stats.Features[f]) + 1) / denominator)
+		}
+		scores = append(scores, score{id: id, log: logP})
+	}
+
+	// Normalize log scores into probabilities (softmax)
+	best := math.Inf(-1)
+	for _, s := range scores {
+		best = math.Max(best, s.log)
+	}
+	total := 0.0
+	for _, s := range scores {
+		total += math.Exp(s.log - best)
+	}
+
+	suggestions := make([]Suggestion, 0, len(scores))
+	for _, s := range scores {
+		suggestions = append(suggestions, Suggestion{
+			CategoryID:   s.id,
+			CategoryName: m.Categories[s.id].Name,
+			Confidence:   math.Exp(s.log-best) / total,
+		})
+	}
+
+	sort.SliceStable(suggestions, func(a, b int) bool {
+		if suggestions[a].Confidence != suggestions[b].Confidence {
+			return suggestions[a].Confidence > suggestions[b].Confidence
+		}
+		return suggestions[a].CategoryID < suggestions[b].CategoryID
+	})
+
+	return suggestions
+}
+
+// Result is the suggestion for one transaction when suggestions are applied.
+type Result struct {
+	Transaction domain.Transaction `json:"transaction"`
+	Suggestion  Suggestion         `json:"suggestion"`
+	// Applied reports whether the suggestion was set as the category.
+	Applied bool `json:"applied"`
+}
+
+// Apply suggests categories for transactions without one and sets those with
+// a confidence of at least threshold. Transactions that already have a
+// category (e.g. from rules) or splits are left unchanged and not reported.
+func (m *Model) Apply(transactions []domain.Transaction, threshold float64) []Result {
+	var results []Result
+	for i := range transactions {
+		tx := &transactions[i]
+		if tx.Category != "" || len(tx.Splits) > 0 {
+			continue
+		}
+
+		s, ok := m.Suggest(*tx)
+		if !ok {
+			continue
+		}
+
+		r := Result{Transaction: *tx, Suggestion: s}
+		if s.Confidence >= threshold {
+			tx.Category = s.CategoryID
+			r.Applied = true
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// Load reads a model file. It returns false when the file does not exist.
+func Load(path string) (*Model, bool, error) {
+	var m Model
+	found, err := storage.LoadJSON(path, &m)
+	if err != nil {
+		return nil, false, fmt.Errorf("loading category model: %w", err)
+	}
+	if !found {
+		return nil, false, nil
+	}
+	if m.Version != modelVersion {
+		return nil, false, fmt.Errorf("category model version %d is not supported, train it again", m.Version)
+	}
+	return &m, true, nil
+}
+
+// ModelPath returns the path of the model of budgetID in dir, e.g. the data
+// directory, so each budget keeps its own model.
+func ModelPath(dir, budgetID string) string {
+	name := strings.TrimSuffix(DefaultFileName, ".json") + "-" + budgetID + ".json"
+	return filepath.Join(dir, name)
+}
+
+// LoadModel loads the model trained for budgetID from dir. It returns nil when
+// no model was trained for the budget yet.
+func LoadModel(dir, budgetID string) (*Model, error) {
+	model, _, err := Load(ModelPath(dir, budgetID))
+	return model, err
+}
+
+// Save writes the model atomically to path.
+func (m *Model) Save(path string) error {
+	if err := storage.SaveJSON(path, m); err != nil {
+		return fmt.Errorf("saving category model: %w", err)
+	}
+	return nil
+}
+
+// payeeText returns the most descriptive payee text of a YNAB transaction.
+func payeeText(t ynab.Transaction) string {
+	if t.ImportPayeeOriginal != "" {
+		return t.PayeeName + " " + t.ImportPayeeOriginal
+	}
+	return t.PayeeName
+}
+
+// features extracts the payee and memo tokens and the amount bucket.
+func features(payee, memo string, amount float64) []string {
+	var feats []string
+	for _, tok := range tokenize(payee) {
+		feats = append(feats, "payee:"+tok)
+	}
+	for _, tok := range tokenize(memo) {
+		feats = append(feats, "memo:"+tok)
+	}
+	return append(feats, amountBucket(amount))
+}
+
+// tokenize lower-cases s and splits it into letter-only words of at least two runes.
+func tokenize(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	tokens := fields[:0]
+	for _, f := range fields {
+		if len([]rune(f)) >= 2 {
+			tokens = append(tokens, f)
+		}
+	}
+	return tokens
+}
+
+// amountBucket maps an amount to a coarse, sign-aware bucket feature.
+func amountBucket(amount float64) string {
+	direction := "out"
+	if amount >= 0 {
+		direction = "in"
+	}
+
+	abs := math.Abs(amount)
+	for _, upper := range amountBuckets {
+		if abs < upper {
+			return fmt.Sprintf("%s%s:<%g", amountFeaturePrefix, direction, upper)
+		}
+	}
+	return fmt.Sprintf("%s%s:>=%g", amountFeaturePrefix, direction, amountBuckets[len(amountBuckets)-1])
+}
@@ -0,0 +1,189 @@
+package suggest
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/stretchr/testify/suite"
+)
+
+// SuggestTestSuite groups all category suggestion tests.
+type SuggestTestSuite struct {
+	suite.Suite
+	history []ynab.Transaction
+	now     time.Time
+}
+
+func TestSuggestTestSuite(t *testing.T) {
+	suite.Run(t, new(SuggestTestSuite))
+}
+
+func (s *SuggestTestSuite) SetupTest() {
+	s.now = time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	s.history = []ynab.Transaction{
+		{ID: "1", PayeeName: "REWE Markt", Amount: -45000, CategoryID: "cat-groceries", CategoryName: "Groceries"},
+		{ID: "2", PayeeName: "REWE City", Amount: -12000, CategoryID: "cat-groceries", CategoryName: "Groceries"},
+		{ID: "3", PayeeName: "Edeka", Amount: -30000, CategoryID: "cat-groceries", CategoryName: "Groceries"},
+		{ID: "4", PayeeName: "Shell Tankstelle", Amount: -60000, CategoryID: "cat-fuel", CategoryName: "Fuel"},
+		{ID: "5", PayeeName: "Aral Tankstelle", Amount: -70000, CategoryID: "cat-fuel", CategoryName: "Fuel"},
+		{ID: "6", PayeeName: "Netflix", Amount: -12990, Memo: "subscription", CategoryID: "cat-streaming", CategoryName: "Streaming"},
+		// Ignored for training
+		{ID: "7", PayeeName: "Unknown", Amount: -1000},
+		{ID: "8", PayeeName: "Transfer : Savings", Amount: -100000, CategoryID: "cat-x", TransferAccountID: "acc-2"},
+		{ID: "9", PayeeName: "Deleted", Amount: -1000, CategoryID: "cat-y", Deleted: true},
+	}
+}
+
+func (s *SuggestTestSuite) TestTrain_IgnoresUncategorizedTransfersAndDeleted() {
+	// Act
+	model, err := Train("budget-1", s.history, s.now)
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal(6, model.Documents)
+	s.Len(model.Categories, 3)
+	s.Equal(3, model.Categories["cat-groceries"].Documents)
+	s.Equal("budget-1", model.BudgetID)
+}
+
+func (s *SuggestTestSuite) TestTrain_WithoutCategorizedTransactions_ReturnsError() {
+	// Act
+	model, err := Train("budget-1", s.history[6:], s.now)
+
+	// Assert
+	s.Error(err)
+	s.Nil(model)
+}
+
+func (s *SuggestTestSuite) TestSuggest_PicksCategoryOfSimilarPayee() {
+	// Arrange
+	model, err := Train("budget-1", s.history, s.now)
+	s.Require().NoError(err)
+
+	tests := []struct {
+		name     string
+		tx       domain.Transaction
+		expected string
+	}{
+		{name: "grocery store", tx: domain.Transaction{Payee: "REWE SAGT DANKE 4711", Amount: -23.5}, expected: "cat-groceries"},
+		{name: "fuel station", tx: domain.Transaction{Payee: "TANKSTELLE MUENCHEN", Amount: -65}, expected: "cat-fuel"},
+		{name: "streaming", tx: domain.Transaction{Payee: "NETFLIX.COM", Amount: -12.99}, expected: "cat-streaming"},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			suggestion, ok := model.Suggest(tt.tx)
+
+			// Assert
+			s.True(ok)
+			s.Equal(tt.expected, suggestion.CategoryID)
+			s.Greater(suggestion.Confidence, 0.5)
+		})
+	}
+}
+
+func (s *SuggestTestSuite) TestRank_ConfidencesSumToOne() {
+	// Arrange
+	model, err := Train("budget-1", s.history, s.now)
+	s.Require().NoError(err)
+
+	// Act
+	ranked := model.Rank(domain.Transaction{Payee: "Something new", Amount: -10})
+
+	// Assert
+	s.Len(ranked, 3)
+	total := 0.0
+	for _, r := range ranked {
+		total += r.Confidence
+	}
+	s.InDelta(1.0, total, 1e-9)
+	s.GreaterOrEqual(ranked[0].Confidence, ranked[1].Confidence)
+}
+
+func (s *SuggestTestSuite) TestApply_SetsCategoryAboveThresholdOnly() {
+	// Arrange
+	model, err := Train("budget-1", s.history, s.now)
+	s.Require().NoError(err)
+
+	transactions := []domain.Transaction{
+		{Payee: "REWE Markt", Amount: -20},
+		{Payee: "Something new", Amount: -10},
+		{Payee: "REWE Markt", Amount: -20, Category: "cat-from-rules"},
+	}
+
+	// Act
+	results := model.Apply(transactions, 0.9)
+
+	// Assert
+	s.Len(results, 2)
+	s.True(results[0].Applied)
+	s.Equal("cat-groceries", transactions[0].Category)
+	s.False(results[1].Applied)
+	s.Empty(transactions[1].Category)
+	s.Equal("cat-from-rules", transactions[2].Category)
+}
+
+func (s *SuggestTestSuite) TestSaveAndLoad_RoundTripsModel() {
+	// Arrange
+	model, err := Train("budget-1", s.history, s.now)
+	s.Require().NoError(err)
+	path := filepath.Join(s.T().TempDir(), DefaultFileName)
+
+	// Act
+	s.Require().NoError(model.Save(path))
+	loaded, found, err := Load(path)
+
+	// Assert
+	s.Require().NoError(err)
+	s.True(found)
+	s.Equal(model.Documents, loaded.Documents)
+	s.Equal(model.Categories["cat-fuel"].Features, loaded.Categories["cat-fuel"].Features)
+}
+
+func (s *SuggestTestSuite) TestLoad_WithMissingFile_ReturnsNotFound() {
+	// Act
+	model, found, err := Load(filepath.Join(s.T().TempDir(), "missing.json"))
+
+	// Assert
+	s.NoError(err)
+	s.False(found)
+	s.Nil(model)
+}
+
+func (s *SuggestTestSuite) TestAmountBucket() {
+	tests := []struct {
+		amount   float64
+		expected string
+	}{
+		{amount: -3, expected: "amount:out:<5"},
+		{amount: -45, expected: "amount:out:<50"},
+		{amount: 2500, expected: "amount:in:>=1000"},
+	}
+
+	for _, tt := range tests {
+		s.Equal(tt.expected, amountBucket(tt.amount))
+	}
+}
+
+func (s *SuggestTestSuite) TestLoadModel_ReadsModelOfBudgetOnly() {
+	// Arrange
+	dir := s.T().TempDir()
+	model, err := Train("budget-1", s.history, s.now)
+	s.Require().NoError(err)
+	s.Require().NoError(model.Save(ModelPath(dir, "budget-1")))
+
+	// Act
+	loaded, err := LoadModel(dir, "budget-1")
+	other, otherErr := LoadModel(dir, "budget-2")
+
+	// Assert
+	s.Require().NoError(err)
+	s.Equal("budget-1", loaded.BudgetID)
+	s.NoError(otherErr)
+	s.Nil(other)
+	s.Equal(filepath.Join(dir, "category-model-budget-1.json"), ModelPath(dir, "budget-1"))
+}
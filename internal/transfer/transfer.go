@@ -0,0 +1,302 @@
+// Package transfer detects pairs of opposite transactions in two accounts that
+// are really one transfer, such as the monthly credit card settlement debited
+// from the checking account, and plans how to turn them into YNAB transfers.
+package transfer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/domain"
+)
+
+const (
+	// DefaultDateWindow is the default maximum number of days between both legs of a transfer.
+	DefaultDateWindow = 5
+
+	// isoDate is the date format used by the YNAB API.
+	isoDate = "2006-01-02"
+)
+
+// DefaultPayeePatterns are regular expressions matching payees of known
+// settlement and transfer bookings.
+var DefaultPayeePatterns = []string{
+	`(?i)miles\s*(&|and|\+)\s*more`,
+	`(?i)kreditkarten?\s*-?\s*abrechnung`,
+	`(?i)\bkreditkarte\b`,
+	`(?i)\bumbuchung\b`,
+	`(?i)\b(ü|ue)bertrag\b`,
+	`(?i)zahlung.*danke`,
+	`(?i)payment.*thank`,
+}
+
+// CompilePatterns compiles payee patterns, falling back to DefaultPayeePatterns when none are given.
+func CompilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	if len(patterns) == 0 {
+		patterns = DefaultPayeePatterns
+	}
+
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid payee pattern %q: %w", p, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// Options configures transfer detection.
+type Options struct {
+	// DateWindow is the maximum number of days between both legs.
+	DateWindow int
+	// PayeePatterns match settlement payees; at least one leg must match.
+	PayeePatterns []*regexp.Regexp
+}
+
+// Leg is one side of a potential transfer. It is either an existing YNAB
+// transaction or a parsed statement row not yet in YNAB.
+type Leg struct {
+	AccountID string    `json:"account_id"`
+	Date      time.Time `json:"date"`
+	// Amount is in milliunits; negative for the outflow leg.
+	Amount int64  `json:"amount"`
+	Payee  string `json:"payee"`
+	// Existing is the YNAB transaction (nil for statement rows).
+	Existing *ynab.Transaction `json:"existing,omitempty"`
+	// Statement is the parsed statement row (nil for YNAB transactions).
+	Statement *domain.Transaction `json:"statement,omitempty"`
+}
+
+// ExistingLeg creates a leg from a YNAB transaction. It returns false for
+// transactions that cannot be part of a new transfer: deleted ones, existing
+// transfers, split transactions, reconciled transactions and transactions
+// with unparseable dates.
+func ExistingLeg(t ynab.Transaction) (Leg, bool) {
+	if t.Deleted || t.TransferAccountID != "" || len(t.Subtransactions) > 0 || t.Amount == 0 {
+		return Leg{}, false
+	}
+	if t.Cleared == ynab.ClearedStatusReconciled {
+		return Leg{}, false
+	}
+
+	date, err := time.Parse(isoDate, t.Date)
+	if err != nil {
+		return Leg{}, false
+	}
+
+	payee := t.PayeeName
+	if t.ImportPayeeOriginal != "" {
+		payee = t.ImportPayeeOriginal
+	}
+
+	return Leg{
+		AccountID: t.AccountID,
+		Date:      date,
+		Amount:    t.Amount,
+		Payee:     payee,
+		Existing:  &t,
+	}, true
+}
+
+// StatementLeg creates a leg from a parsed statement row of the given account.
+func StatementLeg(accountID string, tx domain.Transaction) Leg {
+	return Leg{
+		AccountID: accountID,
+		Date:      tx.Date,
+		Amount:    ynab.FloatToMilliunits(tx.Amount),
+		Payee:     tx.Payee,
+		Statement: &tx,
+	}
+}
+
+// Pair is a detected transfer.
+type Pair struct {
+	// Outflow is the leg leaving the source account.
+	Outflow Leg `json:"outflow"`
+	// Inflow is the leg arriving in the destination account.
+	Inflow Leg `json:"inflow"`
+	// DayDelta is the number of days between both legs.
+	DayDelta int `json:"day_delta"`
+}
+
+// Detect pairs outflows with inflows of the same absolute amount in another
+// account within the date window, where at least one payee matches a
+// settlement pattern. Each leg is used at most once, closest dates first.
+func Detect(legs []Leg, opts Options) []Pair {
+	if opts.DateWindow <= 0 {
+		opts.DateWindow = DefaultDateWindow
+	}
+
+	var candidates []Pair
+	for _, out := range legs {
+		if out.Amount >= 0 {
+			continue
+		}
+		for _, in := range legs {
+			if in.Amount != -out.Amount || in.AccountID == out.AccountID {
+				continue
+			}
+			delta := domain.DayDelta(out.Date, in.Date)
+			if delta > opts.DateWindow {
+				continue
+			}
+			if !matchesAny(opts.PayeePatterns, out.Payee) && !matchesAny(opts.PayeePatterns, in.Payee) {
+				continue
+			}
+			candidates = append(candidates, Pair{Outflow: out, Inflow: in, DayDelta: delta})
+		}
+	}
+
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return candidates[a].DayDelta < candidates[b].DayDelta
+	})
+
+	used := make(map[string]bool)
+	var pairs []Pair
+	for _, c := range candidates {
+		outKey, inKey := legKey(c.Outflow), legKey(c.Inflow)
+		if used[outKey] || used[inKey] {
+			continue
+		}
+		used[outKey] = true
+		used[inKey] = true
+		pairs = append(pairs, c)
+	}
+
+	sort.SliceStable(pairs, func(a, b int) bool {
+		return pairs[a].Outflow.Date.Before(pairs[b].Outflow.Date)
+	})
+
+	return pairs
+}
+
+// Plan lists the YNAB changes that turn detected pairs into transfers.
+type Plan struct {
+	// Create are transfers for pairs where neither leg exists in YNAB yet.
+	Create []ynab.SaveTransaction
+	// Update converts existing transactions into transfers; YNAB creates the other leg.
+	Update []ynab.SaveTransactionWithID
+	// Delete are existing transactions replaced by the leg YNAB creates.
+	Delete []string
+	// Warnings describe changes that lose information, such as replacing a cleared leg.
+	Warnings []string
+}
+
+// IsEmpty reports whether the plan contains no changes.
+func (p *Plan) IsEmpty() bool {
+	return len(p.Create) == 0 && len(p.Update) == 0 && len(p.Delete) == 0
+}
+
+// BuildPlan decides for every pair how to represent it as a transfer, using
+// the transfer payee of the account on the other side:
+//   - both legs in YNAB: one leg becomes a transfer and the other is deleted,
+//     since YNAB creates the matching leg itself. The uncleared leg is the one
+//     replaced, preferring the inflow; replacing a cleared leg adds a warning;
+//   - one leg in YNAB: the existing transaction becomes a transfer;
+//   - no leg in YNAB: the outflow is created as a transfer.
+func BuildPlan(pairs []Pair, accounts []ynab.Account) (*Plan, error) {
+	transferPayees := make(map[string]string, len(accounts))
+	for _, acc := range accounts {
+		transferPayees[acc.ID] = acc.TransferPayeeID
+	}
+
+	payeeFor := func(accountID string) (string, error) {
+		id := transferPayees[accountID]
+		if id == "" {
+			return "", fmt.Errorf("account %s has no transfer payee", accountID)
+		}
+		return id, nil
+	}
+
+	plan := &Plan{}
+	for _, p := range pairs {
+		switch {
+		case p.Outflow.Existing != nil && p.Inflow.Existing != nil:
+			keep, replace, other := p.Outflow, p.Inflow, p.Inflow.AccountID
+			if isCleared(*p.Inflow.Existing) && !isCleared(*p.Outflow.Existing) {
+				keep, replace, other = p.Inflow, p.Outflow, p.Outflow.AccountID
+			}
+			payeeID, err := payeeFor(other)
+			if err != nil {
+				return nil, err
+			}
+			plan.Update = append(plan.Update, toTransfer(*keep.Existing, payeeID))
+			plan.Delete = append(plan.Delete, replace.Existing.ID)
+			if isCleared(*replace.Existing) {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+					"cleared transaction %s (%s, %s) is replaced by an uncleared transfer leg",
+					replace.Existing.ID, replace.Existing.Date, replace.Payee))
+			}
+
+		case p.Outflow.Existing != nil:
+			payeeID, err := payeeFor(p.Inflow.AccountID)
+			if err != nil {
+				return nil, err
+			}
+			plan.Update = append(plan.Update, toTransfer(*p.Outflow.Existing, payeeID))
+
+		case p.Inflow.Existing != nil:
+			payeeID, err := payeeFor(p.Outflow.AccountID)
+			if err != nil {
+				return nil, err
+			}
+			plan.Update = append(plan.Update, toTransfer(*p.Inflow.Existing, payeeID))
+
+		default:
+			payeeID, err := payeeFor(p.Inflow.AccountID)
+			if err != nil {
+				return nil, err
+			}
+			tx := p.Outflow.Statement
+			plan.Create = append(plan.Create, ynab.SaveTransaction{
+				AccountID: p.Outflow.AccountID,
+				Date:      tx.Date.Format(isoDate),
+				Amount:    p.Outflow.Amount,
+				PayeeID:   payeeID,
+				Memo:      tx.Memo,
+				Cleared:   ynab.ClearedStatusCleared,
+				ImportID:  tx.ImportID,
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+// toTransfer converts an existing transaction into a transfer via the given transfer payee.
+func toTransfer(t ynab.Transaction, transferPayeeID string) ynab.SaveTransactionWithID {
+	return ynab.SaveTransactionWithID{
+		ID:         t.ID,
+		PayeeID:    ynab.Ptr(transferPayeeID),
+		PayeeName:  ynab.Ptr(""),
+		CategoryID: ynab.Ptr(""),
+	}
+}
+
+// isCleared reports whether the bank has confirmed the transaction.
+func isCleared(t ynab.Transaction) bool {
+	return t.Cleared == ynab.ClearedStatusCleared || t.Cleared == ynab.ClearedStatusReconciled
+}
+
+// legKey identifies a leg for the used-once bookkeeping.
+func legKey(l Leg) string {
+	if l.Existing != nil {
+		return "ynab:" + l.Existing.ID
+	}
+	return fmt.Sprintf("stmt:%s:%d:%s", l.AccountID, l.Statement.SourceLine, l.Statement.ImportID)
+}
+
+// matchesAny reports whether s matches at least one of the patterns.
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, re := range patterns {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,260 @@
+package transfer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/domain"
+	"github.com/stretchr/testify/suite"
+)
+
+// TransferTestSuite groups all transfer detection tests.
+type TransferTestSuite struct {
+	suite.Suite
+	opts     Options
+	accounts []ynab.Account
+}
+
+func TestTransferTestSuite(t *testing.T) {
+	suite.Run(t, new(TransferTestSuite))
+}
+
+func (s *TransferTestSuite) SetupTest() {
+	patterns, err := CompilePatterns(nil)
+	s.Require().NoError(err)
+	s.opts = Options{DateWindow: 5, PayeePatterns: patterns}
+	s.accounts = []ynab.Account{
+		{ID: "checking", Name: "Sparkasse", TransferPayeeID: "payee-checking"},
+		{ID: "card", Name: "Miles & More", TransferPayeeID: "payee-card"},
+	}
+}
+
+func (s *TransferTestSuite) TestDetect_WithSettlementPair_ReturnsPair() {
+	// Arrange
+	debit, _ := ExistingLeg(ynab.Transaction{ID: "tx-1", AccountID: "checking", Date: "2026-02-05", Amount: -500000, PayeeName: "Miles & More Kreditkartenabrechnung"})
+	credit := StatementLeg("card", domain.Transaction{Date: date(2026, 2, 3), Amount: 500, Payee: "Lastschrift", ImportID: "YNAB:500000:2026-02-03:1", SourceLine: 7})
+
+	// Act
+	pairs := Detect([]Leg{debit, credit}, s.opts)
+
+	// Assert
+	s.Require().Len(pairs, 1)
+	s.Equal("checking", pairs[0].Outflow.AccountID)
+	s.Equal("card", pairs[0].Inflow.AccountID)
+	s.Equal(2, pairs[0].DayDelta)
+}
+
+func (s *TransferTestSuite) TestDetect_RequiresPatternWindowAndOtherAccount() {
+	// Arrange
+	tests := []struct {
+		name string
+		legs []Leg
+	}{
+		{
+			name: "no settlement payee",
+			legs: []Leg{
+				existing("tx-1", "checking", "2026-02-05", -500000, "Rent"),
+				existing("tx-2", "card", "2026-02-05", 500000, "Refund"),
+			},
+		},
+		{
+			name: "outside date window",
+			legs: []Leg{
+				existing("tx-1", "checking", "2026-02-01", -500000, "Kreditkarte"),
+				existing("tx-2", "card", "2026-02-10", 500000, "Kreditkarte"),
+			},
+		},
+		{
+			name: "same account",
+			legs: []Leg{
+				existing("tx-1", "card", "2026-02-05", -500000, "Kreditkarte"),
+				existing("tx-2", "card", "2026-02-05", 500000, "Kreditkarte"),
+			},
+		},
+		{
+			name: "different amount",
+			legs: []Leg{
+				existing("tx-1", "checking", "2026-02-05", -500000, "Kreditkarte"),
+				existing("tx-2", "card", "2026-02-05", 499000, "Kreditkarte"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			// Act
+			pairs := Detect(tt.legs, s.opts)
+
+			// Assert
+			s.Empty(pairs)
+		})
+	}
+}
+
+func (s *TransferTestSuite) TestDetect_UsesEachLegOnceClosestFirst() {
+	// Arrange
+	legs := []Leg{
+		existing("tx-far", "checking", "2026-02-01", -500000, "Kreditkarte"),
+		existing("tx-near", "checking", "2026-02-04", -500000, "Kreditkarte"),
+		existing("tx-in", "card", "2026-02-05", 500000, "Zahlung - Danke"),
+	}
+
+	// Act
+	pairs := Detect(legs, s.opts)
+
+	// Assert
+	s.Require().Len(pairs, 1)
+	s.Equal("tx-near", pairs[0].Outflow.Existing.ID)
+	s.Equal("tx-in", pairs[0].Inflow.Existing.ID)
+}
+
+func (s *TransferTestSuite) TestExistingLeg_SkipsTransfersDeletedAndReconciled() {
+	// Arrange
+	transactions := []ynab.Transaction{
+		{ID: "tx-1", Date: "2026-02-05", Amount: -1000, TransferAccountID: "card"},
+		{ID: "tx-2", Date: "2026-02-05", Amount: -1000, Deleted: true},
+		{ID: "tx-3", Date: "not-a-date", Amount: -1000},
+		{ID: "tx-4", Date: "2026-02-05", Amount: -1000, Cleared: ynab.ClearedStatusReconciled},
+	}
+
+	for _, t := range transactions {
+		// Act
+		_, ok := ExistingLeg(t)
+
+		// Assert
+		s.False(ok, t.ID)
+	}
+}
+
+func (s *TransferTestSuite) TestBuildPlan_WithBothLegsExisting_UpdatesOutflowAndDeletesInflow() {
+	// Arrange
+	pairs := []Pair{{
+		Outflow: existing("tx-1", "checking", "2026-02-05", -500000, "Kreditkarte"),
+		Inflow:  existing("tx-2", "card", "2026-02-04", 500000, "Zahlung - Danke"),
+	}}
+
+	// Act
+	plan, err := BuildPlan(pairs, s.accounts)
+
+	// Assert
+	s.NoError(err)
+	s.Empty(plan.Create)
+	s.Require().Len(plan.Update, 1)
+	s.Equal("tx-1", plan.Update[0].ID)
+	s.Equal(ynab.Ptr("payee-card"), plan.Update[0].PayeeID)
+	s.Equal(ynab.Ptr(""), plan.Update[0].PayeeName)
+	s.Equal(ynab.Ptr(""), plan.Update[0].CategoryID, "transfers clear the category")
+	s.Equal([]string{"tx-2"}, plan.Delete)
+}
+
+func (s *TransferTestSuite) TestBuildPlan_WithClearedInflow_ReplacesUnclearedOutflow() {
+	// Arrange
+	outflow := existing("tx-1", "checking", "2026-02-05", -500000, "Kreditkarte")
+	inflow := existing("tx-2", "card", "2026-02-04", 500000, "Zahlung - Danke")
+	inflow.Existing.Cleared = ynab.ClearedStatusCleared
+	pairs := []Pair{{Outflow: outflow, Inflow: inflow}}
+
+	// Act
+	plan, err := BuildPlan(pairs, s.accounts)
+
+	// Assert
+	s.NoError(err)
+	s.Require().Len(plan.Update, 1)
+	s.Equal("tx-2", plan.Update[0].ID)
+	s.Equal(ynab.Ptr("payee-checking"), plan.Update[0].PayeeID)
+	s.Equal([]string{"tx-1"}, plan.Delete)
+	s.Empty(plan.Warnings)
+}
+
+func (s *TransferTestSuite) TestBuildPlan_WithBothLegsCleared_WarnsAboutReplacedLeg() {
+	// Arrange
+	outflow := existing("tx-1", "checking", "2026-02-05", -500000, "Kreditkarte")
+	outflow.Existing.Cleared = ynab.ClearedStatusCleared
+	inflow := existing("tx-2", "card", "2026-02-04", 500000, "Zahlung - Danke")
+	inflow.Existing.Cleared = ynab.ClearedStatusCleared
+	pairs := []Pair{{Outflow: outflow, Inflow: inflow}}
+
+	// Act
+	plan, err := BuildPlan(pairs, s.accounts)
+
+	// Assert
+	s.NoError(err)
+	s.Equal("tx-1", plan.Update[0].ID)
+	s.Equal([]string{"tx-2"}, plan.Delete)
+	s.Require().Len(plan.Warnings, 1)
+	s.Contains(plan.Warnings[0], "cleared transaction tx-2")
+}
+
+func (s *TransferTestSuite) TestBuildPlan_WithExistingInflowOnly_UpdatesInflow() {
+	// Arrange
+	pairs := []Pair{{
+		Outflow: StatementLeg("checking", domain.Transaction{Date: date(2026, 2, 5), Amount: -500, Payee: "Kreditkarte"}),
+		Inflow:  existing("tx-2", "card", "2026-02-04", 500000, "Zahlung - Danke"),
+	}}
+
+	// Act
+	plan, err := BuildPlan(pairs, s.accounts)
+
+	// Assert
+	s.NoError(err)
+	s.Require().Len(plan.Update, 1)
+	s.Equal("tx-2", plan.Update[0].ID)
+	s.Equal(ynab.Ptr("payee-checking"), plan.Update[0].PayeeID)
+	s.Empty(plan.Delete)
+}
+
+func (s *TransferTestSuite) TestBuildPlan_WithStatementLegsOnly_CreatesOutflow() {
+	// Arrange
+	pairs := []Pair{{
+		Outflow: StatementLeg("checking", domain.Transaction{Date: date(2026, 2, 5), Amount: -500, Payee: "Kreditkarte", ImportID: "YNAB:-500000:2026-02-05:1"}),
+		Inflow:  StatementLeg("card", domain.Transaction{Date: date(2026, 2, 4), Amount: 500, Payee: "Zahlung - Danke"}),
+	}}
+
+	// Act
+	plan, err := BuildPlan(pairs, s.accounts)
+
+	// Assert
+	s.NoError(err)
+	s.Require().Len(plan.Create, 1)
+	s.Equal("checking", plan.Create[0].AccountID)
+	s.Equal("payee-card", plan.Create[0].PayeeID)
+	s.Equal(int64(-500000), plan.Create[0].Amount)
+	s.Equal("2026-02-05", plan.Create[0].Date)
+	s.Equal("YNAB:-500000:2026-02-05:1", plan.Create[0].ImportID)
+}
+
+func (s *TransferTestSuite) TestBuildPlan_WithoutTransferPayee_ReturnsError() {
+	// Arrange
+	pairs := []Pair{{
+		Outflow: existing("tx-1", "checking", "2026-02-05", -500000, "Kreditkarte"),
+		Inflow:  existing("tx-2", "unknown", "2026-02-04", 500000, "Zahlung - Danke"),
+	}}
+
+	// Act
+	plan, err := BuildPlan(pairs, s.accounts)
+
+	// Assert
+	s.Error(err)
+	s.Nil(plan)
+}
+
+func (s *TransferTestSuite) TestCompilePatterns_WithInvalidPattern_ReturnsError() {
+	// Act
+	patterns, err := CompilePatterns([]string{"("})
+
+	// Assert
+	s.Error(err)
+	s.Nil(patterns)
+}
+
+// existing creates a leg from a minimal YNAB transaction.
+func existing(id, accountID, isoDate string, amount int64, payee string) Leg {
+	leg, _ := ExistingLeg(ynab.Transaction{ID: id, AccountID: accountID, Date: isoDate, Amount: amount, PayeeName: payee})
+	return leg
+}
+
+// date creates a UTC date.
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
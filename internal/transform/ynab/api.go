@@ -0,0 +1,75 @@
+package ynab
+
+import (
+	ynabapi "github.com/pgbytes/moneypenny/internal/client/ynab"
+	"github.com/pgbytes/moneypenny/internal/domain"
+)
+
+const (
+	// apiDateFormat is the ISO date format expected by the YNAB API.
+	apiDateFormat = "2006-01-02"
+
+	// maxPayeeNameLength is the longest payee name accepted by the YNAB API.
+	maxPayeeNameLength = 200
+
+	// maxMemoLength is the longest memo accepted by the YNAB API.
+	maxMemoLength = 500
+)
+
+// ToSaveTransaction converts a domain transaction into a YNAB API transaction
+// for the given account. Statement rows are already settled, so they are
+// created as cleared but left unapproved for review in YNAB.
+//
+// Category references must already be resolved to YNAB category IDs.
+// Split transactions are created with one subtransaction per split.
+func ToSaveTransaction(tx domain.Transaction, accountID string) ynabapi.SaveTransaction {
+	save := ynabapi.SaveTransaction{
+		AccountID:  accountID,
+		Date:       tx.Date.Format(apiDateFormat),
+		Amount:     ynabapi.FloatToMilliunits(tx.Amount),
+		PayeeID:    tx.PayeeID,
+		PayeeName:  truncate(tx.Payee, maxPayeeNameLength),
+		CategoryID: tx.Category,
+		Memo:       truncate(tx.Memo, maxMemoLength),
+		Cleared:    ynabapi.ClearedStatusCleared,
+		FlagColor:  tx.FlagColor,
+		ImportID:   tx.ImportID,
+	}
+
+	// A resolved payee ID takes precedence over the name
+	if save.PayeeID != "" {
+		save.PayeeName = ""
+	}
+
+	if len(tx.Splits) > 0 {
+		save.CategoryID = ""
+		for _, split := range tx.Splits {
+			save.Subtransactions = append(save.Subtransactions, ynabapi.SaveSubTransaction{
+				Amount:     ynabapi.FloatToMilliunits(split.Amount),
+				PayeeName:  truncate(split.Payee, maxPayeeNameLength),
+				CategoryID: split.Category,
+				Memo:       truncate(split.Memo, maxMemoLength),
+			})
+		}
+	}
+
+	return save
+}
+
+// ToSaveTransactions converts domain transactions into YNAB API transactions for the given account.
+func ToSaveTransactions(transactions []domain.Transaction, accountID string) []ynabapi.SaveTransaction {
+	result := make([]ynabapi.SaveTransaction, 0, len(transactions))
+	for _, tx := range transactions {
+		result = append(result, ToSaveTransaction(tx, accountID))
+	}
+	return result
+}
+
+// truncate shortens s to at most maxLen runes.
+func truncate(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+	return string(runes[:maxLen])
+}
@@ -0,0 +1,51 @@
+package ynab
+
+import (
+	"strings"
+
+	"github.com/pgbytes/moneypenny/internal/domain"
+)
+
+// WithMerchantPayee returns tx with the merchant name as payee and the location
+// at the start of the memo. Transactions without a merchant are returned unchanged.
+func WithMerchantPayee(tx domain.Transaction) domain.Transaction {
+	if tx.Merchant == "" {
+		return tx
+	}
+
+	tx.Payee = tx.Merchant
+	if location := FormatLocation(tx); location != "" {
+		if tx.Memo == "" {
+			tx.Memo = location
+		} else {
+			tx.Memo = location + "; " + tx.Memo
+		}
+	}
+
+	return tx
+}
+
+// WithMerchantPayees applies WithMerchantPayee to every transaction.
+func WithMerchantPayees(transactions []domain.Transaction) []domain.Transaction {
+	result := make([]domain.Transaction, 0, len(transactions))
+	for _, tx := range transactions {
+		result = append(result, WithMerchantPayee(tx))
+	}
+	return result
+}
+
+// FormatLocation formats the location of a transaction as
+// "POSTAL CITY, REGION, COUNTRY", omitting missing parts.
+func FormatLocation(tx domain.Transaction) string {
+	var parts []string
+	if place := strings.TrimSpace(tx.PostalCode + " " + tx.City); place != "" {
+		parts = append(parts, place)
+	}
+	if tx.Region != "" {
+		parts = append(parts, tx.Region)
+	}
+	if tx.Country != "" {
+		parts = append(parts, tx.Country)
+	}
+	return strings.Join(parts, ", ")
+}
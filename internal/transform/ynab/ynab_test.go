@@ -376,3 +376,146 @@ func (s *FormatAmountTestSuite) TestFormatAmount_WithTwoDecimalPlaces_FormatsCor
 		})
 	}
 }
+
+// ToSaveTransactionTestSuite groups API conversion tests.
+type ToSaveTransactionTestSuite struct {
+	suite.Suite
+}
+
+func TestToSaveTransactionTestSuite(t *testing.T) {
+	suite.Run(t, new(ToSaveTransactionTestSuite))
+}
+
+// TestToSaveTransaction_WithForeignTransaction_MapsAllFields tests field mapping.
+func (s *ToSaveTransactionTestSuite) TestToSaveTransaction_WithForeignTransaction_MapsAllFields() {
+	// Arrange
+	tx := domain.Transaction{
+		Date:     time.Date(2026, 1, 28, 0, 0, 0, 0, time.UTC),
+		Payee:    "RECALL, 19709 MIDDLETOWN, DE, USA",
+		Memo:     "Coffee",
+		Amount:   -8.44,
+		ImportID: "YNAB:-8440:2026-01-28:1",
+	}
+
+	// Act
+	result := ToSaveTransaction(tx, "acc-1")
+
+	// Assert
+	s.Equal("acc-1", result.AccountID)
+	s.Equal("2026-01-28", result.Date)
+	s.Equal(int64(-8440), result.Amount)
+	s.Equal("RECALL, 19709 MIDDLETOWN, DE, USA", result.PayeeName)
+	s.Equal("Coffee", result.Memo)
+	s.Equal("YNAB:-8440:2026-01-28:1", result.ImportID)
+	s.Equal("cleared", string(result.Cleared))
+	s.False(result.Approved)
+}
+
+// TestToSaveTransaction_WithLongPayee_TruncatesToLimit tests API length limits.
+func (s *ToSaveTransactionTestSuite) TestToSaveTransaction_WithLongPayee_TruncatesToLimit() {
+	// Arrange
+	tx := domain.Transaction{
+		Date:  time.Date(2026, 1, 28, 0, 0, 0, 0, time.UTC),
+		Payee: strings.Repeat("ü", 250),
+	}
+
+	// Act
+	result := ToSaveTransaction(tx, "acc-1")
+
+	// Assert
+	s.Equal(200, len([]rune(result.PayeeName)))
+}
+
+// TestToSaveTransactions_WithMultipleTransactions_KeepsOrder tests slice conversion.
+func (s *ToSaveTransactionTestSuite) TestToSaveTransactions_WithMultipleTransactions_KeepsOrder() {
+	// Arrange
+	transactions := []domain.Transaction{
+		{Date: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Payee: "First", Amount: -1},
+		{Date: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), Payee: "Second", Amount: -2},
+	}
+
+	// Act
+	result := ToSaveTransactions(transactions, "acc-1")
+
+	// Assert
+	s.Len(result, 2)
+	s.Equal("First", result[0].PayeeName)
+	s.Equal("Second", result[1].PayeeName)
+}
+
+// TestToSaveTransaction_WithSplits_CreatesSubtransactions tests split conversion.
+func (s *ToSaveTransactionTestSuite) TestToSaveTransaction_WithSplits_CreatesSubtransactions() {
+	// Arrange
+	tx := domain.Transaction{
+		Date:      time.Date(2026, 1, 28, 0, 0, 0, 0, time.UTC),
+		Payee:     "Supermarket",
+		Amount:    -30,
+		Category:  "cat-ignored",
+		FlagColor: "blue",
+		Splits: []domain.Split{
+			{Amount: -20, Category: "cat-groceries"},
+			{Amount: -10, Category: "cat-household", Memo: "Cleaning"},
+		},
+	}
+
+	// Act
+	result := ToSaveTransaction(tx, "acc-1")
+
+	// Assert
+	s.Empty(result.CategoryID)
+	s.Equal("blue", result.FlagColor)
+	s.Require().Len(result.Subtransactions, 2)
+	s.Equal(int64(-20000), result.Subtransactions[0].Amount)
+	s.Equal("cat-groceries", result.Subtransactions[0].CategoryID)
+	s.Equal("Cleaning", result.Subtransactions[1].Memo)
+}
+
+// TestToSaveTransaction_WithPayeeID_OmitsPayeeName tests resolved payees.
+func (s *ToSaveTransactionTestSuite) TestToSaveTransaction_WithPayeeID_OmitsPayeeName() {
+	// Arrange
+	tx := domain.Transaction{
+		Date:    time.Date(2026, 1, 28, 0, 0, 0, 0, time.UTC),
+		Payee:   "REWE Markt 4711",
+		PayeeID: "payee-rewe",
+	}
+
+	// Act
+	result := ToSaveTransaction(tx, "acc-1")
+
+	// Assert
+	s.Equal("payee-rewe", result.PayeeID)
+	s.Empty(result.PayeeName)
+}
+
+// TestWithMerchantPayee_WithLocation_MovesLocationToMemo tests merchant payees.
+func (s *ToSaveTransactionTestSuite) TestWithMerchantPayee_WithLocation_MovesLocationToMemo() {
+	// Arrange
+	tx := domain.Transaction{
+		Payee:      "RECALL, 19709 MIDDLETOWN, DE, USA",
+		Merchant:   "RECALL",
+		PostalCode: "19709",
+		City:       "MIDDLETOWN",
+		Region:     "DE",
+		Country:    "USA",
+		Memo:       "Conference",
+	}
+
+	// Act
+	result := WithMerchantPayee(tx)
+
+	// Assert
+	s.Equal("RECALL", result.Payee)
+	s.Equal("19709 MIDDLETOWN, DE, USA; Conference", result.Memo)
+}
+
+// TestWithMerchantPayee_WithoutMerchant_KeepsTransaction tests rows without location.
+func (s *ToSaveTransactionTestSuite) TestWithMerchantPayee_WithoutMerchant_KeepsTransaction() {
+	// Arrange
+	tx := domain.Transaction{Payee: "AUSLANDSEINSATZENTGELT", Memo: "Fee for transaction: RECALL", Country: "USA"}
+
+	// Act
+	result := WithMerchantPayee(tx)
+
+	// Assert
+	s.Equal(tx, result)
+}